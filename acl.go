@@ -0,0 +1,336 @@
+// File: pkg/namedzone/acl.go
+package namedzone
+
+import (
+	"cmp"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+)
+
+// ExpandACL resolves the acl name (or a built-in: any, none, localhost,
+// localnets) to the flat set of client addresses it admits, as merged,
+// sorted prefixes. Nested lists and ACL references are expanded and
+// negations applied with named's first-match rule. Key terms count as not
+// matching, as for an unsigned client. localhost and localnets are taken
+// from the interfaces of the machine running the code.
+func (c *Config) ExpandACL(name string) ([]netip.Prefix, error) {
+	e := expander{c: c, seen: map[string]bool{}}
+	set, err := e.ref(name)
+	if err != nil {
+		return nil, err
+	}
+	return set.prefixes(), nil
+}
+
+// ExpandMatchList is ExpandACL for a match list, e.g. a zone's
+// allowTransfer.
+func (c *Config) ExpandMatchList(terms []MatchTerm) ([]netip.Prefix, error) {
+	e := expander{c: c, seen: map[string]bool{}}
+	set, err := e.list(terms)
+	if err != nil {
+		return nil, err
+	}
+	return set.prefixes(), nil
+}
+
+// Matches reports whether named would admit a client at address client,
+// signed with TSIG key (empty when unsigned), by the match list terms. It
+// follows named: the first term that matches decides, a negated term that
+// matches rejects, and a nested list or ACL matches only where it admits,
+// so a rejection inside it falls through rather than being flipped by an
+// outer negation. No matching term rejects. Undefined ACLs match nothing;
+// localhost and localnets are taken from the local interfaces.
+func (c *Config) Matches(terms []MatchTerm, client netip.Addr, key string) bool {
+	e := expander{c: c, seen: map[string]bool{}}
+	return e.match(terms, client.Unmap(), key) > 0
+}
+
+// match returns 1 when terms admit the client, -1 when they reject it and 0
+// when no term matches.
+func (e *expander) match(terms []MatchTerm, client netip.Addr, key string) int {
+	for _, t := range terms {
+		var hit bool
+		switch {
+		case len(t.Nested) > 0:
+			hit = e.match(t.Nested, client, key) > 0
+		case t.ACLRef != "":
+			hit = e.matchRef(t.ACLRef, client, key)
+		case t.Key != "":
+			hit = t.Key == key
+		case t.Address != "":
+			p, err := ParseMatchPrefix(t.Address)
+			hit = err == nil && p.Contains(client)
+		}
+		if !hit {
+			continue
+		}
+		if t.Not {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func (e *expander) matchRef(name string, client netip.Addr, key string) bool {
+	switch name {
+	case "any":
+		return true
+	case "none":
+		return false
+	case "localhost", "localnets":
+		set, err := localACL(name)
+		return err == nil && set.contains(client)
+	}
+	i := slices.IndexFunc(e.c.ACLs, func(a ACL) bool { return a.Name == name })
+	if i < 0 || e.seen[name] {
+		return false
+	}
+	e.seen[name] = true
+	defer delete(e.seen, name)
+	return e.match(e.c.ACLs[i].Elements, client, key) > 0
+}
+
+// ViewQuery describes a query for SelectViewFor.
+type ViewQuery struct {
+	Client netip.Addr
+	// Destination is the server address the query was sent to. The zero
+	// value means unknown: match-destinations is then not checked.
+	Destination netip.Addr
+	Key         string // TSIG key the query is signed with, if any
+	Recursive   bool   // the RD bit; views with match-recursive-only need it
+}
+
+// SelectView returns the view named would answer a recursive query from
+// client, signed with key (empty when unsigned), or nil when no view
+// matches. The destination address is not considered; see SelectViewFor.
+func (c *Config) SelectView(client netip.Addr, key string) *View {
+	return c.SelectViewFor(ViewQuery{Client: client, Key: key, Recursive: true})
+}
+
+// SelectViewFor returns the first view, in order, whose match-clients admits
+// the client, whose match-destinations admits the destination, and whose
+// match-recursive-only, if set, is met. Unset lists admit everything. It
+// returns nil when no view matches, which named answers with REFUSED.
+func (c *Config) SelectViewFor(q ViewQuery) *View {
+	for i := range c.Views {
+		v := &c.Views[i]
+		switch {
+		case len(v.MatchClients) > 0 && !c.Matches(v.MatchClients, q.Client, q.Key):
+		case len(v.MatchDestinations) > 0 && q.Destination.IsValid() && !c.Matches(v.MatchDestinations, q.Destination, q.Key):
+		case v.MatchRecursive != nil && *v.MatchRecursive && !q.Recursive:
+		default:
+			return v
+		}
+	}
+	return nil
+}
+
+// expander evaluates match lists against the ACLs of c.
+type expander struct {
+	c    *Config
+	seen map[string]bool // ACLs being expanded, to catch cycles
+}
+
+// list returns the addresses terms admit: each term decides the addresses
+// no earlier term decided.
+func (e *expander) list(terms []MatchTerm) (addrSet, error) {
+	var admitted, decided addrSet
+	for _, t := range terms {
+		s, err := e.term(t)
+		if err != nil {
+			return nil, err
+		}
+		if !t.Not {
+			admitted = admitted.union(s.minus(decided))
+		}
+		decided = decided.union(s)
+	}
+	return admitted, nil
+}
+
+// term returns the addresses a term matches before negation. A nested list
+// or ACL matches only where it admits: negative results inside it are not
+// turned positive by an outer negation.
+func (e *expander) term(t MatchTerm) (addrSet, error) {
+	switch {
+	case len(t.Nested) > 0:
+		return e.list(t.Nested)
+	case t.ACLRef != "":
+		return e.ref(t.ACLRef)
+	case t.Address != "":
+		p, err := ParseMatchPrefix(t.Address)
+		if err != nil {
+			return nil, err
+		}
+		return addrSet{prefixRange(p)}, nil
+	}
+	return nil, nil // key terms
+}
+
+func (e *expander) ref(name string) (addrSet, error) {
+	switch name {
+	case "any":
+		return addrSet{
+			prefixRange(netip.MustParsePrefix("0.0.0.0/0")),
+			prefixRange(netip.MustParsePrefix("::/0")),
+		}, nil
+	case "none":
+		return nil, nil
+	case "localhost", "localnets":
+		return localACL(name)
+	}
+	if e.seen[name] {
+		return nil, &ValueError{Path: "acl", Value: name, Msg: "refers to itself"}
+	}
+	i := slices.IndexFunc(e.c.ACLs, func(a ACL) bool { return a.Name == name })
+	if i < 0 {
+		return nil, &ReferenceError{Kind: "acl", Name: name, Path: "acl"}
+	}
+	e.seen[name] = true
+	defer delete(e.seen, name)
+	return e.list(e.c.ACLs[i].Elements)
+}
+
+// localACL resolves localhost (the host's addresses) or localnets (the
+// networks they are on) from the local interfaces.
+func localACL(name string) (addrSet, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("namedzone: %s: %w", name, err)
+	}
+	var set addrSet
+	for _, a := range addrs {
+		n, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip, ok := netip.AddrFromSlice(n.IP)
+		if !ok {
+			continue
+		}
+		ip = ip.Unmap()
+		bits, _ := n.Mask.Size()
+		if ip.Is4() && len(n.Mask) == net.IPv6len {
+			bits -= 96
+		}
+		if name == "localhost" {
+			bits = ip.BitLen()
+		}
+		set = set.union(addrSet{prefixRange(netip.PrefixFrom(ip, bits))})
+	}
+	return set, nil
+}
+
+// addrRange is an inclusive range of addresses of one family.
+type addrRange struct{ from, to netip.Addr }
+
+// addrSet is a sorted list of disjoint, non-adjacent ranges.
+type addrSet []addrRange
+
+func prefixRange(p netip.Prefix) addrRange {
+	p = p.Masked()
+	return addrRange{from: p.Addr(), to: lastAddr(p)}
+}
+
+// lastAddr returns the highest address in p.
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Addr().As16()
+	bits := p.Bits()
+	if p.Addr().Is4() {
+		bits += 96
+	}
+	for i := bits; i < 128; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	a := netip.AddrFrom16(b)
+	if p.Addr().Is4() {
+		a = a.Unmap()
+	}
+	return a
+}
+
+func (s addrSet) union(o addrSet) addrSet {
+	all := append(slices.Clone(s), o...)
+	slices.SortFunc(all, func(a, b addrRange) int { return a.from.Compare(b.from) })
+	var out addrSet
+	for _, r := range all {
+		if n := len(out); n > 0 {
+			last := &out[n-1]
+			next := last.to.Next()
+			if last.to.BitLen() == r.from.BitLen() && (!next.IsValid() || r.from.Compare(next) <= 0) {
+				if r.to.Compare(last.to) > 0 {
+					last.to = r.to
+				}
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func (s addrSet) contains(a netip.Addr) bool {
+	return slices.ContainsFunc(s, func(r addrRange) bool {
+		return r.from.BitLen() == a.BitLen() && r.from.Compare(a) <= 0 && a.Compare(r.to) <= 0
+	})
+}
+
+func (s addrSet) minus(o addrSet) addrSet {
+	var out addrSet
+	for _, r := range s {
+		pieces := []addrRange{r}
+		for _, x := range o {
+			var rest []addrRange
+			for _, p := range pieces {
+				if x.to.BitLen() != p.from.BitLen() || x.to.Less(p.from) || p.to.Less(x.from) {
+					rest = append(rest, p)
+					continue
+				}
+				if p.from.Less(x.from) {
+					rest = append(rest, addrRange{p.from, x.from.Prev()})
+				}
+				if x.to.Less(p.to) {
+					rest = append(rest, addrRange{x.to.Next(), p.to})
+				}
+			}
+			pieces = rest
+		}
+		out = append(out, pieces...)
+	}
+	return out
+}
+
+// prefixes splits the set into the fewest CIDR prefixes.
+func (s addrSet) prefixes() []netip.Prefix {
+	var out []netip.Prefix
+	for _, r := range s {
+		for from := r.from; ; {
+			p := largestPrefix(from, r.to)
+			out = append(out, p)
+			last := lastAddr(p)
+			if last == r.to {
+				break
+			}
+			from = last.Next()
+		}
+	}
+	slices.SortFunc(out, func(a, b netip.Prefix) int {
+		return cmp.Or(a.Addr().Compare(b.Addr()), cmp.Compare(a.Bits(), b.Bits()))
+	})
+	return out
+}
+
+// largestPrefix returns the largest prefix that starts at from and ends at
+// or before to.
+func largestPrefix(from, to netip.Addr) netip.Prefix {
+	for bits := 0; bits < from.BitLen(); bits++ {
+		p := netip.PrefixFrom(from, bits)
+		if p.Masked().Addr() == from && lastAddr(p).Compare(to) <= 0 {
+			return p
+		}
+	}
+	return netip.PrefixFrom(from, from.BitLen())
+}
@@ -2,39 +2,84 @@
 package namedzone
 
 import (
-	"errors"
+	"io"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
 )
 
 // GetZone returns the first zone with the given name (top-level or within any view).
 func (c *Config) GetZone(name string) *Zone {
-	for i := range c.Zones {
-		if c.Zones[i].Name == name {
+	name = c.zoneKey(name)
+	if i := c.zonePos(name); i >= 0 {
+		return &c.Zones[i]
+	}
+	for i := range c.Views {
+		v := &c.Views[i]
+		if j := c.zonePosIn(v, name); j >= 0 {
+			return &v.Zones[j]
+		}
+	}
+	return nil
+}
+
+// ZoneRef is a zone together with the view that holds it.
+type ZoneRef struct {
+	View string // empty for a top-level zone
+	Zone *Zone
+}
+
+// GetZoneInView returns the zone with the given name in the named view, or
+// among the top-level zones when view is empty.
+func (c *Config) GetZoneInView(view, name string) *Zone {
+	name = c.zoneKey(name)
+	if view == "" {
+		if i := c.zonePos(name); i >= 0 {
 			return &c.Zones[i]
 		}
+		return nil
+	}
+	v := c.FindView(view)
+	if v == nil {
+		return nil
+	}
+	if i := c.zonePosIn(v, name); i >= 0 {
+		return &v.Zones[i]
+	}
+	return nil
+}
+
+// GetZones returns every instance of the zone name: the top-level one first,
+// then one per view that defines it, in view order.
+func (c *Config) GetZones(name string) []ZoneRef {
+	var out []ZoneRef
+	name = c.zoneKey(name)
+	if i := c.zonePos(name); i >= 0 {
+		out = append(out, ZoneRef{Zone: &c.Zones[i]})
 	}
 	for i := range c.Views {
-		for j := range c.Views[i].Zones {
-			if c.Views[i].Zones[j].Name == name {
-				return &c.Views[i].Zones[j]
-			}
+		v := &c.Views[i]
+		if j := c.zonePosIn(v, name); j >= 0 {
+			out = append(out, ZoneRef{View: v.Name, Zone: &v.Zones[j]})
 		}
 	}
-	return nil
+	return out
 }
 
 // UpsertZone inserts or replaces a top-level zone by name.
 func (c *Config) UpsertZone(z Zone) {
-	for i := range c.Zones {
-		if c.Zones[i].Name == z.Name {
-			c.Zones[i] = z
-			return
-		}
+	z = c.idnZone(z)
+	defer c.audit("UpsertZone", z.Name, nil)
+	if i := c.zonePos(z.Name); i >= 0 {
+		c.Zones[i] = z
+		return
 	}
-	c.Zones = append(c.Zones, z)
+	c.appendZone(z)
 }
 
 // RemoveZone removes a top-level zone by name and returns true if found.
 func (c *Config) RemoveZone(name string) bool {
+	name = c.zoneKey(name)
 	out := c.Zones[:0]
 	removed := false
 	for _, z := range c.Zones {
@@ -45,28 +90,29 @@ func (c *Config) RemoveZone(name string) bool {
 		out = append(out, z)
 	}
 	c.Zones = out
+	if removed {
+		c.forgetZones()
+		c.audit("RemoveZone", name, nil)
+	}
 	return removed
 }
 
 // FindView returns a pointer to the view with the given name.
 func (c *Config) FindView(name string) *View {
-	for i := range c.Views {
-		if c.Views[i].Name == name {
-			return &c.Views[i]
-		}
+	if i := c.viewPos(name); i >= 0 {
+		return &c.Views[i]
 	}
 	return nil
 }
 
 // UpsertView inserts or replaces a view by name.
 func (c *Config) UpsertView(v View) {
-	for i := range c.Views {
-		if c.Views[i].Name == v.Name {
-			c.Views[i] = v
-			return
-		}
+	defer c.audit("UpsertView", v.Name, nil)
+	if i := c.viewPos(v.Name); i >= 0 {
+		c.Views[i] = v
+		return
 	}
-	c.Views = append(c.Views, v)
+	c.appendView(v)
 }
 
 // RemoveView removes a view by name and returns true if found.
@@ -81,11 +127,16 @@ func (c *Config) RemoveView(name string) bool {
 		out = append(out, v)
 	}
 	c.Views = out
+	if removed {
+		c.forgetViews()
+		c.audit("RemoveView", name, nil)
+	}
 	return removed
 }
 
 // SetRecursion sets global options.recursion (creates Options if absent).
 func (c *Config) SetRecursion(b bool) {
+	defer c.audit("SetRecursion", boolWord(b), nil)
 	if c.Options == nil {
 		c.Options = &Options{}
 	}
@@ -93,15 +144,53 @@ func (c *Config) SetRecursion(b bool) {
 }
 
 // Save applies the typed config back to the underlying AST and writes the file.
-// It requires that the Config originated from FromFile (i.e., has c.ast populated).
+// A Config built in code (or decoded from JSON) gets a fresh AST.
+// For a LoadTree config the root file is written to path and each changed
+// included file is written back in place. Configs loaded with FromFS are
+// written to their FS, which must then be a WriteFS.
 func (c *Config) Save(path string) error {
-	if c.ast == nil {
-		return errors.New("namedzone: no underlying AST; call FromFile first")
+	if c.fsys == nil {
+		return c.SaveFS(osFS{}, path)
+	}
+	w, ok := c.fsys.(WriteFS)
+	if !ok {
+		return ErrReadOnlyFS
+	}
+	return c.SaveFS(w, path)
+}
+
+// FromReader parses a named.conf from r and builds a typed Config. Include
+// statements are not followed.
+func FromReader(r io.Reader) (*Config, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
 	}
-	if err := c.Apply(c.ast); err != nil {
-		return err
+	f, err := nc.Parse(b)
+	if err != nil {
+		return nil, err
 	}
-	return c.ast.Save(path)
+	return FromFile(f)
+}
+
+// WriteTo applies the typed config to the underlying AST and writes the
+// rendered root file to w. It implements io.WriterTo. A Config built in code
+// is rendered from scratch.
+func (c *Config) WriteTo(w io.Writer) (int64, error) {
+	if err := c.Apply(nil); err != nil {
+		return 0, err
+	}
+	n, err := w.Write(render(c.ast))
+	return int64(n), err
+}
+
+// Render returns the named.conf text WriteTo would write.
+func (c *Config) Render() (string, error) {
+	var sb strings.Builder
+	if _, err := c.WriteTo(&sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
 }
 
 // ---- View-scoped helpers (for web APIs) ----
@@ -109,22 +198,23 @@ func (c *Config) Save(path string) error {
 // UpsertZone inserts/replaces a zone inside a specific view by name. If the
 // view does not exist, it is created with default settings.
 func (c *Config) UpsertZoneInView(viewName string, z Zone) {
+	z = c.idnZone(z)
+	defer c.audit("UpsertZoneInView", viewName+"/"+z.Name, nil)
 	v := c.FindView(viewName)
 	if v == nil {
-		c.Views = append(c.Views, View{Name: viewName, Zones: []Zone{z}})
+		c.appendView(View{Name: viewName, Zones: []Zone{z}})
 		return
 	}
-	for i := range v.Zones {
-		if v.Zones[i].Name == z.Name {
-			v.Zones[i] = z
-			return
-		}
+	if i := c.zonePosIn(v, z.Name); i >= 0 {
+		v.Zones[i] = z
+		return
 	}
-	v.Zones = append(v.Zones, z)
+	c.appendZoneIn(v, z)
 }
 
 // RemoveZoneInView removes a zone by name from a specific view.
 func (c *Config) RemoveZoneInView(viewName, zoneName string) bool {
+	zoneName = c.zoneKey(zoneName)
 	v := c.FindView(viewName)
 	if v == nil {
 		return false
@@ -139,11 +229,16 @@ func (c *Config) RemoveZoneInView(viewName, zoneName string) bool {
 		out = append(out, z)
 	}
 	v.Zones = out
+	if removed {
+		c.forgetZonesIn(v.Name)
+		c.audit("RemoveZoneInView", viewName+"/"+zoneName, nil)
+	}
 	return removed
 }
 
 // SetTrustAnchorsInView replaces (or sets) trust-anchors inside the given view.
 func (c *Config) SetTrustAnchorsInView(viewName string, ta TrustAnchors) {
+	defer c.audit("SetTrustAnchorsInView", viewName, nil)
 	v := c.FindView(viewName)
 	if v == nil {
 		c.Views = append(c.Views, View{Name: viewName, TrustAnchors: &ta})
@@ -151,3 +246,88 @@ func (c *Config) SetTrustAnchorsInView(viewName string, ta TrustAnchors) {
 	}
 	v.TrustAnchors = &ta
 }
+
+// ---- Un-modeled options (Options.Other) ----
+
+// isTypedOption reports whether parseOptions maps keyword onto a typed field
+// rather than Other. It asks the parser itself so it never drifts from it;
+// the probe value "1" is a valid size and duration, so the options that fall
+// back to Other on a bad value still count as typed.
+func isTypedOption(keyword string) bool {
+	op := (&loader{}).parseOptions(nc.NewBlockStmt("options", []nc.Node{nc.NewSimpleStmt(keyword + " 1")}))
+	return len(op.Other) == 0
+}
+
+// Get returns the raw value of the first option called name. Typed options
+// are rendered from their field, so Get works for any option the Config holds.
+func (o *Options) Get(name string) (string, bool) {
+	if isTypedOption(name) {
+		for _, n := range buildOptions(*o).Body {
+			if st, ok := n.(*nc.Stmt); ok && st.Keyword == name {
+				return stmtValue(st), true
+			}
+		}
+		return "", false
+	}
+	for _, kv := range o.Other {
+		if kv.Name == name {
+			return kv.Raw, true
+		}
+	}
+	return "", false
+}
+
+// GetAll returns the raw values of every occurrence of an un-modeled option.
+func (o *Options) GetAll(name string) []string {
+	var out []string
+	for _, kv := range o.Other {
+		if kv.Name == name {
+			out = append(out, kv.Raw)
+		}
+	}
+	return out
+}
+
+// Set stores raw as the value of an un-modeled option, replacing all earlier
+// occurrences in place. Options with a typed field must be set through it.
+func (o *Options) Set(name, raw string) error {
+	if isTypedOption(name) {
+		return &ConflictError{Kind: "option", Name: name, Msg: "has a typed field; set it directly"}
+	}
+	out := o.Other[:0]
+	set := false
+	for _, kv := range o.Other {
+		if kv.Name == name {
+			if set {
+				continue
+			}
+			kv.Raw = raw
+			set = true
+		}
+		out = append(out, kv)
+	}
+	if !set {
+		out = append(out, RawKV{Name: name, Raw: raw})
+	}
+	o.Other = out
+	return nil
+}
+
+// Delete removes every occurrence of an un-modeled option and reports whether
+// any was present. Options with a typed field must be cleared through it.
+func (o *Options) Delete(name string) (bool, error) {
+	if isTypedOption(name) {
+		return false, &ConflictError{Kind: "option", Name: name, Msg: "has a typed field; clear it directly"}
+	}
+	out := o.Other[:0]
+	removed := false
+	for _, kv := range o.Other {
+		if kv.Name == name {
+			removed = true
+			continue
+		}
+		out = append(out, kv)
+	}
+	o.Other = out
+	return removed, nil
+}
@@ -0,0 +1,136 @@
+// File: pkg/namedzone/apis/v1alpha1/convert.go
+package v1alpha1
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+// Resources is the set of objects describing one named.conf.
+type Resources struct {
+	Options *Options
+	ACLs    []ACL
+	Views   []View
+	Zones   []Zone
+}
+
+// ResourceName returns the object name used for a zone, view or acl by
+// FromConfig: the name lowercased, with characters Kubernetes does not
+// allow in names replaced by "-", "root" for the root zone, and the view
+// prefixed with "--" for zones in a view.
+func ResourceName(view, name string) string {
+	clean := func(s string) string {
+		s = strings.Trim(strings.ToLower(s), ".")
+		return strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.' {
+				return r
+			}
+			return '-'
+		}, s)
+	}
+	n := cmp.Or(clean(name), "root")
+	if view != "" {
+		n = clean(view) + "--" + n
+	}
+	return n
+}
+
+func typeMeta(kind string) TypeMeta { return TypeMeta{APIVersion: APIVersion, Kind: kind} }
+
+// FromConfig converts the options, ACLs, views and zones of c to resources
+// named with ResourceName. Views are numbered in order. Other statements
+// (keys, tls, logging, ...) are not covered by these resources.
+func FromConfig(c *namedzone.Config) *Resources {
+	c = c.Clone()
+	r := &Resources{}
+	if c.Options != nil {
+		r.Options = &Options{TypeMeta: typeMeta("Options"), ObjectMeta: ObjectMeta{Name: "options"}, Spec: OptionsSpec{Options: *c.Options}}
+	}
+	for _, a := range c.ACLs {
+		r.ACLs = append(r.ACLs, ACL{TypeMeta: typeMeta("ACL"), ObjectMeta: ObjectMeta{Name: ResourceName("", a.Name)}, Spec: ACLSpec{ACL: a}})
+	}
+	zone := func(view string, z namedzone.Zone) {
+		r.Zones = append(r.Zones, Zone{TypeMeta: typeMeta("Zone"), ObjectMeta: ObjectMeta{Name: ResourceName(view, z.Name)}, Spec: ZoneSpec{View: view, Zone: z}})
+	}
+	for _, z := range c.Zones {
+		zone("", z)
+	}
+	for i, v := range c.Views {
+		for _, z := range v.Zones {
+			zone(v.Name, z)
+		}
+		v.Zones = nil
+		r.Views = append(r.Views, View{TypeMeta: typeMeta("View"), ObjectMeta: ObjectMeta{Name: ResourceName("", v.Name)}, Spec: ViewSpec{Order: i, View: v}})
+	}
+	return r
+}
+
+// Config builds the Config r describes. An item whose spec has no name
+// takes the object name. ACLs and top-level zones are sorted by name,
+// views by Order and then name, and zones placed in the view their spec
+// names. A zone in an undefined view is a *namedzone.ReferenceError, and
+// two objects defining the same item a *namedzone.ConflictError. The
+// config is not validated; see Config.Validate.
+func (r *Resources) Config() (*namedzone.Config, error) {
+	c := &namedzone.Config{}
+	if r.Options != nil {
+		o := r.Options.DeepCopy().Spec.Options
+		c.Options = &o
+	}
+
+	acls := slices.Clone(r.ACLs)
+	slices.SortStableFunc(acls, func(a, b ACL) int { return strings.Compare(aclName(a), aclName(b)) })
+	for _, obj := range acls {
+		a := obj.DeepCopy().Spec.ACL
+		a.Name = aclName(obj)
+		if slices.ContainsFunc(c.ACLs, func(o namedzone.ACL) bool { return o.Name == a.Name }) {
+			return nil, &namedzone.ConflictError{Kind: "acl", Name: a.Name, Msg: "defined by more than one object"}
+		}
+		c.ACLs = append(c.ACLs, a)
+	}
+
+	views := slices.Clone(r.Views)
+	slices.SortStableFunc(views, func(a, b View) int {
+		return cmp.Or(cmp.Compare(a.Spec.Order, b.Spec.Order), strings.Compare(viewName(a), viewName(b)))
+	})
+	for _, obj := range views {
+		v := obj.DeepCopy().Spec.View
+		v.Name, v.Zones = viewName(obj), nil
+		if c.FindView(v.Name) != nil {
+			return nil, &namedzone.ConflictError{Kind: "view", Name: v.Name, Msg: "defined by more than one object"}
+		}
+		c.Views = append(c.Views, v)
+	}
+
+	zones := slices.Clone(r.Zones)
+	slices.SortStableFunc(zones, func(a, b Zone) int { return strings.Compare(zoneName(a), zoneName(b)) })
+	for _, obj := range zones {
+		z := obj.DeepCopy().Spec.Zone
+		z.Name = zoneName(obj)
+		view := obj.Spec.View
+		path := "zone[" + z.Name + "]"
+		if view != "" {
+			path = "view[" + view + "]." + path
+		}
+		if view != "" && c.FindView(view) == nil {
+			return nil, &namedzone.ReferenceError{Kind: "view", Name: view, Path: path}
+		}
+		if c.GetZoneInView(view, z.Name) != nil {
+			return nil, &namedzone.ConflictError{Kind: "zone", Name: path, Msg: "defined by more than one object"}
+		}
+		if view == "" {
+			c.Zones = append(c.Zones, z)
+			continue
+		}
+		v := c.FindView(view)
+		v.Zones = append(v.Zones, z)
+	}
+	return c, nil
+}
+
+func aclName(a ACL) string   { return cmp.Or(a.Spec.Name, a.ObjectMeta.Name) }
+func viewName(v View) string { return cmp.Or(v.Spec.Name, v.ObjectMeta.Name) }
+func zoneName(z Zone) string { return cmp.Or(z.Spec.Name, z.ObjectMeta.Name) }
@@ -0,0 +1,344 @@
+// File: pkg/namedzone/apis/v1alpha1/types.go
+
+// Package v1alpha1 holds Kubernetes-style API types for Zone, View, ACL and
+// Options resources, in group namedzone.dlukt.github.io, and converters to
+// and from namedzone types, so an operator can reconcile custom resources
+// into a named.conf with this package as its engine.
+//
+// The package does not import k8s.io/apimachinery. TypeMeta, ObjectMeta,
+// ListMeta and Condition mirror the metav1 fields the resources need, with
+// the same JSON names, so objects decode from the API server's JSON as they
+// are; an operator that registers the types with a scheme embeds its own
+// metav1 types alongside the Spec and Status defined here. Every type has
+// DeepCopy and DeepCopyInto like controller-gen output.
+package v1alpha1
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/dlukt/namedzone"
+)
+
+// Group and Version of the resources.
+const (
+	Group   = "namedzone.dlukt.github.io"
+	Version = "v1alpha1"
+)
+
+// APIVersion is the apiVersion of the resources.
+const APIVersion = Group + "/" + Version
+
+// TypeMeta mirrors metav1.TypeMeta.
+type TypeMeta struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+}
+
+// ObjectMeta mirrors the metav1.ObjectMeta fields an operator reads.
+type ObjectMeta struct {
+	Name            string            `json:"name,omitempty"`
+	Namespace       string            `json:"namespace,omitempty"`
+	UID             string            `json:"uid,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+	Generation      int64             `json:"generation,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+}
+
+// ListMeta mirrors metav1.ListMeta.
+type ListMeta struct {
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	Continue        string `json:"continue,omitempty"`
+}
+
+// Condition mirrors metav1.Condition.
+type Condition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"` // "True", "False" or "Unknown"
+	ObservedGeneration int64     `json:"observedGeneration,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+	Reason             string    `json:"reason"`
+	Message            string    `json:"message"`
+}
+
+// Status is the status of every resource: the generation last reconciled
+// and conditions such as Ready.
+type Status struct {
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	Conditions         []Condition `json:"conditions,omitempty"`
+}
+
+// ZoneSpec is a zone statement and the view that holds it.
+type ZoneSpec struct {
+	// View names the View resource holding the zone; empty for a
+	// top-level zone.
+	View           string `json:"view,omitempty"`
+	namedzone.Zone `json:",inline"`
+}
+
+// Zone is a zone resource.
+type Zone struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+	Spec       ZoneSpec `json:"spec"`
+	Status     Status   `json:"status,omitempty"`
+}
+
+// ZoneList is a list of Zone.
+type ZoneList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+	Items    []Zone `json:"items"`
+}
+
+// ViewSpec is a view statement without its zones, which are Zone resources
+// naming the view.
+type ViewSpec struct {
+	// Order places the view among the others; named matches views in
+	// order. Ties go by name.
+	Order          int `json:"order,omitempty"`
+	namedzone.View `json:",inline"`
+}
+
+// View is a view resource.
+type View struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+	Spec       ViewSpec `json:"spec"`
+	Status     Status   `json:"status,omitempty"`
+}
+
+// ViewList is a list of View.
+type ViewList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+	Items    []View `json:"items"`
+}
+
+// ACLSpec is an acl statement.
+type ACLSpec struct {
+	namedzone.ACL `json:",inline"`
+}
+
+// ACL is an acl resource.
+type ACL struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+	Spec       ACLSpec `json:"spec"`
+	Status     Status  `json:"status,omitempty"`
+}
+
+// ACLList is a list of ACL.
+type ACLList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+	Items    []ACL `json:"items"`
+}
+
+// OptionsSpec is the options statement.
+type OptionsSpec struct {
+	namedzone.Options `json:",inline"`
+}
+
+// Options is the options resource; a config has at most one.
+type Options struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+	Spec       OptionsSpec `json:"spec"`
+	Status     Status      `json:"status,omitempty"`
+}
+
+// OptionsList is a list of Options.
+type OptionsList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+	Items    []Options `json:"items"`
+}
+
+// DeepCopyInto copies m into out.
+func (m *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
+	*out = *m
+	out.Labels = maps.Clone(m.Labels)
+	out.Annotations = maps.Clone(m.Annotations)
+}
+
+// DeepCopyInto copies s into out.
+func (s *Status) DeepCopyInto(out *Status) {
+	*out = *s
+	out.Conditions = slices.Clone(s.Conditions)
+}
+
+// The namedzone types are copied through Config.Clone, which shares no
+// slice, map or pointer with the original.
+
+// DeepCopyInto copies s into out.
+func (s *ZoneSpec) DeepCopyInto(out *ZoneSpec) {
+	out.View = s.View
+	out.Zone = (&namedzone.Config{Zones: []namedzone.Zone{s.Zone}}).Clone().Zones[0]
+}
+
+// DeepCopyInto copies s into out.
+func (s *ViewSpec) DeepCopyInto(out *ViewSpec) {
+	out.Order = s.Order
+	out.View = (&namedzone.Config{Views: []namedzone.View{s.View}}).Clone().Views[0]
+}
+
+// DeepCopyInto copies s into out.
+func (s *ACLSpec) DeepCopyInto(out *ACLSpec) {
+	out.ACL = (&namedzone.Config{ACLs: []namedzone.ACL{s.ACL}}).Clone().ACLs[0]
+}
+
+// DeepCopyInto copies s into out.
+func (s *OptionsSpec) DeepCopyInto(out *OptionsSpec) {
+	o := s.Options
+	out.Options = *(&namedzone.Config{Options: &o}).Clone().Options
+}
+
+// DeepCopyInto copies z into out.
+func (z *Zone) DeepCopyInto(out *Zone) {
+	out.TypeMeta = z.TypeMeta
+	z.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	z.Spec.DeepCopyInto(&out.Spec)
+	z.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of z.
+func (z *Zone) DeepCopy() *Zone {
+	if z == nil {
+		return nil
+	}
+	out := new(Zone)
+	z.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies l into out.
+func (l *ZoneList) DeepCopyInto(out *ZoneList) {
+	out.TypeMeta, out.ListMeta = l.TypeMeta, l.ListMeta
+	out.Items = copyItems(l.Items, (*Zone).DeepCopyInto)
+}
+
+// DeepCopy returns a deep copy of l.
+func (l *ZoneList) DeepCopy() *ZoneList {
+	if l == nil {
+		return nil
+	}
+	out := new(ZoneList)
+	l.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies v into out.
+func (v *View) DeepCopyInto(out *View) {
+	out.TypeMeta = v.TypeMeta
+	v.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	v.Spec.DeepCopyInto(&out.Spec)
+	v.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of v.
+func (v *View) DeepCopy() *View {
+	if v == nil {
+		return nil
+	}
+	out := new(View)
+	v.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies l into out.
+func (l *ViewList) DeepCopyInto(out *ViewList) {
+	out.TypeMeta, out.ListMeta = l.TypeMeta, l.ListMeta
+	out.Items = copyItems(l.Items, (*View).DeepCopyInto)
+}
+
+// DeepCopy returns a deep copy of l.
+func (l *ViewList) DeepCopy() *ViewList {
+	if l == nil {
+		return nil
+	}
+	out := new(ViewList)
+	l.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies a into out.
+func (a *ACL) DeepCopyInto(out *ACL) {
+	out.TypeMeta = a.TypeMeta
+	a.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	a.Spec.DeepCopyInto(&out.Spec)
+	a.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of a.
+func (a *ACL) DeepCopy() *ACL {
+	if a == nil {
+		return nil
+	}
+	out := new(ACL)
+	a.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies l into out.
+func (l *ACLList) DeepCopyInto(out *ACLList) {
+	out.TypeMeta, out.ListMeta = l.TypeMeta, l.ListMeta
+	out.Items = copyItems(l.Items, (*ACL).DeepCopyInto)
+}
+
+// DeepCopy returns a deep copy of l.
+func (l *ACLList) DeepCopy() *ACLList {
+	if l == nil {
+		return nil
+	}
+	out := new(ACLList)
+	l.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies o into out.
+func (o *Options) DeepCopyInto(out *Options) {
+	out.TypeMeta = o.TypeMeta
+	o.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	o.Spec.DeepCopyInto(&out.Spec)
+	o.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of o.
+func (o *Options) DeepCopy() *Options {
+	if o == nil {
+		return nil
+	}
+	out := new(Options)
+	o.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies l into out.
+func (l *OptionsList) DeepCopyInto(out *OptionsList) {
+	out.TypeMeta, out.ListMeta = l.TypeMeta, l.ListMeta
+	out.Items = copyItems(l.Items, (*Options).DeepCopyInto)
+}
+
+// DeepCopy returns a deep copy of l.
+func (l *OptionsList) DeepCopy() *OptionsList {
+	if l == nil {
+		return nil
+	}
+	out := new(OptionsList)
+	l.DeepCopyInto(out)
+	return out
+}
+
+func copyItems[T any](items []T, into func(*T, *T)) []T {
+	if items == nil {
+		return nil
+	}
+	out := make([]T, len(items))
+	for i := range items {
+		into(&items[i], &out[i])
+	}
+	return out
+}
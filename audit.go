@@ -0,0 +1,228 @@
+// File: pkg/namedzone/audit.go
+package namedzone
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditRecord is one entry of the audit trail: who did what, when. Records
+// form a hash chain: Hash covers the record, PrevHash included, so editing,
+// dropping or reordering records breaks VerifyAuditLog.
+type AuditRecord struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor,omitempty"`
+	Action string    `json:"action"`           // the API call, e.g. "UpsertZone", "Save"
+	Target string    `json:"target,omitempty"` // zone or view name, file path, ...
+
+	// Changes is what the call changed in the AST, for Save and friends.
+	Changes *ChangeSet `json:"changes,omitempty"`
+
+	PrevHash string `json:"prevHash"`
+	Hash     string `json:"hash"`
+}
+
+// digest returns the hex SHA-256 of r with Hash cleared.
+func (r AuditRecord) digest() string {
+	r.Hash = ""
+	b, _ := json.Marshal(r)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditSink stores audit records, e.g. in a file, syslog or a database.
+type AuditSink interface {
+	Record(AuditRecord) error
+}
+
+// AuditChain is implemented by sinks that continue a log they already hold,
+// such as a JSON-lines file appended to by successive runs. LastHash
+// returns the Hash of the last stored record, "" for an empty log; records
+// then chain to it rather than to the previous record of the same Config.
+type AuditChain interface {
+	LastHash() (string, error)
+}
+
+// auditor chains the records of one Config into its sink.
+type auditor struct {
+	mu    sync.Mutex
+	sink  AuditSink
+	actor string
+	last  string
+}
+
+// SetAudit sends a record of every mutating call on c (UpsertZone,
+// RemoveView, SetRecursion, patches, Merge, Save, ...) to sink, attributed
+// to actor. A nil sink turns auditing off. Saves fail, before anything is
+// written, if their record cannot be stored; for other calls the failure is
+// logged. Sinks that implement AuditChain continue the chain they hold.
+func (c *Config) SetAudit(sink AuditSink, actor string) {
+	if sink == nil {
+		c.auditor = nil
+		return
+	}
+	c.auditor = &auditor{sink: sink, actor: actor}
+}
+
+// SetAuditActor changes who subsequent records are attributed to, e.g. per
+// request in a server.
+func (c *Config) SetAuditActor(actor string) {
+	if a := c.auditor; a != nil {
+		a.mu.Lock()
+		a.actor = actor
+		a.mu.Unlock()
+	}
+}
+
+// audit records action on target. The error is logged as well as returned.
+func (c *Config) audit(action, target string, cs *ChangeSet) error {
+	a := c.auditor
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if ch, ok := a.sink.(AuditChain); ok {
+		last, err := ch.LastHash()
+		if err != nil {
+			c.log().Error("audit chain not readable", "action", action, "target", target, "err", err)
+			return err
+		}
+		a.last = last
+	}
+	r := AuditRecord{Time: time.Now().UTC(), Actor: a.actor, Action: action, Target: target, Changes: cs, PrevHash: a.last}
+	r.Hash = r.digest()
+	if err := a.sink.Record(r); err != nil {
+		c.log().Error("audit record not stored", "action", action, "target", target, "err", err)
+		return err
+	}
+	a.last = r.Hash
+	return nil
+}
+
+// recordIf records action on target if err is nil, and returns err.
+func (c *Config) recordIf(err error, action, target string) error {
+	if err == nil {
+		c.audit(action, target, nil)
+	}
+	return err
+}
+
+// applyForSave is Apply(nil) that also returns the ChangeSet when auditing.
+func (c *Config) applyForSave() (*ChangeSet, error) {
+	if c.auditor == nil {
+		return nil, c.Apply(nil)
+	}
+	return c.ApplyWithChanges(nil)
+}
+
+// JSONLinesSink writes audit records to w as JSON, one per line. It
+// implements AuditChain, so Configs sharing it, and runs appending to the
+// same file, form one chain.
+type JSONLinesSink struct {
+	mu   sync.Mutex
+	w    io.Writer
+	last string
+	err  error
+}
+
+// NewJSONLinesSink returns a sink writing to w, typically a file opened
+// with O_APPEND. When w is a regular file, the chain continues from the
+// last record already in it.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	s := &JSONLinesSink{w: w}
+	if f, ok := w.(*os.File); ok {
+		s.last, s.err = lastAuditHash(f.Name())
+	}
+	return s
+}
+
+func (s *JSONLinesSink) Record(r AuditRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	s.last = r.Hash
+	return nil
+}
+
+// LastHash returns the Hash of the last record written, or read from the
+// file when none was written yet. It fails if that file could not be read.
+func (s *JSONLinesSink) LastHash() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last, s.err
+}
+
+// lastAuditHash returns the Hash of the last record of the JSON-lines log
+// name, read backwards from the end. Files that are not regular, such as
+// os.Stdout, have no records to continue.
+func lastAuditHash(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || !fi.Mode().IsRegular() {
+		return "", err
+	}
+	var tail []byte
+	for end := fi.Size(); end > 0; {
+		n := min(end, 64<<10)
+		buf := make([]byte, n)
+		if _, err := f.ReadAt(buf, end-n); err != nil {
+			return "", err
+		}
+		tail, end = append(buf, tail...), end-n
+		line := bytes.TrimRight(tail, "\n")
+		i := bytes.LastIndexByte(line, '\n')
+		if i < 0 && end > 0 {
+			continue
+		}
+		if len(line) == 0 {
+			return "", nil
+		}
+		var r AuditRecord
+		if err := json.Unmarshal(line[i+1:], &r); err != nil {
+			return "", fmt.Errorf("namedzone: %s: last audit record: %w", name, err)
+		}
+		return r.Hash, nil
+	}
+	return "", nil
+}
+
+// VerifyAuditLog checks the hash chain of a JSON-lines audit log and reports
+// the first record (1-based line) that was altered or is out of sequence.
+func VerifyAuditLog(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 16<<20)
+	prev := ""
+	for line := 1; sc.Scan(); line++ {
+		var rec AuditRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return fmt.Errorf("namedzone: audit log line %d: %w", line, err)
+		}
+		if line > 1 && rec.PrevHash != prev {
+			return fmt.Errorf("namedzone: audit log line %d: chain broken", line)
+		}
+		if rec.digest() != rec.Hash {
+			return fmt.Errorf("namedzone: audit log line %d: record altered", line)
+		}
+		prev = rec.Hash
+	}
+	return sc.Err()
+}
@@ -0,0 +1,74 @@
+// File: pkg/namedzone/audit_test.go
+package namedzone
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAuditChainAcrossRuns appends to one log from two Configs, as two runs
+// of a program would, and checks the chain holds.
+func TestAuditChainAcrossRuns(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "audit.log")
+	for run := range 2 {
+		f, err := os.OpenFile(log, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := &Config{}
+		c.SetAudit(NewJSONLinesSink(f), "run"+string(rune('0'+run)))
+		c.UpsertZone(Zone{Name: "example.com", Type: ZonePrimary, File: "x"})
+		c.SetRecursion(false)
+		f.Close()
+	}
+	f, err := os.Open(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := VerifyAuditLog(f); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type failingSink struct{}
+
+func (failingSink) Record(AuditRecord) error { return errors.New("sink down") }
+
+// TestSaveUnrecorded checks that a save whose record cannot be stored
+// writes nothing.
+func TestSaveUnrecorded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "named.conf")
+	const src = "options { recursion yes; };\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadTree(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetRecursion(false)
+	c.SetAudit(failingSink{}, "")
+	for name, save := range map[string]func() error{
+		"SaveWith": func() error { return c.SaveWith(path, SaveOptions{}) },
+		"Save":     func() error { return c.Save(path) },
+	} {
+		if err := save(); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+		b, _ := os.ReadFile(path)
+		if string(b) != src {
+			t.Errorf("%s: file changed to %q", name, b)
+		}
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temporary file left: %s", e.Name())
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// File: pkg/namedzone/authexport/authexport.go
+
+// Package authexport translates the authoritative side of a
+// namedzone.Config into configurations for NSD (ExportNSD) and Knot DNS
+// (ExportKnot): primary and secondary zones with their zone files,
+// primaries, allow-transfer and also-notify, the TSIG keys they use, and
+// the listen addresses. Anything without an equivalent is left out and
+// reported as a note, so the output can be reviewed before it is deployed.
+package authexport
+
+import (
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+// Options control ExportNSD and ExportKnot.
+type Options struct {
+	// View selects the view whose zones are exported along with the
+	// top-level ones; empty exports top-level zones only.
+	View string
+}
+
+// server is a remote server: a primary or a notify target.
+type server struct {
+	addr netip.Addr
+	port int
+	key  string
+}
+
+func (s server) String() string {
+	return netip.AddrPortFrom(s.addr, uint16(s.port)).String()
+}
+
+// zone is the authoritative part of one zone statement.
+type zone struct {
+	name      string
+	primary   bool
+	file      string
+	primaries []server
+	notify    []server
+	// transfer is who may transfer the zone: unsigned from prefixes, or
+	// signed with one of keys from anywhere
+	transfer []netip.Prefix
+	keys     []string
+}
+
+// model is what both exporters write.
+type model struct {
+	listen []netip.AddrPort
+	keys   []namedzone.Key
+	zones  []zone
+	notes  []string
+}
+
+func (m *model) note(format string, args ...any) {
+	m.notes = append(m.notes, fmt.Sprintf(format, args...))
+}
+
+func build(cfg *namedzone.Config, opts Options) (*model, error) {
+	if opts.View != "" && cfg.FindView(opts.View) == nil {
+		return nil, &namedzone.ReferenceError{Kind: "view", Name: opts.View, Path: "view[" + opts.View + "]"}
+	}
+	m := &model{}
+	m.listeners(cfg)
+	used := map[string]bool{}
+	for v, z := range cfg.AllZones() {
+		view, p := "", "zone["+z.Name+"]"
+		if v != nil {
+			if v.Name != opts.View {
+				continue
+			}
+			view, p = v.Name, "view["+v.Name+"]."+p
+		}
+		switch z.Type {
+		case namedzone.ZonePrimary, namedzone.ZoneSecondary:
+		case namedzone.ZoneHint, namedzone.ZoneForward, namedzone.ZoneStub, namedzone.ZoneStaticStub, namedzone.ZoneMirror:
+			m.note("%s: %s zone is resolver configuration; not exported", p, z.Type)
+			continue
+		default:
+			m.note("%s: %s zone not exported", p, z.Type)
+			continue
+		}
+		zn, err := m.zone(cfg, view, p, *z)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range slices.Concat(zn.primaries, zn.notify) {
+			used[s.key] = true
+		}
+		for _, k := range zn.keys {
+			used[k] = true
+		}
+		m.zones = append(m.zones, zn)
+	}
+	for _, k := range cfg.Keys {
+		if used[k.Name] {
+			m.keys = append(m.keys, k)
+		}
+	}
+	return m, nil
+}
+
+// listeners collects the plain DNS listen-on addresses.
+func (m *model) listeners(cfg *namedzone.Config) {
+	for _, l := range cfg.Summary().Listeners {
+		if l.Transport != "dns" {
+			m.note("listen-on %s: %s listener not exported", l.Family, l.Transport)
+			continue
+		}
+		for _, a := range l.Addrs {
+			var addr netip.Addr
+			switch {
+			case a == "any" && l.Family == "ipv6":
+				addr = netip.IPv6Unspecified()
+			case a == "any":
+				addr = netip.IPv4Unspecified()
+			default:
+				var err error
+				if addr, err = netip.ParseAddr(a); err != nil {
+					m.note("listen-on %s: %q is not an address; not exported", l.Family, a)
+					continue
+				}
+			}
+			m.listen = append(m.listen, netip.AddrPortFrom(addr, uint16(l.Port)))
+		}
+	}
+}
+
+func (m *model) zone(cfg *namedzone.Config, view, path string, z namedzone.Zone) (zone, error) {
+	zn := zone{name: strings.TrimSuffix(z.Name, "."), primary: z.Type == namedzone.ZonePrimary, file: z.File}
+	if zn.name == "" {
+		zn.name = "."
+	}
+	if zn.file != "" && !filepath.IsAbs(zn.file) && cfg.Options != nil && cfg.Options.Directory != "" {
+		zn.file = filepath.Join(cfg.Options.Directory, zn.file)
+	}
+	e, err := cfg.EffectiveZoneSettings(view, z.Name)
+	if err != nil {
+		return zn, err
+	}
+	if !zn.primary {
+		zn.primaries = m.servers(path+".primaries", cfg.ZonePrimaries(z))
+	}
+	zn.notify = m.servers(path+".alsoNotify", cfg.ZoneAlsoNotify(z))
+	if e.Notify.Value == "no" {
+		zn.notify = nil
+	} else if e.Notify.Value != "explicit" && zn.primary {
+		m.note("%s: named also notifies the zone's NS servers; only also-notify is exported", path)
+	}
+
+	terms := e.AllowTransfer.Value
+	if len(terms) == 0 {
+		// named's default: anyone may transfer
+		terms = []namedzone.MatchTerm{{ACLRef: "any"}}
+		m.note("%s: no allow-transfer; exported as open to any address", path)
+	}
+	if zn.transfer, err = cfg.ExpandMatchList(terms); err != nil {
+		return zn, err
+	}
+	zn.keys = termKeys(cfg, terms, map[string]bool{})
+	return zn, nil
+}
+
+// servers converts a resolved server list; port defaults to 53.
+func (m *model) servers(path string, items []namedzone.RemoteServerItem) []server {
+	var out []server
+	for _, it := range items {
+		a, err := netip.ParseAddr(it.Address)
+		if err != nil {
+			m.note("%s: %q is not an address; not exported", path, it.Address)
+			continue
+		}
+		s := server{addr: a, port: 53, key: it.Key}
+		if it.Port != nil {
+			s.port = *it.Port
+		}
+		if it.TLS != "" && it.TLS != "none" {
+			m.note("%s: %s uses tls %q; exported as a plain TCP transfer", path, it.Address, it.TLS)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// termKeys returns the keys a match list admits, through nested lists and
+// the ACLs it references; negated keys are skipped.
+func termKeys(cfg *namedzone.Config, terms []namedzone.MatchTerm, seen map[string]bool) []string {
+	var out []string
+	for _, t := range terms {
+		switch {
+		case t.Not:
+		case t.Key != "":
+			out = append(out, t.Key)
+		case len(t.Nested) > 0:
+			out = append(out, termKeys(cfg, t.Nested, seen)...)
+		case t.ACLRef != "" && !seen[t.ACLRef]:
+			seen[t.ACLRef] = true
+			if i := slices.IndexFunc(cfg.ACLs, func(a namedzone.ACL) bool { return a.Name == t.ACLRef }); i >= 0 {
+				out = append(out, termKeys(cfg, cfg.ACLs[i].Elements, seen)...)
+			}
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+func quote(s string) string { return strconv.Quote(s) }
@@ -0,0 +1,120 @@
+// File: pkg/namedzone/authexport/knot.go
+package authexport
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+// ExportKnot renders the primary and secondary zones of cfg as a knot.conf.
+// Every primary and notify target becomes a remote, shared by the zones
+// that use the same address, port and key. allow-transfer becomes a
+// transfer acl per zone, its addresses expanded with
+// Config.ExpandMatchList and each of its keys in an acl of its own; a
+// secondary gets a notify acl for its primaries. Notes list what could not
+// be translated.
+func ExportKnot(cfg *namedzone.Config, opts Options) ([]byte, []string, error) {
+	m, err := build(cfg, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var remotes []server
+	remote := func(s server) string {
+		i := slices.Index(remotes, s)
+		if i < 0 {
+			i = len(remotes)
+			remotes = append(remotes, s)
+		}
+		return "remote" + strconv.Itoa(i+1)
+	}
+
+	var acls, zones strings.Builder
+	for _, z := range m.zones {
+		var primaries, notify, acl []string
+		for _, s := range z.primaries {
+			primaries = append(primaries, remote(s))
+		}
+		for _, s := range z.notify {
+			notify = append(notify, remote(s))
+		}
+		if len(z.transfer) > 0 {
+			id := z.name + " transfer"
+			var addrs []string
+			for _, p := range z.transfer {
+				addrs = append(addrs, p.String())
+			}
+			fmt.Fprintf(&acls, "  - id: %s\n    address: %s\n    action: transfer\n", quote(id), list(addrs))
+			acl = append(acl, id)
+		}
+		for _, k := range z.keys {
+			id := z.name + " transfer " + k
+			fmt.Fprintf(&acls, "  - id: %s\n    key: %s\n    action: transfer\n", quote(id), quote(k))
+			acl = append(acl, id)
+		}
+		if len(primaries) > 0 {
+			id := z.name + " notify"
+			fmt.Fprintf(&acls, "  - id: %s\n    remote: %s\n    action: notify\n", quote(id), list(primaries))
+			acl = append(acl, id)
+		}
+
+		fmt.Fprintf(&zones, "  - domain: %s\n", quote(z.name))
+		if z.file != "" {
+			fmt.Fprintf(&zones, "    file: %s\n", quote(z.file))
+		}
+		if len(primaries) > 0 {
+			fmt.Fprintf(&zones, "    master: %s\n", list(primaries))
+		}
+		if len(notify) > 0 {
+			fmt.Fprintf(&zones, "    notify: %s\n", list(notify))
+		}
+		if len(acl) > 0 {
+			fmt.Fprintf(&zones, "    acl: %s\n", list(acl))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated from named.conf by namedzone.\n\n")
+	b.WriteString("server:\n")
+	var listen []string
+	for _, a := range m.listen {
+		listen = append(listen, fmt.Sprintf("%s@%d", a.Addr(), a.Port()))
+	}
+	if len(listen) > 0 {
+		fmt.Fprintf(&b, "    listen: %s\n", list(listen))
+	}
+	if len(m.keys) > 0 {
+		b.WriteString("\nkey:\n")
+		for _, k := range m.keys {
+			fmt.Fprintf(&b, "  - id: %s\n    algorithm: %s\n    secret: %s\n", quote(k.Name), k.Algorithm, quote(k.Secret))
+		}
+	}
+	if len(remotes) > 0 {
+		b.WriteString("\nremote:\n")
+		for i, s := range remotes {
+			fmt.Fprintf(&b, "  - id: remote%d\n    address: %s@%d\n", i+1, s.addr, s.port)
+			if s.key != "" {
+				fmt.Fprintf(&b, "    key: %s\n", quote(s.key))
+			}
+		}
+	}
+	if acls.Len() > 0 {
+		b.WriteString("\nacl:\n" + acls.String())
+	}
+	if zones.Len() > 0 {
+		b.WriteString("\nzone:\n" + zones.String())
+	}
+	return []byte(b.String()), m.notes, nil
+}
+
+// list writes a YAML flow sequence of quoted strings.
+func list(items []string) string {
+	q := make([]string, len(items))
+	for i, s := range items {
+		q[i] = quote(s)
+	}
+	return "[ " + strings.Join(q, ", ") + " ]"
+}
@@ -0,0 +1,65 @@
+// File: pkg/namedzone/authexport/nsd.go
+package authexport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+// ExportNSD renders the primary and secondary zones of cfg as an nsd.conf.
+// Primaries become request-xfr and allow-notify, also-notify becomes
+// notify, and allow-transfer becomes provide-xfr: its addresses expanded
+// with Config.ExpandMatchList and its keys allowed from any address. Notes
+// list what could not be translated.
+func ExportNSD(cfg *namedzone.Config, opts Options) ([]byte, []string, error) {
+	m, err := build(cfg, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var b strings.Builder
+	b.WriteString("# Generated from named.conf by namedzone.\n\n")
+	b.WriteString("server:\n")
+	for _, a := range m.listen {
+		fmt.Fprintf(&b, "\tip-address: %s@%d\n", a.Addr(), a.Port())
+	}
+	for _, k := range m.keys {
+		fmt.Fprintf(&b, "\nkey:\n\tname: %s\n\talgorithm: %s\n\tsecret: %s\n", quote(k.Name), k.Algorithm, quote(k.Secret))
+	}
+	for _, z := range m.zones {
+		fmt.Fprintf(&b, "\nzone:\n\tname: %s\n", quote(z.name))
+		if z.file != "" {
+			fmt.Fprintf(&b, "\tzonefile: %s\n", quote(z.file))
+		}
+		for _, s := range z.primaries {
+			fmt.Fprintf(&b, "\tallow-notify: %s %s\n", s.addr, nsdKey(s.key))
+			fmt.Fprintf(&b, "\trequest-xfr: %s %s\n", nsdAddr(s), nsdKey(s.key))
+		}
+		for _, s := range z.notify {
+			fmt.Fprintf(&b, "\tnotify: %s %s\n", nsdAddr(s), nsdKey(s.key))
+		}
+		for _, p := range z.transfer {
+			fmt.Fprintf(&b, "\tprovide-xfr: %s NOKEY\n", p)
+		}
+		for _, k := range z.keys {
+			fmt.Fprintf(&b, "\tprovide-xfr: 0.0.0.0/0 %s\n\tprovide-xfr: ::0/0 %s\n", k, k)
+		}
+	}
+	return []byte(b.String()), m.notes, nil
+}
+
+// nsdAddr writes s in NSD's addr[@port] form.
+func nsdAddr(s server) string {
+	if s.port == 53 {
+		return s.addr.String()
+	}
+	return fmt.Sprintf("%s@%d", s.addr, s.port)
+}
+
+func nsdKey(key string) string {
+	if key == "" {
+		return "NOKEY"
+	}
+	return key
+}
@@ -0,0 +1,132 @@
+// File: pkg/namedzone/canonical.go
+package namedzone
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// modeledKeywords are the top-level statements sync writes.
+var modeledKeywords = []string{
+	"include", "acl", "key", "key-store", "remote-servers", "primaries", "masters",
+	"tls", "http", "controls", "logging", "options", "trust-anchors", "view", "zone",
+}
+
+// RenderCanonical returns the config as named.conf text that depends only
+// on its content, not on how the source was written: comments are dropped,
+// keywords are the modern spellings, zones and named definitions (ACLs,
+// keys, TLS, ...) are sorted by name, and every statement is laid out one
+// per line with tab indentation. Views, includes and trust anchors keep
+// their order, which is significant to named. Statements the typed model
+// does not cover are kept, after options, in source order. For a LoadTree
+// config the whole tree is rendered as one file, without includes.
+//
+// The Config and its AST are not modified.
+func (c *Config) RenderCanonical() (string, error) {
+	if err := c.checkWritable(); err != nil {
+		return "", err
+	}
+	k := &Config{
+		ACLs:          sortedByName(c.ACLs, func(a ACL) string { return a.Name }),
+		Keys:          sortedByName(c.Keys, func(k Key) string { return k.Name }),
+		KeyStores:     sortedByName(c.KeyStores, func(k KeyStore) string { return k.Name }),
+		RemoteServers: sortedByName(c.RemoteServers, func(r RemoteServers) string { return r.Name }),
+		TLS:           sortedByName(c.TLS, func(t TLS) string { return t.Name }),
+		HTTP:          sortedByName(c.HTTP, func(h HTTP) string { return h.Name }),
+		Controls:      copyOf(c.Controls),
+		Logging:       copyOf(c.Logging),
+		Options:       copyOf(c.Options),
+		TrustAnchors:  slices.Clone(c.TrustAnchors),
+		Zones:         slices.Clone(c.Zones),
+	}
+	for _, v := range c.Views {
+		v.Zones = slices.Clone(v.Zones)
+		if c.files != nil {
+			v.Includes = nil
+		}
+		k.Views = append(k.Views, v)
+	}
+	if c.idn {
+		if err := k.punycodeZones(); err != nil {
+			return "", err
+		}
+	}
+	k.Zones = sortedZones(k.Zones)
+	for i := range k.Views {
+		k.Views[i].Zones = sortedZones(k.Views[i].Zones)
+	}
+	if c.files == nil {
+		k.Includes = slices.Clone(c.Includes)
+	}
+	for kw, items := range c.custom {
+		if k.custom == nil {
+			k.custom = map[string][]customItem{}
+		}
+		k.custom[kw] = slices.Clone(items)
+	}
+	k.modernizeKeywords()
+
+	f := &nc.File{}
+	k.sync(k.syncer(f))
+	if others := c.unmodeled(); len(others) > 0 {
+		at := slices.IndexFunc(f.Nodes, func(n nc.Node) bool {
+			st, ok := n.(*nc.Stmt)
+			return ok && (st.Keyword == "trust-anchors" || st.Keyword == "view" || st.Keyword == "zone")
+		})
+		if at < 0 {
+			at = len(f.Nodes)
+		}
+		f.Nodes = slices.Insert(f.Nodes, at, others...)
+	}
+	return layout(string(f.Bytes()), Format{Tabs: true}, true), nil
+}
+
+// unmodeled returns the top-level statements of every source file that sync
+// does not write, root file first. Statements with a registered handler are
+// written by sync.
+func (c *Config) unmodeled() []nc.Node {
+	files := []*nc.File{c.ast}
+	if c.files != nil {
+		files = files[:0]
+		for _, name := range c.Files() {
+			files = append(files, c.files[name])
+		}
+	}
+	var out []nc.Node
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		for _, n := range f.Nodes {
+			st, ok := n.(*nc.Stmt)
+			if !ok || slices.Contains(modeledKeywords, st.Keyword) {
+				continue
+			}
+			if _, typed := c.custom[st.Keyword]; !typed {
+				out = append(out, st, &nc.Raw{Text: "\n"})
+			}
+		}
+	}
+	return out
+}
+
+func sortedByName[T any](items []T, name func(T) string) []T {
+	return slices.SortedStableFunc(slices.Values(items), func(a, b T) int { return cmp.Compare(name(a), name(b)) })
+}
+
+func sortedZones(zs []Zone) []Zone {
+	return slices.SortedStableFunc(slices.Values(zs), func(a, b Zone) int {
+		return cmp.Or(cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)), cmp.Compare(a.Class, b.Class))
+	})
+}
+
+func copyOf[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
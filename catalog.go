@@ -0,0 +1,148 @@
+// File: pkg/namedzone/catalog.go
+package namedzone
+
+import (
+	"cmp"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dlukt/namedzone/zonefile"
+)
+
+// CatalogOptions describe a catalog zone (RFC 9432) produced from a Config.
+type CatalogOptions struct {
+	// Name of the catalog zone, e.g. "catalog.example".
+	Name string
+	// View holds the member zones; empty for top-level zones.
+	View string
+	// Members selects the member zones; nil takes every primary zone.
+	Members func(z Zone) bool
+	// Groups sets the group property of member zones, by zone name.
+	Groups map[string]string
+	// Coo sets the change-of-ownership property of member zones, by zone
+	// name, to the catalog zone taking them over.
+	Coo map[string]string
+	// Zone holds the settings of the catalog zone statement (allow-transfer,
+	// also-notify, ...); an empty File becomes "db.<name>".
+	Zone Zone
+}
+
+// CatalogMemberID returns the unique label of a member zone in a catalog
+// zone: the hex SHA-1 of the zone name in lower-case wire format, as
+// produced by BIND and the RFC 9432 examples.
+func CatalogMemberID(zone string) string {
+	var wire []byte
+	for l := range strings.SplitSeq(strings.ToLower(strings.TrimSuffix(zone, ".")), ".") {
+		if l != "" {
+			wire = append(append(wire, byte(len(l))), l...)
+		}
+	}
+	sum := sha1.Sum(append(wire, 0))
+	return hex.EncodeToString(sum[:])
+}
+
+// BuildCatalogZone returns the statement and the zone data of a catalog
+// zone listing the member zones of c: the SOA and NS records RFC 9432
+// prescribes, the version property and one PTR record per member with its
+// group and coo properties. Nothing is added to c.
+func (c *Config) BuildCatalogZone(opts CatalogOptions) (Zone, *zonefile.Zone, error) {
+	if opts.Name == "" {
+		return Zone{}, nil, &ValueError{Path: "name", Msg: "catalog zone needs a name"}
+	}
+	name := strings.TrimSuffix(opts.Name, ".")
+	z := opts.Zone
+	z.Name, z.Type = name, ZonePrimary
+	z.File = cmp.Or(z.File, "db."+name)
+
+	y, m, d := time.Now().Date()
+	serial := uint32(y*1000000 + int(m)*10000 + d*100)
+	data := zonefile.New(name, 0)
+	for _, r := range []zonefile.Record{
+		{Name: "@", Type: "SOA", Data: fmt.Sprintf("invalid. invalid. %d 3600 600 2147483646 0", serial)},
+		{Name: "@", Type: "NS", Data: "invalid."},
+		{Name: "version", Type: "TXT", Data: `"2"`},
+	} {
+		if err := data.AddRecord(r); err != nil {
+			return Zone{}, nil, err
+		}
+	}
+	if _, _, err := c.UpdateCatalogZone(data, opts); err != nil {
+		return Zone{}, nil, err
+	}
+	data.SetSerial(serial)
+	return z, data, nil
+}
+
+// UpdateCatalogZone brings the member records of the catalog zone data cat
+// in line with the member zones of c: members of zones added since are
+// added, those of removed zones go, and group and coo properties follow
+// opts. It returns the records added and removed; the SOA serial is bumped
+// by the edits.
+func (c *Config) UpdateCatalogZone(cat *zonefile.Zone, opts CatalogOptions) (added, removed []zonefile.Record, err error) {
+	want := c.catalogRecords(cat.Origin, opts)
+	suffix := ".zones." + strings.ToLower(cat.Origin)
+	var have []zonefile.Record
+	for _, r := range cat.Records() {
+		if strings.HasSuffix(strings.ToLower(r.Name), suffix) {
+			have = append(have, r)
+		}
+	}
+	for _, r := range have {
+		if slices.ContainsFunc(want, func(w zonefile.Record) bool { return sameRecord(r, w) }) {
+			continue
+		}
+		if err := cat.DeleteRecord(r); err != nil {
+			return added, removed, err
+		}
+		removed = append(removed, r)
+	}
+	for _, w := range want {
+		if slices.ContainsFunc(have, func(r zonefile.Record) bool { return sameRecord(r, w) }) {
+			continue
+		}
+		if err := cat.AddRecord(w); err != nil {
+			return added, removed, err
+		}
+		added = append(added, w)
+	}
+	return added, removed, nil
+}
+
+// catalogRecords returns the member and property records of the catalog
+// zone origin for the member zones of c.
+func (c *Config) catalogRecords(origin string, opts CatalogOptions) []zonefile.Record {
+	var zones []Zone
+	if opts.View == "" {
+		zones = c.Zones
+	} else if v := c.FindView(opts.View); v != nil {
+		zones = v.Zones
+	}
+	self := strings.TrimSuffix(strings.ToLower(origin), ".")
+	var out []zonefile.Record
+	for _, z := range zones {
+		member := z.Type == ZonePrimary
+		if opts.Members != nil {
+			member = opts.Members(z)
+		}
+		if !member || strings.ToLower(z.Name) == self {
+			continue
+		}
+		id := CatalogMemberID(z.Name) + ".zones." + origin
+		out = append(out, zonefile.Record{Name: id, Type: "PTR", Data: fqdn(z.Name)})
+		if g := opts.Groups[z.Name]; g != "" {
+			out = append(out, zonefile.Record{Name: "group." + id, Type: "TXT", Data: `"` + g + `"`})
+		}
+		if to := opts.Coo[z.Name]; to != "" {
+			out = append(out, zonefile.Record{Name: "coo." + id, Type: "PTR", Data: fqdn(to)})
+		}
+	}
+	return out
+}
+
+func sameRecord(a, b zonefile.Record) bool {
+	return strings.EqualFold(a.Name, b.Name) && strings.EqualFold(a.Type, b.Type) && strings.EqualFold(strings.TrimSpace(a.Data), strings.TrimSpace(b.Data))
+}
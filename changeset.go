@@ -0,0 +1,125 @@
+// File: pkg/namedzone/changeset.go
+package namedzone
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// StmtChange is one top-level statement that Apply added, removed or
+// rewrote. Before and After hold the statement text as written to the file.
+type StmtChange struct {
+	Change  ChangeKind `json:"change"` // Added, Removed or Modified (rewritten)
+	File    string     `json:"file,omitempty"`
+	Keyword string     `json:"keyword"`
+	Name    string     `json:"name,omitempty"`
+	Before  string     `json:"before,omitempty"`
+	After   string     `json:"after,omitempty"`
+}
+
+// ChangeSet lists what an Apply changed in the AST.
+type ChangeSet struct {
+	Changes []StmtChange `json:"changes,omitempty"`
+}
+
+// Empty reports whether the AST was left as it was.
+func (cs *ChangeSet) Empty() bool { return len(cs.Changes) == 0 }
+
+// ApplyWithChanges is Apply that also reports which top-level statements it
+// added, removed or rewrote, with their text before and after. A statement
+// counts as rewritten when its text changed, including formatting.
+func (c *Config) ApplyWithChanges(f *nc.File) (*ChangeSet, error) {
+	files := map[string]*nc.File{"": f}
+	if f == nil || f == c.ast {
+		if c.files != nil {
+			files = c.files
+		} else {
+			files = map[string]*nc.File{"": c.ast}
+		}
+	}
+	before := map[string][]stmtSnap{}
+	for name, file := range files {
+		before[name] = snapshot(file)
+	}
+	if err := c.Apply(f); err != nil {
+		return nil, err
+	}
+	if f == nil && c.files == nil {
+		files = map[string]*nc.File{"": c.ast} // Apply may have created it
+	}
+	cs := &ChangeSet{}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		cs.Changes = append(cs.Changes, compareSnaps(name, before[name], snapshot(files[name]))...)
+	}
+	return cs, nil
+}
+
+type stmtSnap struct {
+	keyword, name, text string
+}
+
+func snapshot(f *nc.File) []stmtSnap {
+	if f == nil {
+		return nil
+	}
+	var out []stmtSnap
+	for _, n := range f.Nodes {
+		st, ok := n.(*nc.Stmt)
+		if !ok {
+			continue
+		}
+		s := stmtSnap{keyword: st.Keyword, text: stmtText(st)}
+		switch st.Keyword {
+		case "options", "logging", "controls", "trust-anchors":
+		default:
+			s.name = headNameAfter(st, st.Keyword)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// stmtText renders one statement without the comments attached before it.
+func stmtText(st *nc.Stmt) string {
+	t := strings.TrimSpace(string((&nc.File{Nodes: []nc.Node{st}}).Bytes()))
+	if h := headText(st); h != "" {
+		if i := strings.Index(t, h); i > 0 {
+			t = t[i:]
+		}
+	}
+	return t
+}
+
+// compareSnaps pairs statements by keyword and name, in order of appearance.
+func compareSnaps(file string, before, after []stmtSnap) []StmtChange {
+	type key struct{ keyword, name string }
+	pending := map[key][]stmtSnap{}
+	for _, s := range before {
+		k := key{s.keyword, s.name}
+		pending[k] = append(pending[k], s)
+	}
+	var out []StmtChange
+	for _, s := range after {
+		k := key{s.keyword, s.name}
+		q := pending[k]
+		if len(q) == 0 {
+			out = append(out, StmtChange{Change: Added, File: file, Keyword: s.keyword, Name: s.name, After: s.text})
+			continue
+		}
+		pending[k] = q[1:]
+		if q[0].text != s.text {
+			out = append(out, StmtChange{Change: Modified, File: file, Keyword: s.keyword, Name: s.name, Before: q[0].text, After: s.text})
+		}
+	}
+	for _, s := range before {
+		k := key{s.keyword, s.name}
+		if q := pending[k]; len(q) > 0 && q[0] == s {
+			pending[k] = q[1:]
+			out = append(out, StmtChange{Change: Removed, File: file, Keyword: s.keyword, Name: s.name, Before: s.text})
+		}
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+// File: pkg/namedzone/clone.go
+package namedzone
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// Clone returns a deep copy of the typed config: no slice, map or pointer
+// (such as the *bool settings) is shared with c, so edits on either side
+// never show on the other. The copy has no AST, like a Config built in code;
+// use CloneWithAST for a preview that renders like the original. Locks and
+// audit sinks stay with c; values of custom statements (see
+// RegisterStatement) are copied as they are.
+func (c *Config) Clone() *Config {
+	n := c.cloneTyped()
+	n.ast, n.files, n.root, n.target, n.fsys, n.clean, n.savedAs = nil, nil, "", "", nil, nil, ""
+	n.eachStmt(func(st **nc.Stmt) { *st = nil })
+	return n
+}
+
+// CloneWithAST is Clone plus a detached deep copy of the AST (every file of
+// a LoadTree config), so the copy can be applied, rendered and saved without
+// touching c.
+func (c *Config) CloneWithAST() *Config {
+	n := c.cloneTyped()
+	moved := map[*nc.Stmt]*nc.Stmt{}
+	if c.ast != nil {
+		n.ast = copyFile(c.ast, moved)
+	}
+	if c.files != nil {
+		n.files = make(map[string]*nc.File, len(c.files))
+		for name, f := range c.files {
+			if f == c.ast {
+				n.files[name] = n.ast
+				continue
+			}
+			n.files[name] = copyFile(f, moved)
+		}
+	}
+	n.eachStmt(func(st **nc.Stmt) {
+		if to, ok := moved[*st]; ok {
+			*st = to
+		}
+	})
+	n.clean = make(map[*nc.Stmt]string, len(c.clean))
+	for st, text := range c.clean {
+		if to, ok := moved[st]; ok {
+			n.clean[to] = text
+		}
+	}
+	return n
+}
+
+// cloneTyped deep-copies the typed fields and the settings of c.
+func (c *Config) cloneTyped() *Config {
+	n := deepCopy(reflect.ValueOf(c).Elem()).Addr().Interface().(*Config)
+	n.lock, n.auditor, n.index = nil, nil, nil
+	n.format, n.history = copyOf(c.format), copyOf(c.history)
+	n.validators = slices.Clone(c.validators)
+	n.custom = maps.Clone(c.custom)
+	for kw, items := range n.custom {
+		n.custom[kw] = slices.Clone(items)
+	}
+	return n
+}
+
+// deepCopy copies v, following pointers, slices and maps through exported
+// struct fields. Unexported fields (AST references, origins, legacy
+// spellings) are copied as they are.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.New(v.Type().Elem())
+		n.Elem().Set(deepCopy(v.Elem()))
+		return n
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			n.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return n
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			n.SetMapIndex(it.Key(), deepCopy(it.Value()))
+		}
+		return n
+	case reflect.Struct:
+		n := reflect.New(v.Type()).Elem()
+		n.Set(v)
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				n.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return n
+	}
+	return v
+}
+
+// copyFile deep-copies f, recording where each statement went.
+func copyFile(f *nc.File, moved map[*nc.Stmt]*nc.Stmt) *nc.File {
+	n := *f
+	n.Nodes = copyNodes(f.Nodes, moved)
+	return &n
+}
+
+func copyNodes(nodes []nc.Node, moved map[*nc.Stmt]*nc.Stmt) []nc.Node {
+	if nodes == nil {
+		return nil
+	}
+	out := make([]nc.Node, len(nodes))
+	for i, n := range nodes {
+		switch x := n.(type) {
+		case *nc.Stmt:
+			st := *x
+			st.Body = copyNodes(x.Body, moved)
+			moved[x] = &st
+			out[i] = &st
+		case *nc.Raw:
+			r := *x
+			out[i] = &r
+		default:
+			out[i] = n
+		}
+	}
+	return out
+}
+
+// eachStmt calls fn with the statement reference of every item, view
+// members included.
+func (c *Config) eachStmt(fn func(**nc.Stmt)) {
+	refs(c.Includes, fn)
+	refs(c.ACLs, fn)
+	refs(c.Keys, fn)
+	refs(c.KeyStores, fn)
+	refs(c.RemoteServers, fn)
+	refs(c.TLS, fn)
+	refs(c.HTTP, fn)
+	refs(c.TrustAnchors, fn)
+	refs(c.Views, fn)
+	refs(c.Zones, fn)
+	if c.Controls != nil {
+		fn(c.Controls.ref())
+	}
+	if c.Logging != nil {
+		fn(c.Logging.ref())
+	}
+	if c.Options != nil {
+		fn(c.Options.ref())
+	}
+	for i := range c.Views {
+		v := &c.Views[i]
+		refs(v.Zones, fn)
+		refs(v.Includes, fn)
+		if v.TrustAnchors != nil {
+			fn(v.TrustAnchors.ref())
+		}
+	}
+	for _, items := range c.custom {
+		refs(items, fn)
+	}
+}
+
+func refs[T any, P stmtRef[T]](items []T, fn func(**nc.Stmt)) {
+	for i := range items {
+		fn(P(&items[i]).ref())
+	}
+}
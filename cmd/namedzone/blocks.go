@@ -0,0 +1,209 @@
+// File: pkg/namedzone/cmd/namedzone/blocks.go
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+func init() {
+	commands = append(commands,
+		command{group: "key", name: "list", usage: "[-reveal]", run: keyList},
+		command{group: "key", name: "get", usage: "NAME [-reveal]", run: keyGet},
+		command{group: "key", name: "add", usage: "NAME [-algorithm A] [-secret S] [-replace]", write: true, run: keyAdd},
+		command{group: "key", name: "remove", usage: "NAME [-cascade]", write: true, run: keyRemove},
+		command{group: "acl", name: "list", run: aclList},
+		command{group: "acl", name: "get", usage: "NAME", run: aclGet},
+		command{group: "acl", name: "add", usage: "NAME ELEMENT... [-replace]", write: true, run: aclAdd},
+		command{group: "acl", name: "remove", usage: "NAME [-cascade]", write: true, run: aclRemove},
+	)
+}
+
+// secretSizes are the key sizes generated per algorithm: the HMAC output
+// length, as tsig-keygen does.
+var secretSizes = map[string]int{
+	"hmac-md5": 16, "hmac-sha1": 20, "hmac-sha224": 28,
+	"hmac-sha256": 32, "hmac-sha384": 48, "hmac-sha512": 64,
+}
+
+func redact(k namedzone.Key, reveal bool) namedzone.Key {
+	if !reveal {
+		k.Secret = ""
+	}
+	return k
+}
+
+func keyList(e *env, args []string) error {
+	fs := flag.NewFlagSet("key list", flag.ContinueOnError)
+	reveal := fs.Bool("reveal", false, "include the secrets")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 0); err != nil {
+		return err
+	}
+	out := []namedzone.Key{}
+	for _, k := range e.cfg.Keys {
+		out = append(out, redact(k, *reveal))
+	}
+	return e.print(out)
+}
+
+func keyGet(e *env, args []string) error {
+	fs := flag.NewFlagSet("key get", flag.ContinueOnError)
+	reveal := fs.Bool("reveal", false, "include the secret")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(e.cfg.Keys, func(k namedzone.Key) bool { return k.Name == pos[0] })
+	if i < 0 {
+		return notFound("key", pos[0])
+	}
+	return e.print(redact(e.cfg.Keys[i], *reveal))
+}
+
+// keyAdd prints the key with its secret, so a generated one can be handed
+// to the other side.
+func keyAdd(e *env, args []string) error {
+	fs := flag.NewFlagSet("key add", flag.ContinueOnError)
+	alg := fs.String("algorithm", "hmac-sha256", "TSIG algorithm")
+	secret := fs.String("secret", "", "base64 secret; generated when empty")
+	replace := fs.Bool("replace", false, "replace an existing key")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	k := namedzone.Key{Name: pos[0], Algorithm: *alg, Secret: *secret}
+	if k.Secret == "" {
+		n, ok := secretSizes[k.Algorithm]
+		if !ok {
+			return usageError("no secret given and none can be generated for %q", k.Algorithm)
+		}
+		b := make([]byte, n)
+		rand.Read(b)
+		k.Secret = base64.StdEncoding.EncodeToString(b)
+	} else if _, err := base64.StdEncoding.DecodeString(k.Secret); err != nil {
+		return &namedzone.ValueError{Path: "keys[" + k.Name + "].secret", Msg: "not base64"}
+	}
+	i := slices.IndexFunc(e.cfg.Keys, func(o namedzone.Key) bool { return o.Name == k.Name })
+	switch {
+	case i < 0:
+		e.cfg.Keys = append(e.cfg.Keys, k)
+	case *replace:
+		e.cfg.Keys[i] = k
+	default:
+		return exists("key", k.Name)
+	}
+	return e.print(k)
+}
+
+func keyRemove(e *env, args []string) error {
+	return remove(e, "key", args, e.cfg.RemoveKey)
+}
+
+func aclList(e *env, args []string) error {
+	pos, err := parse(flag.NewFlagSet("acl list", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 0); err != nil {
+		return err
+	}
+	return e.print(nonNil(e.cfg.ACLs))
+}
+
+func aclGet(e *env, args []string) error {
+	pos, err := parse(flag.NewFlagSet("acl get", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(e.cfg.ACLs, func(a namedzone.ACL) bool { return a.Name == pos[0] })
+	if i < 0 {
+		return notFound("acl", pos[0])
+	}
+	return e.print(e.cfg.ACLs[i])
+}
+
+// aclAdd takes each element in named.conf syntax: an address or prefix,
+// "key NAME", an ACL name, "!" negations or a nested "{ ...; }" list.
+func aclAdd(e *env, args []string) error {
+	fs := flag.NewFlagSet("acl add", flag.ContinueOnError)
+	replace := fs.Bool("replace", false, "replace an existing acl")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) < 2 {
+		return usageError("want a name and at least one element")
+	}
+	name := pos[0]
+	c, err := parseBlock(fmt.Sprintf("acl %q { %s };", name, terms(strings.Join(pos[1:], "; "))))
+	if err != nil {
+		return err
+	}
+	a := c.ACLs[0]
+	i := slices.IndexFunc(e.cfg.ACLs, func(o namedzone.ACL) bool { return o.Name == name })
+	switch {
+	case i < 0:
+		e.cfg.ACLs = append(e.cfg.ACLs, a)
+	case *replace:
+		e.cfg.ACLs[i] = a
+	default:
+		return exists("acl", name)
+	}
+	return e.print(a)
+}
+
+func aclRemove(e *env, args []string) error {
+	return remove(e, "acl", args, e.cfg.RemoveACL)
+}
+
+// remove runs a Remove method; a block still in use is refused unless
+// -cascade is given, and the references are printed either way.
+func remove(e *env, kind string, args []string, fn func(string, namedzone.RemovePolicy) ([]namedzone.Reference, error)) error {
+	fs := flag.NewFlagSet(kind+" remove", flag.ContinueOnError)
+	cascade := fs.Bool("cascade", false, "also remove the references")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	policy := namedzone.RemoveRefuse
+	if *cascade {
+		policy = namedzone.RemoveCascade
+	}
+	refs, err := fn(pos[0], policy)
+	if len(refs) > 0 {
+		e.print(refs)
+	}
+	return err
+}
+
+// terms ends a list of match-list elements with the semicolon named.conf
+// wants after the last one.
+func terms(s string) string {
+	s = strings.TrimSpace(s)
+	if s != "" && !strings.HasSuffix(s, ";") {
+		s += ";"
+	}
+	return s
+}
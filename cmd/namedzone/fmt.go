@@ -0,0 +1,138 @@
+// File: pkg/namedzone/cmd/namedzone/fmt.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+func init() {
+	commands = append(commands,
+		command{group: "fmt", usage: "[-check] [-l] [FILE|DIR...]", files: true, run: fmtFiles},
+	)
+}
+
+// canonical is the layout fmt writes, the one RenderCanonical uses.
+var canonical = namedzone.Format{Tabs: true}
+
+// fmtFiles rewrites named.conf files in the canonical layout with
+// namedzone.FormatSource. Without arguments it formats every file of the
+// tree at -c; a directory stands for the *.conf files below it. With
+// -check nothing is written: the files that are not formatted are listed
+// and the command fails, for CI.
+func fmtFiles(e *env, args []string) error {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	check := fs.Bool("check", false, "list unformatted files and fail instead of rewriting them")
+	list := fs.Bool("l", false, "list the files that were rewritten")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	files, err := fmtTargets(e.path, pos)
+	if err != nil {
+		return err
+	}
+	var failed, unformatted int
+	for _, name := range files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			report(e.stderr, err)
+			failed++
+			continue
+		}
+		out, err := namedzone.FormatSource(src, canonical)
+		if err != nil {
+			report(e.stderr, fmt.Errorf("%s: %s", name, strings.TrimPrefix(err.Error(), "namedzone: ")))
+			failed++
+			continue
+		}
+		if bytes.Equal(src, out) {
+			continue
+		}
+		unformatted++
+		if !*check {
+			if err := writeFile(name, out); err != nil {
+				report(e.stderr, err)
+				failed++
+				continue
+			}
+		}
+		if *check || *list {
+			fmt.Fprintln(e.stdout, name)
+		}
+	}
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d of %d files could not be formatted", failed, len(files))
+	case *check && unformatted > 0:
+		return fmt.Errorf("%d of %d files are not formatted", unformatted, len(files))
+	}
+	return nil
+}
+
+// fmtTargets returns the files to format: the named files, the *.conf files
+// below the named directories, or the files of the tree at root.
+func fmtTargets(root string, args []string) ([]string, error) {
+	if len(args) == 0 {
+		cfg, err := namedzone.LoadTree(root)
+		if err != nil {
+			return nil, err
+		}
+		return cfg.Files(), nil
+	}
+	var out []string
+	for _, a := range args {
+		st, err := os.Stat(a)
+		if err != nil {
+			return nil, err
+		}
+		if !st.IsDir() {
+			out = append(out, a)
+			continue
+		}
+		err = filepath.WalkDir(a, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.HasSuffix(p, ".conf") {
+				out = append(out, p)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// writeFile replaces name with data through a temporary file in the same
+// directory, keeping its mode.
+func writeFile(name string, data []byte) error {
+	st, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), st.Mode().Perm())
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
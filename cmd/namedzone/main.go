@@ -0,0 +1,326 @@
+// File: pkg/namedzone/cmd/namedzone/main.go
+
+// Command namedzone edits a named.conf through the namedzone typed API, so
+// edits can be scripted without writing Go.
+//
+// Usage:
+//
+//	namedzone [-c named.conf] [-o json|yaml] [-n] [-force] <command> [args]
+//
+// Commands:
+//
+//	zone list [-view V]
+//	zone get NAME [-view V]
+//	zone add NAME [-view V] [-type T] [-file F] [-body TEXT | -f FILE] [-replace]
+//	zone remove NAME [-view V]
+//	view list | get NAME | remove NAME
+//	view add NAME [-match-clients LIST] [-body TEXT | -f FILE] [-replace]
+//	key list [-reveal] | get NAME [-reveal] | remove NAME [-cascade]
+//	key add NAME [-algorithm A] [-secret S] [-replace]
+//	acl list | get NAME | remove NAME [-cascade]
+//	acl add NAME ELEMENT... [-replace]
+//	options get [NAME]
+//	options set NAME VALUE...
+//	options unset NAME
+//	options put -f FILE
+//	fmt [-check] [-l] [FILE|DIR...]
+//
+// Items are printed as JSON (or YAML with -o yaml) in the package's JSON
+// projection; -f reads the same projection from a JSON or YAML file, "-"
+// for stdin. -body takes named.conf statements, e.g. -body 'type
+// secondary; primaries { 192.0.2.1; };'. Commands that change the config
+// take the config lock, refuse changes that add validation errors unless
+// -force is given, and save the config in place before printing the
+// result; -n prints the changed config instead of saving it.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+	"github.com/dlukt/namedzone/yaml"
+)
+
+// command is one "<group> <name>" subcommand, or a single-word one when
+// name is empty.
+type command struct {
+	group, name string
+	usage       string
+	write       bool // changes the config
+	files       bool // works on the files itself; the config is not loaded
+	run         func(e *env, args []string) error
+}
+
+var commands []command
+
+// env is what a command runs against.
+type env struct {
+	cfg    *namedzone.Config
+	path   string
+	format string
+	stdout io.Writer
+	stderr io.Writer
+	stdin  io.Reader
+}
+
+// errUsage marks errors caused by how the command was called.
+var errUsage = errors.New("usage")
+
+func usageError(format string, args ...any) error {
+	return &cliError{errUsage, fmt.Sprintf(format, args...)}
+}
+
+// cliError is an error of the command itself rather than of the package.
+type cliError struct {
+	class error
+	msg   string
+}
+
+func (e *cliError) Error() string        { return "namedzone: " + e.msg }
+func (e *cliError) Is(target error) bool { return target == e.class }
+
+// report prints err, which the package errors already prefix with
+// "namedzone:".
+func report(w io.Writer, err error) {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "namedzone: ") {
+		msg = "namedzone: " + msg
+	}
+	fmt.Fprintln(w, msg)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("namedzone", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("c", defaultPath(), "named.conf `path` ($NAMEDZONE_CONF)")
+	format := fs.String("o", "json", "output `format`: json or yaml")
+	dryRun := fs.Bool("n", false, "print the changed config instead of saving it")
+	force := fs.Bool("force", false, "save changes that add validation errors")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: namedzone [flags] <command> [args]\n\ncommands:")
+		for _, g := range []string{"zone", "view", "key", "acl", "options", "fmt"} {
+			for _, c := range commands {
+				if c.group == g {
+					fmt.Fprintln(stderr, "  "+strings.TrimSpace(c.title()+" "+c.usage))
+				}
+			}
+		}
+		fmt.Fprintln(stderr, "\nflags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "json" && *format != "yaml" {
+		fmt.Fprintf(stderr, "namedzone: unknown output format %q\n", *format)
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	i := slices.IndexFunc(commands, func(c command) bool {
+		return c.group == fs.Arg(0) && (c.name == "" || c.name == fs.Arg(1))
+	})
+	if i < 0 {
+		fmt.Fprintf(stderr, "namedzone: unknown command %q\n", strings.Join(fs.Args()[:min(2, fs.NArg())], " "))
+		return 2
+	}
+	cmd := commands[i]
+	args = fs.Args()[1:]
+	if cmd.name != "" {
+		args = args[1:]
+	}
+	if cmd.files {
+		e := &env{path: *path, format: *format, stdout: stdout, stderr: stderr, stdin: stdin}
+		return exit(stderr, cmd, cmd.run(e, args))
+	}
+
+	cfg, warnings, err := namedzone.LoadTreeWith(*path, namedzone.LoadOptions{Lock: cmd.write && !*dryRun})
+	if err != nil {
+		report(stderr, err)
+		return 1
+	}
+	defer cfg.Unlock()
+	if cmd.write {
+		for _, w := range warnings {
+			fmt.Fprintf(stderr, "warning: %s:%d:%d: %s: %s\n", w.File, w.Line, w.Column, w.Keyword, w.Message)
+		}
+	}
+	before := errorIssues(cfg)
+	// output is held back until the change is known to be saved
+	var out bytes.Buffer
+	e := &env{cfg: cfg, path: *path, format: *format, stdout: &out, stderr: stderr, stdin: stdin}
+	if err := cmd.run(e, args); err != nil {
+		stdout.Write(out.Bytes())
+		return exit(stderr, cmd, err)
+	}
+	if !cmd.write {
+		stdout.Write(out.Bytes())
+		return 0
+	}
+	if added := newIssues(before, errorIssues(cfg)); len(added) > 0 && !*force {
+		for _, is := range added {
+			fmt.Fprintln(stderr, is)
+		}
+		fmt.Fprintln(stderr, "namedzone: change adds validation errors; not saved (use -force)")
+		return 1
+	}
+	if *dryRun {
+		text, err := cfg.Render()
+		if err != nil {
+			report(stderr, err)
+			return 1
+		}
+		io.WriteString(stdout, text)
+		return 0
+	}
+	if err := cfg.Save(*path); err != nil {
+		report(stderr, err)
+		return 1
+	}
+	stdout.Write(out.Bytes())
+	return 0
+}
+
+// exit reports err and returns the exit status for it.
+func exit(stderr io.Writer, cmd command, err error) int {
+	if err == nil {
+		return 0
+	}
+	report(stderr, err)
+	if errors.Is(err, errUsage) {
+		fmt.Fprintln(stderr, "usage: namedzone "+cmd.title()+" "+cmd.usage)
+		return 2
+	}
+	return 1
+}
+
+func (c command) title() string { return strings.TrimSpace(c.group + " " + c.name) }
+
+func defaultPath() string {
+	if p := os.Getenv("NAMEDZONE_CONF"); p != "" {
+		return p
+	}
+	return "/etc/named.conf"
+}
+
+func errorIssues(c *namedzone.Config) []namedzone.Issue {
+	var out []namedzone.Issue
+	for _, is := range c.Validate() {
+		if is.Severity == namedzone.SeverityError {
+			out = append(out, is)
+		}
+	}
+	return out
+}
+
+func newIssues(before, after []namedzone.Issue) []namedzone.Issue {
+	var out []namedzone.Issue
+	for _, is := range after {
+		if !slices.ContainsFunc(before, func(o namedzone.Issue) bool { return o.Path == is.Path && o.Message == is.Message }) {
+			out = append(out, is)
+		}
+	}
+	return out
+}
+
+// parse parses args with fs, allowing flags after positional arguments,
+// and returns the positional ones.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError("%v", err)
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// exactly checks the number of positional arguments.
+func exactly(pos []string, n int) error {
+	if len(pos) != n {
+		return usageError("want %d arguments, got %d", n, len(pos))
+	}
+	return nil
+}
+
+// print writes v in the output format.
+func (e *env) print(v any) error {
+	if e.format == "yaml" {
+		b, err := yaml.MarshalValue(v)
+		if err != nil {
+			return err
+		}
+		_, err = e.stdout.Write(b)
+		return err
+	}
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// readFile decodes the JSON or YAML document in the file name ("-" for
+// stdin) into v.
+func (e *env) readFile(name string, v any) error {
+	var b []byte
+	var err error
+	if name == "-" {
+		b, err = io.ReadAll(e.stdin)
+	} else {
+		b, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return err
+	}
+	// YAML is a superset of JSON
+	if err := yaml.UnmarshalValue(b, v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// parseBlock parses named.conf text holding a single statement.
+func parseBlock(text string) (*namedzone.Config, error) {
+	c, err := namedzone.FromReader(strings.NewReader(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid -body: %w", err)
+	}
+	return c, nil
+}
+
+func notFound(kind, name string) error {
+	return &cliError{errNotFound, fmt.Sprintf("%s %q not found", kind, name)}
+}
+
+var errNotFound = errors.New("not found")
+
+// exists is the error of an add without -replace.
+func exists(kind, name string) error {
+	return &namedzone.ConflictError{Kind: kind, Name: name, Msg: "already exists; use -replace"}
+}
+
+// nonNil makes empty lists print as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
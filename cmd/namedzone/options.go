@@ -0,0 +1,134 @@
+// File: pkg/namedzone/cmd/namedzone/options.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+func init() {
+	commands = append(commands,
+		command{group: "options", name: "get", usage: "[NAME]", run: optionsGet},
+		command{group: "options", name: "set", usage: "NAME VALUE...", write: true, run: optionsSet},
+		command{group: "options", name: "unset", usage: "NAME", write: true, run: optionsUnset},
+		command{group: "options", name: "put", usage: "-f FILE", write: true, run: optionsPut},
+	)
+}
+
+func (e *env) options() *namedzone.Options {
+	if e.cfg.Options == nil {
+		e.cfg.Options = &namedzone.Options{}
+	}
+	return e.cfg.Options
+}
+
+// optionsGet prints the options block, or the raw named.conf value of one
+// option.
+func optionsGet(e *env, args []string) error {
+	pos, err := parse(flag.NewFlagSet("options get", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	switch len(pos) {
+	case 0:
+		return e.print(e.options())
+	case 1:
+		v, ok := e.options().Get(pos[0])
+		if !ok {
+			return notFound("option", pos[0])
+		}
+		_, err := io.WriteString(e.stdout, v+"\n")
+		return err
+	}
+	return usageError("want at most one option name")
+}
+
+// optionsSet takes the value in named.conf syntax, e.g.
+//
+//	options set allow-query '{ 10.0.0.0/8; }'
+//
+// An option with a typed field is parsed into it; the others are stored
+// raw in Options.Other.
+func optionsSet(e *env, args []string) error {
+	pos, err := parse(flag.NewFlagSet("options set", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if len(pos) < 2 {
+		return usageError("want an option name and a value")
+	}
+	name, value := pos[0], strings.TrimSuffix(strings.TrimSpace(strings.Join(pos[1:], " ")), ";")
+	c, err := namedzone.FromReader(strings.NewReader(fmt.Sprintf("options { %s %s; };", name, value)))
+	if err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+	parsed := c.Options
+	if parsed == nil {
+		return fmt.Errorf("invalid value for %s", name)
+	}
+	o := e.options()
+	if len(parsed.Other) > 0 {
+		if err := o.Set(name, parsed.Other[0].Raw); err != nil {
+			return err
+		}
+	} else {
+		// every Options field is omitempty, so only the parsed one is set
+		b, err := json.Marshal(parsed)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, o); err != nil {
+			return err
+		}
+	}
+	v, _ := o.Get(name)
+	_, err = io.WriteString(e.stdout, v+"\n")
+	return err
+}
+
+// optionsUnset removes an option kept raw; typed ones are changed with set
+// or put.
+func optionsUnset(e *env, args []string) error {
+	pos, err := parse(flag.NewFlagSet("options unset", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	removed, err := e.options().Delete(pos[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return notFound("option", pos[0])
+	}
+	return nil
+}
+
+// optionsPut replaces the whole options block.
+func optionsPut(e *env, args []string) error {
+	fs := flag.NewFlagSet("options put", flag.ContinueOnError)
+	from := fs.String("f", "", "read the options from a JSON or YAML file")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 0); err != nil {
+		return err
+	}
+	if *from == "" {
+		return usageError("-f is required")
+	}
+	var o namedzone.Options
+	if err := e.readFile(*from, &o); err != nil {
+		return err
+	}
+	e.cfg.Options = &o
+	return e.print(&o)
+}
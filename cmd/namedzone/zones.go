@@ -0,0 +1,235 @@
+// File: pkg/namedzone/cmd/namedzone/zones.go
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/dlukt/namedzone"
+)
+
+func init() {
+	commands = append(commands,
+		command{group: "zone", name: "list", usage: "[-view V]", run: zoneList},
+		command{group: "zone", name: "get", usage: "NAME [-view V]", run: zoneGet},
+		command{group: "zone", name: "add", usage: "NAME [-view V] [-type T] [-file F] [-body TEXT | -f FILE] [-replace]", write: true, run: zoneAdd},
+		command{group: "zone", name: "remove", usage: "NAME [-view V]", write: true, run: zoneRemove},
+		command{group: "view", name: "list", run: viewList},
+		command{group: "view", name: "get", usage: "NAME", run: viewGet},
+		command{group: "view", name: "add", usage: "NAME [-match-clients LIST] [-body TEXT | -f FILE] [-replace]", write: true, run: viewAdd},
+		command{group: "view", name: "remove", usage: "NAME", write: true, run: viewRemove},
+	)
+}
+
+func zoneList(e *env, args []string) error {
+	fs := flag.NewFlagSet("zone list", flag.ContinueOnError)
+	view := fs.String("view", "", "list the zones of this view")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 0); err != nil {
+		return err
+	}
+	if *view == "" {
+		return e.print(nonNil(e.cfg.Zones))
+	}
+	v := e.cfg.FindView(*view)
+	if v == nil {
+		return notFound("view", *view)
+	}
+	return e.print(nonNil(v.Zones))
+}
+
+func zoneGet(e *env, args []string) error {
+	fs := flag.NewFlagSet("zone get", flag.ContinueOnError)
+	view := fs.String("view", "", "the view holding the zone")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	z := e.cfg.GetZoneInView(*view, pos[0])
+	if z == nil {
+		return notFound("zone", pos[0])
+	}
+	return e.print(z)
+}
+
+func zoneAdd(e *env, args []string) error {
+	fs := flag.NewFlagSet("zone add", flag.ContinueOnError)
+	view := fs.String("view", "", "add the zone to this view")
+	typ := fs.String("type", "", "zone type")
+	file := fs.String("file", "", "zone file")
+	body := fs.String("body", "", "zone statements in named.conf syntax")
+	from := fs.String("f", "", "read the zone from a JSON or YAML file")
+	replace := fs.Bool("replace", false, "replace an existing zone")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	if *body != "" && *from != "" {
+		return usageError("-body and -f are exclusive")
+	}
+	name := pos[0]
+	if *view != "" && e.cfg.FindView(*view) == nil {
+		return notFound("view", *view)
+	}
+	if e.cfg.GetZoneInView(*view, name) != nil && !*replace {
+		return exists("zone", name)
+	}
+
+	z := namedzone.Zone{Name: name}
+	switch {
+	case *from != "":
+		if err := e.readFile(*from, &z); err != nil {
+			return err
+		}
+		if z.Name != name {
+			return fmt.Errorf("%s: zone %q does not match %q", *from, z.Name, name)
+		}
+	case *body != "":
+		c, err := parseBlock(fmt.Sprintf("zone %q { %s };", name, *body))
+		if err != nil {
+			return err
+		}
+		z = c.Zones[0]
+	}
+	if *typ != "" {
+		z.Type = namedzone.ZoneType(*typ)
+	}
+	if *file != "" {
+		z.File = *file
+	}
+	if z.Type == "" {
+		return usageError("zone type not set; use -type, -body or -f")
+	}
+
+	if *view == "" {
+		e.cfg.UpsertZone(z)
+	} else {
+		e.cfg.UpsertZoneInView(*view, z)
+	}
+	return e.print(e.cfg.GetZoneInView(*view, name))
+}
+
+func zoneRemove(e *env, args []string) error {
+	fs := flag.NewFlagSet("zone remove", flag.ContinueOnError)
+	view := fs.String("view", "", "the view holding the zone")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	removed := false
+	if *view == "" {
+		removed = e.cfg.RemoveZone(pos[0])
+	} else {
+		removed = e.cfg.RemoveZoneInView(*view, pos[0])
+	}
+	if !removed {
+		return notFound("zone", pos[0])
+	}
+	return nil
+}
+
+func viewList(e *env, args []string) error {
+	pos, err := parse(flag.NewFlagSet("view list", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 0); err != nil {
+		return err
+	}
+	return e.print(nonNil(e.cfg.Views))
+}
+
+func viewGet(e *env, args []string) error {
+	pos, err := parse(flag.NewFlagSet("view get", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	v := e.cfg.FindView(pos[0])
+	if v == nil {
+		return notFound("view", pos[0])
+	}
+	return e.print(v)
+}
+
+// viewAdd keeps the zones of a replaced view unless the new one has its
+// own; zones are managed with the zone commands.
+func viewAdd(e *env, args []string) error {
+	fs := flag.NewFlagSet("view add", flag.ContinueOnError)
+	match := fs.String("match-clients", "", "match-clients elements in named.conf syntax")
+	body := fs.String("body", "", "view statements in named.conf syntax")
+	from := fs.String("f", "", "read the view from a JSON or YAML file")
+	replace := fs.Bool("replace", false, "replace an existing view")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	if *body != "" && *from != "" {
+		return usageError("-body and -f are exclusive")
+	}
+	name := pos[0]
+	old := e.cfg.FindView(name)
+	if old != nil && !*replace {
+		return exists("view", name)
+	}
+
+	v := namedzone.View{Name: name}
+	switch {
+	case *from != "":
+		if err := e.readFile(*from, &v); err != nil {
+			return err
+		}
+		if v.Name != name {
+			return fmt.Errorf("%s: view %q does not match %q", *from, v.Name, name)
+		}
+	case *body != "":
+		c, err := parseBlock(fmt.Sprintf("view %q { %s };", name, *body))
+		if err != nil {
+			return err
+		}
+		v = c.Views[0]
+	}
+	if *match != "" {
+		c, err := parseBlock(fmt.Sprintf("view %q { match-clients { %s }; };", name, terms(*match)))
+		if err != nil {
+			return err
+		}
+		v.MatchClients = c.Views[0].MatchClients
+	}
+	if old != nil && v.Zones == nil {
+		v.Zones = old.Zones
+	}
+	e.cfg.UpsertView(v)
+	return e.print(e.cfg.FindView(name))
+}
+
+func viewRemove(e *env, args []string) error {
+	pos, err := parse(flag.NewFlagSet("view remove", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := exactly(pos, 1); err != nil {
+		return err
+	}
+	if !e.cfg.RemoveView(pos[0]) {
+		return notFound("view", pos[0])
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// File: pkg/namedzone/compat.go
+package namedzone
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// Feature is a named.conf statement, option or value and the BIND versions
+// that accept it.
+type Feature struct {
+	// Name is the keyword as written, or a keyword and its first value for
+	// features that are values ("type mirror", "masterfile-format map")
+	// and listen-on transports ("listen-on tls", "listen-on http").
+	Name string `json:"name"`
+	// Top restricts the feature to top-level statements, e.g. the tls
+	// block rather than the tls option of forwarders.
+	Top     bool   `json:"top,omitempty"`
+	Since   string `json:"since,omitempty"`   // first version that accepts it
+	Removed string `json:"removed,omitempty"` // first version that rejects it
+}
+
+// Features is the matrix CheckCompatibility consults. It covers the
+// statements and options that commonly break mixed deployments, not the
+// whole grammar; append to it for local needs.
+var Features = []Feature{
+	{Name: "tls", Top: true, Since: "9.18.0"},
+	{Name: "http", Top: true, Since: "9.18.0"},
+	{Name: "listen-on tls", Since: "9.18.0"},
+	{Name: "listen-on http", Since: "9.18.0"},
+	{Name: "key-store", Top: true, Since: "9.20.0"},
+	{Name: "remote-servers", Top: true, Since: "9.20.0"},
+	{Name: "primaries", Since: "9.16.12"},
+	{Name: "parental-agents", Since: "9.18.0"},
+	{Name: "checkds", Since: "9.18.0"},
+	{Name: "dnssec-policy", Since: "9.16.0"},
+	{Name: "trust-anchors", Since: "9.16.0"},
+	{Name: "type primary", Since: "9.16.0"},
+	{Name: "type secondary", Since: "9.16.0"},
+	{Name: "type mirror", Since: "9.14.0"},
+	{Name: "catalog-zones", Since: "9.11.0"},
+	{Name: "dnstap", Since: "9.11.0"},
+	{Name: "rate-limit", Since: "9.10.0"},
+	{Name: "require-server-cookie", Since: "9.11.0"},
+	{Name: "minimal-any", Since: "9.11.0"},
+	{Name: "lmdb-mapsize", Since: "9.12.0"},
+	{Name: "stale-answer-enable", Since: "9.12.0"},
+	{Name: "qname-minimization", Since: "9.14.0"},
+
+	{Name: "dnssec-lookaside", Removed: "9.16.0"},
+	{Name: "filter-aaaa", Removed: "9.16.0"},
+	{Name: "dnssec-enable", Removed: "9.18.0"},
+	{Name: "auto-dnssec", Removed: "9.20.0"},
+	{Name: "trusted-keys", Removed: "9.20.0"},
+	{Name: "managed-keys", Removed: "9.20.0"},
+	{Name: "dnssec-must-be-secure", Removed: "9.20.0"},
+	{Name: "glue-cache", Removed: "9.20.0"},
+	{Name: "tkey-dhkey", Removed: "9.20.0"},
+	{Name: "masterfile-format map", Removed: "9.20.0"},
+}
+
+// CheckCompatibility reports the statements and options of c that named
+// version (e.g. "9.18" or "9.18.24") does not accept, by Features: those
+// added later and those already removed. The config is checked as Apply
+// would write it, including statements the typed model does not cover.
+// Legacy spellings are checked as kept, so set ModernizeKeywords (or not)
+// before checking. The result uses Validate's Issue type and paths.
+func (c *Config) CheckCompatibility(version string) ([]Issue, error) {
+	target, err := parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	k := c.Clone()
+	f := &nc.File{}
+	k.sync(k.syncer(f))
+	nodes := append(f.Nodes, c.unmodeled()...)
+
+	var issues []Issue
+	var walk func(path string, nodes []nc.Node, top bool)
+	walk = func(path string, nodes []nc.Node, top bool) {
+		for _, n := range nodes {
+			st, ok := n.(*nc.Stmt)
+			if !ok {
+				continue
+			}
+			p := stmtPath(path, st, top)
+			for _, name := range featureNames(st) {
+				for _, ft := range Features {
+					if ft.Name != name || ft.Top && !top {
+						continue
+					}
+					if msg := ft.check(target); msg != "" {
+						issues = append(issues, Issue{Severity: SeverityError, Path: p, Message: msg,
+							Err: &ValueError{Path: p, Value: name, Msg: msg}})
+					}
+				}
+			}
+			if st.HasBlock {
+				walk(p, st.Body, false)
+			}
+		}
+	}
+	walk("", nodes, true)
+	return issues, nil
+}
+
+// check returns why target does not accept ft, or "".
+func (ft Feature) check(target [3]int) string {
+	if ft.Since != "" {
+		if v, err := parseVersion(ft.Since); err == nil && slices.Compare(target[:], v[:]) < 0 {
+			return fmt.Sprintf("%q requires named %s or later", ft.Name, ft.Since)
+		}
+	}
+	if ft.Removed != "" {
+		if v, err := parseVersion(ft.Removed); err == nil && slices.Compare(target[:], v[:]) >= 0 {
+			return fmt.Sprintf("%q was removed in named %s", ft.Name, ft.Removed)
+		}
+	}
+	return ""
+}
+
+// featureNames returns the Feature names a statement can match.
+func featureNames(st *nc.Stmt) []string {
+	names := []string{st.Keyword}
+	toks := strings.Fields(headText(st))
+	switch st.Keyword {
+	case "type", "masterfile-format":
+		if len(toks) > 1 {
+			names = append(names, st.Keyword+" "+strings.TrimSuffix(toks[1], ";"))
+		}
+	case "listen-on", "listen-on-v6":
+		for i, t := range toks {
+			if (t == "tls" || t == "http") && i+1 < len(toks) {
+				names = append(names, "listen-on "+t)
+			}
+		}
+	}
+	return names
+}
+
+// stmtPath extends a Validate-style path with st: named blocks are indexed
+// by name, other statements appended by keyword.
+func stmtPath(path string, st *nc.Stmt, top bool) string {
+	field := map[string]string{
+		"zone": "zones", "view": "views", "acl": "acls", "key": "keys", "key-store": "keyStores",
+		"tls": "tls", "http": "http", "remote-servers": "remoteServers", "primaries": "remoteServers", "masters": "remoteServers",
+	}[st.Keyword]
+	if field != "" && (top || st.Keyword == "zone") {
+		if name := headNameAfter(st, st.Keyword); name != "" {
+			return joinPath(path, fmt.Sprintf("%s[%q]", field, name))
+		}
+	}
+	return joinPath(path, st.Keyword)
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// parseVersion parses a BIND version such as "9.18" or "9.18.24-S1" into
+// major, minor and patch.
+func parseVersion(s string) ([3]int, error) {
+	var v [3]int
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".", 3)
+	if len(parts) < 2 {
+		return v, &ValueError{Path: "version", Value: s, Msg: "want major.minor[.patch]"}
+	}
+	for i, p := range parts {
+		if i == 2 {
+			p = p[:len(p)-len(strings.TrimLeft(p, "0123456789"))]
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return v, &ValueError{Path: "version", Value: s, Msg: "want major.minor[.patch]"}
+		}
+		v[i] = n
+	}
+	return v, nil
+}
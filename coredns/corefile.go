@@ -0,0 +1,198 @@
+// File: pkg/namedzone/coredns/corefile.go
+package coredns
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// serverBlock is a Corefile server block: its keys and directives.
+type serverBlock struct {
+	Keys       []string
+	Directives []directive
+	Line       int
+}
+
+// directive is a plugin line, with the lines of its block, if any.
+type directive struct {
+	Name  string
+	Args  []string
+	Block []directive
+	Line  int
+}
+
+type token struct {
+	text   string
+	line   int
+	quoted bool
+}
+
+// lexCorefile splits src into lines of tokens. Braces are tokens of their
+// own, comments run from # to the end of the line, and "quoted" or
+// `backquoted` strings keep their spaces.
+func lexCorefile(src string) ([][]token, error) {
+	var lines [][]token
+	var cur []token
+	n := 1
+	r := []rune(src)
+	for i := 0; i < len(r); i++ {
+		c := r[i]
+		switch {
+		case c == '\n':
+			if len(cur) > 0 {
+				lines = append(lines, cur)
+				cur = nil
+			}
+			n++
+		case unicode.IsSpace(c):
+		case c == '#':
+			for i+1 < len(r) && r[i+1] != '\n' {
+				i++
+			}
+		case c == '{' || c == '}':
+			cur = append(cur, token{text: string(c), line: n})
+		case c == '"' || c == '`':
+			j := i + 1
+			var b strings.Builder
+			for ; j < len(r) && r[j] != c; j++ {
+				if c == '"' && r[j] == '\\' && j+1 < len(r) {
+					j++
+				}
+				if r[j] == '\n' {
+					n++
+				}
+				b.WriteRune(r[j])
+			}
+			if j == len(r) {
+				return nil, fmt.Errorf("namedzone: Corefile line %d: unterminated string", n)
+			}
+			cur = append(cur, token{text: b.String(), line: n, quoted: true})
+			i = j
+		default:
+			j := i
+			for j < len(r) && !unicode.IsSpace(r[j]) && r[j] != '{' && r[j] != '}' && r[j] != '#' {
+				j++
+			}
+			cur = append(cur, token{text: string(r[i:j]), line: n})
+			i = j - 1
+		}
+	}
+	if len(cur) > 0 {
+		lines = append(lines, cur)
+	}
+	return lines, nil
+}
+
+// parseCorefile reads the server blocks of a Corefile. A file made of a
+// single block without braces is not supported; CoreDNS requires braces
+// once there is more than one key or directive line.
+func parseCorefile(src string) ([]serverBlock, error) {
+	lines, err := lexCorefile(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &corefileParser{lines: lines}
+	var blocks []serverBlock
+	for p.i < len(p.lines) {
+		l := p.lines[p.i]
+		p.i++
+		sb := serverBlock{Line: l[0].line}
+		for _, t := range l {
+			if t.text == "{" && !t.quoted {
+				break
+			}
+			for _, k := range strings.Split(t.text, ",") {
+				if k != "" {
+					sb.Keys = append(sb.Keys, k)
+				}
+			}
+		}
+		if last := l[len(l)-1]; last.text != "{" || last.quoted {
+			return nil, fmt.Errorf("namedzone: Corefile line %d: want { after server block keys", sb.Line)
+		}
+		if sb.Directives, err = p.block(sb.Line); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, sb)
+	}
+	return blocks, nil
+}
+
+type corefileParser struct {
+	lines [][]token
+	i     int
+}
+
+// block reads directives up to the closing brace of a block opened on line
+// open.
+func (p *corefileParser) block(open int) ([]directive, error) {
+	var out []directive
+	for p.i < len(p.lines) {
+		l := p.lines[p.i]
+		p.i++
+		if l[0].text == "}" && !l[0].quoted {
+			if len(l) > 1 {
+				return nil, fmt.Errorf("namedzone: Corefile line %d: unexpected %q after }", l[0].line, l[1].text)
+			}
+			return out, nil
+		}
+		d := directive{Name: l[0].text, Line: l[0].line}
+		opens := false
+		for _, t := range l[1:] {
+			if !t.quoted && (t.text == "{" || t.text == "}") {
+				if t.text == "}" || opens {
+					return nil, fmt.Errorf("namedzone: Corefile line %d: unexpected %s", t.line, t.text)
+				}
+				opens = true
+				continue
+			}
+			if opens {
+				return nil, fmt.Errorf("namedzone: Corefile line %d: unexpected %q after {", t.line, t.text)
+			}
+			d.Args = append(d.Args, t.text)
+		}
+		if opens {
+			var err error
+			if d.Block, err = p.block(d.Line); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, d)
+	}
+	return nil, fmt.Errorf("namedzone: Corefile line %d: block not closed", open)
+}
+
+// writer renders server blocks with CoreDNS's four-space indentation.
+type writer struct {
+	b strings.Builder
+}
+
+func (w *writer) block(keys []string, body []directive) {
+	if w.b.Len() > 0 {
+		w.b.WriteString("\n")
+	}
+	w.b.WriteString(strings.Join(keys, " ") + " {\n")
+	w.directives(body, 1)
+	w.b.WriteString("}\n")
+}
+
+func (w *writer) directives(ds []directive, depth int) {
+	indent := strings.Repeat("    ", depth)
+	for _, d := range ds {
+		w.b.WriteString(indent + d.Name)
+		for _, a := range d.Args {
+			if a == "" || strings.ContainsAny(a, " \t#{}\"`") {
+				a = strconv.Quote(a)
+			}
+			w.b.WriteString(" " + a)
+		}
+		if d.Block != nil {
+			w.b.WriteString(" {\n")
+			w.directives(d.Block, depth+1)
+			w.b.WriteString(indent + "}")
+		}
+		w.b.WriteString("\n")
+	}
+}
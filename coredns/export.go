@@ -0,0 +1,345 @@
+// File: pkg/namedzone/coredns/export.go
+
+// Package coredns converts between a namedzone.Config and a CoreDNS
+// Corefile, best effort, for the features both have: forwarding (DoT
+// upstreams included), primary zones served with the file plugin,
+// secondary zones, zone transfers, query ACLs and DoT/DoH listeners. Export
+// and Import report what they could not map as notes.
+package coredns
+
+import (
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+// Options control Export.
+type Options struct {
+	// View selects the view whose zones are exported along with the
+	// top-level ones; empty exports top-level zones only.
+	View string
+}
+
+// Export renders cfg as a Corefile: a server block per zone, repeated for
+// each listener transport, and a root block forwarding everything else
+// when named forwards. ACLs are expanded to prefixes with
+// Config.ExpandMatchList, so localhost and localnets are taken from the
+// interfaces of the machine running Export.
+func Export(cfg *namedzone.Config, opts Options) ([]byte, []string, error) {
+	x := &exporter{cfg: cfg}
+	if opts.View != "" && cfg.FindView(opts.View) == nil {
+		return nil, nil, &namedzone.ReferenceError{Kind: "view", Name: opts.View, Path: "view[" + opts.View + "]"}
+	}
+	o := cfg.Options
+	if o == nil {
+		o = &namedzone.Options{}
+	}
+	x.listeners()
+	acl, err := x.acl("options.allowQuery", o.AllowQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type site struct {
+		zone string
+		body []directive
+	}
+	var sites []site
+	if o.Recursion == nil || *o.Recursion {
+		if len(o.Forwarders) > 0 {
+			if o.Forward != "only" {
+				x.note("options: forward first; CoreDNS forwards only and does not recurse")
+			}
+			body := slices.Concat(acl, x.forward("options.forwarders", ".", o.Forwarders), []directive{{Name: "cache"}})
+			sites = append(sites, site{".", body})
+		} else {
+			x.note("options: named recurses; CoreDNS cannot, add a forward to the root block")
+		}
+	}
+	for v, z := range cfg.AllZones() {
+		view, p := "", "zone["+z.Name+"]"
+		if v != nil {
+			if v.Name != opts.View {
+				continue
+			}
+			view, p = v.Name, "view["+v.Name+"]."+p
+		}
+		body, err := x.zone(view, p, *z)
+		if err != nil {
+			return nil, nil, err
+		}
+		if body != nil {
+			sites = append(sites, site{fqdn(z.Name), slices.Concat(acl, body)})
+		}
+	}
+
+	var w writer
+	for _, s := range sites {
+		for _, t := range x.transports {
+			body := s.body
+			if len(t.bind) > 0 {
+				body = append([]directive{{Name: "bind", Args: t.bind}}, body...)
+			}
+			if t.tls != nil {
+				args := []string{t.tls.CertFile, t.tls.KeyFile}
+				if t.tls.CAFile != "" {
+					args = append(args, t.tls.CAFile)
+				}
+				body = append([]directive{{Name: "tls", Args: args}}, body...)
+			}
+			w.block([]string{t.scheme + s.zone + ":" + strconv.Itoa(t.port)}, body)
+		}
+	}
+	return []byte(w.b.String()), x.notes, nil
+}
+
+type exporter struct {
+	cfg        *namedzone.Config
+	notes      []string
+	transports []transport
+}
+
+// transport is a listener as CoreDNS sees it: a scheme and port shared by
+// every server block, with the addresses to bind.
+type transport struct {
+	scheme string // "", "tls://" or "https://"
+	port   int
+	bind   []string
+	tls    *namedzone.TLS
+}
+
+func (x *exporter) note(format string, args ...any) {
+	x.notes = append(x.notes, fmt.Sprintf(format, args...))
+}
+
+// listeners maps listen-on and listen-on-v6 to transports. Both families
+// listening on any binds every address, CoreDNS's default.
+func (x *exporter) listeners() {
+	for _, l := range x.cfg.Summary().Listeners {
+		var scheme string
+		switch l.Transport {
+		case "http":
+			x.note("listen-on %s: DNS over plain HTTP has no CoreDNS equivalent", l.Family)
+			continue
+		case "tls":
+			scheme = "tls://"
+		case "https":
+			scheme = "https://"
+		}
+		if slices.Equal(l.Addrs, []string{"none"}) {
+			continue
+		}
+		i := slices.IndexFunc(x.transports, func(t transport) bool { return t.scheme == scheme && t.port == l.Port })
+		if i < 0 {
+			x.transports = append(x.transports, transport{scheme: scheme, port: l.Port})
+			i = len(x.transports) - 1
+		}
+		t := &x.transports[i]
+		if scheme != "" && t.tls == nil {
+			if t.tls = x.findTLS(l.TLS); t.tls == nil || t.tls.CertFile == "" {
+				t.tls = nil
+				x.note("listen-on %s: tls %q has no certificate; add a tls directive", l.Family, l.TLS)
+			}
+		}
+		for _, a := range l.Addrs {
+			switch {
+			case a == "any" && l.Family == "ipv6":
+				a = "::"
+			case a == "any":
+				a = "0.0.0.0"
+			default:
+				if _, err := netip.ParseAddr(a); err != nil {
+					x.note("listen-on %s: %q is not an address; not exported", l.Family, a)
+					continue
+				}
+			}
+			t.bind = append(t.bind, a)
+		}
+	}
+	for i := range x.transports {
+		if t := &x.transports[i]; slices.Contains(t.bind, "0.0.0.0") && slices.Contains(t.bind, "::") {
+			t.bind = nil
+		}
+	}
+}
+
+// acl renders a match list as the acl plugin; a list that admits everyone
+// needs none.
+func (x *exporter) acl(path string, terms []namedzone.MatchTerm) ([]directive, error) {
+	if terms == nil {
+		return nil, nil
+	}
+	if hasKey(terms) {
+		x.note("%s: key terms have no CoreDNS acl equivalent; exported as not matching", path)
+	}
+	nets, err := x.nets(terms)
+	if err != nil || nets == nil {
+		return nil, err
+	}
+	block := []directive{{Name: "block"}}
+	if len(nets) > 0 {
+		block = append([]directive{{Name: "allow", Args: append([]string{"net"}, nets...)}}, block...)
+	}
+	return []directive{{Name: "acl", Block: block}}, nil
+}
+
+// nets expands terms to prefixes; it returns nil when they cover every
+// address and an empty list when they admit none.
+func (x *exporter) nets(terms []namedzone.MatchTerm) ([]string, error) {
+	prefixes, err := x.cfg.ExpandMatchList(terms)
+	if err != nil {
+		return nil, err
+	}
+	out := []string{}
+	var v4, v6 bool
+	for _, p := range prefixes {
+		v4 = v4 || p == netip.MustParsePrefix("0.0.0.0/0")
+		v6 = v6 || p == netip.MustParsePrefix("::/0")
+		out = append(out, p.String())
+	}
+	if v4 && v6 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// forward renders forwarders as the forward plugin for the names below from.
+func (x *exporter) forward(path, from string, fwd []namedzone.Forwarder) []directive {
+	d := directive{Name: "forward", Args: []string{from}}
+	servername := ""
+	for _, f := range fwd {
+		addr := f.Address
+		if a, err := netip.ParseAddr(addr); err == nil && a.Is6() {
+			addr = "[" + addr + "]"
+		}
+		if f.Port != nil {
+			addr += ":" + strconv.Itoa(*f.Port)
+		}
+		if f.TLS != "" && f.TLS != "none" {
+			addr = "tls://" + addr
+			t := x.findTLS(f.TLS)
+			switch {
+			case t == nil || t.RemoteHost == "":
+				x.note("%s: tls %q has no remote-hostname; add tls_servername", path, f.TLS)
+			case servername == "":
+				servername = t.RemoteHost
+			case servername != t.RemoteHost:
+				x.note("%s: CoreDNS takes one tls_servername per forward; %q not exported", path, t.RemoteHost)
+			}
+		}
+		d.Args = append(d.Args, addr)
+	}
+	if servername != "" {
+		d.Block = []directive{{Name: "tls_servername", Args: []string{servername}}}
+	}
+	return []directive{d}
+}
+
+// zone renders the plugins serving z; nil when it has no equivalent.
+func (x *exporter) zone(view, path string, z namedzone.Zone) ([]directive, error) {
+	e, err := x.cfg.EffectiveZoneSettings(view, z.Name)
+	if err != nil {
+		return nil, err
+	}
+	var body []directive
+	switch z.Type {
+	case namedzone.ZoneForward:
+		if len(e.Forwarders.Value) == 0 {
+			x.note("%s: forward zone without forwarders not exported", path)
+			return nil, nil
+		}
+		if e.Forward.Value != "only" {
+			x.note("%s: forward first; CoreDNS forwards only", path)
+		}
+		return x.forward(path+".forwarders", ".", e.Forwarders.Value), nil
+	case namedzone.ZonePrimary:
+		file := z.File
+		if file != "" && !filepath.IsAbs(file) && x.cfg.Options != nil && x.cfg.Options.Directory != "" {
+			file = filepath.Join(x.cfg.Options.Directory, file)
+		}
+		body = []directive{{Name: "file", Args: []string{file}}}
+	case namedzone.ZoneSecondary:
+		var from []directive
+		for _, s := range x.cfg.ZonePrimaries(z) {
+			a, err := netip.ParseAddr(s.Address)
+			if err != nil {
+				x.note("%s: primary %q is not an address; not exported", path, s.Address)
+				continue
+			}
+			addr := a.String()
+			if s.Port != nil {
+				addr = netip.AddrPortFrom(a, uint16(*s.Port)).String()
+			}
+			if s.Key != "" || (s.TLS != "" && s.TLS != "none") {
+				x.note("%s: primary %s uses key or tls; exported as a plain transfer", path, s.Address)
+			}
+			from = append(from, directive{Name: "transfer", Args: []string{"from", addr}})
+		}
+		body = []directive{{Name: "secondary", Block: from}}
+	default:
+		x.note("%s: %s zone has no CoreDNS equivalent; not exported", path, z.Type)
+		return nil, nil
+	}
+
+	if len(e.AlsoNotify.Value) > 0 {
+		x.note("%s: also-notify not exported; CoreDNS notifies the transfer to addresses", path)
+	}
+	if terms := e.AllowTransfer.Value; terms != nil {
+		if hasKey(terms) {
+			x.note("%s.allowTransfer: key terms not exported", path)
+		}
+		nets, err := x.nets(terms)
+		if err != nil {
+			return nil, err
+		}
+		to := []string{"*"}
+		if nets != nil {
+			to = nil
+			// transfer to takes addresses, not networks
+			for _, n := range nets {
+				if p := netip.MustParsePrefix(n); p.IsSingleIP() {
+					to = append(to, p.Addr().String())
+				} else {
+					x.note("%s.allowTransfer: network %s not exported; transfer to takes addresses", path, n)
+				}
+			}
+		}
+		if len(to) > 0 {
+			body = append(body, directive{Name: "transfer", Block: []directive{{Name: "to", Args: to}}})
+		}
+	} else {
+		// named's default allows anyone; CoreDNS transfers only when told to
+		body = append(body, directive{Name: "transfer", Block: []directive{{Name: "to", Args: []string{"*"}}}})
+	}
+	return body, nil
+}
+
+func (x *exporter) findTLS(name string) *namedzone.TLS {
+	for i, t := range x.cfg.TLS {
+		if t.Name == name {
+			return &x.cfg.TLS[i]
+		}
+	}
+	return nil
+}
+
+func hasKey(terms []namedzone.MatchTerm) bool {
+	for _, t := range terms {
+		if t.Key != "" || hasKey(t.Nested) {
+			return true
+		}
+	}
+	return false
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
@@ -0,0 +1,435 @@
+// File: pkg/namedzone/coredns/import.go
+package coredns
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/netip"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+// Import reads a Corefile into a Config. The root block's forward and acl
+// become the global forwarders (forward only) and allow-query; other
+// blocks become forward, primary (file) and secondary zones, with transfer
+// to mapped to allow-transfer. The first transport of the server keys,
+// plain DNS preferred, becomes listen-on, with bind as its addresses and a
+// tls directive as its tls block. Snippets, import and plugins without a
+// named equivalent are reported as notes.
+func Import(r io.Reader) (*namedzone.Config, []string, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	blocks, err := parseCorefile(string(src))
+	if err != nil {
+		return nil, nil, err
+	}
+	im := &importer{cfg: &namedzone.Config{Options: &namedzone.Options{}}}
+	for _, sb := range blocks {
+		if err := im.block(sb); err != nil {
+			return nil, nil, err
+		}
+	}
+	im.listen()
+	return im.cfg, im.notes, nil
+}
+
+type importer struct {
+	cfg   *namedzone.Config
+	notes []string
+	// listeners found in server keys, in order, with their bind addresses
+	listeners []listener
+}
+
+type listener struct {
+	scheme string
+	port   int
+	bind   []string
+	tls    string // tls block name
+}
+
+func (im *importer) note(format string, args ...any) {
+	im.notes = append(im.notes, fmt.Sprintf(format, args...))
+}
+
+// errorf reports a directive that cannot be read.
+func errorf(d directive, format string, args ...any) error {
+	return &namedzone.ValueError{Path: "line " + strconv.Itoa(d.Line) + "." + d.Name, Value: strings.Join(d.Args, " "), Msg: fmt.Sprintf(format, args...)}
+}
+
+// serverKey splits [scheme://]zone[:port]; zone may be a reverse CIDR.
+func serverKey(key string) (scheme, zone string, port int, err error) {
+	if s, rest, ok := strings.Cut(key, "://"); ok {
+		scheme, key = s, rest
+	}
+	ports := map[string]int{"": 53, "dns": 53, "tls": 853, "https": 443}
+	port, ok := ports[scheme]
+	if !ok {
+		return "", "", 0, fmt.Errorf("unsupported transport %s", scheme)
+	}
+	if scheme == "dns" {
+		scheme = ""
+	}
+	if i := strings.LastIndex(key, ":"); i >= 0 {
+		if port, err = strconv.Atoi(key[i+1:]); err != nil {
+			return "", "", 0, fmt.Errorf("invalid port in %q", key)
+		}
+		key = key[:i]
+	}
+	if p, err := netip.ParsePrefix(key); err == nil {
+		if key, err = namedzone.ReverseZoneFor(p); err != nil {
+			return "", "", 0, err
+		}
+	}
+	zone = strings.ToLower(strings.TrimSuffix(key, "."))
+	if zone == "" {
+		zone = "."
+	}
+	return scheme, zone, port, nil
+}
+
+func (im *importer) block(sb serverBlock) error {
+	var zones []string
+	var keyListeners []int
+	for _, k := range sb.Keys {
+		if strings.HasPrefix(k, "(") {
+			im.note("line %d: snippet %s not imported", sb.Line, k)
+			return nil
+		}
+		scheme, zone, port, err := serverKey(k)
+		if err != nil {
+			return &namedzone.ValueError{Path: "line " + strconv.Itoa(sb.Line), Value: k, Msg: err.Error()}
+		}
+		if !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+		i := slices.IndexFunc(im.listeners, func(l listener) bool { return l.scheme == scheme && l.port == port })
+		if i < 0 {
+			im.listeners = append(im.listeners, listener{scheme: scheme, port: port})
+			i = len(im.listeners) - 1
+		}
+		keyListeners = append(keyListeners, i)
+	}
+
+	var transferTo []namedzone.MatchTerm
+	var transferZones []string
+	for _, d := range sb.Directives {
+		switch d.Name {
+		case "bind":
+			for _, i := range keyListeners {
+				for _, a := range d.Args {
+					if _, err := netip.ParseAddr(a); err != nil {
+						im.note("line %d: bind %s is not an address; not imported", d.Line, a)
+						continue
+					}
+					if !slices.Contains(im.listeners[i].bind, a) {
+						im.listeners[i].bind = append(im.listeners[i].bind, a)
+					}
+				}
+			}
+		case "tls":
+			if len(d.Args) < 2 {
+				return errorf(d, "want cert and key files")
+			}
+			t := namedzone.TLS{Name: "coredns-" + strconv.Itoa(len(im.cfg.TLS)+1), CertFile: d.Args[0], KeyFile: d.Args[1]}
+			if len(d.Args) > 2 {
+				t.CAFile = d.Args[2]
+			}
+			im.cfg.TLS = append(im.cfg.TLS, t)
+			for _, i := range keyListeners {
+				if im.listeners[i].scheme != "" && im.listeners[i].tls == "" {
+					im.listeners[i].tls = t.Name
+				}
+			}
+		case "forward":
+			if err := im.forward(d, zones); err != nil {
+				return err
+			}
+		case "file":
+			if len(d.Args) == 0 {
+				return errorf(d, "want a zone file")
+			}
+			for _, z := range directiveZones(d.Args[1:], zones) {
+				im.zone(d, namedzone.Zone{Name: z, Type: namedzone.ZonePrimary, File: d.Args[0]})
+			}
+		case "secondary":
+			var primaries []namedzone.RemoteServerItem
+			for _, t := range d.Block {
+				if t.Name != "transfer" || len(t.Args) < 2 || t.Args[0] != "from" {
+					im.note("line %d: secondary %s not imported", t.Line, t.Name)
+					continue
+				}
+				for _, a := range t.Args[1:] {
+					it, err := remote(a)
+					if err != nil {
+						return errorf(t, "%v", err)
+					}
+					primaries = append(primaries, it)
+				}
+			}
+			for _, z := range directiveZones(d.Args, zones) {
+				im.zone(d, namedzone.Zone{Name: z, Type: namedzone.ZoneSecondary, Primaries: primaries})
+			}
+		case "transfer":
+			for _, t := range d.Block {
+				if t.Name != "to" {
+					continue
+				}
+				for _, a := range t.Args {
+					if a == "*" {
+						transferTo = append(transferTo, namedzone.MatchTerm{ACLRef: "any"})
+						continue
+					}
+					it, err := remote(a)
+					if err != nil {
+						return errorf(t, "%v", err)
+					}
+					transferTo = append(transferTo, namedzone.MatchTerm{Address: it.Address})
+				}
+			}
+			transferZones = directiveZones(d.Args, zones)
+		case "acl":
+			terms, err := im.acl(d)
+			if err != nil {
+				return err
+			}
+			if !slices.Equal(zones, []string{"."}) {
+				// Export repeats the global allow-query in every block
+				if reflect.DeepEqual(terms, im.cfg.Options.AllowQuery) {
+					continue
+				}
+				im.note("line %d: acl on %s not imported; allow-query is global in this model", d.Line, strings.Join(zones, " "))
+				continue
+			}
+			im.cfg.Options.AllowQuery = terms
+		case "cache", "errors", "log", "health", "ready", "reload", "loop", "loadbalance", "prometheus", "debug":
+			// named does these itself, or they have nothing to configure
+		default:
+			im.note("line %d: %s plugin not imported", d.Line, d.Name)
+		}
+	}
+	for _, name := range transferZones {
+		if z := im.cfg.GetZone(name); z != nil {
+			z.AllowTransfer = transferTo
+		}
+	}
+	return nil
+}
+
+// directiveZones returns the zones a directive names, or those of its block.
+func directiveZones(args, block []string) []string {
+	if len(args) == 0 {
+		return block
+	}
+	var out []string
+	for _, a := range args {
+		out = append(out, strings.ToLower(strings.TrimSuffix(a, ".")))
+	}
+	return out
+}
+
+// zone adds z unless a zone of that name was already imported.
+func (im *importer) zone(d directive, z namedzone.Zone) {
+	if z.Name == "." && z.Type != namedzone.ZoneForward {
+		im.note("line %d: %s for the root zone not imported", d.Line, d.Name)
+		return
+	}
+	if im.cfg.GetZone(z.Name) != nil {
+		im.note("line %d: zone %s already imported; %s ignored", d.Line, z.Name, d.Name)
+		return
+	}
+	im.cfg.UpsertZone(z)
+}
+
+// forward maps the forward plugin: the root of the root block sets the
+// global forwarders, anything else a forward zone.
+func (im *importer) forward(d directive, zones []string) error {
+	if len(d.Args) < 2 {
+		return errorf(d, "want a zone and upstreams")
+	}
+	tls := ""
+	for _, o := range d.Block {
+		switch o.Name {
+		case "tls_servername":
+			if len(o.Args) != 1 {
+				return errorf(o, "want a server name")
+			}
+			tls = "coredns-" + o.Args[0]
+			if !slices.ContainsFunc(im.cfg.TLS, func(t namedzone.TLS) bool { return t.Name == tls }) {
+				im.cfg.TLS = append(im.cfg.TLS, namedzone.TLS{Name: tls, RemoteHost: o.Args[0]})
+			}
+		default:
+			im.note("line %d: forward option %s not imported", o.Line, o.Name)
+		}
+	}
+	var fwd []namedzone.Forwarder
+	for _, a := range d.Args[1:] {
+		scheme, addr, ok := strings.Cut(a, "://")
+		if !ok {
+			scheme, addr = "dns", a
+		}
+		if scheme != "dns" && scheme != "tls" {
+			im.note("line %d: upstream %s not imported", d.Line, a)
+			continue
+		}
+		if strings.HasPrefix(addr, "/") {
+			im.note("line %d: upstreams from %s not imported", d.Line, addr)
+			continue
+		}
+		it, err := remote(addr)
+		if err != nil {
+			return errorf(d, "%v", err)
+		}
+		f := namedzone.Forwarder{Address: it.Address, Port: it.Port}
+		if scheme == "tls" {
+			f.TLS = cmp.Or(tls, "ephemeral")
+			if f.Port != nil && *f.Port == 853 {
+				f.Port = nil
+			}
+		}
+		fwd = append(fwd, f)
+	}
+
+	from := strings.ToLower(strings.TrimSuffix(d.Args[0], "."))
+	targets := zones
+	if from != "" {
+		targets = []string{from}
+	}
+	for _, z := range targets {
+		if z == "." || z == "" {
+			o := im.cfg.Options
+			for _, f := range fwd {
+				if !slices.ContainsFunc(o.Forwarders, func(g namedzone.Forwarder) bool { return reflect.DeepEqual(f, g) }) {
+					o.Forwarders = append(o.Forwarders, f)
+				}
+			}
+			o.Forward = "only"
+			continue
+		}
+		im.zone(d, namedzone.Zone{Name: z, Type: namedzone.ZoneForward, Forward: "only", Forwarders: fwd})
+	}
+	return nil
+}
+
+// remote parses addr, [addr]:port or addr:port.
+func remote(s string) (namedzone.RemoteServerItem, error) {
+	if a, err := netip.ParseAddr(s); err == nil {
+		return namedzone.RemoteServerItem{Address: a.String()}, nil
+	}
+	ap, err := netip.ParseAddrPort(s)
+	if err != nil {
+		return namedzone.RemoteServerItem{}, fmt.Errorf("invalid address %q", s)
+	}
+	it := namedzone.RemoteServerItem{Address: ap.Addr().String()}
+	if p := int(ap.Port()); p != 53 {
+		it.Port = &p
+	}
+	return it, nil
+}
+
+// acl maps the acl plugin to a match list. CoreDNS admits what no rule
+// matches, so a list without a final catch-all ends with any; filter and
+// drop are read as block.
+func (im *importer) acl(d directive) ([]namedzone.MatchTerm, error) {
+	var terms []namedzone.MatchTerm
+	for _, r := range d.Block {
+		var nets []string
+		all := true
+		for i := 0; i < len(r.Args); i++ {
+			switch r.Args[i] {
+			case "type":
+				for i+1 < len(r.Args) && r.Args[i+1] != "net" {
+					i++
+					if r.Args[i] != "*" {
+						all = false
+					}
+				}
+			case "net":
+				nets = append(nets, r.Args[i+1:]...)
+				i = len(r.Args)
+			}
+		}
+		if !all {
+			im.note("line %d: acl %s by query type not imported", r.Line, r.Name)
+			continue
+		}
+		allow := r.Name == "allow"
+		if !allow && r.Name != "block" && r.Name != "filter" && r.Name != "drop" {
+			return nil, errorf(r, "unknown acl action")
+		}
+		if len(nets) == 0 || slices.Contains(nets, "*") {
+			if allow {
+				terms = append(terms, namedzone.MatchTerm{ACLRef: "any"})
+			}
+			return orNone(terms), nil
+		}
+		for _, n := range nets {
+			if _, err := netip.ParsePrefix(n); err != nil {
+				if _, err := netip.ParseAddr(n); err != nil {
+					return nil, errorf(r, "invalid net %q", n)
+				}
+			}
+			terms = append(terms, namedzone.MatchTerm{Not: !allow, Address: n})
+		}
+	}
+	return append(terms, namedzone.MatchTerm{ACLRef: "any"}), nil
+}
+
+// orNone returns terms, or none when they are empty.
+func orNone(terms []namedzone.MatchTerm) []namedzone.MatchTerm {
+	if len(terms) == 0 {
+		return []namedzone.MatchTerm{{ACLRef: "none"}}
+	}
+	return terms
+}
+
+// listen sets listen-on from the first listener, plain DNS preferred.
+func (im *importer) listen() {
+	if len(im.listeners) == 0 {
+		return
+	}
+	i := max(slices.IndexFunc(im.listeners, func(l listener) bool { return l.scheme == "" }), 0)
+	l := im.listeners[i]
+	for j, other := range im.listeners {
+		if j != i {
+			im.note("listener %s:%d not imported; listen-on holds one listener per family", cmp.Or(other.scheme, "dns://"), other.port)
+		}
+	}
+	if l.scheme == "" && l.port == 53 && len(l.bind) == 0 {
+		return // named's default
+	}
+	tmpl := namedzone.Listen{TLS: l.tls}
+	if l.scheme == "https" {
+		tmpl.HTTP = "default"
+		tmpl.TLS = cmp.Or(l.tls, "ephemeral")
+	} else if l.scheme == "tls" {
+		tmpl.TLS = cmp.Or(l.tls, "ephemeral")
+	}
+	if p := map[string]int{"": 53, "tls": 853, "https": 443}[l.scheme]; l.port != p {
+		port := l.port
+		tmpl.Port = &port
+	}
+	v4, v6 := tmpl, tmpl
+	for _, a := range l.bind {
+		if netip.MustParseAddr(a).Is4() {
+			v4.Addrs = append(v4.Addrs, namedzone.MatchTerm{Address: a})
+		} else {
+			v6.Addrs = append(v6.Addrs, namedzone.MatchTerm{Address: a})
+		}
+	}
+	for _, p := range []*namedzone.Listen{&v4, &v6} {
+		switch {
+		case len(l.bind) == 0:
+			p.Addrs = []namedzone.MatchTerm{{ACLRef: "any"}}
+		case len(p.Addrs) == 0:
+			p.Addrs = []namedzone.MatchTerm{{ACLRef: "none"}}
+		}
+	}
+	im.cfg.Options.ListenOn, im.cfg.Options.ListenOnV6 = &v4, &v6
+}
@@ -0,0 +1,169 @@
+// File: pkg/namedzone/custom.go
+package namedzone
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// StatementHandler gives a top-level statement the typed model does not
+// cover, such as one added by a vendor patch, a typed representation.
+type StatementHandler struct {
+	// Parse turns a loaded statement into a value. An error leaves the
+	// statement untyped and raises a Warning.
+	Parse func(st *nc.Stmt) (any, error)
+	// Build turns a value back into a statement, e.g. with nc.NewBlockStmt.
+	Build func(v any) (*nc.Stmt, error)
+}
+
+var (
+	handlersMu sync.RWMutex
+	handlers   = map[string]StatementHandler{}
+)
+
+// RegisterStatement installs h for top-level statements with keyword. Load
+// then hands such statements to h.Parse, Config.Custom returns the values,
+// and Apply writes them back with h.Build. It panics if keyword is one the
+// typed model already covers or already has a handler, like sql.Register.
+func RegisterStatement(keyword string, h StatementHandler) {
+	keyword = strings.ToLower(keyword)
+	if h.Parse == nil || h.Build == nil {
+		panic("namedzone: RegisterStatement " + keyword + ": Parse and Build are required")
+	}
+	if slices.Contains(modeledKeywords, keyword) {
+		panic("namedzone: RegisterStatement " + keyword + ": statement is built in")
+	}
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	if _, dup := handlers[keyword]; dup {
+		panic("namedzone: RegisterStatement " + keyword + ": registered twice")
+	}
+	handlers[keyword] = h
+}
+
+func handlerFor(keyword string) (StatementHandler, bool) {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	h, ok := handlers[keyword]
+	return h, ok
+}
+
+// customItem is a value of a registered statement. A statement the handler
+// failed to parse is kept as is, with raw set.
+type customItem struct {
+	value  any
+	raw    bool
+	stmt   *nc.Stmt
+	origin string
+}
+
+func (it customItem) Origin() string  { return it.origin }
+func (it *customItem) ref() **nc.Stmt { return &it.stmt }
+
+// Custom returns the values of the statements with keyword, as parsed by
+// its registered handler, in file order.
+func (c *Config) Custom(keyword string) []any {
+	var out []any
+	for _, it := range c.custom[strings.ToLower(keyword)] {
+		if !it.raw {
+			out = append(out, it.value)
+		}
+	}
+	return out
+}
+
+// SetCustom replaces the values of the statements with keyword; Apply
+// writes them with the handler's Build. Values keep the statement (and file)
+// of the value at the same position, which stays untouched if it builds
+// the same. Statements the handler could not parse are left alone.
+func (c *Config) SetCustom(keyword string, values []any) error {
+	keyword = strings.ToLower(keyword)
+	if _, ok := handlerFor(keyword); !ok {
+		return &ValueError{Path: "custom", Value: keyword, Msg: "no statement handler registered for"}
+	}
+	var old, items []customItem
+	for _, it := range c.custom[keyword] {
+		if it.raw {
+			items = append(items, it)
+		} else {
+			old = append(old, it)
+		}
+	}
+	for i, v := range values {
+		it := customItem{value: v}
+		if i < len(old) {
+			it.stmt, it.origin = old[i].stmt, old[i].origin
+		}
+		items = append(items, it)
+	}
+	if c.custom == nil {
+		c.custom = map[string][]customItem{}
+	}
+	c.custom[keyword] = items
+	return nil
+}
+
+// parseCustom hands st to its registered handler, if there is one.
+func (ld *loader) parseCustom(cfg *Config, st *nc.Stmt) bool {
+	h, ok := handlerFor(st.Keyword)
+	if !ok {
+		return false
+	}
+	it := customItem{stmt: st, origin: ld.file}
+	var err error
+	if it.value, err = h.Parse(st); err != nil {
+		ld.warn(st, "statement handler: %v", err)
+		it.value, it.raw = nil, true
+	}
+	if cfg.custom == nil {
+		cfg.custom = map[string][]customItem{}
+	}
+	cfg.custom[st.Keyword] = append(cfg.custom[st.Keyword], it)
+	return true
+}
+
+// checkCustom builds every custom value once so Apply can fail before it
+// touches the AST.
+func (c *Config) checkCustom() error {
+	for _, kw := range slices.Sorted(maps.Keys(c.custom)) {
+		h, ok := handlerFor(kw)
+		if !ok {
+			continue
+		}
+		for i, it := range c.custom[kw] {
+			if it.raw {
+				continue
+			}
+			st, err := h.Build(it.value)
+			if err == nil && st == nil {
+				err = fmt.Errorf("Build returned no statement")
+			}
+			if err != nil {
+				return &ValueError{Path: fmt.Sprintf("custom[%s][%d]", kw, i), Msg: err.Error()}
+			}
+		}
+	}
+	return nil
+}
+
+// syncCustom writes the custom values; checkCustom has vetted them.
+func (c *Config) syncCustom(s syncer) {
+	for _, kw := range slices.Sorted(maps.Keys(c.custom)) {
+		h, ok := handlerFor(kw)
+		if !ok {
+			continue
+		}
+		syncBlocks(s, c.custom[kw], func(it customItem) *nc.Stmt {
+			if it.raw {
+				return it.stmt
+			}
+			st, _ := h.Build(it.value)
+			return st
+		}, kw)
+	}
+}
@@ -0,0 +1,244 @@
+// File: pkg/namedzone/diff.go
+package namedzone
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ChangeKind classifies an ItemDiff.
+type ChangeKind string
+
+const (
+	Added    ChangeKind = "added"
+	Removed  ChangeKind = "removed"
+	Modified ChangeKind = "modified"
+)
+
+// ConfigDiff is a semantic difference between two configs. It ignores
+// formatting, comments, the order of named items and raw options, and nil
+// versus empty values; the order inside lists that named evaluates in order
+// (match lists, forwarders) does count.
+type ConfigDiff struct {
+	// Items covers named things: zones (top-level and per view), views, ACLs,
+	// keys, key-stores, remote-servers, tls, http, log channels and categories.
+	Items []ItemDiff `json:"items,omitempty"`
+	// Settings covers everything else, e.g. options.recursion.
+	Settings []FieldChange `json:"settings,omitempty"`
+}
+
+// ItemDiff describes one named item that was added, removed or modified.
+type ItemDiff struct {
+	Kind   string        `json:"kind"` // zone, view, acl, key, ...
+	Name   string        `json:"name"`
+	Path   string        `json:"path"` // GetPath syntax, e.g. views[int].zones[example.com]
+	Change ChangeKind    `json:"change"`
+	Fields []FieldChange `json:"fields,omitempty"`
+
+	// ACL membership delta, set for modified ACLs.
+	MembersAdded   []MatchTerm `json:"membersAdded,omitempty"`
+	MembersRemoved []MatchTerm `json:"membersRemoved,omitempty"`
+}
+
+// FieldChange is a value that differs. Old or New is nil when the value is
+// unset on that side. Values are in JSON projection form.
+type FieldChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// Empty reports whether the configs are semantically equal.
+func (d *ConfigDiff) Empty() bool { return len(d.Items) == 0 && len(d.Settings) == 0 }
+
+// String renders the diff one change per line, for logs and drift reports.
+func (d *ConfigDiff) String() string {
+	var b strings.Builder
+	for _, it := range d.Items {
+		switch it.Change {
+		case Added:
+			fmt.Fprintf(&b, "+ %s %s\n", it.Kind, it.Path)
+		case Removed:
+			fmt.Fprintf(&b, "- %s %s\n", it.Kind, it.Path)
+		default:
+			fmt.Fprintf(&b, "~ %s %s\n", it.Kind, it.Path)
+			for _, f := range it.Fields {
+				fmt.Fprintf(&b, "    %s: %v -> %v\n", f.Path, f.Old, f.New)
+			}
+			for _, m := range it.MembersAdded {
+				fmt.Fprintf(&b, "    + %s\n", serializeMatchTerm(m))
+			}
+			for _, m := range it.MembersRemoved {
+				fmt.Fprintf(&b, "    - %s\n", serializeMatchTerm(m))
+			}
+		}
+	}
+	for _, f := range d.Settings {
+		fmt.Fprintf(&b, "~ %s: %v -> %v\n", f.Path, f.Old, f.New)
+	}
+	return b.String()
+}
+
+// Diff compares a (before) with b (after). A nil config counts as empty.
+func Diff(a, b *Config) *ConfigDiff {
+	if a == nil {
+		a = &Config{}
+	}
+	if b == nil {
+		b = &Config{}
+	}
+	da, errA := a.jsonDoc()
+	db, errB := b.jsonDoc()
+	d := &ConfigDiff{}
+	if errA != nil || errB != nil {
+		// the projection only fails to marshal on invalid Size/Duration text
+		d.Settings = append(d.Settings, FieldChange{Path: "", Old: fmt.Sprint(errA), New: fmt.Sprint(errB)})
+		return d
+	}
+	diffValue(d, nil, "", "", normalize(da), normalize(db))
+	for i := range d.Items {
+		if it := &d.Items[i]; it.Kind == "acl" && it.Change == Modified {
+			it.MembersAdded, it.MembersRemoved = memberDelta(findACL(a, it.Name), findACL(b, it.Name))
+		}
+	}
+	return d
+}
+
+var diffKinds = map[string]string{
+	"zones": "zone", "views": "view", "acls": "acl", "keys": "key",
+	"keyStores": "key-store", "remoteServers": "remote-servers", "tls": "tls",
+	"http": "http", "channels": "channel", "categories": "category",
+}
+
+// diffValue records differences between x and y at path. Changes go to item
+// when inside a named item, otherwise to d.Settings.
+func diffValue(d *ConfigDiff, item *ItemDiff, path, field string, x, y any) {
+	record := func(old, new any) {
+		fc := FieldChange{Path: path, Old: plainNumbers(old), New: plainNumbers(new)}
+		if item != nil {
+			fc.Path = strings.TrimPrefix(strings.TrimPrefix(path, item.Path), ".")
+			item.Fields = append(item.Fields, fc)
+		} else {
+			d.Settings = append(d.Settings, fc)
+		}
+	}
+	if kind, ok := diffKinds[field]; ok && objectList(x) && objectList(y) {
+		xa, _ := x.([]any)
+		ya, _ := y.([]any)
+		diffNamed(d, path, kind, xa, ya)
+		return
+	}
+	xm, xok := x.(map[string]any)
+	ym, yok := y.(map[string]any)
+	if !xok || !yok {
+		if !jsonEqual(x, y) {
+			record(x, y)
+		}
+		return
+	}
+	keys := make([]string, 0, len(xm)+len(ym))
+	for k := range xm {
+		keys = append(keys, k)
+	}
+	for k := range ym {
+		if _, ok := xm[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		diffValue(d, item, p, k, xm[k], ym[k])
+	}
+}
+
+func diffNamed(d *ConfigDiff, path, kind string, xs, ys []any) {
+	name := func(v any) string {
+		m, _ := v.(map[string]any)
+		s, _ := m["name"].(string)
+		return s
+	}
+	index := func(vs []any, n string) int {
+		return slices.IndexFunc(vs, func(v any) bool { return name(v) == n })
+	}
+	for _, y := range ys {
+		n := name(y)
+		it := ItemDiff{Kind: kind, Name: n, Path: path + "[" + n + "]"}
+		i := index(xs, n)
+		if i < 0 {
+			it.Change = Added
+			d.Items = append(d.Items, it)
+			continue
+		}
+		it.Change = Modified
+		at := len(d.Items)
+		d.Items = append(d.Items, it)
+		diffValue(d, &it, it.Path, "", xs[i], y)
+		// nested named items (view zones) were appended after it
+		if len(it.Fields) > 0 {
+			d.Items[at] = it
+		} else {
+			d.Items = slices.Delete(d.Items, at, at+1)
+		}
+	}
+	for _, x := range xs {
+		if n := name(x); index(ys, n) < 0 {
+			d.Items = append(d.Items, ItemDiff{Kind: kind, Name: n, Path: path + "[" + n + "]", Change: Removed})
+		}
+	}
+}
+
+// objectList reports whether v is absent or a list of objects; "keys" and
+// "tls" are also plain string fields in some blocks.
+func objectList(v any) bool {
+	if v == nil {
+		return true
+	}
+	vs, ok := v.([]any)
+	if !ok {
+		return false
+	}
+	for _, e := range vs {
+		if _, ok := e.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func findACL(c *Config, name string) []MatchTerm {
+	for _, a := range c.ACLs {
+		if a.Name == name {
+			return a.Elements
+		}
+	}
+	return nil
+}
+
+// memberDelta compares ACL elements as rendered text, counting duplicates.
+func memberDelta(old, cur []MatchTerm) (added, removed []MatchTerm) {
+	count := map[string]int{}
+	for _, m := range old {
+		count[serializeMatchTerm(m)]++
+	}
+	for _, m := range cur {
+		k := serializeMatchTerm(m)
+		if count[k] > 0 {
+			count[k]--
+			continue
+		}
+		added = append(added, m)
+	}
+	for _, m := range old {
+		k := serializeMatchTerm(m)
+		if count[k] > 0 {
+			count[k]--
+			removed = append(removed, m)
+		}
+	}
+	return added, removed
+}
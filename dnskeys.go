@@ -0,0 +1,267 @@
+// File: pkg/namedzone/dnskeys.go
+package namedzone
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dlukt/namedzone/zonefile"
+)
+
+// KeyScanOptions configures ScanKeys.
+type KeyScanOptions struct {
+	// FS reads the key directories; nil reads the OS filesystem. Within an
+	// FS, absolute paths are taken relative to its root, as for InspectTLS.
+	FS fs.FS
+	// Dirs are scanned in addition to the key-directory of every zone.
+	Dirs []string
+}
+
+// DNSKey is a key pair found in a key directory: K<zone>.+<alg>+<tag>.key
+// and, when present, the matching .private file.
+type DNSKey struct {
+	Zone          string `json:"zone"` // owner of the DNSKEY record, lower case without the trailing dot ("." for the root)
+	Algorithm     int    `json:"algorithm"`
+	AlgorithmName string `json:"algorithmName,omitempty"`
+	KeyTag        uint16 `json:"keyTag"`
+	Flags         uint16 `json:"flags"`
+
+	File    string `json:"file"`              // the .key file
+	Private string `json:"private,omitempty"` // the .private file, when it exists
+
+	// Timing metadata, from the .private file or else the comments of the
+	// .key file; zero when not set.
+	Created  time.Time `json:"created,omitzero"`
+	Publish  time.Time `json:"publish,omitzero"`
+	Activate time.Time `json:"activate,omitzero"`
+	Inactive time.Time `json:"inactive,omitzero"`
+	Delete   time.Time `json:"delete,omitzero"`
+	Revoke   time.Time `json:"revoke,omitzero"`
+
+	// Zones lists the GetPath paths of the zones that use the key: zones of
+	// that name whose key-directory holds it. Policy is the effective
+	// dnssec-policy of the first of them.
+	Zones  []string `json:"zones,omitempty"`
+	Policy string   `json:"policy,omitempty"`
+
+	Problems []string `json:"problems,omitempty"`
+}
+
+// KSK reports whether the key has the SEP flag, i.e. is a key-signing key.
+func (k DNSKey) KSK() bool { return k.Flags&0x0001 != 0 }
+
+// State returns the timing state of the key at now: "deleted", "inactive",
+// "active", "published" or "created".
+func (k DNSKey) State(now time.Time) string {
+	reached := func(t time.Time) bool { return !t.IsZero() && !now.Before(t) }
+	switch {
+	case reached(k.Delete):
+		return "deleted"
+	case reached(k.Inactive):
+		return "inactive"
+	case reached(k.Activate):
+		return "active"
+	case reached(k.Publish):
+		return "published"
+	}
+	return "created"
+}
+
+// ScanKeys reads the DNSSEC key files in the key-directory of every zone
+// (see InspectSigning) and in opts.Dirs, parses algorithm, key tag, flags
+// and timing metadata, and associates each key with the zones and
+// dnssec-policy that use it. Keys no zone uses are reported with no Zones.
+// Unreadable directories are returned as a joined error alongside the keys
+// that could be read.
+func (c *Config) ScanKeys(opts KeyScanOptions) ([]DNSKey, error) {
+	dfs := diskFS{fsys: opts.FS}
+	var dirs []string
+	for _, d := range opts.Dirs {
+		dirs = append(dirs, filepath.Clean(d))
+	}
+	for _, z := range c.AllZones() {
+		dirs = append(dirs, filepath.Clean(c.keyDirectory(z)))
+	}
+	slices.Sort(dirs)
+	dirs = slices.Compact(dirs)
+
+	var out []DNSKey
+	var errs []error
+	for _, dir := range dirs {
+		entries, err := dfs.readDir(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("namedzone: key directory %s: %w", dir, err))
+			continue
+		}
+		for _, de := range entries {
+			n := de.Name()
+			if de.IsDir() || !strings.HasPrefix(n, "K") || !strings.HasSuffix(n, ".key") {
+				continue
+			}
+			k := DNSKey{File: filepath.Join(dir, n)}
+			k.read(dfs)
+			c.useKey(&k, dir)
+			out = append(out, k)
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+// read parses the .key file of k and its .private file.
+func (k *DNSKey) read(dfs diskFS) {
+	problem := func(format string, args ...any) { k.Problems = append(k.Problems, fmt.Sprintf(format, args...)) }
+	base := strings.TrimSuffix(k.File, ".key")
+	if alg, tag, ok := keyFileName(filepath.Base(base)); ok {
+		k.Algorithm, k.KeyTag = alg, tag
+	}
+	data, err := dfs.read(k.File)
+	if err != nil {
+		problem("%v", err)
+		return
+	}
+	k.timing(data, true)
+	// key files written by dnssec-keygen carry no TTL unless one was asked for
+	zf, err := zonefile.Parse(io.MultiReader(strings.NewReader("$TTL 0\n"), bytes.NewReader(data)), ".")
+	if err != nil {
+		problem("%v", err)
+		return
+	}
+	var rr *zonefile.Record
+	for _, r := range zf.Records() {
+		if strings.EqualFold(r.Type, "DNSKEY") {
+			rr = &r
+			break
+		}
+	}
+	if rr == nil {
+		problem("no DNSKEY record in %s", k.File)
+		return
+	}
+	k.Zone = keyOwner(rr.Name)
+	f := strings.Fields(rr.Data)
+	if len(f) < 4 {
+		problem("malformed DNSKEY record")
+		return
+	}
+	flags, err1 := strconv.ParseUint(f[0], 10, 16)
+	proto, err2 := strconv.ParseUint(f[1], 10, 8)
+	alg, err3 := strconv.ParseUint(f[2], 10, 8)
+	pub, err4 := base64.StdEncoding.DecodeString(strings.Join(f[3:], ""))
+	if err := errors.Join(err1, err2, err3, err4); err != nil {
+		problem("malformed DNSKEY record: %v", err)
+		return
+	}
+	tag := keyTag(uint16(flags), uint8(proto), uint8(alg), pub)
+	if k.Algorithm != 0 && (k.Algorithm != int(alg) || k.KeyTag != tag) {
+		problem("file name says algorithm %d tag %d, record has algorithm %d tag %d", k.Algorithm, k.KeyTag, alg, tag)
+	}
+	k.Flags, k.Algorithm, k.KeyTag = uint16(flags), int(alg), tag
+	k.AlgorithmName = dnssecAlgorithms[k.Algorithm]
+
+	priv := base + ".private"
+	if data, err := dfs.read(priv); err == nil {
+		k.Private = priv
+		k.timing(data, false)
+	} else {
+		problem("no private key file")
+	}
+}
+
+// timing sets the timing metadata found in data: "; Publish: 2026..."
+// comment lines of a .key file, or "Publish: 2026..." lines of a .private
+// file. The .private file is read last, so its values win.
+func (k *DNSKey) timing(data []byte, comments bool) {
+	fields := map[string]*time.Time{
+		"Created": &k.Created, "Publish": &k.Publish, "Activate": &k.Activate,
+		"Inactive": &k.Inactive, "Delete": &k.Delete, "Revoke": &k.Revoke,
+	}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if comments {
+			var ok bool
+			if line, ok = strings.CutPrefix(line, ";"); !ok {
+				continue
+			}
+		}
+		name, val, ok := strings.Cut(strings.TrimSpace(line), ":")
+		dst := fields[name]
+		if !ok || dst == nil {
+			continue
+		}
+		val, _, _ = strings.Cut(strings.TrimSpace(val), " ")
+		if t, ok := sigTime(val); ok {
+			*dst = t
+		}
+	}
+}
+
+// useKey records the zones whose key-directory dir holds k.
+func (c *Config) useKey(k *DNSKey, dir string) {
+	for v, z := range c.AllZones() {
+		if keyOwner(z.Name) != k.Zone || filepath.Clean(c.keyDirectory(z)) != dir {
+			continue
+		}
+		view := ""
+		if v != nil {
+			view = v.Name
+		}
+		k.Zones = append(k.Zones, zonePath(view, z.Name))
+		if k.Policy == "" {
+			if e, err := c.EffectiveZoneSettings(view, z.Name); err == nil {
+				k.Policy = e.DNSSECPolicy.Value
+			}
+		}
+	}
+}
+
+// keyOwner normalizes a zone name as DNSKey.Zone holds it.
+func keyOwner(name string) string {
+	if name = strings.ToLower(strings.TrimSuffix(name, ".")); name == "" {
+		return "."
+	}
+	return name
+}
+
+// keyFileName splits the base name K<zone>.+<alg>+<tag>.
+func keyFileName(base string) (alg int, tag uint16, ok bool) {
+	i := strings.LastIndex(base, ".+")
+	if i < 0 {
+		return 0, 0, false
+	}
+	a, t, ok := strings.Cut(base[i+2:], "+")
+	if !ok {
+		return 0, 0, false
+	}
+	an, err1 := strconv.Atoi(a)
+	tn, err2 := strconv.ParseUint(t, 10, 16)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return an, uint16(tn), true
+}
+
+// keyTag computes the RFC 4034 Appendix B key tag of a DNSKEY.
+func keyTag(flags uint16, proto, alg uint8, pub []byte) uint16 {
+	rdata := append([]byte{byte(flags >> 8), byte(flags), proto, alg}, pub...)
+	var ac uint32
+	for i, b := range rdata {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+	ac += ac >> 16 & 0xFFFF
+	return uint16(ac & 0xFFFF)
+}
@@ -0,0 +1,334 @@
+// File: pkg/namedzone/dnsmasq/dnsmasq.go
+
+// Package dnsmasq imports a dnsmasq configuration into a namedzone.Config,
+// to assist moving DNS service from dnsmasq to named. server= lines become
+// global or per-domain forwarders, address= and local= domains become
+// primary zones answered from generated zone files, and listen-address and
+// port become listen-on. Options without a named equivalent, DHCP among
+// them, are reported as notes.
+package dnsmasq
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+	"github.com/dlukt/namedzone/zonefile"
+)
+
+// Options control Import.
+type Options struct {
+	// NameServer is the NS and SOA primary of the generated zones; empty
+	// means "localhost.".
+	NameServer string
+}
+
+// Result is an imported configuration.
+type Result struct {
+	Config *namedzone.Config
+	// Zones holds the data of each generated primary zone, keyed by the
+	// zone's file, for the caller to write to the options directory.
+	Zones map[string]*zonefile.Zone
+	// Notes list what was not imported.
+	Notes []string
+}
+
+// Import reads a dnsmasq.conf from r. Conf-file and conf-dir are not
+// followed. dnsmasq only forwards, so forwarders are set with forward only;
+// address=/domain/ip answers for the domain and every name below it, so its
+// zone gets the address at the apex and on a wildcard.
+func Import(r io.Reader, opts Options) (*Result, error) {
+	im := &importer{opts: opts, records: map[string][]zonefile.Record{}, seen: map[string]bool{}, port: 53}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if err := im.option(n, key, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return im.result()
+}
+
+type importer struct {
+	opts       Options
+	options    namedzone.Options
+	forwarders []namedzone.Forwarder
+	records    map[string][]zonefile.Record // by zone file
+	// order is the order zones were first seen in, so output is stable
+	order    []namedzone.Zone
+	listen   []string
+	port     int
+	ttl      uint32
+	noResolv bool
+	notes    []string
+	seen     map[string]bool // option keys already noted
+}
+
+func (im *importer) note(format string, args ...any) {
+	im.notes = append(im.notes, fmt.Sprintf(format, args...))
+}
+
+func (im *importer) option(n int, key, value string) error {
+	where := "line " + strconv.Itoa(n)
+	bad := func(msg string) error {
+		return &namedzone.ValueError{Path: where + "." + key, Value: value, Msg: msg}
+	}
+	switch key {
+	case "server", "local":
+		domains, addr, ok := domainSpec(value)
+		if !ok {
+			if key == "local" {
+				return bad("want /domain/")
+			}
+			f, err := forwarder(value)
+			if err != nil {
+				return bad(err.Error())
+			}
+			im.forwarders = append(im.forwarders, f)
+			return nil
+		}
+		for _, d := range domains {
+			switch {
+			case d == "":
+				im.note("%s: %s for unqualified names not imported", where, key)
+			case key == "local" || addr == "":
+				im.static(where, d)
+			case addr == "#":
+				im.note("%s: server=/%s/# (use the default servers) not imported", where, d)
+			default:
+				f, err := forwarder(addr)
+				if err != nil {
+					return bad(err.Error())
+				}
+				im.forward(where, d, f)
+			}
+		}
+	case "address":
+		domains, addr, ok := domainSpec(value)
+		if !ok {
+			return bad("want /domain/[address]")
+		}
+		var records []zonefile.Record
+		switch addr {
+		case "":
+		case "#":
+			records = []zonefile.Record{{Type: "A", Data: "0.0.0.0"}, {Type: "AAAA", Data: "::"}}
+		default:
+			a, err := netip.ParseAddr(addr)
+			if err != nil {
+				return bad("invalid address")
+			}
+			r := zonefile.Record{Type: "A", Data: a.String()}
+			if a.Is6() {
+				r.Type = "AAAA"
+			}
+			records = []zonefile.Record{r}
+		}
+		for _, d := range domains {
+			if d == "" || d == "#" {
+				im.note("%s: address=/%s/ not imported", where, d)
+				continue
+			}
+			var rr []zonefile.Record
+			for _, name := range []string{"@", "*"} {
+				for _, r := range records {
+					r.Name = name
+					rr = append(rr, r)
+				}
+			}
+			im.static(where, d, rr...)
+		}
+	case "listen-address":
+		for _, a := range strings.Split(value, ",") {
+			if _, err := netip.ParseAddr(strings.TrimSpace(a)); err != nil {
+				return bad("invalid address")
+			}
+			im.listen = append(im.listen, strings.TrimSpace(a))
+		}
+	case "interface":
+		im.note("%s: interface=%s not imported; named listens on addresses, add the interface's to listen-on", where, value)
+	case "port":
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 0 || p > 65535 {
+			return bad("invalid port")
+		}
+		if p == 0 {
+			im.note("%s: port=0 disables DNS in dnsmasq; named still listens on 53", where)
+			p = 53
+		}
+		im.port = p
+	case "local-ttl":
+		t, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return bad("invalid TTL")
+		}
+		im.ttl = uint32(t)
+	case "no-resolv":
+		im.noResolv = true
+	case "domain-needed", "bogus-priv", "bind-interfaces", "bind-dynamic", "strict-order":
+		// no named equivalent is needed: named never forwards unqualified
+		// names and answers from its own zones only
+	default:
+		if strings.HasPrefix(key, "dhcp") {
+			key = "dhcp-*"
+		}
+		if !im.seen[key] {
+			im.seen[key] = true
+			im.note("%s: %s not imported", where, key)
+		}
+	}
+	return nil
+}
+
+// domainSpec splits a /domain/.../value argument.
+func domainSpec(s string) (domains []string, value string, ok bool) {
+	if !strings.HasPrefix(s, "/") {
+		return nil, "", false
+	}
+	f := strings.Split(s[1:], "/")
+	if len(f) < 2 {
+		return nil, "", false
+	}
+	for _, d := range f[:len(f)-1] {
+		domains = append(domains, strings.ToLower(strings.TrimSuffix(d, ".")))
+	}
+	return domains, f[len(f)-1], true
+}
+
+// forwarder parses a server address: addr[#port], optionally followed by
+// @source or @interface, which named sets globally (query-source), so it is
+// dropped.
+func forwarder(s string) (namedzone.Forwarder, error) {
+	s, _, _ = strings.Cut(s, "@")
+	addr, port, hasPort := strings.Cut(s, "#")
+	a, err := netip.ParseAddr(addr)
+	if err != nil {
+		return namedzone.Forwarder{}, fmt.Errorf("invalid server address")
+	}
+	f := namedzone.Forwarder{Address: a.String()}
+	if hasPort {
+		p, err := strconv.Atoi(port)
+		if err != nil || p <= 0 || p > 65535 {
+			return namedzone.Forwarder{}, fmt.Errorf("invalid server port")
+		}
+		if p != 53 {
+			f.Port = &p
+		}
+	}
+	return f, nil
+}
+
+// zone returns the zone for domain, adding it with type t when new. A domain
+// used both for forwarding and for local answers keeps its first use.
+func (im *importer) zone(where, domain string, t namedzone.ZoneType) *namedzone.Zone {
+	for i := range im.order {
+		if z := &im.order[i]; z.Name == domain {
+			if z.Type != t {
+				im.note("%s: %s is already a %s zone; ignored", where, domain, z.Type)
+				return nil
+			}
+			return z
+		}
+	}
+	im.order = append(im.order, namedzone.Zone{Name: domain, Type: t})
+	return &im.order[len(im.order)-1]
+}
+
+func (im *importer) forward(where, domain string, f namedzone.Forwarder) {
+	if z := im.zone(where, domain, namedzone.ZoneForward); z != nil {
+		z.Forward = "only"
+		z.Forwarders = append(z.Forwarders, f)
+	}
+}
+
+// static adds records to the zone of a local domain, creating the zone.
+func (im *importer) static(where, domain string, records ...zonefile.Record) {
+	z := im.zone(where, domain, namedzone.ZonePrimary)
+	if z == nil {
+		return
+	}
+	z.File = "db." + domain
+	im.records[z.File] = append(im.records[z.File], records...)
+}
+
+// zoneData builds the zone file of a local domain: SOA, NS and its records.
+func (im *importer) zoneData(z namedzone.Zone) (*zonefile.Zone, error) {
+	ns := cmp.Or(im.opts.NameServer, "localhost.")
+	if !strings.HasSuffix(ns, ".") {
+		ns += "."
+	}
+	soa := ns + " hostmaster." + ns + " 1 86400 7200 3600000 " + strconv.FormatUint(uint64(im.ttl), 10)
+	zf := zonefile.New(z.Name+".", im.ttl)
+	records := append([]zonefile.Record{{Name: "@", Type: "SOA", Data: soa}, {Name: "@", Type: "NS", Data: ns}}, im.records[z.File]...)
+	for _, r := range records {
+		if err := zf.AddRecord(r); err != nil {
+			return nil, &namedzone.ValueError{Path: "zone[" + z.Name + "]", Value: r.String(), Msg: err.Error()}
+		}
+	}
+	zf.SetSerial(1)
+	return zf, nil
+}
+
+func (im *importer) result() (*Result, error) {
+	cfg := &namedzone.Config{Options: &im.options}
+	o := cfg.Options
+	o.Recursion = namedzone.BoolPtr(true)
+	if len(im.forwarders) > 0 {
+		o.Forwarders, o.Forward = im.forwarders, "only"
+	}
+	if !im.noResolv {
+		im.note("dnsmasq also forwards to the servers in /etc/resolv.conf (no no-resolv); add them to forwarders")
+	}
+
+	var v4, v6 []namedzone.MatchTerm
+	for _, a := range im.listen {
+		if netip.MustParseAddr(a).Is4() {
+			v4 = append(v4, namedzone.MatchTerm{Address: a})
+		} else {
+			v6 = append(v6, namedzone.MatchTerm{Address: a})
+		}
+	}
+	var port *int
+	if im.port != 53 {
+		port = &im.port
+	}
+	if len(im.listen) > 0 {
+		if v4 == nil {
+			v4 = []namedzone.MatchTerm{{ACLRef: "none"}}
+		}
+		if v6 == nil {
+			v6 = []namedzone.MatchTerm{{ACLRef: "none"}}
+		}
+		o.ListenOn, o.ListenOnV6 = &namedzone.Listen{Port: port, Addrs: v4}, &namedzone.Listen{Port: port, Addrs: v6}
+	} else if port != nil {
+		all := []namedzone.MatchTerm{{ACLRef: "any"}}
+		o.ListenOn, o.ListenOnV6 = &namedzone.Listen{Port: port, Addrs: all}, &namedzone.Listen{Port: port, Addrs: all}
+	}
+
+	res := &Result{Config: cfg, Zones: map[string]*zonefile.Zone{}}
+	for _, z := range im.order {
+		if z.Type == namedzone.ZonePrimary {
+			zf, err := im.zoneData(z)
+			if err != nil {
+				return nil, err
+			}
+			res.Zones[z.File] = zf
+		}
+		cfg.UpsertZone(z)
+	}
+	res.Notes = im.notes
+	return res, nil
+}
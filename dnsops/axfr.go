@@ -0,0 +1,211 @@
+// File: pkg/namedzone/dnsops/axfr.go
+package dnsops
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dlukt/namedzone"
+	"github.com/dlukt/namedzone/zonefile"
+	"github.com/miekg/dns"
+)
+
+// TransferFunc transfers zone from server ("addr:port") and returns its
+// records in transfer order.
+type TransferFunc func(ctx context.Context, server, zone string, key *TSIG) ([]dns.RR, error)
+
+// ImportOptions control Import.
+type ImportOptions struct {
+	// Primary is the server to transfer from, "addr" or "addr:port".
+	Primary string
+	// Key signs the transfers; its key block is added to the config.
+	Key *TSIG
+	// Zones to import. When empty they are discovered from the catalog
+	// zone named by Catalog.
+	Zones   []string
+	Catalog string
+	// Type of the generated zone statements: secondary (the default),
+	// transferring from Primary, or primary, serving the imported data.
+	Type namedzone.ZoneType
+	// View receives the zones; empty for top level.
+	View string
+	// File maps a zone to the file of its statement; nil means
+	// "db.<zone>" for primaries and "secondary/db.<zone>" for secondaries.
+	File func(zone string) string
+	// Dir, when set, receives a zone file with the transferred data of
+	// every zone, at File resolved against it. Primary zones need it.
+	Dir string
+	// Transfer performs the AXFR; nil uses AXFR.
+	Transfer TransferFunc
+}
+
+// ImportedZone is the outcome of importing one zone.
+type ImportedZone struct {
+	Name    string `json:"name"`
+	Serial  uint32 `json:"serial,omitempty"`
+	Records int    `json:"records"`
+	File    string `json:"file,omitempty"` // zone file written
+	Err     error  `json:"-"`
+}
+
+// Import transfers zones from an existing primary and adds the matching
+// zone statements to cfg (replacing zones of the same name), optionally
+// writing the transferred data as zone files. Zones that fail to transfer
+// are reported with their error and left out of cfg; the returned error
+// joins those errors.
+func Import(ctx context.Context, cfg *namedzone.Config, opts ImportOptions) ([]ImportedZone, error) {
+	transfer := opts.Transfer
+	if transfer == nil {
+		transfer = AXFR
+	}
+	typ := cmp.Or(opts.Type, namedzone.ZoneSecondary)
+	if typ != namedzone.ZoneSecondary && typ != namedzone.ZonePrimary {
+		return nil, &namedzone.ValueError{Path: "type", Value: string(typ), Msg: "import makes primary or secondary zones"}
+	}
+	if typ == namedzone.ZonePrimary && opts.Dir == "" {
+		return nil, &namedzone.ValueError{Path: "dir", Msg: "primary zones need a directory for their zone files"}
+	}
+	file := opts.File
+	if file == nil {
+		file = func(zone string) string {
+			if typ == namedzone.ZonePrimary {
+				return "db." + zone
+			}
+			return "secondary/db." + zone
+		}
+	}
+	server := hostPort(opts.Primary, 53)
+	zones := opts.Zones
+	if len(zones) == 0 {
+		if opts.Catalog == "" {
+			return nil, &namedzone.ValueError{Path: "zones", Msg: "no zones given and no catalog zone to discover them from"}
+		}
+		var err error
+		if zones, err = catalogMembers(ctx, transfer, server, opts.Catalog, opts.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	primary := namedzone.RemoteServerItem{Address: opts.Primary}
+	if host, port, err := net.SplitHostPort(opts.Primary); err == nil {
+		primary.Address = host
+		if p, _ := strconv.Atoi(port); p != 53 {
+			primary.Port = &p
+		}
+	}
+	if opts.Key != nil {
+		primary.Key = opts.Key.Name
+		if keyOf(cfg, opts.Key.Name) == nil {
+			cfg.Keys = append(cfg.Keys, namedzone.Key{Name: opts.Key.Name, Algorithm: opts.Key.Algorithm, Secret: opts.Key.Secret})
+		}
+	}
+
+	var out []ImportedZone
+	var errs []error
+	for _, name := range zones {
+		name = strings.TrimSuffix(name, ".")
+		res := ImportedZone{Name: name}
+		data, err := fetch(ctx, transfer, server, name, opts.Key)
+		if err == nil {
+			res.Serial, _ = data.Serial()
+			res.Records = len(data.Records())
+			if opts.Dir != "" {
+				res.File = filepath.Join(opts.Dir, file(name))
+				if err = os.MkdirAll(filepath.Dir(res.File), 0o755); err == nil {
+					err = data.Save(res.File)
+				}
+			}
+		}
+		if err != nil {
+			res.Err = fmt.Errorf("dnsops: import %s: %w", name, err)
+			errs = append(errs, res.Err)
+			out = append(out, res)
+			continue
+		}
+		z := namedzone.Zone{Name: name, Type: typ, File: file(name)}
+		if typ == namedzone.ZoneSecondary {
+			z.Primaries = []namedzone.RemoteServerItem{primary}
+		}
+		if opts.View == "" {
+			cfg.UpsertZone(z)
+		} else {
+			cfg.UpsertZoneInView(opts.View, z)
+		}
+		out = append(out, res)
+	}
+	return out, errors.Join(errs...)
+}
+
+// fetch transfers zone and returns it as zone file data.
+func fetch(ctx context.Context, transfer TransferFunc, server, zone string, key *TSIG) (*zonefile.Zone, error) {
+	rrs, err := transfer(ctx, server, zone, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rrs) == 0 || rrs[0].Header().Rrtype != dns.TypeSOA {
+		return nil, errors.New("transfer does not start with the SOA")
+	}
+	if last := len(rrs) - 1; last > 0 && rrs[last].Header().Rrtype == dns.TypeSOA {
+		rrs = rrs[:last]
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "$TTL %d\n", rrs[0].Header().Ttl)
+	for _, rr := range rrs {
+		sb.WriteString(rr.String())
+		sb.WriteByte('\n')
+	}
+	return zonefile.Parse(strings.NewReader(sb.String()), zone)
+}
+
+// catalogMembers returns the member zones of a catalog zone (RFC 9432).
+func catalogMembers(ctx context.Context, transfer TransferFunc, server, catalog string, key *TSIG) ([]string, error) {
+	rrs, err := transfer(ctx, server, catalog, key)
+	if err != nil {
+		return nil, fmt.Errorf("dnsops: catalog %s: %w", catalog, err)
+	}
+	suffix := ".zones." + strings.ToLower(dns.Fqdn(catalog))
+	var out []string
+	for _, rr := range rrs {
+		ptr, ok := rr.(*dns.PTR)
+		if !ok {
+			continue
+		}
+		// members are <id>.zones.<catalog>; properties have more labels
+		if id, ok := strings.CutSuffix(strings.ToLower(ptr.Hdr.Name), suffix); ok && !strings.Contains(id, ".") {
+			out = append(out, strings.TrimSuffix(ptr.Ptr, "."))
+		}
+	}
+	return out, nil
+}
+
+// AXFR transfers zone from server with a TCP zone transfer, signed with key
+// when one is given.
+func AXFR(ctx context.Context, server, zone string, key *TSIG) ([]dns.RR, error) {
+	m := new(dns.Msg)
+	m.SetAxfr(dns.Fqdn(zone))
+	t := &dns.Transfer{DialTimeout: timeout(ctx, 10*time.Second), ReadTimeout: timeout(ctx, time.Minute)}
+	t.TsigSecret = sign(m, key)
+	ch, err := t.In(m, server)
+	if err != nil {
+		return nil, err
+	}
+	var rrs []dns.RR
+	for env := range ch {
+		if env.Error != nil {
+			return nil, env.Error
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rrs = append(rrs, env.RR...)
+	}
+	return rrs, nil
+}
@@ -0,0 +1,263 @@
+// File: pkg/namedzone/dnsops/delegation.go
+package dnsops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+	"github.com/dlukt/namedzone/zonefile"
+	"github.com/miekg/dns"
+)
+
+// DelegationReport compares the NS records of a primary zone with its
+// delegation at the parent. Names are absolute and lower case.
+type DelegationReport struct {
+	View     string   `json:"view,omitempty"`
+	Zone     string   `json:"zone"`
+	Parent   string   `json:"parent,omitempty"`
+	ZoneNS   []string `json:"zoneNS"`
+	ParentNS []string `json:"parentNS"`
+	// MissingAtParent are name servers of the zone the parent does not
+	// delegate to; ExtraAtParent the reverse.
+	MissingAtParent []string `json:"missingAtParent,omitempty"`
+	ExtraAtParent   []string `json:"extraAtParent,omitempty"`
+	// MissingGlue are in-zone name servers the parent gives no address for;
+	// GlueMismatch those whose glue differs from the zone's addresses.
+	MissingGlue  []string `json:"missingGlue,omitempty"`
+	GlueMismatch []string `json:"glueMismatch,omitempty"`
+	OK           bool     `json:"ok"`
+	Err          error    `json:"-"`
+}
+
+// DelegationOptions control CheckDelegations.
+type DelegationOptions struct {
+	// Dir resolves relative zone file paths; empty means the options
+	// directory of the config.
+	Dir string
+	// Local, when set, is asked for the NS records of zones whose file
+	// cannot be read ("addr" or "addr:port").
+	Local string
+	// Resolver is a recursive server used to find the parent zone and its
+	// servers; empty means the first nameserver of /etc/resolv.conf.
+	Resolver string
+	// Exchange sends the queries; nil uses Exchange.
+	Exchange Exchanger
+}
+
+// CheckDelegations compares, for every primary zone of cfg (in views too),
+// the apex NS records of its zone file (with $INCLUDE and $GENERATE) or of
+// the data the local server serves, with the referral one of the parent
+// zone's servers gives, reporting name servers missing on either side and
+// missing or mismatched glue for in-zone name servers.
+func CheckDelegations(ctx context.Context, cfg *namedzone.Config, opts DelegationOptions) []DelegationReport {
+	exchange := opts.Exchange
+	if exchange == nil {
+		exchange = Exchange
+	}
+	resolver := opts.Resolver
+	if resolver == "" {
+		if cc, err := dns.ClientConfigFromFile("/etc/resolv.conf"); err == nil && len(cc.Servers) > 0 {
+			resolver = net.JoinHostPort(cc.Servers[0], cc.Port)
+		}
+	}
+	dir := opts.Dir
+	if dir == "" && cfg.Options != nil {
+		dir = cfg.Options.Directory
+	}
+	var out []DelegationReport
+	for v, z := range cfg.AllZones() {
+		if z.Type != namedzone.ZonePrimary {
+			continue
+		}
+		r := DelegationReport{Zone: strings.ToLower(dns.Fqdn(z.Name))}
+		if v != nil {
+			r.View = v.Name
+		}
+		r.Err = r.check(ctx, exchange, resolver, opts.Local, zoneData(dir, z))
+		r.OK = r.Err == nil && len(r.MissingAtParent)+len(r.ExtraAtParent)+len(r.MissingGlue)+len(r.GlueMismatch) == 0
+		out = append(out, r)
+	}
+	return out
+}
+
+// zoneData loads the zone file of z, or returns nil.
+func zoneData(dir string, z *namedzone.Zone) *zonefile.Zone {
+	if z.File == "" {
+		return nil
+	}
+	path := z.File
+	if !filepath.IsAbs(path) && dir != "" {
+		path = filepath.Join(dir, path)
+	}
+	data, err := zonefile.LoadTree(path, z.Name, dir)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (r *DelegationReport) check(ctx context.Context, exchange Exchanger, resolver, local string, data *zonefile.Zone) error {
+	addrs := map[string][]string{} // in-zone name server -> addresses from the zone
+	switch {
+	case data != nil:
+		for _, rec := range data.AllRecords() {
+			name := strings.ToLower(rec.Name)
+			switch {
+			case rec.Type == "NS" && name == r.Zone:
+				r.ZoneNS = append(r.ZoneNS, strings.ToLower(rec.Data))
+			case rec.Type == "A" || rec.Type == "AAAA":
+				if a, err := netip.ParseAddr(rec.Data); err == nil {
+					addrs[name] = append(addrs[name], a.String())
+				}
+			}
+		}
+	case local != "":
+		ans, err := query(ctx, exchange, hostPort(local, 53), r.Zone, dns.TypeNS, false)
+		if err != nil {
+			return err
+		}
+		for _, rr := range ans.Answer {
+			if ns, ok := rr.(*dns.NS); ok {
+				r.ZoneNS = append(r.ZoneNS, strings.ToLower(ns.Ns))
+			}
+		}
+		for _, ns := range r.ZoneNS {
+			if inZone(ns, r.Zone) {
+				addrs[ns] = lookupAddrs(ctx, exchange, hostPort(local, 53), ns, false)
+			}
+		}
+	default:
+		return errors.New("dnsops: zone file not readable and no local server to ask")
+	}
+	if resolver == "" {
+		return errors.New("dnsops: no resolver to find the parent zone")
+	}
+
+	parent, servers, err := parentServers(ctx, exchange, resolver, r.Zone)
+	if err != nil {
+		return err
+	}
+	r.Parent = parent
+	var referral *dns.Msg
+	for _, s := range servers {
+		if referral, err = query(ctx, exchange, s, r.Zone, dns.TypeNS, false); err == nil {
+			break
+		}
+	}
+	if referral == nil {
+		return fmt.Errorf("dnsops: no server of %s answered: %w", parent, err)
+	}
+	glue := map[string][]string{}
+	for _, rr := range append(append([]dns.RR{}, referral.Ns...), referral.Answer...) {
+		if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, r.Zone) && !slices.Contains(r.ParentNS, strings.ToLower(ns.Ns)) {
+			r.ParentNS = append(r.ParentNS, strings.ToLower(ns.Ns))
+		}
+	}
+	for _, rr := range referral.Extra {
+		switch a := rr.(type) {
+		case *dns.A:
+			glue[strings.ToLower(a.Hdr.Name)] = append(glue[strings.ToLower(a.Hdr.Name)], a.A.String())
+		case *dns.AAAA:
+			glue[strings.ToLower(a.Hdr.Name)] = append(glue[strings.ToLower(a.Hdr.Name)], a.AAAA.String())
+		}
+	}
+
+	r.MissingAtParent = minus(r.ZoneNS, r.ParentNS)
+	r.ExtraAtParent = minus(r.ParentNS, r.ZoneNS)
+	for _, ns := range r.ParentNS {
+		if !inZone(ns, r.Zone) {
+			continue
+		}
+		switch have, want := glue[ns], addrs[ns]; {
+		case len(have) == 0:
+			r.MissingGlue = append(r.MissingGlue, ns)
+		case len(want) > 0 && !sameSet(have, want):
+			r.GlueMismatch = append(r.GlueMismatch, ns)
+		}
+	}
+	return nil
+}
+
+// parentServers finds the zone above zone through the resolver and returns
+// it with the addresses of its name servers.
+func parentServers(ctx context.Context, exchange Exchanger, resolver, zone string) (string, []string, error) {
+	labels := dns.SplitDomainName(zone)
+	for i := 1; i <= len(labels); i++ {
+		parent := dns.Fqdn(strings.Join(labels[i:], "."))
+		ans, err := query(ctx, exchange, resolver, parent, dns.TypeNS, true)
+		if err != nil {
+			return "", nil, err
+		}
+		var servers []string
+		for _, rr := range ans.Answer {
+			if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, parent) {
+				for _, a := range lookupAddrs(ctx, exchange, resolver, ns.Ns, true) {
+					servers = append(servers, net.JoinHostPort(a, "53"))
+				}
+			}
+		}
+		if len(servers) > 0 {
+			return parent, servers, nil
+		}
+	}
+	return "", nil, fmt.Errorf("dnsops: no parent zone found for %s", zone)
+}
+
+func lookupAddrs(ctx context.Context, exchange Exchanger, server, name string, rd bool) []string {
+	var out []string
+	for _, t := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		ans, err := query(ctx, exchange, server, name, t, rd)
+		if err != nil {
+			continue
+		}
+		for _, rr := range ans.Answer {
+			switch a := rr.(type) {
+			case *dns.A:
+				out = append(out, a.A.String())
+			case *dns.AAAA:
+				out = append(out, a.AAAA.String())
+			}
+		}
+	}
+	return out
+}
+
+func query(ctx context.Context, exchange Exchanger, server, name string, t uint16, rd bool) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), t)
+	m.RecursionDesired = rd
+	ans, err := exchange(ctx, server, m, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ans.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("dnsops: %s %s at %s: %s", name, dns.TypeToString[t], server, dns.RcodeToString[ans.Rcode])
+	}
+	return ans, nil
+}
+
+func inZone(name, zone string) bool {
+	return dns.IsSubDomain(zone, name)
+}
+
+// minus returns the elements of a not in b.
+func minus(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func sameSet(a, b []string) bool {
+	return len(minus(a, b)) == 0 && len(minus(b, a)) == 0
+}
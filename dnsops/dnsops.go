@@ -0,0 +1,79 @@
+// File: pkg/namedzone/dnsops/dnsops.go
+
+// Package dnsops talks DNS to running servers on behalf of a namedzone
+// Config: importing zones by AXFR and checking that what the config
+// describes is what the servers actually do. It is kept apart from
+// namedzone so that the config model itself needs no DNS library.
+package dnsops
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/dlukt/namedzone"
+	"github.com/miekg/dns"
+)
+
+// TSIG is a transaction signature key, as in a named.conf key block.
+type TSIG struct {
+	Name      string `json:"name"`
+	Algorithm string `json:"algorithm"` // e.g. "hmac-sha256"
+	Secret    string `json:"secret"`    // base64
+}
+
+// hostPort adds the DNS port to an address given without one.
+func hostPort(addr string, port int) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(addr, strconv.Itoa(port))
+}
+
+// timeout is the time left until the deadline of ctx, or def.
+func timeout(ctx context.Context, def time.Duration) time.Duration {
+	if d, ok := ctx.Deadline(); ok {
+		return time.Until(d)
+	}
+	return def
+}
+
+// sign adds the TSIG record of k to m and returns the secret map a client
+// or transfer needs to verify the answer; nil without a key.
+func sign(m *dns.Msg, k *TSIG) map[string]string {
+	if k == nil {
+		return nil
+	}
+	name := dns.Fqdn(k.Name)
+	m.SetTsig(name, dns.Fqdn(k.Algorithm), 300, time.Now().Unix())
+	return map[string]string{name: k.Secret}
+}
+
+// Exchanger sends m to server ("addr:port") and returns the answer, signing
+// with key when one is given. Checks take one so tests and callers with
+// special transports can replace the network.
+type Exchanger func(ctx context.Context, server string, m *dns.Msg, key *TSIG) (*dns.Msg, error)
+
+// Exchange is the default Exchanger: UDP, retried over TCP when the answer
+// is truncated.
+func Exchange(ctx context.Context, server string, m *dns.Msg, key *TSIG) (*dns.Msg, error) {
+	c := &dns.Client{Timeout: timeout(ctx, 5*time.Second)}
+	c.TsigSecret = sign(m, key)
+	r, _, err := c.ExchangeContext(ctx, m, server)
+	if err == nil && r.Truncated {
+		c.Net = "tcp"
+		r, _, err = c.ExchangeContext(ctx, m, server)
+	}
+	return r, err
+}
+
+// keyOf returns the key block name of cfg as a TSIG, or nil.
+func keyOf(cfg *namedzone.Config, name string) *TSIG {
+	for _, k := range cfg.Keys {
+		if k.Name == name && name != "" {
+			return &TSIG{Name: k.Name, Algorithm: k.Algorithm, Secret: k.Secret}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// File: pkg/namedzone/dnsops/forwarders.go
+package dnsops
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dlukt/namedzone"
+	"github.com/miekg/dns"
+)
+
+// ForwarderReport is the result of probing one forwarder over one
+// transport.
+type ForwarderReport struct {
+	Scope     string        `json:"scope"` // "options" or a zone path such as view[int].zone[example.com]
+	Server    string        `json:"server"`
+	Transport string        `json:"transport"` // "udp", "tcp" or "tls" (DoT)
+	Query     string        `json:"query"`
+	OK        bool          `json:"ok"`
+	Rcode     string        `json:"rcode,omitempty"`
+	Latency   time.Duration `json:"latency,omitempty"`
+	Err       error         `json:"-"`
+}
+
+// ProbeFunc sends m to server over transport ("udp", "tcp" or "tcp-tls",
+// with tlsConf) and returns the answer and the round-trip time.
+type ProbeFunc func(ctx context.Context, transport, server string, m *dns.Msg, tlsConf *tls.Config) (*dns.Msg, time.Duration, error)
+
+// ForwarderOptions control CheckForwarders.
+type ForwarderOptions struct {
+	// Name queried at forwarders of options; zone forwarders are asked for
+	// the SOA of their zone. Empty means the root (".").
+	Name string
+	// Probe sends the queries; nil uses Probe.
+	Probe ProbeFunc
+}
+
+// CheckForwarders probes every forwarder of cfg, in options and in zones
+// (in views too), with a recursive SOA query: over UDP and TCP, or over
+// TLS (DoT) when the forwarder names a tls block, whose remote-hostname and
+// ca-file are used as named uses them. A forwarder is OK on a transport
+// when it answers NOERROR or NXDOMAIN.
+func CheckForwarders(ctx context.Context, cfg *namedzone.Config, opts ForwarderOptions) []ForwarderReport {
+	probe := opts.Probe
+	if probe == nil {
+		probe = Probe
+	}
+	var out []ForwarderReport
+	check := func(scope, name string, fwd []namedzone.Forwarder) {
+		for _, f := range fwd {
+			transports, port := []string{"udp", "tcp"}, 53
+			var tlsConf *tls.Config
+			var tlsErr error
+			if f.TLS != "" && f.TLS != "none" {
+				transports, port = []string{"tcp-tls"}, 853
+				tlsConf, tlsErr = clientTLS(cfg, f.TLS)
+			}
+			if f.Port != nil {
+				port = *f.Port
+			}
+			server := net.JoinHostPort(f.Address, strconv.Itoa(port))
+			for _, tr := range transports {
+				r := ForwarderReport{Scope: scope, Server: server, Transport: tr, Query: name}
+				if tr == "tcp-tls" {
+					r.Transport = "tls"
+				}
+				if tlsErr != nil {
+					r.Err = tlsErr
+					out = append(out, r)
+					continue
+				}
+				m := new(dns.Msg)
+				m.SetQuestion(dns.Fqdn(name), dns.TypeSOA)
+				ans, rtt, err := probe(ctx, tr, server, m, tlsConf)
+				r.Latency, r.Err = rtt, err
+				if err == nil {
+					r.Rcode = dns.RcodeToString[ans.Rcode]
+					r.OK = ans.Rcode == dns.RcodeSuccess || ans.Rcode == dns.RcodeNameError
+					if !r.OK {
+						r.Err = errors.New("dnsops: forwarder answered " + r.Rcode)
+					}
+				}
+				out = append(out, r)
+			}
+		}
+	}
+	name := opts.Name
+	if name == "" {
+		name = "."
+	}
+	if cfg.Options != nil {
+		check("options", name, cfg.Options.Forwarders)
+	}
+	for v, z := range cfg.AllZones() {
+		scope := "zone[" + z.Name + "]"
+		if v != nil {
+			scope = "view[" + v.Name + "]." + scope
+		}
+		check(scope, z.Name, z.Forwarders)
+	}
+	return out
+}
+
+// clientTLS builds the client side of the tls block name: server name from
+// remote-hostname and verification against ca-file. Without a ca-file named
+// does not verify the server, and neither does the probe.
+func clientTLS(cfg *namedzone.Config, name string) (*tls.Config, error) {
+	conf := &tls.Config{InsecureSkipVerify: true}
+	if name == "ephemeral" {
+		return conf, nil
+	}
+	for _, t := range cfg.TLS {
+		if t.Name != name {
+			continue
+		}
+		conf.ServerName = t.RemoteHost
+		if t.CAFile != "" {
+			pem, err := os.ReadFile(t.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			conf.RootCAs = x509.NewCertPool()
+			if !conf.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, errors.New("dnsops: no certificates in " + t.CAFile)
+			}
+			conf.InsecureSkipVerify = false
+		}
+		if t.CertFile != "" && t.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			conf.Certificates = []tls.Certificate{cert}
+		}
+		return conf, nil
+	}
+	return nil, &namedzone.ReferenceError{Kind: "tls", Name: name, Path: "tls"}
+}
+
+// Probe is the default ProbeFunc.
+func Probe(ctx context.Context, transport, server string, m *dns.Msg, tlsConf *tls.Config) (*dns.Msg, time.Duration, error) {
+	c := &dns.Client{Net: transport, TLSConfig: tlsConf, Timeout: timeout(ctx, 5*time.Second)}
+	return c.ExchangeContext(ctx, m, server)
+}
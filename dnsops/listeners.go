@@ -0,0 +1,246 @@
+// File: pkg/namedzone/dnsops/listeners.go
+package dnsops
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/dlukt/namedzone"
+	"github.com/miekg/dns"
+)
+
+// ListenerReport is the result of probing one encrypted or HTTP listener
+// at one address.
+type ListenerReport struct {
+	Family    string `json:"family"`    // "ipv4" or "ipv6"
+	Transport string `json:"transport"` // "tls" (DoT), "https" (DoH) or "http"
+	Server    string `json:"server"`
+	TLS       string `json:"tls,omitempty"`
+	HTTP      string `json:"http,omitempty"`
+
+	// Handshake is true once the TLS handshake completed; Protocol is the
+	// negotiated ALPN protocol or, for DoH, the HTTP version.
+	Handshake bool   `json:"handshake"`
+	Protocol  string `json:"protocol,omitempty"`
+	// CertMatch reports whether the server presented the certificate of
+	// the tls block's cert-file; nil when not compared (ephemeral).
+	CertMatch *bool `json:"certMatch,omitempty"`
+
+	OK      bool          `json:"ok"` // the test query was answered
+	Rcode   string        `json:"rcode,omitempty"`
+	Latency time.Duration `json:"latency,omitempty"`
+	Err     error         `json:"-"`
+}
+
+// ListenerOptions control CheckListeners.
+type ListenerOptions struct {
+	// Name is queried (SOA); empty means the root (".").
+	Name string
+	// Dial opens connections; nil uses a net.Dialer.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// CheckListeners probes every listen-on and listen-on-v6 of cfg that names
+// a tls or http block: at each listed address (the loopback address for
+// "any" and other match lists) it completes the TLS handshake, compares the
+// certificate presented with the tls block's cert-file, and sends a test
+// query over DoT, or over DoH to the first endpoint of the http block.
+func CheckListeners(ctx context.Context, cfg *namedzone.Config, opts ListenerOptions) []ListenerReport {
+	dial := opts.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	name := opts.Name
+	if name == "" {
+		name = "."
+	}
+	var out []ListenerReport
+	for _, l := range cfg.Summary().Listeners {
+		if l.Transport == "dns" {
+			continue
+		}
+		for _, addr := range listenerAddrs(l) {
+			r := ListenerReport{Family: l.Family, Transport: l.Transport, Server: net.JoinHostPort(addr, strconv.Itoa(l.Port)), TLS: l.TLS, HTTP: l.HTTP}
+			r.Err = r.probe(ctx, cfg, dial, name)
+			r.OK = r.Err == nil
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// listenerAddrs returns the addresses to probe a listener at: the plain
+// addresses it lists, or the loopback address of its family.
+func listenerAddrs(l namedzone.ListenerSummary) []string {
+	var out []string
+	for _, a := range l.Addrs {
+		if ip, err := netip.ParseAddr(a); err == nil && !ip.IsUnspecified() {
+			out = append(out, ip.String())
+		}
+	}
+	if len(out) == 0 {
+		if l.Family == "ipv6" {
+			return []string{"::1"}
+		}
+		return []string{"127.0.0.1"}
+	}
+	return slices.Compact(out)
+}
+
+func (r *ListenerReport) probe(ctx context.Context, cfg *namedzone.Config, dial func(context.Context, string, string) (net.Conn, error), name string) error {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), dns.TypeSOA)
+	var want []byte
+	conf := &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"dot"}}
+	if r.Transport != "http" {
+		var err error
+		if want, conf.ServerName, err = listenerCert(cfg, r.TLS); err != nil {
+			return err
+		}
+	}
+	start := time.Now()
+	conn, err := dial(ctx, "tcp", r.Server)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if d, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(d)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	}
+
+	var tc *tls.Conn
+	if r.Transport != "http" {
+		if r.Transport == "https" {
+			conf.NextProtos = []string{"h2"}
+		}
+		tc = tls.Client(conn, conf)
+		if err := tc.HandshakeContext(ctx); err != nil {
+			return err
+		}
+		st := tc.ConnectionState()
+		r.Handshake, r.Protocol = true, st.NegotiatedProtocol
+		if want != nil {
+			match := len(st.PeerCertificates) > 0 && bytes.Equal(st.PeerCertificates[0].Raw, want)
+			r.CertMatch = &match
+			if !match {
+				return errors.New("dnsops: server certificate differs from the tls block's cert-file")
+			}
+		}
+	}
+
+	var ans *dns.Msg
+	if r.Transport == "tls" {
+		dc := &dns.Conn{Conn: tc}
+		if err = dc.WriteMsg(m); err == nil {
+			ans, err = dc.ReadMsg()
+		}
+	} else {
+		ans, err = r.doh(ctx, cfg, conn, tc, m)
+	}
+	r.Latency = time.Since(start)
+	if err != nil {
+		return err
+	}
+	r.Rcode = dns.RcodeToString[ans.Rcode]
+	if ans.Rcode != dns.RcodeSuccess && ans.Rcode != dns.RcodeNameError && ans.Rcode != dns.RcodeRefused {
+		return errors.New("dnsops: listener answered " + r.Rcode)
+	}
+	return nil
+}
+
+// doh sends m as a DoH POST over the connection already open.
+func (r *ListenerReport) doh(ctx context.Context, cfg *namedzone.Config, conn net.Conn, tc *tls.Conn, m *dns.Msg) (*dns.Msg, error) {
+	endpoint := "/dns-query"
+	for _, h := range cfg.HTTP {
+		if h.Name == r.HTTP && len(h.Endpoints) > 0 {
+			endpoint = h.Endpoints[0]
+		}
+	}
+	wire, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+	scheme, tr := "http", &http.Transport{}
+	used := false
+	take := func() (net.Conn, error) {
+		if used {
+			return nil, errors.New("dnsops: probe connection already used")
+		}
+		used = true
+		if tc != nil {
+			return tc, nil
+		}
+		return conn, nil
+	}
+	if tc != nil {
+		scheme = "https"
+		tr.ForceAttemptHTTP2 = true
+		tr.DialTLSContext = func(context.Context, string, string) (net.Conn, error) { return take() }
+		if r.Protocol == "h2" {
+			tr.Protocols = new(http.Protocols)
+			tr.Protocols.SetHTTP2(true)
+		}
+	} else {
+		tr.DialContext = func(context.Context, string, string) (net.Conn, error) { return take() }
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scheme+"://"+r.Server+endpoint, bytes.NewReader(wire))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if tc != nil {
+		r.Protocol = resp.Proto
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dnsops: DoH endpoint %s answered %s", endpoint, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 65535))
+	if err != nil {
+		return nil, err
+	}
+	ans := new(dns.Msg)
+	return ans, ans.Unpack(body)
+}
+
+// listenerCert returns the DER leaf certificate of the tls block name and
+// its remote-hostname; the certificate is nil for ephemeral.
+func listenerCert(cfg *namedzone.Config, name string) ([]byte, string, error) {
+	if name == "ephemeral" {
+		return nil, "", nil
+	}
+	for _, t := range cfg.TLS {
+		if t.Name != name {
+			continue
+		}
+		data, err := os.ReadFile(t.CertFile)
+		if err != nil {
+			return nil, "", err
+		}
+		b, _ := pem.Decode(data)
+		if b == nil || b.Type != "CERTIFICATE" {
+			return nil, "", errors.New("dnsops: no certificate in " + t.CertFile)
+		}
+		return b.Bytes, t.RemoteHost, nil
+	}
+	return nil, "", &namedzone.ReferenceError{Kind: "tls", Name: name, Path: "tls"}
+}
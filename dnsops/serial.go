@@ -0,0 +1,136 @@
+// File: pkg/namedzone/dnsops/serial.go
+package dnsops
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/dlukt/namedzone"
+	"github.com/miekg/dns"
+)
+
+// SerialStatus classifies the serial of a secondary zone.
+type SerialStatus string
+
+const (
+	SerialOK        SerialStatus = "ok"        // local serial equals the newest primary serial
+	SerialLagging   SerialStatus = "lagging"   // local serial is behind a primary
+	SerialRegressed SerialStatus = "regressed" // a primary is behind the local copy; transfers stop
+	SerialUnknown   SerialStatus = "unknown"   // the local server or every primary failed to answer
+)
+
+// SerialReport is the serial state of one secondary zone.
+type SerialReport struct {
+	View      string          `json:"view,omitempty"`
+	Zone      string          `json:"zone"`
+	Status    SerialStatus    `json:"status"`
+	Local     *uint32         `json:"local,omitempty"`
+	LocalErr  error           `json:"-"`
+	Primaries []PrimarySerial `json:"primaries"`
+}
+
+// PrimarySerial is the serial one primary serves.
+type PrimarySerial struct {
+	Server string  `json:"server"`
+	Serial *uint32 `json:"serial,omitempty"`
+	Err    error   `json:"-"`
+}
+
+// SerialOptions control CheckSerials.
+type SerialOptions struct {
+	// Local is the server holding the secondary copies; empty means
+	// "127.0.0.1:53".
+	Local string
+	// LocalKey signs the queries to Local, e.g. to select a view.
+	LocalKey *TSIG
+	// Exchange sends the queries; nil uses Exchange.
+	Exchange Exchanger
+}
+
+// CheckSerials queries the SOA serial of every secondary zone of cfg (in
+// views too) at its configured primaries, signed with the key each primary
+// entry names, and at the local server, and reports zones whose copy lags
+// behind or runs ahead of its primaries. Serials are compared in RFC 1982
+// serial arithmetic.
+func CheckSerials(ctx context.Context, cfg *namedzone.Config, opts SerialOptions) []SerialReport {
+	exchange := opts.Exchange
+	if exchange == nil {
+		exchange = Exchange
+	}
+	local := hostPort(cmp.Or(opts.Local, "127.0.0.1"), 53)
+	var out []SerialReport
+	for v, z := range cfg.AllZones() {
+		if z.Type != namedzone.ZoneSecondary {
+			continue
+		}
+		r := SerialReport{Zone: z.Name}
+		if v != nil {
+			r.View = v.Name
+		}
+		if s, err := soaSerial(ctx, exchange, local, z.Name, opts.LocalKey); err != nil {
+			r.LocalErr = err
+		} else {
+			r.Local = &s
+		}
+		var newest *uint32
+		for _, p := range cfg.ZonePrimaries(*z) {
+			port := 53
+			if p.Port != nil {
+				port = *p.Port
+			}
+			ps := PrimarySerial{Server: net.JoinHostPort(p.Address, strconv.Itoa(port))}
+			if s, err := soaSerial(ctx, exchange, ps.Server, z.Name, keyOf(cfg, p.Key)); err != nil {
+				ps.Err = err
+			} else {
+				ps.Serial = &s
+				if newest == nil || serialLess(*newest, s) {
+					newest = &s
+				}
+			}
+			r.Primaries = append(r.Primaries, ps)
+		}
+		r.Status = serialStatus(r.Local, newest)
+		out = append(out, r)
+	}
+	return out
+}
+
+func serialStatus(local, primary *uint32) SerialStatus {
+	switch {
+	case local == nil || primary == nil:
+		return SerialUnknown
+	case serialLess(*local, *primary):
+		return SerialLagging
+	case serialLess(*primary, *local):
+		return SerialRegressed
+	}
+	return SerialOK
+}
+
+// serialLess reports a < b in RFC 1982 serial number arithmetic.
+func serialLess(a, b uint32) bool {
+	return a != b && b-a < 1<<31
+}
+
+// soaSerial asks server for the SOA of zone without recursion.
+func soaSerial(ctx context.Context, exchange Exchanger, server, zone string, key *TSIG) (uint32, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(zone), dns.TypeSOA)
+	m.RecursionDesired = false
+	r, err := exchange(ctx, server, m, key)
+	if err != nil {
+		return 0, err
+	}
+	if r.Rcode != dns.RcodeSuccess {
+		return 0, errors.New("dnsops: SOA query answered " + dns.RcodeToString[r.Rcode])
+	}
+	for _, rr := range r.Answer {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa.Serial, nil
+		}
+	}
+	return 0, errors.New("dnsops: no SOA in the answer")
+}
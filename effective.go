@@ -0,0 +1,126 @@
+// File: pkg/namedzone/effective.go
+package namedzone
+
+import "strings"
+
+// Inherited is the effective value of a setting and the level it comes from.
+type Inherited[T any] struct {
+	Value T      `json:"value"`
+	From  string `json:"from"` // "zone", "view", "options" or "default"
+}
+
+// EffectiveSettings holds the values named uses for one zone after layering
+// options, view and zone. Raw values (Notify, DNSSECPolicy from options)
+// are taken from Options.Other. Defaults are named's where they are fixed;
+// a default From with a zero Value means named picks the value itself.
+type EffectiveSettings struct {
+	View string `json:"view,omitempty"`
+	Zone string `json:"zone"`
+
+	AllowQuery       Inherited[[]MatchTerm]        `json:"allowQuery"`
+	AllowTransfer    Inherited[[]MatchTerm]        `json:"allowTransfer"`
+	AllowUpdate      Inherited[[]MatchTerm]        `json:"allowUpdate"`
+	AlsoNotify       Inherited[[]RemoteServerItem] `json:"alsoNotify"`
+	Notify           Inherited[string]             `json:"notify"`
+	Forwarders       Inherited[[]Forwarder]        `json:"forwarders"`
+	Forward          Inherited[string]             `json:"forward"`
+	Recursion        Inherited[bool]               `json:"recursion"`
+	DNSSECValidation Inherited[string]             `json:"dnssecValidation"`
+	DNSSECPolicy     Inherited[string]             `json:"dnssecPolicy"`
+	MasterfileFormat Inherited[MasterfileFormat]   `json:"masterfileFormat"`
+	MasterfileStyle  Inherited[MasterfileStyle]    `json:"masterfileStyle"`
+}
+
+// EffectiveZoneSettings resolves the inheritable settings of the zone name
+// in view (top-level when view is empty) by layering options, then the
+// view, then the zone: the most specific level that sets a value wins.
+// Settings the typed model holds only at some levels are resolved from
+// those; e.g. allow-query is not modeled on views and zones.
+func (c *Config) EffectiveZoneSettings(view, name string) (*EffectiveSettings, error) {
+	z := c.GetZoneInView(view, name)
+	if z == nil {
+		path := "zone[" + name + "]"
+		if view != "" {
+			path = "view[" + view + "]." + path
+		}
+		return nil, &ReferenceError{Kind: "zone", Name: name, Path: path}
+	}
+	var v *View
+	if view != "" {
+		v = c.FindView(view)
+	}
+	o := c.Options
+	if o == nil {
+		o = &Options{}
+	}
+	raw := func(name string) string {
+		s, _ := o.Get(name)
+		return strings.TrimSpace(s)
+	}
+
+	e := &EffectiveSettings{View: view, Zone: z.Name}
+	e.AllowQuery = layered([]MatchTerm{{ACLRef: "any"}}, level("options", o.AllowQuery))
+	e.AllowTransfer = layered(nil, level("options", o.AllowTransfer), level("zone", z.AllowTransfer))
+	e.AllowUpdate = layered([]MatchTerm{{ACLRef: "none"}}, level("options", o.AllowUpdate), level("zone", z.AllowUpdate))
+	e.AlsoNotify = layered(nil, level("zone", z.AlsoNotify))
+	e.Forwarders = layered(nil, level("options", o.Forwarders), level("zone", z.Forwarders))
+	e.Forward = layered("first", level("options", o.Forward), level("zone", z.Forward))
+	e.DNSSECValidation = layered("auto", level("options", o.DNSSECValidation))
+	e.MasterfileFormat = layered("", level("options", o.MasterfileFormat), level("zone", z.MasterfileFormat))
+	e.MasterfileStyle = layered(MasterfileStyle("relative"), level("options", o.MasterfileStyle), level("zone", z.MasterfileStyle))
+
+	e.Notify = layered("yes", level("options", raw("notify")), level("zone", z.Notify))
+	e.DNSSECPolicy = layered("none", level("options", trimQuotes(raw("dnssec-policy"))), level("zone", z.DNSSECPolicy))
+
+	recursion := layered(true)
+	if o.Recursion != nil {
+		recursion = Inherited[bool]{Value: *o.Recursion, From: "options"}
+	}
+	if v != nil && v.Recursion != nil {
+		recursion = Inherited[bool]{Value: *v.Recursion, From: "view"}
+	}
+	e.Recursion = recursion
+	return e, nil
+}
+
+// setLevel is the value of a setting at one level; set is false when the
+// level leaves it unset.
+type setLevel[T any] struct {
+	from  string
+	value T
+	set   bool
+}
+
+func level[T any](from string, v T) setLevel[T] {
+	return setLevel[T]{from: from, value: v, set: !isEmpty(v)}
+}
+
+func isEmpty(v any) bool {
+	switch x := v.(type) {
+	case string:
+		return x == ""
+	case MasterfileFormat:
+		return x == ""
+	case MasterfileStyle:
+		return x == ""
+	case []MatchTerm:
+		return len(x) == 0
+	case []Forwarder:
+		return len(x) == 0
+	case []RemoteServerItem:
+		return len(x) == 0
+	}
+	return false
+}
+
+// layered returns the value of the last level that sets one, from least to
+// most specific, or def.
+func layered[T any](def T, levels ...setLevel[T]) Inherited[T] {
+	out := Inherited[T]{Value: def, From: "default"}
+	for _, l := range levels {
+		if l.set {
+			out = Inherited[T]{Value: l.value, From: l.from}
+		}
+	}
+	return out
+}
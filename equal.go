@@ -0,0 +1,181 @@
+// File: pkg/namedzone/equal.go
+package namedzone
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Equal reports whether c and other hold the same configuration. Like Diff
+// it ignores formatting, comments, the order of named items and of raw
+// options, and the difference between nil and empty values; unlike Diff it
+// stops at the answer. A nil config counts as empty.
+func (c *Config) Equal(other *Config) bool {
+	return Diff(c, other).Empty()
+}
+
+// Fingerprint returns the hex SHA-256 of c in canonical form: configs that
+// are Equal have the same fingerprint, so agents can compare versions across
+// a fleet without shipping the files. The value is stable across releases
+// for as long as the JSON projection is.
+func (c *Config) Fingerprint() (string, error) {
+	if c == nil {
+		c = &Config{}
+	}
+	doc, err := semanticDoc(c)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(plainNumbers(sortNamed(doc, "")))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// sortNamed orders the named collections Diff matches by name, so the order
+// of items does not change the encoding. Objects encode with sorted keys.
+func sortNamed(v any, field string) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, e := range x {
+			x[k] = sortNamed(e, k)
+		}
+	case []any:
+		for i := range x {
+			x[i] = sortNamed(x[i], "")
+		}
+		if _, ok := diffKinds[field]; ok && objectList(x) {
+			slices.SortStableFunc(x, func(a, b any) int {
+				na, _ := a.(map[string]any)["name"].(string)
+				nb, _ := b.(map[string]any)["name"].(string)
+				return strings.Compare(na, nb)
+			})
+		}
+	}
+	return v
+}
+
+// Equal reports whether z and o describe the same zone.
+func (z Zone) Equal(o Zone) bool { return semanticEqual(z, o) }
+
+// Equal reports whether v and o describe the same view, zones included.
+func (v View) Equal(o View) bool { return semanticEqual(v, o) }
+
+// Equal reports whether a and o describe the same ACL. Element order counts.
+func (a ACL) Equal(o ACL) bool { return semanticEqual(a, o) }
+
+func (k Key) Equal(o Key) bool                     { return semanticEqual(k, o) }
+func (k KeyStore) Equal(o KeyStore) bool           { return semanticEqual(k, o) }
+func (r RemoteServers) Equal(o RemoteServers) bool { return semanticEqual(r, o) }
+func (t TLS) Equal(o TLS) bool                     { return semanticEqual(t, o) }
+func (h HTTP) Equal(o HTTP) bool                   { return semanticEqual(h, o) }
+func (i Include) Equal(o Include) bool             { return semanticEqual(i, o) }
+func (t TrustAnchors) Equal(o TrustAnchors) bool   { return semanticEqual(t, o) }
+
+// The singleton blocks compare by pointer so that a nil block equals an
+// empty one.
+func (c *Controls) Equal(o *Controls) bool { return semanticEqual(c, o) }
+func (l *Logging) Equal(o *Logging) bool   { return semanticEqual(l, o) }
+func (o *Options) Equal(p *Options) bool   { return semanticEqual(o, p) }
+
+// semanticEqual compares the normalized JSON projections of x and y the way
+// Diff does.
+func semanticEqual(x, y any) bool {
+	dx, err := semanticDoc(x)
+	if err != nil {
+		return false
+	}
+	dy, err := semanticDoc(y)
+	if err != nil {
+		return false
+	}
+	var d ConfigDiff
+	diffValue(&d, nil, "", "", dx, dy)
+	return d.Empty()
+}
+
+func semanticDoc(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	if doc = normalize(doc); empty(doc) {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+// normalize drops null and empty values from objects, so nil and empty
+// slices, maps and blocks look alike, and puts raw options in a canonical
+// form: whitespace and comments squeezed out of the text, sorted by name.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, e := range x {
+			e = normalize(e)
+			if empty(e) {
+				delete(x, k)
+				continue
+			}
+			if k == "other" {
+				e = canonicalRaw(e)
+			}
+			x[k] = e
+		}
+		return x
+	case []any:
+		for i := range x {
+			x[i] = normalize(x[i])
+		}
+		return x
+	}
+	return v
+}
+
+func empty(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(x) == 0
+	case []any:
+		return len(x) == 0
+	}
+	return false
+}
+
+// canonicalRaw rewrites a list of RawKV projections in canonical form.
+func canonicalRaw(v any) any {
+	list, ok := v.([]any)
+	if !ok || !objectList(list) {
+		return v
+	}
+	key := func(e any) (string, string) {
+		m := e.(map[string]any)
+		name, _ := m["name"].(string)
+		raw, _ := m["raw"].(string)
+		return name, raw
+	}
+	for _, e := range list {
+		m := e.(map[string]any)
+		if raw, ok := m["raw"].(string); ok {
+			m["raw"] = strings.Join(strings.Fields(stripComments(raw)), " ")
+		}
+	}
+	slices.SortStableFunc(list, func(a, b any) int {
+		na, ra := key(a)
+		nb, rb := key(b)
+		return cmp.Or(strings.Compare(na, nb), strings.Compare(ra, rb))
+	})
+	return list
+}
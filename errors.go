@@ -0,0 +1,158 @@
+// File: pkg/namedzone/errors.go
+package namedzone
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Sentinels for errors.Is. Each error type below matches its sentinel, so
+// callers can branch on the class without knowing the concrete type.
+var (
+	ErrParse        = errors.New("namedzone: parse error")
+	ErrReference    = errors.New("namedzone: dangling reference")
+	ErrConflict     = errors.New("namedzone: conflict")
+	ErrUnsupported  = errors.New("namedzone: unsupported statement")
+	ErrInvalidValue = errors.New("namedzone: invalid value")
+	ErrCheckFailed  = errors.New("namedzone: config check failed")
+	ErrLocked       = errors.New("namedzone: config is locked")
+	ErrInUse        = errors.New("namedzone: still referenced")
+	ErrReload       = errors.New("namedzone: reload failed")
+
+	// ErrReadOnlyFS is returned by Save when the Config was loaded with
+	// FromFS from a filesystem that is not a WriteFS; use SaveFS instead.
+	ErrReadOnlyFS = errors.New("namedzone: Save: source fs.FS is not writable")
+)
+
+// ParseError reports a statement whose value could not be interpreted.
+type ParseError struct {
+	Offset  int
+	Line    int
+	Column  int
+	Keyword string
+	Msg     string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("namedzone: %d:%d: %s: %s", e.Line, e.Column, e.Keyword, e.Msg)
+}
+
+func (e *ParseError) Is(target error) bool { return target == ErrParse }
+
+// UnsupportedStatementError reports a statement inside a typed block that
+// the typed model cannot hold.
+type UnsupportedStatementError struct {
+	Offset  int
+	Line    int
+	Column  int
+	Keyword string
+	Block   string
+}
+
+func (e *UnsupportedStatementError) Error() string {
+	return fmt.Sprintf("namedzone: %d:%d: %s: not modeled in %s", e.Line, e.Column, e.Keyword, e.Block)
+}
+
+func (e *UnsupportedStatementError) Is(target error) bool { return target == ErrUnsupported }
+
+// ReferenceError reports a name that does not resolve, e.g. an acl, key,
+// tls, http or remote-servers block used but never defined.
+type ReferenceError struct {
+	Kind string // "acl", "key", "tls", "http", "remote-servers"
+	Name string
+	Path string // where the reference was found
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("namedzone: %s: undefined %s %q", e.Path, e.Kind, e.Name)
+}
+
+func (e *ReferenceError) Is(target error) bool { return target == ErrReference }
+
+// ConflictError reports an operation that collides with existing state,
+// such as a duplicate name or an option owned by a typed field.
+type ConflictError struct {
+	Kind string
+	Name string
+	Msg  string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("namedzone: %s %q: %s", e.Kind, e.Name, e.Msg)
+}
+
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+// ValueError reports a typed field holding a value named would reject.
+type ValueError struct {
+	Path  string
+	Value string
+	Msg   string
+}
+
+func (e *ValueError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("namedzone: %s: %s", e.Path, e.Msg)
+	}
+	return fmt.Sprintf("namedzone: %s: %s %q", e.Path, e.Msg, e.Value)
+}
+
+func (e *ValueError) Is(target error) bool { return target == ErrInvalidValue }
+
+// CheckError reports a config that SaveOptions.Check rejected; nothing was
+// written. Err is what the check returned.
+type CheckError struct {
+	Path string
+	Err  error
+}
+
+func (e *CheckError) Error() string {
+	return fmt.Sprintf("namedzone: %s: check failed: %v", e.Path, e.Err)
+}
+
+func (e *CheckError) Is(target error) bool { return target == ErrCheckFailed }
+
+func (e *CheckError) Unwrap() error { return e.Err }
+
+// LockError reports that the lock requested with LoadOptions.Lock was still
+// held by someone else when LockTimeout ran out.
+type LockError struct {
+	Path    string // the lock file
+	Timeout time.Duration
+}
+
+func (e *LockError) Error() string {
+	return fmt.Sprintf("namedzone: %s: lock not acquired within %s", e.Path, e.Timeout)
+}
+
+func (e *LockError) Is(target error) bool { return target == ErrLocked }
+
+// InUseError reports a block that could not be removed because Refs still
+// use it.
+type InUseError struct {
+	Kind string
+	Name string
+	Refs []Reference
+}
+
+func (e *InUseError) Error() string {
+	return fmt.Sprintf("namedzone: %s %q: still referenced in %d places", e.Kind, e.Name, len(e.Refs))
+}
+
+func (e *InUseError) Is(target error) bool { return target == ErrInUse }
+
+// ReloadError reports that SaveAndReload saved the config but could not
+// make named load it. Err is what the ReloadStrategy returned.
+type ReloadError struct {
+	Path string
+	Err  error
+}
+
+func (e *ReloadError) Error() string {
+	return fmt.Sprintf("namedzone: %s: saved, but reload failed: %v", e.Path, e.Err)
+}
+
+func (e *ReloadError) Is(target error) bool { return target == ErrReload }
+
+func (e *ReloadError) Unwrap() error { return e.Err }
@@ -0,0 +1,227 @@
+// File: pkg/namedzone/explain.go
+package namedzone
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// Explanation describes every instance of a zone; see Config.Explain.
+type Explanation struct {
+	Zone      string            `json:"zone"`
+	Instances []ZoneExplanation `json:"instances"`
+}
+
+// ZoneExplanation describes one instance of a zone: the top-level zone or
+// the zone in one view.
+type ZoneExplanation struct {
+	View string `json:"view,omitempty"`
+	// ViewClients is the view's match-clients; empty means every client.
+	ViewClients []MatchTerm `json:"viewClients,omitempty"`
+	Type        ZoneType    `json:"type"`
+
+	// Source says where the zone's data comes from, e.g. "zone file
+	// db.example" or "transfers from primaries 192.0.2.1".
+	Source     string                 `json:"source"`
+	File       string                 `json:"file,omitempty"`
+	Primaries  []RemoteServerItem     `json:"primaries,omitempty"` // named lists resolved
+	Forwarders Inherited[[]Forwarder] `json:"forwarders"`
+
+	Query      Inherited[[]MatchTerm]        `json:"query"`
+	Transfer   Inherited[[]MatchTerm]        `json:"transfer"`
+	Update     Inherited[[]MatchTerm]        `json:"update"`
+	AlsoNotify Inherited[[]RemoteServerItem] `json:"alsoNotify"`
+	DNSSEC     Inherited[string]             `json:"dnssecPolicy"`
+
+	// Keys and TLS name the key and tls blocks involved, through the
+	// access lists (and the ACLs they use), primaries, also-notify and
+	// forwarders.
+	Keys []string `json:"keys,omitempty"`
+	TLS  []string `json:"tls,omitempty"`
+}
+
+// Explain reports, for each instance of the zone name, its view, type and
+// data source, who may query, transfer and update it after inheritance (see
+// EffectiveZoneSettings), and the keys and TLS profiles involved. It fails
+// with a *ReferenceError when the zone is not defined.
+func (c *Config) Explain(name string) (*Explanation, error) {
+	refs := c.GetZones(name)
+	if len(refs) == 0 {
+		return nil, &ReferenceError{Kind: "zone", Name: name, Path: "zone[" + name + "]"}
+	}
+	ex := &Explanation{Zone: refs[0].Zone.Name}
+	for _, r := range refs {
+		e, err := c.EffectiveZoneSettings(r.View, name)
+		if err != nil {
+			return nil, err
+		}
+		z := r.Zone
+		ze := ZoneExplanation{
+			View: r.View, Type: z.Type, File: z.File,
+			Primaries:  c.resolveServers(z.PrimariesRef, z.Primaries),
+			Forwarders: e.Forwarders,
+			Query:      e.AllowQuery, Transfer: e.AllowTransfer, Update: e.AllowUpdate,
+			AlsoNotify: e.AlsoNotify, DNSSEC: e.DNSSECPolicy,
+		}
+		if v := c.FindView(r.View); v != nil {
+			ze.ViewClients = v.MatchClients
+		}
+		ze.Source = zoneSource(ze)
+
+		keys, tls := map[string]bool{}, map[string]bool{}
+		for _, l := range [][]MatchTerm{ze.ViewClients, ze.Query.Value, ze.Transfer.Value, ze.Update.Value} {
+			c.termKeys(l, keys, map[string]bool{})
+		}
+		for _, it := range slices.Concat(ze.Primaries, c.resolveServers("", ze.AlsoNotify.Value)) {
+			keys[it.Key], tls[it.TLS] = true, true
+		}
+		for _, f := range ze.Forwarders.Value {
+			tls[f.TLS] = true
+		}
+		delete(keys, "")
+		delete(tls, "")
+		ze.Keys, ze.TLS = slices.Sorted(maps.Keys(keys)), slices.Sorted(maps.Keys(tls))
+		ex.Instances = append(ex.Instances, ze)
+	}
+	return ex, nil
+}
+
+// zoneSource describes where the data of the zone comes from.
+func zoneSource(ze ZoneExplanation) string {
+	servers := func(items []RemoteServerItem) string {
+		var out []string
+		for _, it := range items {
+			out = append(out, serializeRemoteServerItem(it))
+		}
+		return strings.Join(out, ", ")
+	}
+	switch ze.Type {
+	case ZonePrimary, ZoneHint:
+		return "zone file " + ze.File
+	case ZoneSecondary, ZoneMirror, ZoneStub:
+		s := "transfers from primaries " + servers(ze.Primaries)
+		if ze.File != "" {
+			s += ", cached in " + ze.File
+		}
+		return s
+	case ZoneForward:
+		var out []string
+		for _, f := range ze.Forwarders.Value {
+			out = append(out, f.Address)
+		}
+		return "forwards queries (" + ze.Forwarders.From + ") to " + strings.Join(out, ", ")
+	case ZoneStaticStub:
+		return "static delegation to " + servers(ze.Primaries)
+	case ZoneRedirect:
+		if ze.File != "" {
+			return "redirect data from zone file " + ze.File
+		}
+		return "redirect data from primaries " + servers(ze.Primaries)
+	}
+	return "unknown (zone type " + string(ze.Type) + ")"
+}
+
+// ZonePrimaries returns the primaries of z as plain addresses: entries
+// naming remote-servers lists, including PrimariesRef, are expanded, and
+// a key or tls set on a list entry carries over to the addresses it holds.
+func (c *Config) ZonePrimaries(z Zone) []RemoteServerItem {
+	return c.resolveServers(z.PrimariesRef, z.Primaries)
+}
+
+// ZoneAlsoNotify is ZonePrimaries for the also-notify list of z.
+func (c *Config) ZoneAlsoNotify(z Zone) []RemoteServerItem {
+	return c.resolveServers("", z.AlsoNotify)
+}
+
+// resolveServers returns the servers of a primaries or also-notify list:
+// the remote-servers block ref, if set, then items, with items that name a
+// remote-servers block replaced by its servers. Key and TLS given on such an
+// item apply to servers of the list that set none.
+func (c *Config) resolveServers(ref string, items []RemoteServerItem) []RemoteServerItem {
+	seen := map[string]bool{}
+	var expand func(items []RemoteServerItem, key, tls string) []RemoteServerItem
+	list := func(name, key, tls string) []RemoteServerItem {
+		i := slices.IndexFunc(c.RemoteServers, func(r RemoteServers) bool { return r.Name == name })
+		if i < 0 || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return expand(c.RemoteServers[i].Servers, key, tls)
+	}
+	expand = func(items []RemoteServerItem, key, tls string) []RemoteServerItem {
+		var out []RemoteServerItem
+		for _, it := range items {
+			it.Key, it.TLS = cmp.Or(it.Key, key), cmp.Or(it.TLS, tls)
+			if _, err := netip.ParseAddr(it.Address); err != nil && slices.ContainsFunc(c.RemoteServers, func(r RemoteServers) bool { return r.Name == it.Address }) {
+				out = append(out, list(it.Address, it.Key, it.TLS)...)
+				continue
+			}
+			out = append(out, it)
+		}
+		return out
+	}
+	var out []RemoteServerItem
+	if ref != "" {
+		out = list(ref, "", "")
+	}
+	return append(out, expand(items, "", "")...)
+}
+
+// termKeys adds the keys used by terms, and by the ACLs they reference, to
+// keys.
+func (c *Config) termKeys(terms []MatchTerm, keys, seen map[string]bool) {
+	for _, t := range terms {
+		switch {
+		case len(t.Nested) > 0:
+			c.termKeys(t.Nested, keys, seen)
+		case t.Key != "":
+			keys[t.Key] = true
+		case t.ACLRef != "" && !seen[t.ACLRef]:
+			seen[t.ACLRef] = true
+			if i := slices.IndexFunc(c.ACLs, func(a ACL) bool { return a.Name == t.ACLRef }); i >= 0 {
+				c.termKeys(c.ACLs[i].Elements, keys, seen)
+			}
+		}
+	}
+}
+
+// String renders the explanation as indented text for support tickets.
+func (ex *Explanation) String() string {
+	var b strings.Builder
+	list := func(terms []MatchTerm) string {
+		if len(terms) == 0 {
+			return "named's default"
+		}
+		return serializeMatchList(terms)
+	}
+	fmt.Fprintf(&b, "zone %s\n", ex.Zone)
+	for _, ze := range ex.Instances {
+		where := "top level"
+		if ze.View != "" {
+			where = "view " + ze.View
+			if len(ze.ViewClients) > 0 {
+				where += " (clients " + serializeMatchList(ze.ViewClients) + ")"
+			}
+		}
+		fmt.Fprintf(&b, "  in %s: type %s\n", where, ze.Type)
+		fmt.Fprintf(&b, "    data: %s\n", ze.Source)
+		fmt.Fprintf(&b, "    query: %s (%s)\n", list(ze.Query.Value), ze.Query.From)
+		fmt.Fprintf(&b, "    transfer: %s (%s)\n", list(ze.Transfer.Value), ze.Transfer.From)
+		fmt.Fprintf(&b, "    update: %s (%s)\n", list(ze.Update.Value), ze.Update.From)
+		if ze.DNSSEC.Value != "" {
+			fmt.Fprintf(&b, "    dnssec-policy: %s (%s)\n", ze.DNSSEC.Value, ze.DNSSEC.From)
+		}
+		if len(ze.Keys) > 0 {
+			fmt.Fprintf(&b, "    keys: %s\n", strings.Join(ze.Keys, ", "))
+		}
+		if len(ze.TLS) > 0 {
+			fmt.Fprintf(&b, "    tls: %s\n", strings.Join(ze.TLS, ", "))
+		}
+	}
+	return b.String()
+}
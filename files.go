@@ -0,0 +1,159 @@
+// File: pkg/namedzone/files.go
+package namedzone
+
+import (
+	"fmt"
+	"path/filepath"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// FileKind categorizes a path returned by ReferencedFiles.
+type FileKind string
+
+const (
+	FileInclude   FileKind = "include"
+	FileZone      FileKind = "zone"
+	FileJournal   FileKind = "journal"
+	FileTLSCert   FileKind = "tls-cert"
+	FileTLSKey    FileKind = "tls-key"
+	FileTLSCA     FileKind = "tls-ca"
+	FileDHParam   FileKind = "dhparam"
+	FilePKCS11    FileKind = "pkcs11" // a PKCS#11 URI rather than a path
+	FileKeytab    FileKind = "keytab"
+	FileLog       FileKind = "log"
+	FileRuntime   FileKind = "runtime"   // pid, statistics, dump and other files named writes
+	FileDirectory FileKind = "directory" // working, key and new-zones directories
+)
+
+// FileRef is an external path the config depends on.
+type FileRef struct {
+	Kind FileKind `json:"kind"`
+	Path string   `json:"path"` // as written
+	// Resolved is Path made absolute against the options directory, where
+	// named resolves it; it equals Path for absolute paths, includes (read
+	// before the directory applies), the directory itself and PKCS#11 URIs.
+	Resolved string `json:"resolved"`
+	Source   string `json:"source"` // GetPath path of the item naming it
+}
+
+// ReferencedFiles lists every external path the config names, in config
+// order: includes, zone files and their journals, tls certificate, key, CA
+// and dhparam files, key-store PKCS#11 URIs, the GSS-TSIG keytab, log
+// channel files, and the files and directories options point named at.
+// Journals are the explicit journal option, or the file plus ".jnl" for
+// zones that keep one: secondaries and mirrors with a file, and primaries
+// that accept updates or are signed by a dnssec-policy. Defaults named
+// uses for unset options are not listed.
+func (c *Config) ReferencedFiles() []FileRef {
+	var out []FileRef
+	add := func(kind FileKind, source, path string) {
+		if path == "" {
+			return
+		}
+		r := FileRef{Kind: kind, Path: path, Resolved: path, Source: source}
+		if kind != FileInclude && kind != FilePKCS11 && source != "options.directory" {
+			r.Resolved = c.inDirectory(path)
+		}
+		out = append(out, r)
+	}
+
+	for i, inc := range c.Includes {
+		add(FileInclude, fmt.Sprintf("include[#%d]", i), inc.Path)
+	}
+	for _, v := range c.Views {
+		for i, inc := range v.Includes {
+			add(FileInclude, fmt.Sprintf("view[%s].include[#%d]", v.Name, i), inc.Path)
+		}
+	}
+	if o := c.Options; o != nil {
+		add(FileDirectory, "options.directory", o.Directory)
+		for _, name := range []string{"key-directory", "managed-keys-directory", "new-zones-directory", "geoip-directory"} {
+			if raw, ok := o.Get(name); ok {
+				add(FileDirectory, "options", trimQuotes(raw))
+			}
+		}
+		add(FileKeytab, "options.tkeyGssapiKeytab", o.TKeyGSSAPIKeytab)
+		for _, f := range []struct{ field, path string }{
+			{"pidFile", o.PIDFile}, {"statisticsFile", o.StatisticsFile}, {"dumpFile", o.DumpFile},
+			{"secrootsFile", o.SecrootsFile}, {"recursingFile", o.RecursingFile},
+			{"memstatisticsFile", o.MemstatisticsFile}, {"lockFile", o.LockFile}, {"sessionKeyfile", o.SessionKeyFile},
+		} {
+			add(FileRuntime, "options."+f.field, f.path)
+		}
+		if raw, ok := o.Get("bindkeys-file"); ok {
+			add(FileRuntime, "options", trimQuotes(raw))
+		}
+	}
+	for _, t := range c.TLS {
+		p := "tls[" + t.Name + "]"
+		add(FileTLSCert, p+".certFile", t.CertFile)
+		add(FileTLSKey, p+".keyFile", t.KeyFile)
+		add(FileTLSCA, p+".caFile", t.CAFile)
+		add(FileDHParam, p+".dhparamFile", t.DHParamFile)
+	}
+	for _, k := range c.KeyStores {
+		add(FilePKCS11, "keyStore["+k.Name+"].pkcs11Uri", k.PKCS11URI)
+	}
+	if c.Logging != nil {
+		for _, ch := range c.Logging.Channels {
+			if ch.File != nil {
+				add(FileLog, "logging.channel["+ch.Name+"].file", ch.File.Path)
+			}
+		}
+	}
+	for v, z := range c.AllZones() {
+		view, path := "", "zone["+z.Name+"]"
+		if v != nil {
+			view, path = v.Name, "view["+v.Name+"]."+path
+		}
+		add(FileZone, path+".file", z.File)
+		journal := zoneJournal(z.stmt)
+		if journal == "" && z.File != "" && c.keepsJournal(view, z) {
+			journal = z.File + ".jnl"
+		}
+		add(FileJournal, path, journal)
+	}
+	return out
+}
+
+// inDirectory resolves p against the options directory, as named does for
+// the files it opens after reading its config.
+func (c *Config) inDirectory(p string) string {
+	if c.Options == nil || c.Options.Directory == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(c.Options.Directory, p)
+}
+
+// zoneJournal returns the journal option of a loaded zone statement.
+func zoneJournal(st *nc.Stmt) string { return zoneOption(st, "journal") }
+
+// zoneOption returns the unquoted value of an option the typed model does
+// not hold, from a loaded zone statement.
+func zoneOption(st *nc.Stmt, keyword string) string {
+	if st == nil {
+		return ""
+	}
+	for _, n := range st.Body {
+		if s, ok := n.(*nc.Stmt); ok && s.Keyword == keyword {
+			return trimQuotes(stmtValue(s))
+		}
+	}
+	return ""
+}
+
+// keepsJournal reports whether named keeps a journal for z.
+func (c *Config) keepsJournal(view string, z *Zone) bool {
+	switch z.Type {
+	case ZoneSecondary, ZoneMirror:
+		return true
+	case ZonePrimary:
+		e, err := c.EffectiveZoneSettings(view, z.Name)
+		if err != nil {
+			return false
+		}
+		return serializeMatchList(e.AllowUpdate.Value) != "{ none; }" || e.DNSSECPolicy.Value != "none"
+	}
+	return false
+}
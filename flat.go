@@ -0,0 +1,178 @@
+// File: pkg/namedzone/flat.go
+package namedzone
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// FlatConfig is the flattened JSON projection of a Config for declarative
+// tools that diff by key, such as Terraform providers: the named items
+// (zones, views, ACLs, keys, key-stores, remote-servers, tls, http, log
+// channels and categories) are maps keyed by name instead of slices, so
+// reordering them changes nothing. Views carry their Position, since
+// named matches them in order. Includes and trust anchors stay lists.
+type FlatConfig struct {
+	Includes      []Include                `json:"includes,omitempty"`
+	ACLs          map[string]ACL           `json:"acls,omitempty"`
+	Keys          map[string]Key           `json:"keys,omitempty"`
+	KeyStores     map[string]KeyStore      `json:"keyStores,omitempty"`
+	RemoteServers map[string]RemoteServers `json:"remoteServers,omitempty"`
+	TLS           map[string]TLS           `json:"tls,omitempty"`
+	HTTP          map[string]HTTP          `json:"http,omitempty"`
+	Controls      *Controls                `json:"controls,omitempty"`
+	Logging       *FlatLogging             `json:"logging,omitempty"`
+	Options       *Options                 `json:"options,omitempty"`
+	TrustAnchors  []TrustAnchors           `json:"trustAnchors,omitempty"`
+	Views         map[string]FlatView      `json:"views,omitempty"`
+	Zones         map[string]Zone          `json:"zones,omitempty"`
+}
+
+// FlatView is a View with its zones keyed by name and its place among the
+// views.
+type FlatView struct {
+	View
+	Position int             `json:"position"`
+	Zones    map[string]Zone `json:"zones,omitempty"`
+}
+
+// FlatLogging is Logging with channels and categories keyed by name.
+type FlatLogging struct {
+	Logging
+	Channels   map[string]LogChannel  `json:"channels,omitempty"`
+	Categories map[string]LogCategory `json:"categories,omitempty"`
+}
+
+// Flatten returns the flat projection of c. Item names stay in the items
+// as well as in the keys.
+func (c *Config) Flatten() *FlatConfig {
+	f := &FlatConfig{
+		Includes:      slices.Clone(c.Includes),
+		ACLs:          byName(c.ACLs, func(a ACL) string { return a.Name }),
+		Keys:          byName(c.Keys, func(k Key) string { return k.Name }),
+		KeyStores:     byName(c.KeyStores, func(k KeyStore) string { return k.Name }),
+		RemoteServers: byName(c.RemoteServers, func(r RemoteServers) string { return r.Name }),
+		TLS:           byName(c.TLS, func(t TLS) string { return t.Name }),
+		HTTP:          byName(c.HTTP, func(h HTTP) string { return h.Name }),
+		Controls:      c.Controls,
+		Options:       c.Options,
+		TrustAnchors:  slices.Clone(c.TrustAnchors),
+		Zones:         byName(c.Zones, func(z Zone) string { return z.Name }),
+	}
+	if c.Logging != nil {
+		f.Logging = &FlatLogging{
+			Logging:    *c.Logging,
+			Channels:   byName(c.Logging.Channels, func(ch LogChannel) string { return ch.Name }),
+			Categories: byName(c.Logging.Categories, func(cat LogCategory) string { return cat.Name }),
+		}
+		f.Logging.Logging.Channels, f.Logging.Logging.Categories = nil, nil
+	}
+	for i, v := range c.Views {
+		if f.Views == nil {
+			f.Views = map[string]FlatView{}
+		}
+		fv := FlatView{View: v, Position: i, Zones: byName(v.Zones, func(z Zone) string { return z.Name })}
+		fv.View.Zones = nil
+		f.Views[v.Name] = fv
+	}
+	return f
+}
+
+// MarshalFlat encodes the flat projection of c as indented JSON. Map keys
+// are sorted, so equal configs encode alike whatever their item order.
+func (c *Config) MarshalFlat() ([]byte, error) {
+	return json.MarshalIndent(c.Flatten(), "", "  ")
+}
+
+// Config builds the Config f describes: items sorted by name, views by
+// Position and then name. An item without a name takes its key; one whose
+// name differs from its key is a *ValueError.
+func (f *FlatConfig) Config() (*Config, error) {
+	c := &Config{Includes: slices.Clone(f.Includes), Controls: f.Controls, Options: f.Options, TrustAnchors: slices.Clone(f.TrustAnchors)}
+	var err error
+	if c.ACLs, err = fromNamed("acls", f.ACLs, func(a *ACL) *string { return &a.Name }); err != nil {
+		return nil, err
+	}
+	if c.Keys, err = fromNamed("keys", f.Keys, func(k *Key) *string { return &k.Name }); err != nil {
+		return nil, err
+	}
+	if c.KeyStores, err = fromNamed("keyStores", f.KeyStores, func(k *KeyStore) *string { return &k.Name }); err != nil {
+		return nil, err
+	}
+	if c.RemoteServers, err = fromNamed("remoteServers", f.RemoteServers, func(r *RemoteServers) *string { return &r.Name }); err != nil {
+		return nil, err
+	}
+	if c.TLS, err = fromNamed("tls", f.TLS, func(t *TLS) *string { return &t.Name }); err != nil {
+		return nil, err
+	}
+	if c.HTTP, err = fromNamed("http", f.HTTP, func(h *HTTP) *string { return &h.Name }); err != nil {
+		return nil, err
+	}
+	if c.Zones, err = fromNamed("zones", f.Zones, func(z *Zone) *string { return &z.Name }); err != nil {
+		return nil, err
+	}
+	if f.Logging != nil {
+		l := f.Logging.Logging
+		if l.Channels, err = fromNamed("logging.channels", f.Logging.Channels, func(ch *LogChannel) *string { return &ch.Name }); err != nil {
+			return nil, err
+		}
+		if l.Categories, err = fromNamed("logging.categories", f.Logging.Categories, func(cat *LogCategory) *string { return &cat.Name }); err != nil {
+			return nil, err
+		}
+		c.Logging = &l
+	}
+
+	views, err := fromNamed("views", f.Views, func(v *FlatView) *string { return &v.Name })
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(views, func(a, b FlatView) int { return cmp.Compare(a.Position, b.Position) })
+	for _, fv := range views {
+		v := fv.View
+		if v.Zones, err = fromNamed("views["+v.Name+"].zones", fv.Zones, func(z *Zone) *string { return &z.Name }); err != nil {
+			return nil, err
+		}
+		c.Views = append(c.Views, v)
+	}
+	return c, nil
+}
+
+// UnmarshalFlat decodes JSON written by MarshalFlat into a new Config.
+func UnmarshalFlat(data []byte) (*Config, error) {
+	var f FlatConfig
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return f.Config()
+}
+
+// byName keys items by name; nil when there are none.
+func byName[T any](items []T, name func(T) string) map[string]T {
+	if len(items) == 0 {
+		return nil
+	}
+	m := make(map[string]T, len(items))
+	for _, it := range items {
+		m[name(it)] = it
+	}
+	return m
+}
+
+// fromNamed lists the items of m sorted by key, naming unnamed items after
+// their key.
+func fromNamed[T any](path string, m map[string]T, name func(*T) *string) ([]T, error) {
+	var out []T
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		it := m[k]
+		switch n := name(&it); {
+		case *n == "":
+			*n = k
+		case *n != k:
+			return nil, &ValueError{Path: path + "[" + k + "].name", Value: *n, Msg: "does not match its key"}
+		}
+		out = append(out, it)
+	}
+	return out, nil
+}
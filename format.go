@@ -0,0 +1,441 @@
+// File: pkg/namedzone/format.go
+package namedzone
+
+import (
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// Format controls the layout of statements Apply generates. Statements that
+// are kept from the source are never reformatted.
+type Format struct {
+	// Indent is the number of spaces per nesting level; 0 means 2. It is
+	// ignored when Tabs is set.
+	Indent int
+	Tabs   bool
+
+	// InlineLists writes a list of simple elements on one line, as in
+	// "allow-query { localhost; 10.0.0.0/8; };", instead of one element
+	// per line. Statement bodies (zone, options, ...) are always expanded.
+	InlineLists bool
+
+	// MaxWidth, if positive, expands an inline list that would make its
+	// line longer than this many columns (a tab counts as 8).
+	MaxWidth int
+}
+
+// SetFormat makes Apply lay out generated statements as f describes. Without
+// it they are written in the AST's default compact form.
+func (c *Config) SetFormat(f Format) { c.format = &f }
+
+func (f Format) unit() string {
+	switch {
+	case f.Tabs:
+		return "\t"
+	case f.Indent > 0:
+		return strings.Repeat(" ", f.Indent)
+	}
+	return "  "
+}
+
+// dress finishes a statement built by sync: lead (comments taken over from
+// the statement it replaces) goes before its head, and with a Format set the
+// text is laid out and frozen into RawText. Built text that carries comments
+// of its own, from preserved unknown statements, is left to the writer.
+func (s syncer) dress(st *nc.Stmt, text, lead string) {
+	st.HeadRaw = lead + st.HeadRaw
+	if s.format == nil || stripComments(text) != text {
+		return
+	}
+	st.RawText = lead + strings.TrimSuffix(layout(text, *s.format, false), "\n")
+	st.Modified = false
+}
+
+// layout re-lays out named.conf text token by token, one statement per line
+// and comments dropped; gap puts a blank line between top-level statements.
+func layout(src string, f Format, gap bool) string {
+	var b strings.Builder
+	unit := f.unit()
+	toks := tokenize(stripComments(src))
+	var line []string
+	depth := 0
+	bang := false
+	flush := func(end string) {
+		b.WriteString(strings.Repeat(unit, depth))
+		b.WriteString(strings.Join(line, " "))
+		b.WriteString(end)
+		line = nil
+	}
+	for i := 0; i < len(toks); i++ {
+		switch tok := toks[i]; tok {
+		case "!":
+			bang = true
+		case "{":
+			if bang {
+				line, bang = append(line, "!"), false
+			}
+			end := groupEnd(toks, i)
+			if end == i+1 {
+				line = append(line, "{ }")
+				i = end
+				continue
+			}
+			if depth > 0 && f.InlineLists {
+				if list, ok := inlineList(toks[i+1 : end]); ok {
+					width := len(strings.Join(append(line, list), " ")) + 1
+					width += depth * len(strings.ReplaceAll(unit, "\t", "        "))
+					if f.MaxWidth <= 0 || width <= f.MaxWidth {
+						line = append(line, list)
+						i = end
+						continue
+					}
+				}
+			}
+			if len(line) == 0 {
+				flush("{\n")
+			} else {
+				flush(" {\n")
+			}
+			depth++
+		case "}":
+			if len(line) > 0 {
+				flush(";\n")
+			}
+			depth = max(depth-1, 0)
+			line = []string{"}"}
+		case ";":
+			flush(";\n")
+			if depth == 0 && gap {
+				b.WriteString("\n")
+			}
+		default:
+			if bang {
+				tok, bang = "!"+tok, false
+			}
+			line = append(line, tok)
+		}
+	}
+	if len(line) > 0 {
+		flush(";\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// inlineList renders the tokens of a list without nested groups as
+// "{ a; b; }". It fails for anything else.
+func inlineList(toks []string) (string, bool) {
+	var elems, cur []string
+	bang := false
+	for _, t := range toks {
+		switch t {
+		case "{", "}":
+			return "", false
+		case "!":
+			bang = true
+		case ";":
+			elems = append(elems, strings.Join(cur, " ")+";")
+			cur = nil
+		default:
+			if bang {
+				t, bang = "!"+t, false
+			}
+			cur = append(cur, t)
+		}
+	}
+	if len(cur) > 0 {
+		elems = append(elems, strings.Join(cur, " ")+";")
+	}
+	return "{ " + strings.Join(elems, " ") + " }", true
+}
+
+// stripComments removes #, // and /* */ comments outside quoted strings.
+func stripComments(src string) string {
+	var b strings.Builder
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j, len(src)-1)
+			b.WriteString(src[i : j+1])
+			i = j
+		case c == '#' || strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			b.WriteByte('\n')
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// FormatSource re-lays out named.conf source text the way f describes, one
+// statement per line with a blank line between top-level statements, as
+// RenderCanonical does (which uses Format{Tabs: true}). Unlike
+// RenderCanonical it changes nothing but the layout: statements keep their
+// order and spelling, include statements stay, and comments are kept,
+// with a comment found inside a statement moved to the line before it.
+// Single blank lines inside blocks are kept. Text that does not parse, or
+// has unbalanced braces or an unterminated statement, is rejected with a
+// *ParseError or the parser's error.
+func FormatSource(src []byte, f Format) ([]byte, error) {
+	if _, err := nc.Parse(src); err != nil {
+		return nil, err
+	}
+	toks := lexSource(string(src))
+	if err := checkSource(string(src), toks); err != nil {
+		return nil, err
+	}
+	return []byte(layoutSource(toks, f)), nil
+}
+
+// srcToken is a token of named.conf source as tokenize splits it, or a
+// comment; nl counts the line breaks before it and off is its byte offset.
+type srcToken struct {
+	text    string
+	comment bool
+	nl      int
+	off     int
+}
+
+func lexSource(src string) []srcToken {
+	var toks []srcToken
+	nl, i := 0, 0
+	add := func(text string, comment bool) {
+		toks = append(toks, srcToken{text, comment, nl, i})
+		nl = 0
+	}
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			nl++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#' || strings.HasPrefix(src[i:], "//"):
+			j := i
+			for j < len(src) && src[j] != '\n' {
+				j++
+			}
+			add(strings.TrimRight(src[i:j], " \t\r"), true)
+			i = j
+		case strings.HasPrefix(src[i:], "/*"):
+			j := strings.Index(src[i+2:], "*/")
+			if j < 0 {
+				j = len(src)
+			} else {
+				j += i + 4
+			}
+			add(src[i:j], true)
+			i = j
+		case c == '{' || c == '}' || c == ';' || c == '!':
+			add(string(c), false)
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(src))
+			add(src[i:j], false)
+			i = j
+		default:
+			j := i
+			for j < len(src) && !strings.ContainsRune(" \t\n\r{};\"#", rune(src[j])) && !strings.HasPrefix(src[j:], "//") && !strings.HasPrefix(src[j:], "/*") {
+				j++
+			}
+			add(src[i:j], false)
+			i = j
+		}
+	}
+	return toks
+}
+
+// checkSource rejects what layoutSource could not lay out without changing
+// it: a "}" without its "{", and a group or statement left open at the end.
+func checkSource(src string, toks []srcToken) error {
+	var open []srcToken // the "{" of each open group
+	var stmt *srcToken  // the first token of the open statement
+	fail := func(t srcToken, msg string) error {
+		line := 1 + strings.Count(src[:t.off], "\n")
+		col := t.off - strings.LastIndex(src[:t.off], "\n")
+		return &ParseError{Offset: t.off, Line: line, Column: col, Keyword: t.text, Msg: msg}
+	}
+	for i, t := range toks {
+		switch {
+		case t.comment:
+		case t.text == "{":
+			open = append(open, t)
+			stmt = nil
+		case t.text == "}":
+			if len(open) == 0 {
+				return fail(t, "unbalanced }")
+			}
+			if stmt != nil {
+				return fail(*stmt, "missing ;")
+			}
+			open = open[:len(open)-1]
+			stmt = &toks[i]
+		case t.text == ";":
+			stmt = nil
+		case stmt == nil:
+			stmt = &toks[i]
+		}
+	}
+	switch {
+	case len(open) > 0:
+		return fail(open[len(open)-1], "unclosed {")
+	case stmt != nil:
+		return fail(*stmt, "missing ;")
+	}
+	return nil
+}
+
+// layoutSource is layout for lexSource tokens, keeping comments and blank
+// lines. A comment on the line of the previous token stays at the end of
+// that line.
+func layoutSource(toks []srcToken, f Format) string {
+	unit := f.unit()
+	var out, line, lead []string
+	depth := 0
+	bang, gap := false, false
+	blank := func() {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+	emit := func(s string) {
+		if gap {
+			blank()
+			gap = false
+		}
+		out = append(out, strings.Repeat(unit, depth)+s)
+	}
+	flush := func(end string) {
+		for _, c := range lead {
+			emit(c)
+		}
+		emit(strings.Join(line, " ") + end)
+		line, lead = nil, nil
+	}
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		if t.comment {
+			switch {
+			case len(line) > 0:
+				lead = append(lead, t.text)
+			case t.nl == 0 && len(out) > 0:
+				out[len(out)-1] += " " + t.text
+			default:
+				if t.nl > 1 {
+					blank()
+				}
+				emit(t.text)
+			}
+			continue
+		}
+		if len(line) == 0 && !bang && t.nl > 1 {
+			blank()
+		}
+		switch t.text {
+		case "!":
+			bang = true
+		case "{":
+			if bang {
+				line, bang = append(line, "!"), false
+			}
+			end, plain := srcGroupEnd(toks, i)
+			if end == i+1 {
+				line = append(line, "{ }")
+				i = end
+				continue
+			}
+			if depth > 0 && f.InlineLists && plain {
+				texts := make([]string, 0, end-i-1)
+				for _, t := range toks[i+1 : end] {
+					texts = append(texts, t.text)
+				}
+				if list, ok := inlineList(texts); ok {
+					width := len(strings.Join(append(line, list), " ")) + 1
+					width += depth * len(strings.ReplaceAll(unit, "\t", "        "))
+					if f.MaxWidth <= 0 || width <= f.MaxWidth {
+						line = append(line, list)
+						i = end
+						continue
+					}
+				}
+			}
+			if len(line) == 0 {
+				flush("{")
+			} else {
+				flush(" {")
+			}
+			depth++
+		case "}":
+			if len(line) > 0 {
+				flush(";")
+			}
+			depth = max(depth-1, 0)
+			line = []string{"}"}
+		case ";":
+			flush(";")
+			gap = depth == 0
+		default:
+			text := t.text
+			if bang {
+				text, bang = "!"+text, false
+			}
+			line = append(line, text)
+		}
+	}
+	if len(line) > 0 {
+		flush(";")
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// srcGroupEnd returns the index of the "}" closing the group that opens at
+// toks[i], and whether the group holds no comments.
+func srcGroupEnd(toks []srcToken, i int) (int, bool) {
+	depth := 0
+	plain := true
+	for j := i; j < len(toks); j++ {
+		switch {
+		case toks[j].comment:
+			plain = false
+		case toks[j].text == "{":
+			depth++
+		case toks[j].text == "}":
+			depth--
+			if depth == 0 {
+				return j, plain
+			}
+		}
+	}
+	return len(toks) - 1, plain
+}
@@ -0,0 +1,223 @@
+// File: pkg/namedzone/fsys.go
+package namedzone
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WriteFS is a filesystem Save can write to.
+type WriteFS interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// MemFS is an in-memory WriteFS for dry runs and tests, keyed by slash
+// separated path. Directories are implied by the files below them.
+type MemFS map[string]*MemFile
+
+// MemFile is a file in a MemFS.
+type MemFile struct {
+	Data    []byte
+	Mode    fs.FileMode
+	ModTime time.Time
+}
+
+func (m MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if f, ok := m[name]; ok {
+		return &memFile{Reader: bytes.NewReader(f.Data), info: memInfo{name: path.Base(name), f: f}}, nil
+	}
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	var entries []fs.DirEntry
+	for p, f := range m {
+		rest, ok := strings.CutPrefix(p, prefix)
+		if !ok {
+			continue
+		}
+		child, _, sub := strings.Cut(rest, "/")
+		if slices.ContainsFunc(entries, func(e fs.DirEntry) bool { return e.Name() == child }) {
+			continue
+		}
+		info := memInfo{name: child, f: f}
+		if sub {
+			info.f = nil
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	if len(entries) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return &memDir{path: name, entries: entries}, nil
+}
+
+func (m MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	m[name] = &MemFile{Data: append([]byte(nil), data...), Mode: perm, ModTime: time.Now()}
+	return nil
+}
+
+// memInfo describes a MemFS file, or a directory when f is nil.
+type memInfo struct {
+	name string
+	f    *MemFile
+}
+
+func (i memInfo) Name() string { return i.name }
+func (i memInfo) IsDir() bool  { return i.f == nil }
+func (i memInfo) Sys() any     { return nil }
+
+func (i memInfo) Size() int64 {
+	if i.f == nil {
+		return 0
+	}
+	return int64(len(i.f.Data))
+}
+
+func (i memInfo) Mode() fs.FileMode {
+	if i.f == nil {
+		return fs.ModeDir | 0o555
+	}
+	return i.f.Mode
+}
+
+func (i memInfo) ModTime() time.Time {
+	if i.f == nil {
+		return time.Time{}
+	}
+	return i.f.ModTime
+}
+
+type memFile struct {
+	*bytes.Reader
+	info memInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+type memDir struct {
+	path    string
+	entries []fs.DirEntry
+	off     int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return memInfo{name: path.Base(d.path)}, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.off:]
+	if n <= 0 {
+		d.off = len(d.entries)
+		return slices.Clone(rest), nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	rest = rest[:min(n, len(rest))]
+	d.off += len(rest)
+	return slices.Clone(rest), nil
+}
+
+// osFS writes through to the OS filesystem, replacing files via a temporary
+// file like namedconf.File.Save.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) { return os.Open(name) }
+
+func (osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	tmp, err := stage(name, data, perm)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// stage writes data to a new temporary file next to name and syncs it to
+// disk, ready to be renamed over name.
+func stage(name string, data []byte, perm fs.FileMode) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, perm)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return tmp, nil
+}
+
+// FromFS loads the named.conf at name from fsys, following include
+// statements like LoadTree. Absolute include paths are looked up relative to
+// the root of fsys.
+func FromFS(fsys fs.FS, name string) (*Config, error) {
+	cfg, _, err := FromFSWith(fsys, name, LoadOptions{})
+	return cfg, err
+}
+
+// FromFSWith is FromFS with the warnings and strict mode of Load.
+func FromFSWith(fsys fs.FS, name string, opts LoadOptions) (*Config, []Warning, error) {
+	if !fs.ValidPath(name) {
+		return nil, nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return loadTree(&tree{fsys: fsys, dir: path.Dir(name)}, name, opts)
+}
+
+// SaveFS applies the typed config and writes it to name in w. For configs
+// spanning several files, changed included files are written under the
+// names they were loaded from.
+func (c *Config) SaveFS(w WriteFS, name string) error {
+	cs, err := c.applyForSave()
+	if err != nil {
+		return err
+	}
+	if _, ok := w.(osFS); ok {
+		if err := c.snapshot(name); err != nil {
+			return err
+		}
+	}
+	if err := c.audit("Save", name, cs); err != nil {
+		return err
+	}
+	for _, o := range c.outputs(w, name) {
+		if err := w.WriteFile(o.name, o.data, 0o644); err != nil {
+			return err
+		}
+		c.log().Debug("saved config", "path", o.name)
+	}
+	return nil
+}
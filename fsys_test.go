@@ -0,0 +1,47 @@
+// File: pkg/namedzone/fsys_test.go
+package namedzone
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMemFS(t *testing.T) {
+	m := MemFS{}
+	for name, data := range map[string]string{
+		"named.conf":           `include "zones/internal.conf"; options { directory "/var/named"; };`,
+		"zones/internal.conf":  `zone "internal.example" { type hint; file "internal.db"; };`,
+		"zones/keys/tsig.conf": `key "k" { algorithm hmac-sha256; secret "c2VjcmV0"; };`,
+	} {
+		if err := m.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fstest.TestFS(m, "named.conf", "zones/internal.conf", "zones/keys/tsig.conf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile("../x", nil, 0o644); err == nil {
+		t.Error("WriteFile outside the root succeeded")
+	}
+	if _, err := m.Open("nope"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open(nope) = %v", err)
+	}
+
+	cfg, err := FromFS(m, "named.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Options.Directory = "/srv/named"
+	if err := cfg.SaveFS(m, "named.conf"); err != nil {
+		t.Fatal(err)
+	}
+	back, err := FromFS(m, "named.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !back.Equal(cfg) || back.GetZone("internal.example") == nil {
+		t.Errorf("reloaded config differs:\n%s", Diff(cfg, back))
+	}
+}
@@ -2,4 +2,18 @@ module github.com/dlukt/namedzone
 
 go 1.24.6
 
-require github.com/dlukt/namedconf v0.0.0-20250817164227-ab17a41b7fe1
+require (
+	github.com/dlukt/namedconf v0.0.0-20250817164227-ab17a41b7fe1
+	github.com/miekg/dns v1.1.68
+	golang.org/x/net v0.40.0
+	google.golang.org/protobuf v1.36.12
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.24.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
+	golang.org/x/tools v0.33.0 // indirect
+)
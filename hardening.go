@@ -0,0 +1,107 @@
+// File: pkg/namedzone/hardening.go
+package namedzone
+
+import "strings"
+
+// HardeningProfile names a bundle of options ApplyHardeningProfile sets.
+type HardeningProfile string
+
+const (
+	// HardeningBaseline hides the version, restricts zone transfers to
+	// none unless already restricted, turns on response rate limiting,
+	// minimal responses and server cookie enforcement.
+	HardeningBaseline HardeningProfile = "baseline"
+	// HardeningAuthoritative is HardeningBaseline for authoritative-only
+	// servers: recursion is also turned off.
+	HardeningAuthoritative HardeningProfile = "authoritative"
+)
+
+// HardeningChange is one option ApplyHardeningProfile changed. Old is empty
+// when the option was unset.
+type HardeningChange struct {
+	Option string `json:"option"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new"`
+}
+
+// hardening is one setting of a profile. keep reports whether the current
+// value already meets it; set applies it.
+type hardening struct {
+	option string
+	keep   func(c *Config, o *Options) bool
+	set    func(o *Options)
+}
+
+var hardeningProfiles = map[HardeningProfile][]hardening{
+	HardeningBaseline: baselineHardening,
+	HardeningAuthoritative: append(baselineHardening[:len(baselineHardening):len(baselineHardening)], hardening{
+		option: "recursion",
+		keep:   func(_ *Config, o *Options) bool { return o.Recursion != nil && !*o.Recursion },
+		set:    func(o *Options) { o.Recursion = BoolPtr(false) },
+	}),
+}
+
+var baselineHardening = []hardening{
+	{
+		option: "version",
+		keep:   func(_ *Config, o *Options) bool { return o.Version != nil && o.Version.Keyword == "none" },
+		set:    func(o *Options) { o.Version = &ServerIdent{Keyword: "none"} },
+	},
+	{
+		option: "allow-transfer",
+		keep: func(c *Config, o *Options) bool {
+			return len(o.AllowTransfer) > 0 && !c.admitsAll(o.AllowTransfer)
+		},
+		set: func(o *Options) { o.AllowTransfer = []MatchTerm{{ACLRef: "none"}} },
+	},
+	rawHardening("rate-limit", "{ responses-per-second 10; window 5; }", true),
+	{
+		option: "minimal-responses",
+		keep:   func(_ *Config, o *Options) bool { return o.MinimalResponses == MinimalResponsesYes },
+		set:    func(o *Options) { o.MinimalResponses = MinimalResponsesYes },
+	},
+	rawHardening("require-server-cookie", "yes", false),
+}
+
+// rawHardening sets an un-modeled option to raw. With anySet, any existing
+// value is kept, for blocks whose tuning is site-specific.
+func rawHardening(option, raw string, anySet bool) hardening {
+	return hardening{
+		option: option,
+		keep: func(_ *Config, o *Options) bool {
+			cur, ok := o.Get(option)
+			return ok && (anySet || strings.Join(strings.Fields(cur), " ") == raw)
+		},
+		set: func(o *Options) { _ = o.Set(option, raw) },
+	}
+}
+
+// ApplyHardeningProfile brings options up to profile and returns the options
+// it changed, in profile order. Settings already met are left alone, so
+// applying a profile twice changes nothing the second time. Zone and view
+// level overrides are not touched; Lint reports the risky ones.
+func (c *Config) ApplyHardeningProfile(profile HardeningProfile) ([]HardeningChange, error) {
+	settings, ok := hardeningProfiles[profile]
+	if !ok {
+		return nil, &ValueError{Path: "profile", Value: string(profile), Msg: "unknown hardening profile"}
+	}
+	o := c.Options
+	if o == nil {
+		o = &Options{}
+	}
+	var changes []HardeningChange
+	for _, h := range settings {
+		if h.keep(c, o) {
+			continue
+		}
+		old, _ := o.Get(h.option)
+		h.set(o)
+		cur, _ := o.Get(h.option)
+		changes = append(changes, HardeningChange{Option: h.option, Old: old, New: cur})
+	}
+	if len(changes) > 0 {
+		c.Options = o
+		c.audit("ApplyHardeningProfile", string(profile), nil)
+	}
+	return changes, nil
+}
@@ -1,6 +1,38 @@
 // File: pkg/namedzone/helpers.go
 package namedzone
 
+import "log/slog"
+
 // BoolPtr returns a pointer to the provided bool.
 // Useful for succinctly setting optional fields in the typed API.
 func BoolPtr(b bool) *bool { return &b }
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// SetLogger routes diagnostics emitted by Apply and Save to l (nil discards).
+func (c *Config) SetLogger(l *slog.Logger) { c.logger = l }
+
+func (c *Config) log() *slog.Logger {
+	if c.logger == nil {
+		return discardLogger
+	}
+	return c.logger
+}
+
+// OrderPolicy decides where Apply writes the statements of modeled items.
+type OrderPolicy int
+
+const (
+	// OrderInPlace rewrites an edited item where its statement stands and
+	// puts a new item after the item before it in its slice (or before the
+	// first one with a statement, or at the end of the file). Reordering a
+	// slice does not move statements. This is the default.
+	OrderInPlace OrderPolicy = iota
+
+	// OrderItems writes the statements of each kind in slice order,
+	// starting where the first of them stands in the file.
+	OrderItems
+)
+
+// SetOrder selects the OrderPolicy used by Apply and Save.
+func (c *Config) SetOrder(p OrderPolicy) { c.order = p }
@@ -0,0 +1,209 @@
+// File: pkg/namedzone/history.go
+package namedzone
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// snapshotID formats snapshot IDs; they sort by time.
+const snapshotID = "20060102T150405.000000000Z"
+
+// History configures the snapshots Save keeps of the files it replaces.
+type History struct {
+	// Dir holds the snapshots, one subdirectory each. Empty means
+	// <config file>.snapshots next to the config file.
+	Dir string
+	// Keep is how many snapshots are kept, oldest dropped first; 0 means 10.
+	Keep int
+}
+
+// Snapshot describes one saved state of the config files.
+type Snapshot struct {
+	ID    string    `json:"id"`
+	Time  time.Time `json:"time"`
+	Files []string  `json:"files"` // absolute paths, root file first
+}
+
+// SetHistory makes Save and SaveWith on the OS filesystem snapshot the
+// config files, as they are on disk, before replacing them.
+func (c *Config) SetHistory(h History) { c.history = &h }
+
+func (c *Config) historyDir() (string, error) {
+	if c.history == nil {
+		return "", errors.New("namedzone: no history configured; call SetHistory")
+	}
+	if c.history.Dir != "" {
+		return c.history.Dir, nil
+	}
+	if c.savedAs == "" {
+		return "", errors.New("namedzone: config file unknown; save or load it from disk first")
+	}
+	return c.savedAs + ".snapshots", nil
+}
+
+// snapshot records the on-disk content of the config files before a save
+// to path and trims the ring. Files that do not exist yet are skipped.
+func (c *Config) snapshot(path string) error {
+	if c.history == nil {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	c.savedAs = abs
+	dir, err := c.historyDir()
+	if err != nil {
+		return err
+	}
+	files := []string{abs}
+	for _, name := range c.Files() {
+		if name != c.root {
+			files = append(files, name)
+		}
+	}
+	snap := Snapshot{ID: time.Now().UTC().Format(snapshotID)}
+	sdir := filepath.Join(dir, snap.ID)
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if len(snap.Files) == 0 {
+			if err := os.MkdirAll(sdir, 0o750); err != nil {
+				return err
+			}
+		}
+		if err := os.WriteFile(filepath.Join(sdir, strconv.Itoa(len(snap.Files))), data, 0o640); err != nil {
+			return err
+		}
+		snap.Files = append(snap.Files, name)
+	}
+	if len(snap.Files) == 0 {
+		return nil
+	}
+	snap.Time, _ = time.Parse(snapshotID, snap.ID)
+	manifest, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(sdir, "manifest.json"), manifest, 0o640); err != nil {
+		return err
+	}
+	c.log().Debug("snapshot taken", "id", snap.ID, "files", len(snap.Files))
+
+	all, err := c.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	keep := c.history.Keep
+	if keep <= 0 {
+		keep = 10
+	}
+	for _, old := range all[:max(len(all)-keep, 0)] {
+		if err := os.RemoveAll(filepath.Join(dir, old.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSnapshots returns the kept snapshots, oldest first.
+func (c *Config) ListSnapshots() ([]Snapshot, error) {
+	dir, err := c.historyDir()
+	if err != nil {
+		return nil, err
+	}
+	ents, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Snapshot
+	for _, e := range ents {
+		if !e.IsDir() {
+			continue
+		}
+		s, err := readSnapshot(dir, e.Name())
+		if err != nil {
+			continue // not ours
+		}
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b Snapshot) int { return a.Time.Compare(b.Time) })
+	return out, nil
+}
+
+func readSnapshot(dir, id string) (Snapshot, error) {
+	var s Snapshot
+	b, err := os.ReadFile(filepath.Join(dir, id, "manifest.json"))
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(b, &s)
+	return s, err
+}
+
+// Rollback writes the files of snapshot id back in place and reloads c from
+// them; unsaved edits are discarded. The state being replaced is itself
+// snapshotted first, so a rollback can be undone.
+func (c *Config) Rollback(id string) error {
+	dir, err := c.historyDir()
+	if err != nil {
+		return err
+	}
+	snap, err := readSnapshot(dir, id)
+	if err != nil {
+		return &ValueError{Path: "snapshot", Value: id, Msg: "no such snapshot"}
+	}
+	data := make([][]byte, len(snap.Files))
+	for i := range snap.Files {
+		if data[i], err = os.ReadFile(filepath.Join(dir, id, strconv.Itoa(i))); err != nil {
+			return err
+		}
+	}
+	if err := c.snapshot(snap.Files[0]); err != nil {
+		return err
+	}
+	for i, name := range snap.Files {
+		if err := (osFS{}).WriteFile(name, data[i], filePerm(name)); err != nil {
+			return err
+		}
+	}
+
+	var n *Config
+	if c.files != nil {
+		n, _, err = LoadTreeWith(snap.Files[0], LoadOptions{Logger: c.logger})
+	} else {
+		var f *nc.File
+		if f, err = nc.ParseFile(snap.Files[0]); err == nil {
+			n, _, err = Load(f, LoadOptions{Logger: c.logger})
+		}
+	}
+	if err != nil {
+		return err
+	}
+	n.history, n.savedAs, n.order, n.format, n.lock = c.history, c.savedAs, c.order, c.format, c.lock
+	n.auditor, n.validators, n.idn = c.auditor, c.validators, c.idn
+	*c = *n
+	if c.idn {
+		_ = c.punycodeZones()
+	}
+	c.log().Debug("rolled back", "id", id)
+	c.audit("Rollback", id, nil)
+	return nil
+}
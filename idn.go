@@ -0,0 +1,100 @@
+// File: pkg/namedzone/idn.go
+package namedzone
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// idnaProfile is the UTS #46 lookup mapping, nontransitional as in IDNA2008,
+// without the STD3 hostname rules so labels such as _msdcs are accepted.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+	idna.StrictDomainName(false),
+)
+
+// ToASCII converts a domain name with Unicode labels to its punycode form,
+// e.g. "Bücher.example" to "xn--bcher-kva.example", applying the UTS #46
+// mapping (case folding and normalization) first. Names that are already
+// ASCII are returned unchanged.
+func ToASCII(name string) (string, error) {
+	if isASCII(name) {
+		return name, nil
+	}
+	ascii, err := idnaProfile.ToASCII(name)
+	if err != nil {
+		return "", &ValueError{Path: "name", Value: name, Msg: err.Error()}
+	}
+	return ascii, nil
+}
+
+// ToUnicode converts the xn-- labels of a domain name back to Unicode.
+func ToUnicode(name string) (string, error) {
+	u, err := idnaProfile.ToUnicode(name)
+	if err != nil {
+		return "", &ValueError{Path: "name", Value: name, Msg: err.Error()}
+	}
+	return u, nil
+}
+
+// SetIDN turns on internationalized zone names: names given in Unicode (to
+// UpsertZone, GetZone and friends, or set directly on Zone.Name) are stored
+// and written as punycode, and Zone.UnicodeName carries the readable form
+// for the JSON projection. Turning it on converts the zones already there.
+func (c *Config) SetIDN(on bool) error {
+	c.idn = on
+	if !on {
+		return nil
+	}
+	return c.punycodeZones()
+}
+
+// punycodeZones stores every zone name in ASCII form and refreshes
+// UnicodeName.
+func (c *Config) punycodeZones() error {
+	var errs []error
+	for _, z := range c.AllZones() {
+		ascii, err := ToASCII(z.Name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		z.Name, z.UnicodeName = ascii, ""
+		if u, err := ToUnicode(ascii); err == nil && u != ascii {
+			z.UnicodeName = u
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// zoneKey maps a zone name used for lookup to the stored form.
+func (c *Config) zoneKey(name string) string {
+	if !c.idn || isASCII(name) {
+		return name
+	}
+	if ascii, err := ToASCII(name); err == nil {
+		return ascii
+	}
+	return name
+}
+
+// idnZone is zoneKey for a zone about to be stored.
+func (c *Config) idnZone(z Zone) Zone {
+	if n := c.zoneKey(z.Name); n != z.Name {
+		z.UnicodeName, z.Name = z.Name, n
+	}
+	return z
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+// File: pkg/namedzone/idn_test.go
+package namedzone
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToASCII(t *testing.T) {
+	for in, want := range map[string]string{
+		"Bücher.DE":          "xn--bcher-kva.de",
+		"bücher.example.":    "xn--bcher-kva.example.",
+		"_msdcs.bücher.test": "_msdcs.xn--bcher-kva.test",
+		"ＥＸＡＭＰＬＥ.bücher":     "example.xn--bcher-kva",
+		"Example.COM":        "Example.COM",
+		".":                  ".",
+	} {
+		got, err := ToASCII(in)
+		if err != nil || got != want {
+			t.Errorf("ToASCII(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if u, err := ToUnicode("xn--bcher-kva.example."); err != nil || u != "bücher.example." {
+		t.Errorf("ToUnicode = %q, %v", u, err)
+	}
+}
+
+// TestRenderCanonicalIDN leaves the zone names of c as they are.
+func TestRenderCanonicalIDN(t *testing.T) {
+	c := &Config{Zones: []Zone{{Name: "bücher.example", Type: ZoneHint, File: "b"}}}
+	c.idn = true
+	text, err := c.RenderCanonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, `zone "xn--bcher-kva.example"`) {
+		t.Errorf("no punycode name in\n%s", text)
+	}
+	if z := c.Zones[0]; z.Name != "bücher.example" || z.UnicodeName != "" {
+		t.Errorf("zone changed to %q (%q)", z.Name, z.UnicodeName)
+	}
+}
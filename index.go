@@ -0,0 +1,198 @@
+// File: pkg/namedzone/index.go
+package namedzone
+
+import "sync"
+
+// Lookups by name (GetZone, FindView, the Upsert and Remove methods) go
+// through name→position maps built on first use, so they stay O(1) on
+// configs with tens of thousands of zones.
+//
+// An index belongs to one slice: it is rebuilt when the slice no longer has
+// the length and backing array it was built for, which covers appending and
+// assigning a new slice to Zones, Views or View.Zones. The Remove methods
+// drop the index they filter. Every hit is also checked against the
+// element's name, so reordering elements in place is caught as well. What
+// is not caught is giving an element a new name in place (c.Zones[i].Name =
+// ..., or assigning a differently named Zone to c.Zones[i]), or removing
+// elements in place through the exported fields (slices.Delete) and then
+// appending back to the old length: until Reindex is called, lookups may
+// miss.
+type index struct {
+	mu        sync.Mutex
+	zones     nameIndex
+	views     nameIndex
+	viewZones map[string]*nameIndex // by view name
+}
+
+// nameIndex maps names to positions in one slice; first and n identify the
+// slice it was built for.
+type nameIndex struct {
+	first any // *T of element 0
+	n     int
+	pos   map[string]int
+}
+
+// indexInit guards the creation of Config.index.
+var indexInit sync.Mutex
+
+func (c *Config) lookups() *index {
+	indexInit.Lock()
+	defer indexInit.Unlock()
+	if c.index == nil {
+		c.index = &index{}
+	}
+	return c.index
+}
+
+// Reindex drops the lookup indexes, so the next lookup rebuilds them. Call
+// it after renaming zones or views in place through the exported fields.
+func (c *Config) Reindex() {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.zones, ix.views, ix.viewZones = nameIndex{}, nameIndex{}, nil
+}
+
+// forgetZones, forgetViews and forgetZonesIn drop an index after its slice
+// was filtered in place. The slice keeps its backing array, so a later
+// append could restore the length the index was built for.
+func (c *Config) forgetZones() {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.zones = nameIndex{}
+}
+
+func (c *Config) forgetViews() {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.views, ix.viewZones = nameIndex{}, nil
+}
+
+func (c *Config) forgetZonesIn(view string) {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	delete(ix.viewZones, view)
+}
+
+// find returns the position of the first element of s named key, or -1.
+func find[T any](ix *nameIndex, s []T, name func(*T) string, key string) int {
+	for range 2 {
+		if !built(ix, s) {
+			build(ix, s, name)
+		}
+		i, ok := ix.pos[key]
+		if !ok {
+			return -1
+		}
+		if name(&s[i]) == key {
+			return i
+		}
+		ix.pos = nil // reordered in place
+	}
+	return -1
+}
+
+func built[T any](ix *nameIndex, s []T) bool {
+	return ix.pos != nil && ix.n == len(s) && ix.first == head(s)
+}
+
+func build[T any](ix *nameIndex, s []T, name func(*T) string) {
+	ix.first, ix.n, ix.pos = head(s), len(s), make(map[string]int, len(s))
+	for i := range s {
+		if _, dup := ix.pos[name(&s[i])]; !dup {
+			ix.pos[name(&s[i])] = i
+		}
+	}
+}
+
+// appended records that s grew by one element named key, so an append does
+// not cost a rebuild. It must be called with the slice before and after.
+func appended[T any](ix *nameIndex, before, after []T, key string) {
+	if !built(ix, before) {
+		return
+	}
+	ix.first, ix.n = head(after), len(after)
+	if _, dup := ix.pos[key]; !dup {
+		ix.pos[key] = len(after) - 1
+	}
+}
+
+func head[T any](s []T) *T {
+	if len(s) == 0 {
+		return nil
+	}
+	return &s[0]
+}
+
+func zoneName(z *Zone) string { return z.Name }
+func viewName(v *View) string { return v.Name }
+
+// zonePos returns the position of the top-level zone name, or -1.
+func (c *Config) zonePos(name string) int {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return find(&ix.zones, c.Zones, zoneName, name)
+}
+
+// viewPos returns the position of the view name, or -1.
+func (c *Config) viewPos(name string) int {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return find(&ix.views, c.Views, viewName, name)
+}
+
+// zonePosIn returns the position of zone name in view v of c, or -1.
+func (c *Config) zonePosIn(v *View, name string) int {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return find(ix.inView(v.Name), v.Zones, zoneName, name)
+}
+
+func (ix *index) inView(view string) *nameIndex {
+	if ix.viewZones == nil {
+		ix.viewZones = map[string]*nameIndex{}
+	}
+	vz := ix.viewZones[view]
+	if vz == nil {
+		vz = &nameIndex{}
+		ix.viewZones[view] = vz
+	}
+	return vz
+}
+
+// appendZone appends z to the top-level zones, keeping the index current.
+func (c *Config) appendZone(z Zone) {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	before := c.Zones
+	c.Zones = append(c.Zones, z)
+	appended(&ix.zones, before, c.Zones, z.Name)
+}
+
+// appendView appends v to the views, keeping the index current.
+func (c *Config) appendView(v View) {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	before := c.Views
+	c.Views = append(c.Views, v)
+	appended(&ix.views, before, c.Views, v.Name)
+}
+
+// appendZoneIn appends z to the zones of view v of c, keeping the index
+// current.
+func (c *Config) appendZoneIn(v *View, z Zone) {
+	ix := c.lookups()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	before := v.Zones
+	v.Zones = append(v.Zones, z)
+	appended(ix.inView(v.Name), before, v.Zones, z.Name)
+}
@@ -0,0 +1,68 @@
+// File: pkg/namedzone/index_test.go
+package namedzone
+
+import (
+	"slices"
+	"testing"
+)
+
+func indexed(names ...string) *Config {
+	c := &Config{}
+	for _, n := range names {
+		c.Zones = append(c.Zones, Zone{Name: n, Type: ZoneHint, File: n})
+	}
+	c.GetZone(names[0]) // build the index
+	return c
+}
+
+// TestIndexInvalidation checks the edits the lookup index documents as
+// caught.
+func TestIndexInvalidation(t *testing.T) {
+	for name, edit := range map[string]func(c *Config){
+		"append": func(c *Config) { c.Zones = append(c.Zones, Zone{Name: "d"}) },
+		"remove then append": func(c *Config) {
+			c.RemoveZone("b")
+			c.Zones = append(c.Zones, Zone{Name: "d"})
+		},
+		"new slice": func(c *Config) { c.Zones = []Zone{{Name: "c"}, {Name: "d"}, {Name: "a"}} },
+		"reorder":   func(c *Config) { c.Zones[0], c.Zones[2] = c.Zones[2], c.Zones[0] },
+		"rename and reindex": func(c *Config) {
+			c.Zones[1].Name = "d"
+			c.Reindex()
+		},
+	} {
+		c := indexed("a", "b", "c")
+		edit(c)
+		for _, z := range slices.Backward(c.Zones) {
+			if got := c.GetZone(z.Name); got == nil || got.Name != z.Name {
+				t.Errorf("%s: GetZone(%q) = %v", name, z.Name, got)
+			}
+		}
+		n, last := len(c.Zones), c.Zones[len(c.Zones)-1].Name
+		c.UpsertZone(Zone{Name: last, Type: ZoneHint, File: "new"})
+		if len(c.Zones) != n || c.GetZone(last).File != "new" {
+			t.Errorf("%s: UpsertZone of an existing zone appended: %v", name, c.Zones)
+		}
+	}
+}
+
+// TestIndexInvalidationViews covers views and the zones of a view, which
+// have their own indexes.
+func TestIndexInvalidationViews(t *testing.T) {
+	c := &Config{}
+	for _, n := range []string{"a", "b", "c"} {
+		c.UpsertView(View{Name: n})
+		c.UpsertZoneInView("a", Zone{Name: n, Type: ZoneHint, File: n})
+	}
+	c.RemoveView("b")
+	c.Views = append(c.Views, View{Name: "d"})
+	if v := c.FindView("d"); v == nil || v.Name != "d" {
+		t.Errorf("FindView(d) = %v", v)
+	}
+	c.RemoveZoneInView("a", "b")
+	v := c.FindView("a")
+	v.Zones = append(v.Zones, Zone{Name: "d"})
+	if z := c.GetZoneInView("a", "d"); z == nil || z.Name != "d" {
+		t.Errorf("GetZoneInView(a, d) = %v", z)
+	}
+}
@@ -0,0 +1,137 @@
+// File: pkg/namedzone/iter.go
+package namedzone
+
+import (
+	"fmt"
+	"iter"
+)
+
+// AllZones yields every zone with the view that holds it: top-level zones
+// first, with a nil view, then the zones of each view in order. Both
+// pointers address the elements of c, so edits through them stick. The
+// slices must not be grown or shrunk while iterating.
+func (c *Config) AllZones() iter.Seq2[*View, *Zone] {
+	return func(yield func(*View, *Zone) bool) {
+		for i := range c.Zones {
+			if !yield(nil, &c.Zones[i]) {
+				return
+			}
+		}
+		for i := range c.Views {
+			v := &c.Views[i]
+			for j := range v.Zones {
+				if !yield(v, &v.Zones[j]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AllMatchLists yields every address match list in c with its GetPath path,
+// e.g. "view[internal].matchClients" or "controls.inet[#0].allow". Lists
+// that are unset are skipped.
+func (c *Config) AllMatchLists() iter.Seq2[string, *[]MatchTerm] {
+	return func(yield func(string, *[]MatchTerm) bool) {
+		list := func(path string, l *[]MatchTerm) bool {
+			return len(*l) == 0 || yield(path, l)
+		}
+		zone := func(p string, z *Zone) bool {
+			return list(p+".allowUpdate", &z.AllowUpdate) && list(p+".allowTransfer", &z.AllowTransfer)
+		}
+		for i := range c.ACLs {
+			if !list(fmt.Sprintf("acl[%s].elements", c.ACLs[i].Name), &c.ACLs[i].Elements) {
+				return
+			}
+		}
+		if ct := c.Controls; ct != nil {
+			for i := range ct.Inet {
+				if !list(fmt.Sprintf("controls.inet[#%d].allow", i), &ct.Inet[i].Allow) {
+					return
+				}
+			}
+		}
+		if o := c.Options; o != nil {
+			if !list("options.allowQuery", &o.AllowQuery) ||
+				!list("options.allowTransfer", &o.AllowTransfer) ||
+				!list("options.allowUpdate", &o.AllowUpdate) {
+				return
+			}
+			if o.ListenOn != nil && !list("options.listenOn.addrs", &o.ListenOn.Addrs) {
+				return
+			}
+			if o.ListenOnV6 != nil && !list("options.listenOnV6.addrs", &o.ListenOnV6.Addrs) {
+				return
+			}
+			if o.ResponsePadding != nil && !list("options.responsePadding.clients", &o.ResponsePadding.Clients) {
+				return
+			}
+		}
+		for i := range c.Zones {
+			if !zone("zone["+c.Zones[i].Name+"]", &c.Zones[i]) {
+				return
+			}
+		}
+		for i := range c.Views {
+			v := &c.Views[i]
+			p := "view[" + v.Name + "]"
+			if !list(p+".matchClients", &v.MatchClients) || !list(p+".matchDestinations", &v.MatchDestinations) {
+				return
+			}
+			if v.ResponsePadding != nil && !list(p+".responsePadding.clients", &v.ResponsePadding.Clients) {
+				return
+			}
+			for j := range v.Zones {
+				if !zone(p+".zone["+v.Zones[j].Name+"]", &v.Zones[j]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AllACLRefs yields every match term that names an ACL, nested lists
+// included, with its GetPath path such as "zone[example.com].allowTransfer[#1]".
+// Built-in ACLs (any, none, localhost, localnets) are yielded too.
+func (c *Config) AllACLRefs() iter.Seq2[string, *MatchTerm] {
+	return func(yield func(string, *MatchTerm) bool) {
+		for path, l := range c.AllMatchLists() {
+			if !aclRefs(path, *l, yield) {
+				return
+			}
+		}
+	}
+}
+
+func aclRefs(path string, terms []MatchTerm, yield func(string, *MatchTerm) bool) bool {
+	for i := range terms {
+		t := &terms[i]
+		p := fmt.Sprintf("%s[#%d]", path, i)
+		switch {
+		case len(t.Nested) > 0:
+			if !aclRefs(p+".nested", t.Nested, yield) {
+				return false
+			}
+		case t.ACLRef != "":
+			if !yield(p, t) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// zonesByPath is AllZones keyed by GetPath path.
+func (c *Config) zonesByPath() iter.Seq2[string, *Zone] {
+	return func(yield func(string, *Zone) bool) {
+		for v, z := range c.AllZones() {
+			p := "zone[" + z.Name + "]"
+			if v != nil {
+				p = "view[" + v.Name + "]." + p
+			}
+			if !yield(p, z) {
+				return
+			}
+		}
+	}
+}
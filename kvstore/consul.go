@@ -0,0 +1,112 @@
+// File: pkg/namedzone/kvstore/consul.go
+package kvstore
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Consul is a Store on the Consul KV store. Revisions are the keys'
+// ModifyIndex. Consul limits values to 512 KiB by default.
+type Consul struct {
+	// Address is the base URL of the agent, e.g. "http://127.0.0.1:8500".
+	Address string
+	// Token is sent as X-Consul-Token when set.
+	Token string
+	// Datacenter selects a datacenter other than the agent's.
+	Datacenter string
+	// Client sends the requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// Get implements Store.
+func (c *Consul) Get(ctx context.Context, key string) ([]byte, string, error) {
+	var out []struct {
+		Value       []byte
+		ModifyIndex int64
+	}
+	status, err := c.do(ctx, http.MethodGet, "/v1/kv/"+strings.TrimPrefix(key, "/"), nil, &out)
+	if status == http.StatusNotFound {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if len(out) == 0 {
+		return nil, "", ErrNotFound
+	}
+	return out[0].Value, strconv.FormatInt(out[0].ModifyIndex, 10), nil
+}
+
+// CompareAndSwap implements Store with a transaction running the cas verb,
+// which also returns the new ModifyIndex.
+func (c *Consul) CompareAndSwap(ctx context.Context, key string, value []byte, rev string) (string, error) {
+	n, err := intRev(key, rev)
+	if err != nil {
+		return "", err
+	}
+	txn := []any{map[string]any{"KV": map[string]any{"Verb": "cas", "Key": strings.TrimPrefix(key, "/"), "Value": value, "Index": n}}}
+	var out struct {
+		Results []struct {
+			KV struct {
+				ModifyIndex int64
+			}
+		}
+	}
+	status, err := c.do(ctx, http.MethodPut, "/v1/txn", txn, &out)
+	if status == http.StatusConflict {
+		return "", conflict(key, rev)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(out.Results) == 0 {
+		return "", fmt.Errorf("namedzone: consul: transaction returned no result")
+	}
+	return strconv.FormatInt(out.Results[0].KV.ModifyIndex, 10), nil
+}
+
+// do sends a request and decodes a 200 response into out; it returns the
+// status code with any error.
+func (c *Consul) do(ctx context.Context, method, path string, req, out any) (int, error) {
+	u := strings.TrimSuffix(c.Address, "/") + path
+	if c.Datacenter != "" {
+		u += "?dc=" + url.QueryEscape(c.Datacenter)
+	}
+	var body io.Reader
+	if req != nil {
+		b, err := json.Marshal(req)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(b)
+	}
+	r, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return 0, err
+	}
+	if c.Token != "" {
+		r.Header.Set("X-Consul-Token", c.Token)
+	}
+	resp, err := cmp.Or(c.Client, http.DefaultClient).Do(r)
+	if err != nil {
+		return 0, fmt.Errorf("namedzone: consul: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("namedzone: consul: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("namedzone: consul: %s: %s", resp.Status, bytes.TrimSpace(b))
+	}
+	return resp.StatusCode, json.Unmarshal(b, out)
+}
@@ -0,0 +1,150 @@
+// File: pkg/namedzone/kvstore/etcd.go
+package kvstore
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Etcd is a Store on etcd v3, through its JSON gateway (/v3/kv/...).
+// Revisions are the keys' mod revisions.
+type Etcd struct {
+	// Endpoint is the base URL of an etcd member, e.g.
+	// "https://etcd-0:2379".
+	Endpoint string
+	// Username and Password authenticate when etcd has auth enabled.
+	Username, Password string
+	// Client sends the requests, e.g. with client certificates; nil
+	// means http.DefaultClient.
+	Client *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// etcdInt reads the int64 fields the gateway encodes as strings.
+type etcdInt int64
+
+func (n *etcdInt) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseInt(strings.Trim(string(b), `"`), 10, 64)
+	*n = etcdInt(v)
+	return err
+}
+
+// Get implements Store.
+func (e *Etcd) Get(ctx context.Context, key string) ([]byte, string, error) {
+	var out struct {
+		KVs []struct {
+			Value       []byte  `json:"value"`
+			ModRevision etcdInt `json:"mod_revision"`
+		} `json:"kvs"`
+	}
+	if err := e.call(ctx, "/v3/kv/range", map[string]any{"key": []byte(key)}, &out); err != nil {
+		return nil, "", err
+	}
+	if len(out.KVs) == 0 {
+		return nil, "", ErrNotFound
+	}
+	return out.KVs[0].Value, strconv.FormatInt(int64(out.KVs[0].ModRevision), 10), nil
+}
+
+// CompareAndSwap implements Store with a transaction comparing the mod
+// revision, or the version for a new key.
+func (e *Etcd) CompareAndSwap(ctx context.Context, key string, value []byte, rev string) (string, error) {
+	n, err := intRev(key, rev)
+	if err != nil {
+		return "", err
+	}
+	compare := map[string]any{"key": []byte(key), "result": "EQUAL", "target": "MOD", "mod_revision": n}
+	if n == 0 {
+		compare = map[string]any{"key": []byte(key), "result": "EQUAL", "target": "VERSION", "version": 0}
+	}
+	txn := map[string]any{
+		"compare": []any{compare},
+		"success": []any{map[string]any{"request_put": map[string]any{"key": []byte(key), "value": value}}},
+	}
+	var out struct {
+		Header struct {
+			Revision etcdInt `json:"revision"`
+		} `json:"header"`
+		Succeeded bool `json:"succeeded"`
+	}
+	if err := e.call(ctx, "/v3/kv/txn", txn, &out); err != nil {
+		return "", err
+	}
+	if !out.Succeeded {
+		return "", conflict(key, rev)
+	}
+	return strconv.FormatInt(int64(out.Header.Revision), 10), nil
+}
+
+// call posts req to the gateway path and decodes the response into out,
+// authenticating first when a username is set.
+func (e *Etcd) call(ctx context.Context, path string, req, out any) error {
+	token, err := e.auth(ctx)
+	if err != nil {
+		return err
+	}
+	return e.post(ctx, path, token, req, out)
+}
+
+func (e *Etcd) auth(ctx context.Context) (string, error) {
+	if e.Username == "" {
+		return "", nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != "" {
+		return e.token, nil
+	}
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := e.post(ctx, "/v3/auth/authenticate", "", map[string]string{"name": e.Username, "password": e.Password}, &out); err != nil {
+		return "", err
+	}
+	e.token = out.Token
+	return e.token, nil
+}
+
+func (e *Etcd) post(ctx context.Context, path, token string, req, out any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(e.Endpoint, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		r.Header.Set("Authorization", token)
+	}
+	resp, err := cmp.Or(e.Client, http.DefaultClient).Do(r)
+	if err != nil {
+		return fmt.Errorf("namedzone: etcd: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("namedzone: etcd: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusUnauthorized && token != "" {
+			// expired token: authenticate again on the next call
+			e.mu.Lock()
+			e.token = ""
+			e.mu.Unlock()
+		}
+		return fmt.Errorf("namedzone: etcd: %s: %s", resp.Status, bytes.TrimSpace(b))
+	}
+	return json.Unmarshal(b, out)
+}
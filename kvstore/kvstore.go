@@ -0,0 +1,132 @@
+// File: pkg/namedzone/kvstore/kvstore.go
+
+// Package kvstore loads and saves configs in a key-value store instead of
+// local files, with compare-and-swap so concurrent writers cannot overwrite
+// each other's changes. Etcd, Consul and S3 implement Store over the
+// stores' HTTP APIs (the etcd v3 JSON gateway, the Consul KV and
+// transaction endpoints, and S3 conditional writes with Signature Version
+// 4), so the package needs no client libraries.
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/dlukt/namedzone"
+)
+
+// ErrNotFound is returned by Store.Get and Load for a key that is not set.
+var ErrNotFound = errors.New("namedzone: key not found")
+
+// Store is a key-value store with revisions. Revisions are opaque strings:
+// a number for etcd and Consul, an ETag for S3.
+type Store interface {
+	// Get returns the value of key and its revision, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	// CompareAndSwap sets key to value if its revision is still rev, where
+	// "" means the key must not exist, and returns the new revision. A key
+	// changed in the meantime fails with a *namedzone.ConflictError.
+	CompareAndSwap(ctx context.Context, key string, value []byte, rev string) (string, error)
+}
+
+// Load reads the config stored at key and returns it with the revision to
+// pass to Save. The value may be the JSON projection of a Config, as Save
+// writes it, or named.conf text, as SaveText writes it.
+func Load(ctx context.Context, s Store, key string) (*namedzone.Config, string, error) {
+	b, rev, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	c, _, err := decode(b)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, rev, nil
+}
+
+// decode reads a stored config; text reports named.conf text.
+func decode(b []byte) (c *namedzone.Config, text bool, err error) {
+	if t := bytes.TrimSpace(b); len(t) > 0 && t[0] == '{' {
+		c = &namedzone.Config{}
+		return c, false, json.Unmarshal(t, c)
+	}
+	c, err = namedzone.FromReader(bytes.NewReader(b))
+	return c, true, err
+}
+
+// Save stores the JSON projection of c at key if the key is still at
+// revision rev ("" for a new key) and returns the new revision.
+func Save(ctx context.Context, s Store, key string, c *namedzone.Config, rev string) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return s.CompareAndSwap(ctx, key, b, rev)
+}
+
+// SaveText is Save storing c as named.conf text.
+func SaveText(ctx context.Context, s Store, key string, c *namedzone.Config, rev string) (string, error) {
+	text, err := c.Render()
+	if err != nil {
+		return "", err
+	}
+	return s.CompareAndSwap(ctx, key, []byte(text), rev)
+}
+
+// Update applies fn to the config at key and saves the result in the
+// format it was stored in, starting over from a fresh read when another
+// writer got there first, up to attempts times. A missing key starts from
+// an empty config. It returns the new revision.
+func Update(ctx context.Context, s Store, key string, attempts int, fn func(*namedzone.Config) error) (string, error) {
+	var err error
+	for range max(attempts, 1) {
+		c, text := &namedzone.Config{}, false
+		b, rev, gerr := s.Get(ctx, key)
+		switch {
+		case errors.Is(gerr, ErrNotFound):
+		case gerr != nil:
+			return "", gerr
+		default:
+			if c, text, err = decode(b); err != nil {
+				return "", err
+			}
+		}
+		if err := fn(c); err != nil {
+			return "", err
+		}
+		save := Save
+		if text {
+			save = SaveText
+		}
+		var n string
+		if n, err = save(ctx, s, key, c, rev); err == nil {
+			return n, nil
+		}
+		if !errors.Is(err, namedzone.ErrConflict) {
+			return "", err
+		}
+	}
+	return "", err
+}
+
+func conflict(key, rev string) error {
+	if rev == "" {
+		return &namedzone.ConflictError{Kind: "key", Name: key, Msg: "already exists"}
+	}
+	return &namedzone.ConflictError{Kind: "key", Name: key, Msg: "changed since revision " + rev}
+}
+
+// intRev parses the numeric revisions of etcd and Consul; "" is 0.
+func intRev(key, rev string) (int64, error) {
+	if rev == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(rev, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, &namedzone.ValueError{Path: "revision", Value: rev, Msg: "invalid revision for " + key}
+	}
+	return n, nil
+}
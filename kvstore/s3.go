@@ -0,0 +1,243 @@
+// File: pkg/namedzone/kvstore/s3.go
+package kvstore
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// S3 is a Store on an S3-compatible object store. Revisions are ETags and
+// CompareAndSwap uses conditional writes (If-Match, If-None-Match), which
+// the store must support. In a versioned bucket every save is kept as an
+// object version; GetVersion reads an old one back.
+//
+// FS exposes the objects as a filesystem, so a config split over included
+// files loads with namedzone.FromFS, include paths resolved to object keys,
+// and saves with Config.SaveFS.
+type S3 struct {
+	Bucket string
+	Region string // e.g. "eu-central-1"; empty means "us-east-1"
+	// Endpoint is the base URL of an S3-compatible service, addressed
+	// path-style (Endpoint/Bucket/key); empty means AWS, addressed
+	// virtual-hosted style.
+	Endpoint string
+	// Prefix is prepended to every key, e.g. "edge/site-1/".
+	Prefix string
+
+	// Credentials used to sign requests (AWS Signature Version 4).
+	AccessKeyID, SecretAccessKey, SessionToken string
+
+	// Client sends the requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// Get implements Store.
+func (s *S3) Get(ctx context.Context, key string) ([]byte, string, error) {
+	return s.get(ctx, key, "")
+}
+
+// GetVersion returns the value of key as of object version id, with its
+// ETag, in a versioned bucket.
+func (s *S3) GetVersion(ctx context.Context, key, id string) ([]byte, string, error) {
+	return s.get(ctx, key, id)
+}
+
+func (s *S3) get(ctx context.Context, key, version string) ([]byte, string, error) {
+	q := url.Values{}
+	if version != "" {
+		q.Set("versionId", version)
+	}
+	resp, body, err := s.do(ctx, http.MethodGet, key, q, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, resp.Header.Get("ETag"), nil
+	case http.StatusNotFound:
+		return nil, "", ErrNotFound
+	}
+	return nil, "", s3Error(resp, body)
+}
+
+// CompareAndSwap implements Store.
+func (s *S3) CompareAndSwap(ctx context.Context, key string, value []byte, rev string) (string, error) {
+	h := http.Header{}
+	if rev == "" {
+		h.Set("If-None-Match", "*")
+	} else {
+		h.Set("If-Match", rev)
+	}
+	resp, body, err := s.do(ctx, http.MethodPut, key, nil, h, value)
+	if err != nil {
+		return "", err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Header.Get("ETag"), nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		return "", conflict(key, rev)
+	}
+	return "", s3Error(resp, body)
+}
+
+// FS returns the objects under Prefix as a filesystem that ctx bounds the
+// requests of. Writes through it are unconditional.
+func (s *S3) FS(ctx context.Context) *S3FS { return &S3FS{s: s, ctx: ctx} }
+
+// S3FS is a namedzone.WriteFS over the objects of an S3 store; see S3.FS.
+// Only files can be opened: object stores have no directories.
+type S3FS struct {
+	s   *S3
+	ctx context.Context
+}
+
+func (f *S3FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	b, _, err := f.s.Get(f.ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		err = fs.ErrNotExist
+	}
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &object{Reader: bytes.NewReader(b), name: path.Base(name)}, nil
+}
+
+// object is an open S3FS file; it is its own FileInfo. Size comes from the
+// bytes.Reader.
+type object struct {
+	*bytes.Reader
+	name string
+}
+
+func (o *object) Stat() (fs.FileInfo, error) { return o, nil }
+func (o *object) Close() error               { return nil }
+func (o *object) Name() string               { return o.name }
+func (o *object) Mode() fs.FileMode          { return 0o644 }
+func (o *object) ModTime() time.Time         { return time.Time{} }
+func (o *object) IsDir() bool                { return false }
+func (o *object) Sys() any                   { return nil }
+
+func (f *S3FS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	resp, body, err := f.s.do(f.ctx, http.MethodPut, name, nil, nil, data)
+	if err == nil && resp.StatusCode != http.StatusOK {
+		err = s3Error(resp, body)
+	}
+	if err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: err}
+	}
+	return nil
+}
+
+func s3Error(resp *http.Response, body []byte) error {
+	return fmt.Errorf("namedzone: s3: %s: %s", resp.Status, bytes.TrimSpace(body))
+}
+
+// do sends a signed request for the object key and reads the response.
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, h http.Header, body []byte) (*http.Response, []byte, error) {
+	region := cmp.Or(s.Region, "us-east-1")
+	objectPath := "/" + path.Join(s.Prefix, strings.TrimPrefix(key, "/"))
+	var u *url.URL
+	var err error
+	if s.Endpoint != "" {
+		u, err = url.Parse(strings.TrimSuffix(s.Endpoint, "/"))
+		if err == nil {
+			u.Path += "/" + s.Bucket + objectPath
+		}
+	} else {
+		u, err = url.Parse("https://" + s.Bucket + ".s3." + region + ".amazonaws.com" + objectPath)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range h {
+		r.Header[k] = v
+	}
+	if method == http.MethodPut {
+		r.ContentLength = int64(len(body))
+	}
+	s.sign(r, body, region, time.Now().UTC())
+	resp, err := cmp.Or(s.Client, http.DefaultClient).Do(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("namedzone: s3: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("namedzone: s3: %w", err)
+	}
+	return resp, b, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to r. Every
+// header set so far is signed.
+func (s *S3) sign(r *http.Request, body []byte, region string, now time.Time) {
+	sum := sha256.Sum256(body)
+	payload := hex.EncodeToString(sum[:])
+	stamp, day := now.Format("20060102T150405Z"), now.Format("20060102")
+	r.Header.Set("X-Amz-Date", stamp)
+	r.Header.Set("X-Amz-Content-Sha256", payload)
+	if s.SessionToken != "" {
+		r.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+	if s.AccessKeyID == "" {
+		return
+	}
+
+	names := []string{"host"}
+	values := map[string]string{"host": r.URL.Host}
+	for k, v := range r.Header {
+		k = strings.ToLower(k)
+		names = append(names, k)
+		values[k] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	slices.Sort(names)
+	var canonHeaders strings.Builder
+	for _, k := range names {
+		canonHeaders.WriteString(k + ":" + values[k] + "\n")
+	}
+	signed := strings.Join(names, ";")
+	canonical := strings.Join([]string{r.Method, r.URL.EscapedPath(), r.URL.RawQuery, canonHeaders.String(), signed, payload}, "\n")
+
+	scope := day + "/" + region + "/s3/aws4_request"
+	creq := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(creq[:])
+	key := []byte("AWS4" + s.SecretAccessKey)
+	for _, part := range []string{day, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	r.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+hex.EncodeToString(hmacSHA256(key, toSign)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
@@ -0,0 +1,187 @@
+// File: pkg/namedzone/lint.go
+package namedzone
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// Finding is a problem reported by Lint.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"` // GetPath syntax, e.g. view[ext].zone[example.com]
+	Message  string   `json:"message"`
+	// Position is where the item is written, when it is; see PositionOf.
+	Position *Position `json:"position,omitempty"`
+}
+
+func (f Finding) String() string {
+	loc := f.Path
+	if p := f.Position; p != nil {
+		loc = fmt.Sprintf("%s:%d:%d", cmp.Or(p.File, "named.conf"), p.Line, p.Column)
+	}
+	return loc + ": " + string(f.Severity) + ": " + f.Message + " [" + f.Rule + "]"
+}
+
+// LintRule is one check run by Lint. Check calls report for each offending
+// item; the rule's ID and Severity are filled in.
+type LintRule struct {
+	ID       string
+	Severity Severity
+	Check    func(c *Config, report func(path, format string, args ...any))
+}
+
+// LintRules are the built-in security checks run by Lint.
+var LintRules = []LintRule{
+	{ID: "open-recursion", Severity: SeverityError, Check: lintOpenRecursion},
+	{ID: "transfer-any", Severity: SeverityWarning, Check: lintTransferAny},
+	{ID: "update-any", Severity: SeverityError, Check: lintUpdateAny},
+	{ID: "controls-no-keys", Severity: SeverityWarning, Check: lintControlsNoKeys},
+	{ID: "tsig-md5", Severity: SeverityWarning, Check: lintTSIGMD5},
+	{ID: "dnssec-validation-off", Severity: SeverityWarning, Check: lintDNSSECValidationOff},
+	{ID: "dnssec-validation-unset", Severity: SeverityInfo, Check: lintDNSSECValidationUnset},
+	{ID: "wildcard-listen", Severity: SeverityWarning, Check: lintWildcardListen},
+}
+
+// Lint runs the built-in security checks (LintRules) and returns their
+// findings in rule order. Like Validate it never modifies the Config; CI
+// can fail on any finding of SeverityError.
+func (c *Config) Lint() []Finding {
+	return c.LintWith(LintRules...)
+}
+
+// LintWith runs the given rules, e.g. a subset of LintRules or custom ones.
+func (c *Config) LintWith(rules ...LintRule) []Finding {
+	var out []Finding
+	for _, r := range rules {
+		r.Check(c, func(path, format string, args ...any) {
+			f := Finding{Rule: r.ID, Severity: r.Severity, Path: path, Message: fmt.Sprintf(format, args...)}
+			if p, ok := c.PositionOf(path); ok {
+				f.Position = &p
+			}
+			out = append(out, f)
+		})
+	}
+	return out
+}
+
+// admitsAll reports whether terms admit every IPv4 or every IPv6 client.
+// Lists that cannot be expanded, e.g. with undefined ACLs, are left to
+// Validate.
+func (c *Config) admitsAll(terms []MatchTerm) bool {
+	ps, err := c.ExpandMatchList(terms)
+	if err != nil {
+		return false
+	}
+	for _, p := range ps {
+		if p.Bits() == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// lintOpenRecursion flags recursion for any client. named's allow-recursion
+// falls back to allow-query-cache, then allow-query, then
+// { localnets; localhost; }.
+func lintOpenRecursion(c *Config, report func(path, format string, args ...any)) {
+	o := c.Options
+	if o == nil {
+		return
+	}
+	allowed, from := o.AllowQuery, "allow-query"
+	for _, name := range []string{"allow-query-cache", "allow-recursion"} {
+		if raw, ok := o.Get(name); ok {
+			if l := parseMatchList(raw); len(l) > 0 {
+				allowed, from = l, name
+			}
+		}
+	}
+	if !c.admitsAll(allowed) {
+		return
+	}
+	on := o.Recursion == nil || *o.Recursion
+	if on {
+		report("options", "recursion is open to any client through %s", from)
+	}
+	for _, v := range c.Views {
+		if v.Recursion != nil && *v.Recursion && !on {
+			report("view["+v.Name+"]", "recursion is open to any client through options %s", from)
+		}
+	}
+}
+
+func lintTransferAny(c *Config, report func(path, format string, args ...any)) {
+	if o := c.Options; o != nil && c.admitsAll(o.AllowTransfer) {
+		report("options.allowTransfer", "zone transfers are allowed to any client")
+	}
+	for path, z := range c.zonesByPath() {
+		if c.admitsAll(z.AllowTransfer) {
+			report(path+".allowTransfer", "zone transfers of %s are allowed to any client", z.Name)
+		}
+	}
+}
+
+func lintUpdateAny(c *Config, report func(path, format string, args ...any)) {
+	if o := c.Options; o != nil && c.admitsAll(o.AllowUpdate) {
+		report("options.allowUpdate", "dynamic updates are allowed from any client")
+	}
+	for path, z := range c.zonesByPath() {
+		if z.Type == ZonePrimary && c.admitsAll(z.AllowUpdate) {
+			report(path+".allowUpdate", "dynamic updates of %s are allowed from any client", z.Name)
+		}
+	}
+}
+
+func lintControlsNoKeys(c *Config, report func(path, format string, args ...any)) {
+	if c.Controls == nil {
+		return
+	}
+	for i, in := range c.Controls.Inet {
+		if len(in.Keys) == 0 {
+			report(fmt.Sprintf("controls.inet[#%d]", i), "control channel on %s has no keys", in.Address)
+		}
+	}
+}
+
+func lintTSIGMD5(c *Config, report func(path, format string, args ...any)) {
+	for _, k := range c.Keys {
+		if strings.EqualFold(strings.TrimSuffix(k.Algorithm, ".sig-alg.reg.int"), "hmac-md5") {
+			report("key["+k.Name+"]", "key %s uses hmac-md5; use hmac-sha256 or stronger", k.Name)
+		}
+	}
+}
+
+func lintDNSSECValidationOff(c *Config, report func(path, format string, args ...any)) {
+	if o := c.Options; o != nil && o.DNSSECValidation == "no" {
+		report("options.dnssecValidation", "DNSSEC validation is disabled")
+	}
+}
+
+func lintDNSSECValidationUnset(c *Config, report func(path, format string, args ...any)) {
+	if o := c.Options; o == nil || o.DNSSECValidation == "" {
+		report("options", "dnssec-validation is not set; named's default (auto) applies")
+	}
+}
+
+// lintWildcardListen flags listening on every address while queries are not
+// restricted by allow-query. Unset listen-on means { any; }.
+func lintWildcardListen(c *Config, report func(path, format string, args ...any)) {
+	o := c.Options
+	if o == nil {
+		o = &Options{}
+	}
+	if len(o.AllowQuery) > 0 && !c.admitsAll(o.AllowQuery) {
+		return
+	}
+	for _, l := range []struct {
+		path, family string
+		listen       *Listen
+	}{{"options.listenOn", "IPv4", o.ListenOn}, {"options.listenOnV6", "IPv6", o.ListenOnV6}} {
+		if l.listen == nil || c.admitsAll(l.listen.Addrs) {
+			report(l.path, "listening on all %s addresses with no allow-query restriction", l.family)
+		}
+	}
+}
@@ -2,153 +2,562 @@
 package namedzone
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	nc "github.com/dlukt/namedconf"
 )
 
 // FromFile builds a typed Config from a parsed AST. Unknown statements remain untouched in the AST.
 func FromFile(f *nc.File) (*Config, error) {
-	cfg := &Config{ast: f}
-	for _, n := range f.Nodes {
+	cfg, _, err := Load(f, LoadOptions{})
+	return cfg, err
+}
+
+// LoadOptions tunes Load.
+type LoadOptions struct {
+	// Strict makes Load fail when any Warning was raised, i.e. when writing
+	// the typed view back would lose or alter something in the source.
+	Strict bool
+
+	// Logger receives diagnostics (skipped statements, normalizations) during
+	// Load and, via the returned Config, during Apply. Nil discards them.
+	Logger *slog.Logger
+
+	// Lock makes LoadTreeWith take an exclusive advisory lock (flock) on
+	// <path>.lock before reading and hold it until Config.Unlock, so
+	// cooperating processes serialize their load-edit-save cycles.
+	// LockTimeout bounds the wait (zero waits indefinitely); when it runs
+	// out the load fails with a *LockError. Other loaders ignore Lock.
+	Lock        bool
+	LockTimeout time.Duration
+}
+
+// Warning describes a statement that was skipped or only partially
+// understood while loading. Offset is the byte offset of the statement in
+// the source; Line and Column are 1-based.
+type Warning struct {
+	File    string `json:"file,omitempty"`
+	Offset  int    `json:"offset"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Keyword string `json:"keyword"`
+	Message string `json:"message"`
+
+	// Err is the classified error: *ParseError or *UnsupportedStatementError.
+	Err error `json:"-"`
+}
+
+func (w Warning) String() string {
+	pos := fmt.Sprintf("%d:%d", w.Line, w.Column)
+	if w.File != "" {
+		pos = w.File + ":" + pos
+	}
+	return pos + ": " + w.Keyword + ": " + w.Message
+}
+
+// loader carries per-load state through the statement parsers.
+type loader struct {
+	file     string // origin recorded on parsed items ("" for single-file loads)
+	src      []byte
+	warnings []Warning
+	log      *slog.Logger
+	tree     *tree // set by LoadTree to follow include statements
+}
+
+func (ld *loader) warn(st *nc.Stmt, format string, args ...any) {
+	w := ld.position(st)
+	w.Message = fmt.Sprintf(format, args...)
+	w.Err = &ParseError{Offset: w.Offset, Line: w.Line, Column: w.Column, Keyword: w.Keyword, Msg: w.Message}
+	ld.warnings = append(ld.warnings, w)
+	ld.logger().Warn(w.Message, "keyword", w.Keyword, "line", w.Line, "column", w.Column)
+}
+
+func (ld *loader) logger() *slog.Logger {
+	if ld.log == nil {
+		return discardLogger
+	}
+	return ld.log
+}
+
+// position fills the location fields of a Warning for st.
+func (ld *loader) position(st *nc.Stmt) Warning {
+	w := Warning{File: ld.file, Offset: st.Start(), Keyword: st.Keyword}
+	if ld.src != nil && w.Offset <= len(ld.src) {
+		before := ld.src[:w.Offset]
+		w.Line = bytes.Count(before, []byte("\n")) + 1
+		w.Column = w.Offset - bytes.LastIndexByte(before, '\n')
+	}
+	return w
+}
+
+// boolPtr parses a yes/no value, warning when it is not one.
+func (ld *loader) boolPtr(st *nc.Stmt, raw string) *bool {
+	b := parseBoolPtr(raw)
+	if b == nil {
+		ld.warn(st, "invalid boolean %q ignored", raw)
+	}
+	return b
+}
+
+// prefetch parses "trigger [eligibility]", warning when it is malformed.
+func (ld *loader) prefetch(st *nc.Stmt, raw string) *Prefetch {
+	p := parsePrefetch(raw)
+	if p == nil {
+		ld.warn(st, "invalid prefetch %q ignored", raw)
+	}
+	return p
+}
+
+// responsePadding parses "{ acl } block-size N", warning when it is
+// malformed.
+func (ld *loader) responsePadding(st *nc.Stmt, raw string) *ResponsePadding {
+	rp := parseResponsePadding(raw)
+	if rp == nil {
+		ld.warn(st, "invalid response-padding %q ignored", raw)
+	}
+	return rp
+}
+
+// size parses a size value, warning when it is not one.
+func (ld *loader) size(st *nc.Stmt, raw string) *Size {
+	sz, err := ParseSize(raw)
+	if err != nil {
+		ld.warn(st, "invalid size %q ignored", raw)
+		return nil
+	}
+	return &sz
+}
+
+// intPtr parses an integer value, warning when it is not one.
+func (ld *loader) intPtr(st *nc.Stmt, raw string) *int {
+	n := parseIntPtr(raw)
+	if n == nil {
+		ld.warn(st, "invalid integer %q ignored", raw)
+	}
+	return n
+}
+
+// listen parses a listen-on value, warning when no address list was found.
+func (ld *loader) listen(st *nc.Stmt, raw string) *Listen {
+	l := parseListen(raw)
+	if len(l.Addrs) == 0 {
+		ld.warn(st, "no address match list in %q", raw)
+	}
+	return l
+}
+
+// unmodeled warns about a statement inside a typed block that has no field;
+// it is dropped when the enclosing block is rewritten by Apply.
+func (ld *loader) unmodeled(st *nc.Stmt, block string) {
+	w := ld.position(st)
+	w.Message = "not modeled in " + block + "; dropped if the block is rewritten"
+	w.Err = &UnsupportedStatementError{Offset: w.Offset, Line: w.Line, Column: w.Column, Keyword: w.Keyword, Block: block}
+	ld.warnings = append(ld.warnings, w)
+	ld.logger().Warn("statement not modeled", "keyword", w.Keyword, "block", block, "line", w.Line, "column", w.Column)
+}
+
+// Load builds a typed Config like FromFile and also reports what could not
+// be represented. In strict mode any warning turns into an error.
+func Load(f *nc.File, opts LoadOptions) (*Config, []Warning, error) {
+	ld := &loader{src: f.Bytes(), log: opts.Logger}
+	cfg := &Config{ast: f, logger: opts.Logger}
+	ld.loadNodes(cfg, f.Nodes)
+	return ld.finish(cfg, opts)
+}
+
+// finish applies strict-mode semantics to the collected warnings.
+func (ld *loader) finish(cfg *Config, opts LoadOptions) (*Config, []Warning, error) {
+	if opts.Strict && len(ld.warnings) > 0 {
+		errs := make([]error, len(ld.warnings))
+		for i, w := range ld.warnings {
+			errs[i] = w.Err
+		}
+		return nil, ld.warnings, errors.Join(errs...)
+	}
+	cfg.markClean()
+	return cfg, ld.warnings, nil
+}
+
+// loadNodes parses top-level statements into cfg.
+func (ld *loader) loadNodes(cfg *Config, nodes []nc.Node) {
+	for _, n := range nodes {
 		s, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
 		switch s.Keyword {
 		case "include":
-			path := trimQuotes(strings.TrimSpace(strings.TrimSuffix(s.HeadRaw, ";")))
-			cfg.Includes = append(cfg.Includes, Include{Path: path, stmt: s})
+			path := trimQuotes(stmtValue(s))
+			cfg.Includes = append(cfg.Includes, Include{Path: path, stmt: s, origin: ld.file})
+			if ld.tree != nil {
+				ld.follow(s, path, func(f *nc.File) { ld.loadNodes(cfg, f.Nodes) })
+			}
 		case "acl":
-			cfg.ACLs = append(cfg.ACLs, parseACL(s))
+			cfg.ACLs = append(cfg.ACLs, ld.parseACL(s))
 		case "key":
-			cfg.Keys = append(cfg.Keys, parseKey(s))
+			cfg.Keys = append(cfg.Keys, ld.parseKey(s))
 		case "key-store":
-			cfg.KeyStores = append(cfg.KeyStores, parseKeyStore(s))
-		case "remote-servers":
-			cfg.RemoteServers = append(cfg.RemoteServers, parseRemoteServers(s))
+			cfg.KeyStores = append(cfg.KeyStores, ld.parseKeyStore(s))
+		case "remote-servers", "primaries", "masters":
+			cfg.RemoteServers = append(cfg.RemoteServers, ld.parseRemoteServers(s))
 		case "tls":
-			cfg.TLS = append(cfg.TLS, parseTLS(s))
+			cfg.TLS = append(cfg.TLS, ld.parseTLS(s))
 		case "http":
-			cfg.HTTP = append(cfg.HTTP, parseHTTP(s))
+			cfg.HTTP = append(cfg.HTTP, ld.parseHTTP(s))
 		case "controls":
-			c := parseControls(s)
+			c := ld.parseControls(s)
 			cfg.Controls = &c
 		case "logging":
-			lg := parseLogging(s)
+			lg := ld.parseLogging(s)
 			cfg.Logging = &lg
 		case "options":
-			op := parseOptions(s)
+			op := ld.parseOptions(s)
 			cfg.Options = &op
 		case "trust-anchors":
-			ta := parseTrustAnchors(s)
+			ta := ld.parseTrustAnchors(s)
 			cfg.TrustAnchors = append(cfg.TrustAnchors, ta)
 		case "view":
-			v := parseView(s)
+			v := ld.parseView(s)
 			cfg.Views = append(cfg.Views, v)
 		case "zone":
-			z := parseZone(s)
+			z := ld.parseZone(s)
 			cfg.Zones = append(cfg.Zones, z)
 		default:
+			if ld.parseCustom(cfg, s) {
+				continue
+			}
 			// unknown: preserved by AST
+			ld.logger().Debug("statement preserved verbatim", "keyword", s.Keyword)
 		}
 	}
-	return cfg, nil
 }
 
 // Apply mutates the underlying AST to reflect typed changes and keep lossless round-trip for untouched parts.
+// With a nil f it targets the Config's own AST, creating an empty one for a
+// Config that was built in code rather than loaded.
 func (c *Config) Apply(f *nc.File) error {
 	if f == nil {
+		if c.ast == nil {
+			c.ast = &nc.File{}
+		}
 		f = c.ast
 	}
-	if f == nil {
-		return fmt.Errorf("Apply: nil file")
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	if c.idn {
+		if err := c.punycodeZones(); err != nil {
+			return err
+		}
+	}
+	if c.ModernizeKeywords {
+		c.log().Debug("modernizing legacy keywords")
+		c.modernizeKeywords()
+	}
+
+	if c.files != nil && f == c.ast {
+		c.applyTree()
+		return nil
 	}
+	c.sync(c.syncer(f))
+	c.ast = f
+	c.log().Debug("applied typed config", "zones", len(c.Zones), "views", len(c.Views))
+	return nil
+}
 
+// sync rewrites the typed statements of s.f from c.
+func (c *Config) sync(s syncer) {
 	// top-level simple lists/blocks
-	syncIncludes(f, c.Includes)
-	syncBlocks(f, "acl", c.ACLs, buildACL)
-	syncBlocks(f, "key", c.Keys, buildKey)
-	syncBlocks(f, "key-store", c.KeyStores, buildKeyStore)
-	syncBlocks(f, "remote-servers", c.RemoteServers, buildRemoteServers)
-	syncBlocks(f, "tls", c.TLS, buildTLS)
-	syncBlocks(f, "http", c.HTTP, buildHTTP)
-	syncSingleton(f, "controls", c.Controls, buildControls)
-	syncSingleton(f, "logging", c.Logging, buildLogging)
-	syncSingleton(f, "options", c.Options, buildOptions)
-	syncBlocks(f, "trust-anchors", c.TrustAnchors, buildTrustAnchors)
-	syncBlocks(f, "view", c.Views, buildView)
-	syncBlocks(f, "zone", c.Zones, buildZone)
+	syncBlocks(s, c.Includes, buildInclude, "include")
+	syncBlocks(s, c.ACLs, buildACL, "acl")
+	syncBlocks(s, c.Keys, buildKey, "key")
+	syncBlocks(s, c.KeyStores, buildKeyStore, "key-store")
+	syncBlocks(s, c.RemoteServers, buildRemoteServers, "remote-servers", "primaries", "masters")
+	syncBlocks(s, c.TLS, buildTLS, "tls")
+	syncBlocks(s, c.HTTP, buildHTTP, "http")
+	syncSingleton(s, c.effectiveControls(), buildControls, "controls")
+	syncSingleton(s, c.Logging, buildLogging, "logging")
+	syncSingleton(s, c.Options, buildOptions, "options")
+	syncBlocks(s, c.TrustAnchors, buildTrustAnchors, "trust-anchors")
+	syncBlocks(s, c.Views, buildView, "view")
+	syncBlocks(s, c.Zones, buildZone, "zone")
+	c.syncCustom(s)
+}
 
-	c.ast = f
+// markClean records what each loaded item builds to, so Apply can tell the
+// items that were not touched since and keep their original statements.
+func (c *Config) markClean() {
+	c.clean = map[*nc.Stmt]string{}
+	if c.files == nil {
+		c.sync(syncer{clean: c.clean, baseline: true})
+		return
+	}
+	for path := range c.files {
+		c.part(path).sync(syncer{clean: c.clean, baseline: true})
+	}
+}
+
+// checkWritable rejects values that cannot be written as named.conf.
+func (c *Config) checkWritable() error {
+	if err := c.checkMasterfile(); err != nil {
+		return err
+	}
+	if err := c.checkResolverTuning(); err != nil {
+		return err
+	}
+	if err := c.checkLimits(); err != nil {
+		return err
+	}
+	if err := c.checkTrustAnchors(); err != nil {
+		return err
+	}
+	if err := c.checkCustom(); err != nil {
+		return err
+	}
+	return c.runValidators()
+}
+
+// checkMasterfile rejects masterfile-format/style values named would refuse to load.
+func (c *Config) checkMasterfile() error {
+	check := func(where string, f MasterfileFormat, s MasterfileStyle) error {
+		if f != "" && !f.Valid() {
+			return &ValueError{Path: where, Value: string(f), Msg: "invalid masterfile-format"}
+		}
+		if s != "" && !s.Valid() {
+			return &ValueError{Path: where, Value: string(s), Msg: "invalid masterfile-style"}
+		}
+		return nil
+	}
+	if c.Options != nil {
+		if err := check("options", c.Options.MasterfileFormat, c.Options.MasterfileStyle); err != nil {
+			return err
+		}
+	}
+	for _, z := range c.Zones {
+		if err := check("zone "+z.Name, z.MasterfileFormat, z.MasterfileStyle); err != nil {
+			return err
+		}
+	}
+	for _, v := range c.Views {
+		for _, z := range v.Zones {
+			if err := check("view "+v.Name+" zone "+z.Name, z.MasterfileFormat, z.MasterfileStyle); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkResolverTuning rejects prefetch, qname-minimization,
+// minimal-responses and response-padding values named would refuse to load.
+func (c *Config) checkResolverTuning() error {
+	check := func(where string, p *Prefetch, q QNameMinimization, m MinimalResponses, rp *ResponsePadding) error {
+		if p != nil && (p.Trigger < 0 || p.Trigger > 10) {
+			return &ValueError{Path: where, Value: strconv.Itoa(p.Trigger), Msg: "prefetch trigger must be 0-10"}
+		}
+		if p != nil && p.Eligibility != nil && p.Trigger > 0 && *p.Eligibility < p.Trigger+6 {
+			return &ValueError{Path: where, Value: strconv.Itoa(*p.Eligibility), Msg: "prefetch eligibility must be at least trigger+6"}
+		}
+		if q != "" && !q.Valid() {
+			return &ValueError{Path: where, Value: string(q), Msg: "invalid qname-minimization"}
+		}
+		if m != "" && !m.Valid() {
+			return &ValueError{Path: where, Value: string(m), Msg: "invalid minimal-responses"}
+		}
+		if rp != nil && (rp.BlockSize < 1 || rp.BlockSize > 512) {
+			return &ValueError{Path: where, Value: strconv.Itoa(rp.BlockSize), Msg: "response-padding block-size must be 1-512"}
+		}
+		return nil
+	}
+	if o := c.Options; o != nil {
+		if err := check("options", o.Prefetch, o.QNameMinimization, o.MinimalResponses, o.ResponsePadding); err != nil {
+			return err
+		}
+	}
+	for _, v := range c.Views {
+		if err := check("view "+v.Name, v.Prefetch, v.QNameMinimization, v.MinimalResponses, v.ResponsePadding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLimits rejects lmdb-mapsize and max-records values named would
+// refuse to load: the map size must be a byte count from 1M to 1T.
+func (c *Config) checkLimits() error {
+	check := func(where string, mapsize *Size, records *int) error {
+		if sz := mapsize; sz != nil && (sz.Keyword == "unlimited" || sz.Percent != 0 ||
+			sz.Keyword == "" && (sz.Bytes < 1<<20 || sz.Bytes > 1<<40)) {
+			return &ValueError{Path: where, Value: sz.String(), Msg: "lmdb-mapsize must be between 1M and 1T"}
+		}
+		if records != nil && *records < 0 {
+			return &ValueError{Path: where, Value: strconv.Itoa(*records), Msg: "max-records cannot be negative"}
+		}
+		return nil
+	}
+	if o := c.Options; o != nil {
+		if err := check("options", o.LMDBMapSize, o.MaxRecords); err != nil {
+			return err
+		}
+	}
+	for v, z := range c.AllZones() {
+		where := "zone " + z.Name
+		if v != nil {
+			where = "view " + v.Name + " " + where
+		}
+		if err := check(where, nil, z.MaxRecords); err != nil {
+			return err
+		}
+	}
+	for _, v := range c.Views {
+		if err := check("view "+v.Name, v.LMDBMapSize, v.MaxRecords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTrustAnchors validates every trust anchor, top-level and per view.
+func (c *Config) checkTrustAnchors() error {
+	check := func(where string, ta TrustAnchors) error {
+		for _, it := range ta.Items {
+			if err := it.Validate(); err != nil {
+				return fmt.Errorf("%s: %w", where, err)
+			}
+		}
+		return nil
+	}
+	for _, ta := range c.TrustAnchors {
+		if err := check("trust-anchors", ta); err != nil {
+			return err
+		}
+	}
+	for _, v := range c.Views {
+		if v.TrustAnchors != nil {
+			if err := check("view "+v.Name+" trust-anchors", *v.TrustAnchors); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// effectiveControls returns the controls block to write. A Controls value with
+// no channels that is not marked Disabled means "use named's default channel",
+// which is the same as omitting the statement.
+func (c *Config) effectiveControls() *Controls {
+	ct := c.Controls
+	if ct != nil && !ct.Disabled && len(ct.Inet) == 0 && len(ct.Unix) == 0 {
+		c.log().Debug("controls has no channels and is not disabled; omitting statement")
+		return nil
+	}
+	return ct
+}
+
+// modernizeKeywords forgets legacy spellings recorded on load so the builders
+// emit the current keywords.
+func (c *Config) modernizeKeywords() {
+	for i := range c.RemoteServers {
+		c.RemoteServers[i].keyword = ""
+	}
+	modernize := func(zs []Zone) {
+		for i := range zs {
+			zs[i].typeWord = ""
+			zs[i].primariesKW = ""
+		}
+	}
+	modernize(c.Zones)
+	for i := range c.Views {
+		modernize(c.Views[i].Zones)
+	}
+}
+
+// legacyZoneTypes maps pre-9.16 zone type names to their modern equivalents.
+var legacyZoneTypes = map[string]ZoneType{
+	"master": ZonePrimary,
+	"slave":  ZoneSecondary,
+}
+
 // ---------------- Parsers ----------------
 
-func parseACL(s *nc.Stmt) ACL {
+func (ld *loader) parseACL(s *nc.Stmt) ACL {
 	name := headNameAfter(s, "acl")
 	terms := parseMatchListFromBody(s)
-	return ACL{Name: name, Elements: terms, stmt: s}
+	return ACL{Name: name, Elements: terms, stmt: s, origin: ld.file}
 }
 
-func parseKey(s *nc.Stmt) Key {
+func (ld *loader) parseKey(s *nc.Stmt) Key {
 	name := headNameAfter(s, "key")
 	var alg, secret string
 	for _, n := range s.Body {
 		if st, ok := n.(*nc.Stmt); ok {
-			kw := st.Keyword
-			v := strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))
-			v = trimQuotes(v)
-			switch kw {
+			v := trimQuotes(stmtValue(st))
+			switch st.Keyword {
 			case "algorithm":
 				alg = v
 			case "secret":
 				secret = v
+			default:
+				ld.unmodeled(st, "key")
 			}
 		}
 	}
-	return Key{Name: name, Algorithm: alg, Secret: secret, stmt: s}
+	return Key{Name: name, Algorithm: alg, Secret: secret, stmt: s, origin: ld.file}
 }
 
-func parseKeyStore(s *nc.Stmt) KeyStore {
+func (ld *loader) parseKeyStore(s *nc.Stmt) KeyStore {
 	name := headNameAfter(s, "key-store")
 	var uri string
 	for _, n := range s.Body {
-		if st, ok := n.(*nc.Stmt); ok && st.Keyword == "pkcs11-uri" {
-			uri = trimQuotes(strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";")))
+		st, ok := n.(*nc.Stmt)
+		if !ok {
+			continue
+		}
+		if st.Keyword == "pkcs11-uri" {
+			uri = trimQuotes(stmtValue(st))
+		} else {
+			ld.unmodeled(st, "key-store")
 		}
 	}
-	return KeyStore{Name: name, PKCS11URI: uri, stmt: s}
+	return KeyStore{Name: name, PKCS11URI: uri, stmt: s, origin: ld.file}
 }
 
-func parseRemoteServers(s *nc.Stmt) RemoteServers {
-	name := headNameAfter(s, "remote-servers")
+func (ld *loader) parseRemoteServers(s *nc.Stmt) RemoteServers {
+	name := headNameAfter(s, s.Keyword)
 	items := []RemoteServerItem{}
 	for _, n := range s.Body {
 		st, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
-		raw := strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))
+		raw := strings.TrimSpace(strings.TrimSuffix(headText(st), ";"))
 		if raw == "" {
 			continue
 		}
 		items = append(items, parseRemoteServerItem(raw))
 	}
-	return RemoteServers{Name: name, Servers: items, stmt: s}
+	rs := RemoteServers{Name: name, Servers: items, stmt: s, origin: ld.file}
+	if s.Keyword != "remote-servers" {
+		rs.keyword = s.Keyword
+	}
+	return rs
 }
 
-func parseTLS(s *nc.Stmt) TLS {
-	t := TLS{Name: headNameAfter(s, "tls"), stmt: s}
+func (ld *loader) parseTLS(s *nc.Stmt) TLS {
+	t := TLS{Name: headNameAfter(s, "tls"), stmt: s, origin: ld.file}
 	for _, n := range s.Body {
 		st, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
-		v := strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))
+		v := stmtValue(st)
 		vq := trimQuotes(v)
 		switch st.Keyword {
 		case "ca-file":
@@ -164,84 +573,93 @@ func parseTLS(s *nc.Stmt) TLS {
 		case "dhparam-file":
 			t.DHParamFile = vq
 		case "prefer-server-ciphers":
-			t.PreferServer = parseBoolPtr(v)
+			t.PreferServer = ld.boolPtr(st, v)
 		case "protocols":
 			t.Protocols = parseStringList(v)
 		case "remote-hostname":
 			t.RemoteHost = vq
 		case "session-tickets":
-			t.SessionTickets = parseBoolPtr(v)
+			t.SessionTickets = ld.boolPtr(st, v)
+		default:
+			ld.unmodeled(st, "tls")
 		}
 	}
 	return t
 }
 
-func parseHTTP(s *nc.Stmt) HTTP {
-	h := HTTP{Name: headNameAfter(s, "http"), stmt: s}
+func (ld *loader) parseHTTP(s *nc.Stmt) HTTP {
+	h := HTTP{Name: headNameAfter(s, "http"), stmt: s, origin: ld.file}
 	for _, n := range s.Body {
 		st, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
-		v := strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))
+		v := stmtValue(st)
 		switch st.Keyword {
 		case "endpoints":
 			h.Endpoints = parseStringList(v)
 		case "listener-clients":
-			h.ListenerClients = parseIntPtr(v)
+			h.ListenerClients = ld.intPtr(st, v)
 		case "streams-per-connection":
-			h.StreamsPerConnection = parseIntPtr(v)
+			h.StreamsPerConnection = ld.intPtr(st, v)
+		default:
+			ld.unmodeled(st, "http")
 		}
 	}
 	return h
 }
 
-func parseControls(s *nc.Stmt) Controls {
-	c := Controls{stmt: s}
+func (ld *loader) parseControls(s *nc.Stmt) Controls {
+	c := Controls{stmt: s, origin: ld.file}
 	for _, n := range s.Body {
 		st, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
-		raw := strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))
-		if strings.HasPrefix(raw, "inet ") {
-			c.Inet = append(c.Inet, parseControlInet(raw))
-		} else if strings.HasPrefix(raw, "unix ") {
-			c.Unix = append(c.Unix, parseControlUnix(raw))
+		switch st.Keyword {
+		case "inet":
+			c.Inet = append(c.Inet, parseControlInet(stmtValue(st)))
+		case "unix":
+			c.Unix = append(c.Unix, parseControlUnix(stmtValue(st)))
+		default:
+			ld.unmodeled(st, "controls")
 		}
 	}
+	c.Disabled = len(c.Inet) == 0 && len(c.Unix) == 0
 	return c
 }
 
-func parseLogging(s *nc.Stmt) Logging {
-	lg := Logging{stmt: s}
+func (ld *loader) parseLogging(s *nc.Stmt) Logging {
+	lg := Logging{stmt: s, origin: ld.file}
 	for _, n := range s.Body {
 		st, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
 		if st.Keyword == "channel" {
-			lg.Channels = append(lg.Channels, parseLogChannel(st))
+			lg.Channels = append(lg.Channels, ld.parseLogChannel(st))
 		} else if st.Keyword == "category" {
-			lg.Categories = append(lg.Categories, parseLogCategory(st))
+			lg.Categories = append(lg.Categories, ld.parseLogCategory(st))
+		} else {
+			ld.unmodeled(st, "logging")
 		}
 	}
 	return lg
 }
 
-func parseOptions(s *nc.Stmt) Options {
-	op := Options{stmt: s}
+func (ld *loader) parseOptions(s *nc.Stmt) Options {
+	op := Options{stmt: s, origin: ld.file}
 	for _, n := range s.Body {
 		st, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
-		raw := strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))
+		raw := stmtValue(st)
 		switch st.Keyword {
 		case "directory":
 			op.Directory = trimQuotes(raw)
 		case "recursion":
-			op.Recursion = parseBoolPtr(raw)
+			op.Recursion = ld.boolPtr(st, raw)
 		case "allow-query":
 			op.AllowQuery = parseMatchList(raw)
 		case "allow-transfer":
@@ -249,9 +667,9 @@ func parseOptions(s *nc.Stmt) Options {
 		case "allow-update":
 			op.AllowUpdate = parseMatchList(raw)
 		case "listen-on":
-			op.ListenOn = parseListen(raw)
+			op.ListenOn = ld.listen(st, raw)
 		case "listen-on-v6":
-			op.ListenOnV6 = parseListen(raw)
+			op.ListenOnV6 = ld.listen(st, raw)
 		case "forwarders":
 			op.Forwarders = parseForwarders(raw)
 		case "forward":
@@ -262,8 +680,78 @@ func parseOptions(s *nc.Stmt) Options {
 			if f := strings.Fields(raw); len(f) > 0 {
 				op.DNSSECValidation = f[0]
 			}
+		case "masterfile-format":
+			op.MasterfileFormat = MasterfileFormat(firstField(raw))
+		case "masterfile-style":
+			op.MasterfileStyle = MasterfileStyle(firstField(raw))
 		case "rrset-order":
 			op.RRsetOrder = parseRRsetOrder(st)
+		case "prefetch":
+			op.Prefetch = ld.prefetch(st, raw)
+		case "qname-minimization":
+			op.QNameMinimization = QNameMinimization(firstField(raw))
+		case "minimal-responses":
+			op.MinimalResponses = parseMinimalResponses(raw)
+		case "minimal-any":
+			op.MinimalAny = ld.boolPtr(st, raw)
+		case "response-padding":
+			op.ResponsePadding = ld.responsePadding(st, raw)
+		case "tkey-gssapi-keytab":
+			op.TKeyGSSAPIKeytab = trimQuotes(raw)
+		case "tkey-gssapi-credential":
+			op.TKeyGSSAPICredential = trimQuotes(raw)
+		case "tkey-domain":
+			op.TKeyDomain = trimQuotes(raw)
+		case "session-keyfile":
+			op.SessionKeyFile = trimQuotes(raw)
+		case "session-keyname":
+			op.SessionKeyName = trimQuotes(raw)
+		case "session-keyalg":
+			op.SessionKeyAlg = trimQuotes(raw)
+		case "max-cache-size", "max-journal-size":
+			sz, err := ParseSize(raw)
+			if err != nil {
+				op.Other = append(op.Other, RawKV{Name: st.Keyword, Raw: raw})
+			} else if st.Keyword == "max-cache-size" {
+				op.MaxCacheSize = &sz
+			} else {
+				op.MaxJournalSize = &sz
+			}
+		case "lmdb-mapsize":
+			if sz, err := ParseSize(raw); err != nil {
+				op.Other = append(op.Other, RawKV{Name: st.Keyword, Raw: raw})
+			} else {
+				op.LMDBMapSize = &sz
+			}
+		case "max-records":
+			op.MaxRecords = ld.intPtr(st, raw)
+		case "allow-new-zones":
+			op.AllowNewZones = ld.boolPtr(st, raw)
+		case "max-cache-ttl", "max-ncache-ttl":
+			d, err := ParseDuration(raw)
+			if err != nil {
+				op.Other = append(op.Other, RawKV{Name: st.Keyword, Raw: raw})
+			} else if st.Keyword == "max-cache-ttl" {
+				op.MaxCacheTTL = &d
+			} else {
+				op.MaxNCacheTTL = &d
+			}
+		case "version":
+			op.Version = parseServerIdent(raw)
+		case "hostname":
+			op.Hostname = parseServerIdent(raw)
+		case "server-id":
+			op.ServerID = parseServerIdent(raw)
+		case "pid-file", "statistics-file", "dump-file", "secroots-file",
+			"recursing-file", "memstatistics-file", "lock-file":
+			path := trimQuotes(raw)
+			if path == "none" && strings.HasPrefix(strings.TrimSpace(raw), "\"") {
+				if op.quotedNone == nil {
+					op.quotedNone = map[string]bool{}
+				}
+				op.quotedNone[st.Keyword] = true
+			}
+			*op.pathField(st.Keyword) = path
 		default:
 			op.Other = append(op.Other, RawKV{Name: st.Keyword, Raw: raw})
 		}
@@ -271,51 +759,91 @@ func parseOptions(s *nc.Stmt) Options {
 	return op
 }
 
-func parseView(s *nc.Stmt) View {
-	v := View{Name: headNameAfter(s, "view"), stmt: s}
+func (ld *loader) parseView(s *nc.Stmt) View {
+	v := View{Name: headNameAfter(s, "view"), stmt: s, origin: ld.file}
 	v.Class = headClassAfter(s, "view")
-	for _, n := range s.Body {
+	ld.viewBody(&v, s.Body)
+	return v
+}
+
+// viewBody parses statements inside a view; included files continue here.
+func (ld *loader) viewBody(v *View, nodes []nc.Node) {
+	for _, n := range nodes {
 		st, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
-		raw := strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))
+		raw := stmtValue(st)
 		switch st.Keyword {
 		case "match-clients":
 			v.MatchClients = parseMatchList(raw)
 		case "match-destinations":
 			v.MatchDestinations = parseMatchList(raw)
+		case "match-recursive-only":
+			v.MatchRecursive = ld.boolPtr(st, raw)
 		case "recursion":
-			v.Recursion = parseBoolPtr(raw)
+			v.Recursion = ld.boolPtr(st, raw)
+		case "prefetch":
+			v.Prefetch = ld.prefetch(st, raw)
+		case "qname-minimization":
+			v.QNameMinimization = QNameMinimization(firstField(raw))
+		case "minimal-responses":
+			v.MinimalResponses = parseMinimalResponses(raw)
+		case "minimal-any":
+			v.MinimalAny = ld.boolPtr(st, raw)
+		case "response-padding":
+			v.ResponsePadding = ld.responsePadding(st, raw)
+		case "lmdb-mapsize":
+			v.LMDBMapSize = ld.size(st, raw)
+		case "max-records":
+			v.MaxRecords = ld.intPtr(st, raw)
+		case "allow-new-zones":
+			v.AllowNewZones = ld.boolPtr(st, raw)
 		case "trust-anchors":
-			ta := parseTrustAnchors(st)
+			ta := ld.parseTrustAnchors(st)
 			v.TrustAnchors = &ta
 		case "zone":
-			vz := parseZone(st)
+			vz := ld.parseZone(st)
 			v.Zones = append(v.Zones, vz)
 		case "include":
-			v.Includes = append(v.Includes, Include{Path: trimQuotes(raw), stmt: st})
+			path := trimQuotes(raw)
+			v.Includes = append(v.Includes, Include{Path: path, stmt: st, origin: ld.file})
+			if ld.tree != nil {
+				ld.follow(st, path, func(f *nc.File) { ld.viewBody(v, f.Nodes) })
+			}
+		default:
+			ld.unmodeled(st, "view "+v.Name)
 		}
 	}
-	return v
 }
 
-func parseZone(s *nc.Stmt) Zone {
-	z := Zone{Name: headNameAfter(s, "zone"), Class: headClassAfter(s, "zone"), stmt: s}
+func (ld *loader) parseZone(s *nc.Stmt) Zone {
+	z := Zone{Name: headNameAfter(s, "zone"), Class: headClassAfter(s, "zone"), stmt: s, origin: ld.file}
 	for _, n := range s.Body {
 		st, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
-		raw := strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))
+		raw := stmtValue(st)
 		switch st.Keyword {
 		case "type":
 			if f := strings.Fields(raw); len(f) > 0 {
 				z.Type = ZoneType(f[0])
+				if t, ok := legacyZoneTypes[f[0]]; ok {
+					z.Type = t
+					z.typeWord = f[0]
+					ld.logger().Debug("normalized legacy zone type", "zone", z.Name, "from", f[0], "to", string(t))
+				}
 			}
 		case "file":
 			z.File = trimQuotes(raw)
-		case "primaries":
+		case "in-view":
+			z.InView = trimQuotes(raw)
+		case "primaries", "masters":
+			if st.Keyword == "masters" {
+				z.primariesKW = "masters"
+				ld.logger().Debug("normalized legacy keyword", "zone", z.Name, "from", "masters", "to", "primaries")
+			}
 			if strings.HasPrefix(raw, "{") {
 				z.Primaries = parseRemoteServerListBody(raw)
 			} else {
@@ -333,90 +861,356 @@ func parseZone(s *nc.Stmt) Zone {
 			z.AllowTransfer = parseMatchList(raw)
 		case "also-notify":
 			z.AlsoNotify = parseRemoteServerListBody(raw)
+		case "notify":
+			z.Notify = firstField(raw)
 		case "dnssec-policy":
 			z.DNSSECPolicy = trimQuotes(raw)
+		case "masterfile-format":
+			z.MasterfileFormat = MasterfileFormat(firstField(raw))
+		case "masterfile-style":
+			z.MasterfileStyle = MasterfileStyle(firstField(raw))
+		case "max-records":
+			z.MaxRecords = ld.intPtr(st, raw)
+		default:
+			ld.unmodeled(st, "zone "+z.Name)
 		}
 	}
 	return z
 }
 
-func parseTrustAnchors(st *nc.Stmt) TrustAnchors {
-	ta := TrustAnchors{stmt: st}
+func (ld *loader) parseTrustAnchors(st *nc.Stmt) TrustAnchors {
+	ta := TrustAnchors{stmt: st, origin: ld.file}
 	for _, n := range st.Body {
 		ss, ok := n.(*nc.Stmt)
 		if !ok {
 			continue
 		}
-		raw := strings.TrimSpace(strings.TrimSuffix(ss.HeadRaw, ";"))
-		// Capture either static-ds/static-key or initial-ds/initial-key lines in a generic way.
-		fields := strings.Fields(raw)
-		if len(fields) == 0 {
-			continue
-		}
-		name := trimQuotes(fields[0])
-		rest := strings.TrimSpace(strings.TrimPrefix(raw, fields[0]+" "))
-		if strings.Contains(rest, "ds") {
-			ta.Items = append(ta.Items, TrustAnchorItem{Name: name, DS: rest})
-		} else if strings.Contains(rest, "key") {
-			ta.Items = append(ta.Items, TrustAnchorItem{Name: name, DNSKey: rest})
+		raw := strings.TrimSpace(strings.TrimSuffix(headText(ss), ";"))
+		if it, ok := parseTrustAnchorItem(raw); ok {
+			ta.Items = append(ta.Items, it)
+		} else {
+			ld.warn(ss, "malformed trust anchor skipped")
 		}
 	}
 	return ta
 }
 
+// parseTrustAnchorItem parses `"name" kind n n n "data"`.
+func parseTrustAnchorItem(raw string) (TrustAnchorItem, bool) {
+	fields := strings.Fields(raw)
+	if len(fields) < 6 {
+		return TrustAnchorItem{}, false
+	}
+	it := TrustAnchorItem{Name: trimQuotes(fields[0]), Kind: TrustAnchorKind(fields[1])}
+	var nums [3]int
+	for i := range nums {
+		n, err := strconv.Atoi(fields[2+i])
+		if err != nil {
+			return TrustAnchorItem{}, false
+		}
+		nums[i] = n
+	}
+	it.Data = strings.ReplaceAll(strings.Join(fields[5:], ""), "\"", "")
+	switch it.Kind {
+	case AnchorStaticKey, AnchorInitialKey:
+		it.Flags, it.Protocol, it.Algorithm = nums[0], nums[1], nums[2]
+	case AnchorStaticDS, AnchorInitialDS:
+		it.KeyTag, it.Algorithm, it.DigestType = nums[0], nums[1], nums[2]
+	default:
+		return TrustAnchorItem{}, false
+	}
+	return it, true
+}
+
 // ---------------- Builders/Sync ----------------
 
 type builder[T any] func(T) *nc.Stmt
 
-func syncBlocks[T any](f *nc.File, keyword string, items []T, b builder[T]) {
-	var out []nc.Node
-	for _, n := range f.Nodes {
-		s, ok := n.(*nc.Stmt)
-		if ok && s.Keyword == keyword {
-			continue
+// syncer carries the target file of a sync and the text each loaded
+// statement built to (see markClean). In baseline mode it only fills clean.
+type syncer struct {
+	f        *nc.File
+	clean    map[*nc.Stmt]string
+	order    OrderPolicy
+	format   *Format
+	baseline bool
+}
+
+func (c *Config) syncer(f *nc.File) syncer {
+	if c.clean == nil {
+		c.clean = map[*nc.Stmt]string{}
+	}
+	return syncer{f: f, clean: c.clean, order: c.order, format: c.format}
+}
+
+// stmtRef is implemented by pointers to typed items that remember the
+// statement they were loaded from.
+type stmtRef[T any] interface {
+	*T
+	ref() **nc.Stmt
+}
+
+// syncBlocks writes items as the top-level statements with the given
+// keywords. An item whose build output is what it was on load keeps its
+// original statement, comments and formatting included. An edited item is
+// rebuilt in place of its statement, keeping the comments before it; new
+// items are placed according to the order policy, and statements of items
+// that are gone are removed. Comments a blank line separates from the
+// statement below, such as a file header, stay in place.
+func syncBlocks[T any, P stmtRef[T]](s syncer, items []T, b builder[T], keywords ...string) {
+	if s.baseline {
+		for i := range items {
+			if st := *P(&items[i]).ref(); st != nil {
+				s.clean[st] = source(b(items[i]))
+			}
 		}
-		out = append(out, n)
+		return
 	}
-	for _, it := range items {
-		out = append(out, b(it))
+	ours := func(st *nc.Stmt) bool { return slices.Contains(keywords, st.Keyword) }
+	present := map[*nc.Stmt]bool{}
+	var first *nc.Stmt
+	for _, n := range s.f.Nodes {
+		if st, ok := n.(*nc.Stmt); ok && ours(st) {
+			present[st] = true
+			if first == nil {
+				first = st
+			}
+		}
+	}
+	claimed := map[*nc.Stmt]*nc.Stmt{}   // statement in f -> what replaces it
+	home := make([]*nc.Stmt, len(items)) // statement in f each item took over
+	final := make([]*nc.Stmt, len(items))
+	for i := range items {
+		ref := P(&items[i]).ref()
+		st := b(items[i])
+		text := source(st)
+		old, lead := *ref, ""
+		if present[old] && claimed[old] == nil {
+			home[i] = old
+			if s.clean[old] == text {
+				claimed[old], final[i] = old, old
+				continue
+			}
+			lead = leadingComments(old)
+			claimed[old] = st
+		}
+		s.clean[st] = text
+		s.dress(st, text, lead)
+		*ref = st
+		final[i] = st
 	}
-	f.Nodes = out
-}
 
-func syncSingleton[T any](f *nc.File, keyword string, item *T, b builder[T]) {
-	if item == nil {
+	if s.order == OrderItems {
+		var seq []nc.Node
+		for _, st := range final {
+			if len(seq) > 0 {
+				seq = append(seq, &nc.Raw{Text: "\n"})
+			}
+			seq = append(seq, st)
+		}
+		if len(seq) == 0 || first == nil {
+			out := without(s.f.Nodes, ours)
+			for _, st := range final {
+				out = appendStmt(out, st)
+			}
+			s.f.Nodes = out
+			return
+		}
 		var out []nc.Node
-		for _, n := range f.Nodes {
-			s, ok := n.(*nc.Stmt)
-			if ok && s.Keyword == keyword {
+		for _, n := range without(s.f.Nodes, func(st *nc.Stmt) bool { return ours(st) && st != first }) {
+			if n == nc.Node(first) {
+				// a header above the first statement stays at the top
+				if at := claimed[first]; at == nil || at != final[0] {
+					if d := splitDetached(first, at); d != "" {
+						out = append(out, &nc.Raw{Text: d})
+					}
+				}
+				out = append(out, seq...)
 				continue
 			}
 			out = append(out, n)
 		}
-		f.Nodes = out
+		s.f.Nodes = out
+		return
+	}
+
+	// OrderInPlace: a new item goes after the item before it, else before
+	// the first item that has a statement, else at the end of the file.
+	type anchor struct {
+		at    *nc.Stmt
+		after bool
+	}
+	before, after := map[*nc.Stmt][]*nc.Stmt{}, map[*nc.Stmt][]*nc.Stmt{}
+	var tail []*nc.Stmt
+	var prev anchor
+	for i, st := range final {
+		if home[i] != nil {
+			prev = anchor{at: home[i], after: true}
+			continue
+		}
+		if i == 0 {
+			prev = anchor{}
+			for k := range home {
+				if home[k] != nil {
+					prev = anchor{at: home[k]}
+					break
+				}
+			}
+		}
+		switch {
+		case prev.at == nil:
+			tail = append(tail, st)
+		case prev.after:
+			after[prev.at] = append(after[prev.at], st)
+		default:
+			before[prev.at] = append(before[prev.at], st)
+		}
+	}
+	var out []nc.Node
+	for _, n := range without(s.f.Nodes, func(st *nc.Stmt) bool { return ours(st) && claimed[st] == nil }) {
+		st, ok := n.(*nc.Stmt)
+		if !ok || claimed[st] == nil {
+			out = append(out, n)
+			continue
+		}
+		if len(before[st]) > 0 {
+			if d := splitDetached(st, claimed[st]); d != "" {
+				out = append(out, &nc.Raw{Text: d})
+			}
+		}
+		for _, x := range before[st] {
+			out = append(out, x, &nc.Raw{Text: "\n"})
+		}
+		out = append(out, claimed[st])
+		for _, x := range after[st] {
+			out = append(out, &nc.Raw{Text: "\n"}, x)
+		}
+	}
+	for _, st := range tail {
+		out = appendStmt(out, st)
+	}
+	s.f.Nodes = out
+}
+
+// leadingComments returns the comments written before the head of st.
+func leadingComments(st *nc.Stmt) string {
+	h := headText(st)
+	if i := strings.Index(st.HeadRaw, h); h != "" && i > 0 {
+		return st.HeadRaw[:i]
+	}
+	return ""
+}
+
+func syncSingleton[T any, P stmtRef[T]](s syncer, item *T, b builder[T], keywords ...string) {
+	if item == nil {
+		if !s.baseline {
+			s.f.Nodes = without(s.f.Nodes, func(st *nc.Stmt) bool { return slices.Contains(keywords, st.Keyword) })
+		}
 		return
 	}
-	syncBlocks(f, keyword, []T{*item}, b)
+	one := []T{*item}
+	syncBlocks[T, P](s, one, b, keywords...)
+	*item = one[0]
+}
+
+// source returns the text st is written as.
+func source(st *nc.Stmt) string {
+	return string((&nc.File{Nodes: []nc.Node{st}}).Bytes())
 }
 
-func syncIncludes(f *nc.File, incs []Include) {
+// without returns nodes minus the top-level statements drop matches. The
+// line break appendStmt put before a generated statement goes with it; for
+// other statements the blank text after them does.
+func without(nodes []nc.Node, drop func(*nc.Stmt) bool) []nc.Node {
 	var out []nc.Node
-	for _, n := range f.Nodes {
+	skipSpace := false
+	for _, n := range nodes {
+		if r, ok := n.(*nc.Raw); ok && skipSpace && strings.TrimSpace(r.Text) == "" {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
 		s, ok := n.(*nc.Stmt)
-		if ok && s.Keyword == "include" {
+		if !ok || !drop(s) {
+			out = append(out, n)
 			continue
 		}
-		out = append(out, n)
+		d := detachedComments(s)
+		if k := len(out) - 1; s.Modified && k >= 0 {
+			if r, ok := out[k].(*nc.Raw); ok && r.Text == "\n" {
+				out = out[:k]
+				if d != "" {
+					out = append(out, &nc.Raw{Text: "\n" + d})
+				}
+				continue
+			}
+		}
+		if d != "" {
+			out = append(out, &nc.Raw{Text: d})
+		}
+		skipSpace = true
+	}
+	return out
+}
+
+// splitDetached returns the detached comments of old and removes them from
+// cur, the statement taking its place (nil if none), so that statements can
+// be written between the comments and cur.
+func splitDetached(old, cur *nc.Stmt) string {
+	d := detachedComments(old)
+	if d == "" || cur == nil {
+		return d
+	}
+	if !strings.HasPrefix(cur.HeadRaw, d) {
+		return ""
+	}
+	cur.HeadRaw = strings.TrimPrefix(cur.HeadRaw, d)
+	cur.RawText = strings.TrimPrefix(cur.RawText, d)
+	return d
+}
+
+// detachedComments returns the comments before st up to the last blank line
+// among them, such as a file header: they are not about st and outlive it.
+func detachedComments(st *nc.Stmt) string {
+	lines := strings.SplitAfter(leadingComments(st), "\n")
+	for i := len(lines) - 2; i > 0; i-- {
+		pre := strings.Join(lines[:i+1], "")
+		if strings.TrimSpace(lines[i]) == "" && strings.Count(pre, "/*") == strings.Count(pre, "*/") {
+			return pre
+		}
+	}
+	return ""
+}
+
+// appendStmt appends st, starting it on a new line.
+func appendStmt(out []nc.Node, st *nc.Stmt) []nc.Node {
+	if len(out) > 0 {
+		if r, ok := out[len(out)-1].(*nc.Raw); !ok || !strings.HasSuffix(r.Text, "\n") {
+			out = append(out, &nc.Raw{Text: "\n"})
+		}
 	}
-	for _, in := range incs {
-		out = append(out, nc.NewSimpleStmt("include \""+in.Path+"\""))
+	return append(out, st)
+}
+
+// render returns the text of f, newline-terminated.
+func render(f *nc.File) []byte {
+	b := f.Bytes()
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		b = append(b, '\n')
 	}
-	f.Nodes = out
+	return b
+}
+
+func buildInclude(in Include) *nc.Stmt {
+	return nc.NewSimpleStmt("include \"" + in.Path + "\"")
 }
 
 func buildACL(a ACL) *nc.Stmt {
 	head := "acl \"" + a.Name + "\""
-	body := []nc.Node{&nc.Raw{Text: serializeMatchList(a.Elements)}}
+	var body []nc.Node
+	for _, t := range a.Elements {
+		body = append(body, nc.NewSimpleStmt(serializeMatchTerm(t)))
+	}
 	return nc.NewBlockStmt(head, body)
 }
 
@@ -441,7 +1235,11 @@ func buildRemoteServers(rs RemoteServers) *nc.Stmt {
 	for _, it := range rs.Servers {
 		body = append(body, nc.NewSimpleStmt(serializeRemoteServerItem(it)))
 	}
-	return nc.NewBlockStmt("remote-servers \""+rs.Name+"\"", body)
+	kw := "remote-servers"
+	if rs.keyword != "" {
+		kw = rs.keyword
+	}
+	return nc.NewBlockStmt(kw+" \""+rs.Name+"\"", body)
 }
 
 func buildTLS(t TLS) *nc.Stmt {
@@ -490,6 +1288,9 @@ func buildHTTP(h HTTP) *nc.Stmt {
 
 func buildControls(c Controls) *nc.Stmt {
 	body := []nc.Node{}
+	if c.Disabled {
+		return nc.NewBlockStmt("controls", body)
+	}
 	for _, in := range c.Inet {
 		body = append(body, nc.NewSimpleStmt(serializeControlInet(in)))
 	}
@@ -517,8 +1318,8 @@ func buildLogChannel(ch LogChannel) *nc.Stmt {
 		if ch.File.Versions != nil {
 			parts = append(parts, "versions "+strconv.Itoa(*ch.File.Versions))
 		}
-		if ch.File.Size != "" {
-			parts = append(parts, "size "+ch.File.Size)
+		if ch.File.Size != nil {
+			parts = append(parts, "size "+ch.File.Size.String())
 		}
 		if ch.File.Suffix != "" {
 			parts = append(parts, "suffix "+ch.File.Suffix)
@@ -541,11 +1342,15 @@ func buildLogChannel(ch LogChannel) *nc.Stmt {
 	if ch.Null {
 		body = append(body, nc.NewSimpleStmt("null"))
 	}
-	if ch.Severity != "" {
-		body = append(body, nc.NewSimpleStmt("severity "+ch.Severity))
+	if ch.Severity != nil {
+		sev := "severity " + ch.Severity.Level
+		if ch.Severity.DebugLevel != nil {
+			sev += " " + strconv.Itoa(*ch.Severity.DebugLevel)
+		}
+		body = append(body, nc.NewSimpleStmt(sev))
 	}
-	if ch.PrintTime != nil {
-		body = append(body, nc.NewSimpleStmt("print-time "+boolWord(*ch.PrintTime)))
+	if ch.PrintTime != "" {
+		body = append(body, nc.NewSimpleStmt("print-time "+string(ch.PrintTime)))
 	}
 	if ch.PrintCategory != nil {
 		body = append(body, nc.NewSimpleStmt("print-category "+boolWord(*ch.PrintCategory)))
@@ -559,7 +1364,7 @@ func buildLogChannel(ch LogChannel) *nc.Stmt {
 	return nc.NewBlockStmt("channel \""+ch.Name+"\"", body)
 }
 
-func parseLogChannel(st *nc.Stmt) LogChannel {
+func (ld *loader) parseLogChannel(st *nc.Stmt) LogChannel {
 	name := headNameAfter(st, "channel")
 	lc := LogChannel{Name: name}
 	for _, n := range st.Body {
@@ -567,10 +1372,13 @@ func parseLogChannel(st *nc.Stmt) LogChannel {
 		if !ok {
 			continue
 		}
-		raw := strings.TrimSpace(strings.TrimSuffix(ss.HeadRaw, ";"))
+		raw := stmtValue(ss)
 		switch ss.Keyword {
 		case "file":
 			args := strings.Fields(raw)
+			if len(args) == 0 {
+				continue
+			}
 			lf := LogFileDest{Path: trimQuotes(args[0])}
 			for i := 1; i < len(args); i++ {
 				switch args[i] {
@@ -583,7 +1391,9 @@ func parseLogChannel(st *nc.Stmt) LogChannel {
 					}
 				case "size":
 					if i+1 < len(args) {
-						lf.Size = args[i+1]
+						if sz, err := ParseSize(args[i+1]); err == nil {
+							lf.Size = &sz
+						}
 						i++
 					}
 				case "suffix":
@@ -611,28 +1421,34 @@ func parseLogChannel(st *nc.Stmt) LogChannel {
 		case "null":
 			lc.Null = true
 		case "severity":
-			lc.Severity = raw
+			f := strings.Fields(raw)
+			if len(f) > 0 {
+				lc.Severity = &LogSeverity{Level: f[0]}
+				if len(f) > 1 {
+					lc.Severity.DebugLevel = parseIntPtr(f[1])
+				}
+			}
 		case "print-time":
-			lc.PrintTime = parseBoolPtr(raw)
+			lc.PrintTime = PrintTime(strings.ToLower(firstField(raw)))
 		case "print-category":
-			lc.PrintCategory = parseBoolPtr(raw)
+			lc.PrintCategory = ld.boolPtr(ss, raw)
 		case "print-severity":
-			lc.PrintSeverity = parseBoolPtr(raw)
+			lc.PrintSeverity = ld.boolPtr(ss, raw)
 		case "buffered":
-			lc.Buffered = parseBoolPtr(raw)
+			lc.Buffered = ld.boolPtr(ss, raw)
+		default:
+			ld.unmodeled(ss, "channel "+name)
 		}
 	}
 	return lc
 }
 
-func parseLogCategory(st *nc.Stmt) LogCategory {
+func (ld *loader) parseLogCategory(st *nc.Stmt) LogCategory {
 	name := headNameAfter(st, "category")
 	lc := LogCategory{Name: name}
-	if len(st.Body) > 0 {
-		if r, ok := st.Body[0].(*nc.Raw); ok {
-			names := parseStringList(r.Text)
-			lc.Channels = names
-		}
+	raw := stmtValue(st)
+	if i := strings.Index(raw, "{"); i >= 0 {
+		lc.Channels = parseStringList(raw[i:])
 	}
 	return lc
 }
@@ -674,9 +1490,81 @@ func buildOptions(o Options) *nc.Stmt {
 	if o.DNSSECValidation != "" {
 		add("dnssec-validation " + o.DNSSECValidation)
 	}
+	if o.MasterfileFormat != "" {
+		add("masterfile-format " + string(o.MasterfileFormat))
+	}
+	if o.MasterfileStyle != "" {
+		add("masterfile-style " + string(o.MasterfileStyle))
+	}
 	if len(o.RRsetOrder) > 0 {
 		add("rrset-order { " + serializeRRsetOrder(o.RRsetOrder) + " }")
 	}
+	for _, stmt := range resolverTuning(o.Prefetch, o.QNameMinimization, o.MinimalResponses, o.MinimalAny) {
+		add(stmt)
+	}
+	if o.ResponsePadding != nil {
+		add("response-padding " + serializeResponsePadding(*o.ResponsePadding))
+	}
+	if o.TKeyGSSAPIKeytab != "" {
+		add("tkey-gssapi-keytab \"" + o.TKeyGSSAPIKeytab + "\"")
+	}
+	if o.TKeyGSSAPICredential != "" {
+		add("tkey-gssapi-credential \"" + o.TKeyGSSAPICredential + "\"")
+	}
+	if o.TKeyDomain != "" {
+		add("tkey-domain \"" + o.TKeyDomain + "\"")
+	}
+	if o.SessionKeyFile == "none" {
+		add("session-keyfile none")
+	} else if o.SessionKeyFile != "" {
+		add("session-keyfile \"" + o.SessionKeyFile + "\"")
+	}
+	if o.SessionKeyName != "" {
+		add("session-keyname \"" + o.SessionKeyName + "\"")
+	}
+	if o.SessionKeyAlg != "" {
+		add("session-keyalg " + o.SessionKeyAlg)
+	}
+	if o.MaxCacheSize != nil {
+		add("max-cache-size " + o.MaxCacheSize.String())
+	}
+	if o.MaxCacheTTL != nil {
+		add("max-cache-ttl " + o.MaxCacheTTL.String())
+	}
+	if o.MaxNCacheTTL != nil {
+		add("max-ncache-ttl " + o.MaxNCacheTTL.String())
+	}
+	if o.MaxJournalSize != nil {
+		add("max-journal-size " + o.MaxJournalSize.String())
+	}
+	if o.LMDBMapSize != nil {
+		add("lmdb-mapsize " + o.LMDBMapSize.String())
+	}
+	if o.MaxRecords != nil {
+		add("max-records " + strconv.Itoa(*o.MaxRecords))
+	}
+	if o.AllowNewZones != nil {
+		add("allow-new-zones " + boolWord(*o.AllowNewZones))
+	}
+	if o.Version != nil {
+		add("version " + serializeServerIdent(*o.Version))
+	}
+	if o.Hostname != nil {
+		add("hostname " + serializeServerIdent(*o.Hostname))
+	}
+	if o.ServerID != nil {
+		add("server-id " + serializeServerIdent(*o.ServerID))
+	}
+	for _, kw := range pathOptions {
+		switch path := *o.pathField(kw); {
+		case path == "":
+		case path == "none" && (kw == "pid-file" || kw == "lock-file") && !o.quotedNone[kw]:
+			// only pid-file and lock-file accept the bare keyword none
+			add(kw + " none")
+		default:
+			add(kw + " " + quote(path))
+		}
+	}
 	for _, kv := range o.Other {
 		add(kv.Name + " " + kv.Raw)
 	}
@@ -696,9 +1584,27 @@ func buildView(v View) *nc.Stmt {
 	if len(v.MatchDestinations) > 0 {
 		add("match-destinations " + serializeMatchList(v.MatchDestinations))
 	}
+	if v.MatchRecursive != nil {
+		add("match-recursive-only " + boolWord(*v.MatchRecursive))
+	}
 	if v.Recursion != nil {
 		add("recursion " + boolWord(*v.Recursion))
 	}
+	for _, stmt := range resolverTuning(v.Prefetch, v.QNameMinimization, v.MinimalResponses, v.MinimalAny) {
+		add(stmt)
+	}
+	if v.ResponsePadding != nil {
+		add("response-padding " + serializeResponsePadding(*v.ResponsePadding))
+	}
+	if v.LMDBMapSize != nil {
+		add("lmdb-mapsize " + v.LMDBMapSize.String())
+	}
+	if v.MaxRecords != nil {
+		add("max-records " + strconv.Itoa(*v.MaxRecords))
+	}
+	if v.AllowNewZones != nil {
+		add("allow-new-zones " + boolWord(*v.AllowNewZones))
+	}
 	if v.TrustAnchors != nil {
 		body = append(body, buildTrustAnchors(*v.TrustAnchors))
 	}
@@ -718,17 +1624,26 @@ func buildZone(z Zone) *nc.Stmt {
 	}
 	body := []nc.Node{}
 	add := func(stmt string) { body = append(body, nc.NewSimpleStmt(stmt)) }
-	if z.Type != "" {
+	if z.typeWord != "" && legacyZoneTypes[z.typeWord] == z.Type {
+		add("type " + z.typeWord)
+	} else if z.Type != "" {
 		add("type " + string(z.Type))
 	}
+	primariesKW := "primaries"
+	if z.primariesKW != "" {
+		primariesKW = z.primariesKW
+	}
 	if z.File != "" {
 		add("file \"" + z.File + "\"")
 	}
+	if z.InView != "" {
+		add("in-view " + z.InView)
+	}
 	if z.PrimariesRef != "" {
-		add("primaries " + z.PrimariesRef)
+		add(primariesKW + " " + z.PrimariesRef)
 	}
 	if len(z.Primaries) > 0 {
-		add("primaries " + serializeRemoteServerList(z.Primaries))
+		add(primariesKW + " " + serializeRemoteServerList(z.Primaries))
 	}
 	if len(z.Forwarders) > 0 {
 		add("forwarders " + serializeForwarders(z.Forwarders))
@@ -745,20 +1660,28 @@ func buildZone(z Zone) *nc.Stmt {
 	if len(z.AlsoNotify) > 0 {
 		add("also-notify " + serializeRemoteServerList(z.AlsoNotify))
 	}
+	if z.Notify != "" {
+		add("notify " + z.Notify)
+	}
 	if z.DNSSECPolicy != "" {
 		add("dnssec-policy \"" + z.DNSSECPolicy + "\"")
 	}
+	if z.MasterfileFormat != "" {
+		add("masterfile-format " + string(z.MasterfileFormat))
+	}
+	if z.MasterfileStyle != "" {
+		add("masterfile-style " + string(z.MasterfileStyle))
+	}
+	if z.MaxRecords != nil {
+		add("max-records " + strconv.Itoa(*z.MaxRecords))
+	}
 	return nc.NewBlockStmt(head, body)
 }
 
 func buildTrustAnchors(t TrustAnchors) *nc.Stmt {
 	body := []nc.Node{}
 	for _, it := range t.Items {
-		if it.DS != "" {
-			body = append(body, nc.NewSimpleStmt("\""+it.Name+"\" "+it.DS))
-		} else if it.DNSKey != "" {
-			body = append(body, nc.NewSimpleStmt("\""+it.Name+"\" "+it.DNSKey))
-		}
+		body = append(body, nc.NewSimpleStmt(serializeTrustAnchorItem(it)))
 	}
 	return nc.NewBlockStmt("trust-anchors", body)
 }
@@ -0,0 +1,205 @@
+// File: pkg/namedzone/load_test.go
+package namedzone
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadMatchListComments loads match lists with comments of each style in
+// every position an element list allows.
+func TestLoadMatchListComments(t *testing.T) {
+	want := []MatchTerm{
+		{Address: "10.0.0.0/8"},
+		{Not: true, Address: "192.0.2.1"},
+		{Key: "k"},
+		{Nested: []MatchTerm{{ACLRef: "localhost"}, {Address: "2001:db8::/32"}}},
+	}
+	list := func(c string) string {
+		return "{ " + c + "\n" +
+			"10.0.0.0/8; " + c + "\n" +
+			c + "\n" +
+			"! " + c + "\n 192.0.2.1;\n" +
+			"key " + c + "\n \"k\" " + c + "\n;\n" +
+			"{ " + c + "\n localhost; " + c + "\n 2001:db8::/32; " + c + "\n }; " + c + "\n" +
+			"}"
+	}
+	for _, c := range []string{"// office", "# office", "/* lab */", "/* multi\n   line */"} {
+		src := "acl a " + list(c) + ";\n" +
+			"options { allow-query " + list(c) + "; };\n" +
+			"view v { match-clients " + list(c) + "; zone \"x\" { type primary; file \"x\"; allow-transfer " + list(c) + "; }; };\n"
+		cfg, err := FromReader(strings.NewReader(src))
+		if err != nil {
+			t.Fatalf("%q: %v", c, err)
+		}
+		got := map[string][]MatchTerm{
+			"acl":            cfg.ACLs[0].Elements,
+			"allow-query":    cfg.Options.AllowQuery,
+			"match-clients":  cfg.Views[0].MatchClients,
+			"allow-transfer": cfg.Views[0].Zones[0].AllowTransfer,
+		}
+		for where, terms := range got {
+			if !reflect.DeepEqual(terms, want) {
+				t.Errorf("%q: %s = %+v, want %+v", c, where, terms, want)
+			}
+		}
+
+		// an edit makes Apply build the statements again
+		cfg.ACLs[0].Elements = append(cfg.ACLs[0].Elements, MatchTerm{ACLRef: "none"})
+		text, err := cfg.Render()
+		if err != nil {
+			t.Fatalf("%q: render: %v", c, err)
+		}
+		again, err := FromReader(strings.NewReader(text))
+		if err != nil {
+			t.Fatalf("%q: reload: %v\n%s", c, err, text)
+		}
+		if !again.Equal(cfg) {
+			t.Errorf("%q: reloaded config differs:\n%s", c, text)
+		}
+	}
+}
+
+// TestLoadOddHeads loads statements whose head does not start with their
+// keyword as the parser reports it.
+func TestLoadOddHeads(t *testing.T) {
+	for _, tc := range []struct {
+		src   string
+		check func(*Config) bool
+	}{
+		// no name: kept as a nameless view and written back as read
+		{"view {\x80;};", func(c *Config) bool { return len(c.Views) == 1 && c.Views[0].Name == "" }},
+		{"\"options\" { directory \"/x\"; };", func(c *Config) bool { return c.Options != nil && c.Options.Directory == "/x" }},
+		{"\"options\" { \"directory\" \"/x\"; };", func(c *Config) bool { return c.Options != nil && c.Options.Directory == "/x" }},
+		{"OPTIONS { recursion no; };", func(c *Config) bool {
+			return c.Options != nil && c.Options.Recursion != nil && !*c.Options.Recursion
+		}},
+	} {
+		cfg, err := FromReader(strings.NewReader(tc.src))
+		if err != nil {
+			t.Errorf("%q: %v", tc.src, err)
+			continue
+		}
+		if !tc.check(cfg) {
+			t.Errorf("%q: loaded as options = %+v, views = %+v", tc.src, cfg.Options, cfg.Views)
+		}
+		if out, err := cfg.Render(); err != nil || out != tc.src+"\n" {
+			t.Errorf("%q: rendered as %q, %v", tc.src, out, err)
+		}
+	}
+}
+
+// TestLoadUnknownLogCategory renders a category outside the catalog, which
+// Validate only warns about.
+func TestLoadUnknownLogCategory(t *testing.T) {
+	cfg, err := FromReader(strings.NewReader("logging { category delegation-only { null; }; };"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetRecursion(false)
+	if _, err := cfg.Render(); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var warned bool
+	for _, is := range cfg.Validate() {
+		if is.Path == "logging" {
+			warned = is.Severity == SeverityWarning
+		}
+	}
+	if !warned {
+		t.Errorf("Validate: no warning for the unknown category: %v", cfg.Validate())
+	}
+}
+
+// TestPathOptionNone keeps "none" bare only where named accepts the keyword
+// and keeps a quoted "none" quoted.
+func TestPathOptionNone(t *testing.T) {
+	cfg, err := FromReader(strings.NewReader(`options { pid-file none; lock-file "none"; statistics-file "none"; };`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Options.Directory = "/var/named" // rebuild the options block
+	built := &Config{Options: &Options{PIDFile: "none", LockFile: "none", StatisticsFile: "none", DumpFile: "/var/named/dump.db"}}
+	for name, tc := range map[string]struct {
+		cfg  *Config
+		want []string
+	}{
+		"loaded": {cfg, []string{`directory "/var/named";`, `pid-file none;`, `lock-file "none";`, `statistics-file "none";`}},
+		"built":  {built, []string{`pid-file none;`, `lock-file none;`, `statistics-file "none";`, `dump-file "/var/named/dump.db";`}},
+	} {
+		text, err := tc.cfg.Render()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, w := range tc.want {
+			if !strings.Contains(text, w) {
+				t.Errorf("%s: missing %s in\n%s", name, w, text)
+			}
+		}
+	}
+}
+
+// TestLoadBooleanSpellings reads named's other boolean spellings into the
+// typed fields, so rebuilding a block keeps the setting.
+func TestLoadBooleanSpellings(t *testing.T) {
+	cfg, err := FromReader(strings.NewReader(`options { minimal-any 1; directory "/a"; };
+view "v" { match-clients { any; }; minimal-any false; };`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := cfg.Options; o.MinimalAny == nil || !*o.MinimalAny {
+		t.Errorf("options minimal-any = %v", o.MinimalAny)
+	}
+	if v := cfg.Views[0]; v.MinimalAny == nil || *v.MinimalAny {
+		t.Errorf("view minimal-any = %v", v.MinimalAny)
+	}
+	cfg.Options.Directory = "/b"
+	text, err := cfg.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "minimal-any yes;") {
+		t.Errorf("minimal-any lost:\n%s", text)
+	}
+}
+
+// TestLoadAllowNewZonesTrue keeps allow-new-zones spelled true through a
+// rebuild of the options block.
+func TestLoadAllowNewZonesTrue(t *testing.T) {
+	cfg, err := FromReader(strings.NewReader(`options { allow-new-zones true; directory "/a"; };`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Options.Directory = "/b"
+	text, err := cfg.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := FromReader(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, c := range map[string]*Config{"loaded": cfg, "rendered": back} {
+		if !c.AllowsNewZones("") || !c.HasRuntimeZones() {
+			t.Errorf("%s: new zones not allowed:\n%s", name, text)
+		}
+	}
+}
+
+// TestLoadMatchRecursiveOnlyTrue selects views by match-recursive-only
+// spelled true.
+func TestLoadMatchRecursiveOnlyTrue(t *testing.T) {
+	cfg, err := FromReader(strings.NewReader(`view "resolver" { match-clients { any; }; match-recursive-only true; };
+view "auth" { match-clients { any; }; };`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := netip.MustParseAddr("192.0.2.1")
+	for recursive, want := range map[bool]string{true: "resolver", false: "auth"} {
+		if v := cfg.SelectViewFor(ViewQuery{Client: client, Recursive: recursive}); v == nil || v.Name != want {
+			t.Errorf("recursive=%v: view %v, want %s", recursive, v, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// File: pkg/namedzone/lock.go
+package namedzone
+
+import (
+	"os"
+	"time"
+)
+
+// lockPoll is how often a contended lock is retried.
+const lockPoll = 50 * time.Millisecond
+
+// lockFile takes an exclusive advisory lock on name+".lock", creating it if
+// needed. The lock lives on a side file because Save replaces the config
+// file itself. A zero timeout waits as long as it takes.
+func lockFile(name string, timeout time.Duration) (*os.File, error) {
+	lp := name + ".lock"
+	f, err := os.OpenFile(lp, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if ok {
+			return f, nil
+		}
+		if timeout > 0 && time.Now().After(deadline) {
+			f.Close()
+			return nil, &LockError{Path: lp, Timeout: timeout}
+		}
+		time.Sleep(lockPoll)
+	}
+}
+
+// Unlock releases the lock taken by LoadOptions.Lock. It is a no-op for a
+// Config that holds none.
+func (c *Config) Unlock() error {
+	if c.lock == nil {
+		return nil
+	}
+	f := c.lock
+	c.lock = nil
+	err := unlock(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+// File: pkg/namedzone/lock_other.go
+package namedzone
+
+import (
+	"errors"
+	"os"
+)
+
+var errNoFlock = errors.New("namedzone: file locking is not supported on this platform")
+
+func tryLock(*os.File) (bool, error) { return false, errNoFlock }
+
+func unlock(*os.File) error { return errNoFlock }
@@ -0,0 +1,61 @@
+// File: pkg/namedzone/logging.go
+package namedzone
+
+import "slices"
+
+// KnownLogCategories is the catalog of logging categories understood by
+// current BIND 9 releases.
+var KnownLogCategories = []string{
+	"client", "cname", "config", "database", "default", "dispatch",
+	"dnssec", "dnstap", "edns-disabled", "general", "lame-servers",
+	"network", "notify", "nsid", "queries", "query-errors", "rate-limit",
+	"resolver", "rpz", "rpz-passthru", "security", "serve-stale", "spill",
+	"sslkeylog", "trust-anchor-telemetry", "unmatched", "update",
+	"update-security", "xfer-in", "xfer-out", "zoneload",
+}
+
+// IsKnownLogCategory reports whether name is in KnownLogCategories.
+func IsKnownLogCategory(name string) bool { return slices.Contains(KnownLogCategories, name) }
+
+// unknownCategories returns the category names outside the catalog unless
+// AllowUnknownCategories is set.
+func (l *Logging) unknownCategories() []string {
+	if l.AllowUnknownCategories {
+		return nil
+	}
+	var out []string
+	for _, cat := range l.Categories {
+		if !IsKnownLogCategory(cat.Name) {
+			out = append(out, cat.Name)
+		}
+	}
+	return out
+}
+
+// EnsureChannel adds ch if no channel with the same name exists and returns
+// a pointer to the channel stored in l (the existing one is left untouched).
+func (l *Logging) EnsureChannel(ch LogChannel) *LogChannel {
+	for i := range l.Channels {
+		if l.Channels[i].Name == ch.Name {
+			return &l.Channels[i]
+		}
+	}
+	l.Channels = append(l.Channels, ch)
+	return &l.Channels[len(l.Channels)-1]
+}
+
+// RouteCategory binds category name to the given channels, replacing any
+// previous binding for that category.
+func (l *Logging) RouteCategory(name string, channels ...string) error {
+	if !l.AllowUnknownCategories && !IsKnownLogCategory(name) {
+		return &ValueError{Path: "logging", Value: name, Msg: "unknown category"}
+	}
+	for i := range l.Categories {
+		if l.Categories[i].Name == name {
+			l.Categories[i].Channels = channels
+			return nil
+		}
+	}
+	l.Categories = append(l.Categories, LogCategory{Name: name, Channels: channels})
+	return nil
+}
@@ -0,0 +1,127 @@
+// File: pkg/namedzone/manager.go
+package namedzone
+
+import "sync"
+
+// Manager guards a Config for concurrent use, e.g. from HTTP handlers.
+// Reads run in parallel on the live Config or on a Snapshot; mutations,
+// Apply, Render and Save are serialized. Once handed to a Manager the Config
+// must only be reached through it.
+type Manager struct {
+	mu   sync.RWMutex
+	cfg  *Config
+	path string
+}
+
+// NewManager wraps cfg; Save writes it to path.
+func NewManager(cfg *Config, path string) *Manager {
+	return &Manager{cfg: cfg, path: path}
+}
+
+// Read runs fn with the live Config under a read lock. fn must neither
+// modify the Config nor keep references into it after returning.
+func (m *Manager) Read(fn func(c *Config) error) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return fn(m.cfg)
+}
+
+// Update runs fn with the live Config under the write lock.
+func (m *Manager) Update(fn func(c *Config) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return fn(m.cfg)
+}
+
+// Snapshot returns a deep copy of the typed config that the caller may read
+// and modify freely. It has no AST; changes to it do not reach the Manager.
+func (m *Manager) Snapshot() (*Config, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg.Clone(), nil
+}
+
+// GetZone returns a copy of the first zone with the given name.
+func (m *Manager) GetZone(name string) (Zone, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if z := m.cfg.GetZone(name); z != nil {
+		return *z, true
+	}
+	return Zone{}, false
+}
+
+// GetZoneInView returns a copy of the zone name in view (top-level when view
+// is empty).
+func (m *Manager) GetZoneInView(view, name string) (Zone, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if z := m.cfg.GetZoneInView(view, name); z != nil {
+		return *z, true
+	}
+	return Zone{}, false
+}
+
+// UpsertZone inserts or replaces a top-level zone by name.
+func (m *Manager) UpsertZone(z Zone) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg.UpsertZone(z)
+}
+
+// RemoveZone removes a top-level zone by name and returns true if found.
+func (m *Manager) RemoveZone(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg.RemoveZone(name)
+}
+
+// UpsertView inserts or replaces a view by name.
+func (m *Manager) UpsertView(v View) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg.UpsertView(v)
+}
+
+// RemoveView removes a view by name and returns true if found.
+func (m *Manager) RemoveView(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg.RemoveView(name)
+}
+
+// UpsertZoneInView inserts or replaces a zone inside a view.
+func (m *Manager) UpsertZoneInView(viewName string, z Zone) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg.UpsertZoneInView(viewName, z)
+}
+
+// RemoveZoneInView removes a zone from a view and returns true if found.
+func (m *Manager) RemoveZoneInView(viewName, zoneName string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg.RemoveZoneInView(viewName, zoneName)
+}
+
+// SetRecursion sets global options.recursion.
+func (m *Manager) SetRecursion(b bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg.SetRecursion(b)
+}
+
+// Render returns the named.conf text Save would write. It applies the
+// typed config to the AST, so it takes the write lock.
+func (m *Manager) Render() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg.Render()
+}
+
+// Save writes the config to the Manager's path.
+func (m *Manager) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg.Save(m.path)
+}
@@ -0,0 +1,211 @@
+// File: pkg/namedzone/merge.go
+package namedzone
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// MergeStrategy decides what Merge does when both configs define the same
+// item differently.
+type MergeStrategy int
+
+const (
+	// MergeError fails with a *ConflictError on the first conflict.
+	MergeError MergeStrategy = iota
+	// MergeOurs keeps the receiver's definition.
+	MergeOurs
+	// MergeTheirs takes the other config's definition.
+	MergeTheirs
+)
+
+// Merge folds other into c, e.g. site overrides into a base template. Named
+// items (ACLs, keys, key-stores, remote-servers, tls, http, trust-anchors,
+// views, zones) are matched by name: items only in other are appended, and
+// items in both that differ are resolved by strategy. Views merge their zones
+// by name the same way and their own settings field by field, as do options.
+// Logging merges channels and categories by name, and controls merge as one
+// block. Includes are unioned. c is unchanged if an error is returned.
+func (c *Config) Merge(other *Config, strategy MergeStrategy) error {
+	if other == nil {
+		return nil
+	}
+	var n Config
+	var err error
+	m := merger{strategy: strategy}
+	n.Includes = mergeNamed(&m, c.Includes, other.Includes, "include", func(i Include) string { return i.Path })
+	n.ACLs = mergeNamed(&m, c.ACLs, other.ACLs, "acl", func(a ACL) string { return a.Name })
+	n.Keys = mergeNamed(&m, c.Keys, other.Keys, "key", func(k Key) string { return k.Name })
+	n.KeyStores = mergeNamed(&m, c.KeyStores, other.KeyStores, "key-store", func(k KeyStore) string { return k.Name })
+	n.RemoteServers = mergeNamed(&m, c.RemoteServers, other.RemoteServers, "remote-servers", func(r RemoteServers) string { return r.Name })
+	n.TLS = mergeNamed(&m, c.TLS, other.TLS, "tls", func(t TLS) string { return t.Name })
+	n.HTTP = mergeNamed(&m, c.HTTP, other.HTTP, "http", func(h HTTP) string { return h.Name })
+	// trust-anchors blocks have no name; keep each distinct block once
+	n.TrustAnchors = mergeNamed(&m, c.TrustAnchors, other.TrustAnchors, "trust-anchors", func(t TrustAnchors) string {
+		b, _ := json.Marshal(t)
+		return string(b)
+	})
+	n.Zones = mergeNamed(&m, c.Zones, other.Zones, "zone", func(z Zone) string { return z.Name })
+	n.Controls = mergeWhole(&m, c.Controls, other.Controls, "controls")
+	n.Logging = m.logging(c.Logging, other.Logging)
+	n.ModernizeKeywords = c.ModernizeKeywords
+	if n.Views, err = m.views(c.Views, other.Views); err != nil {
+		return err
+	}
+	switch {
+	case other.Options == nil:
+		n.Options = c.Options
+	case c.Options == nil:
+		n.Options = other.Options
+	default:
+		o, err := mergeFields(&m, *c.Options, *other.Options, "option")
+		if err != nil {
+			return err
+		}
+		n.Options = &o
+	}
+	if m.err != nil {
+		return m.err
+	}
+	c.replaceTyped(&n)
+	// items taken from other must not point at files outside this tree
+	foreign := func(origin *string) {
+		if _, ok := c.files[*origin]; !ok {
+			*origin = ""
+		}
+	}
+	c.eachOrigin(foreign)
+	for i := range c.Views {
+		for j := range c.Views[i].Zones {
+			foreign(&c.Views[i].Zones[j].origin)
+		}
+	}
+	c.audit("Merge", "", nil)
+	return nil
+}
+
+func (m *merger) views(ours, theirs []View) ([]View, error) {
+	out := slices.Clone(ours)
+	for _, t := range theirs {
+		i := slices.IndexFunc(out, func(v View) bool { return v.Name == t.Name })
+		if i < 0 {
+			out = append(out, t)
+			continue
+		}
+		o := out[i]
+		zones := mergeNamed(m, o.Zones, t.Zones, "zone", func(z Zone) string { return z.Name })
+		includes := mergeNamed(m, o.Includes, t.Includes, "include", func(i Include) string { return i.Path })
+		o.Zones, o.Includes, t.Zones, t.Includes = nil, nil, nil, nil
+		v, err := mergeFields(m, o, t, "view "+t.Name)
+		if err != nil {
+			return nil, err
+		}
+		v.Zones, v.Includes = zones, includes
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (m *merger) logging(ours, theirs *Logging) *Logging {
+	switch {
+	case theirs == nil:
+		return ours
+	case ours == nil:
+		return theirs
+	}
+	l := *ours
+	l.Channels = mergeNamed(m, ours.Channels, theirs.Channels, "channel", func(ch LogChannel) string { return ch.Name })
+	l.Categories = mergeNamed(m, ours.Categories, theirs.Categories, "category", func(c LogCategory) string { return c.Name })
+	l.AllowUnknownCategories = ours.AllowUnknownCategories || theirs.AllowUnknownCategories
+	return &l
+}
+
+// mergeFields merges two values field by field on their JSON projection;
+// fields set on both sides are resolved by strategy.
+func mergeFields[T any](m *merger, ours, theirs T, kind string) (T, error) {
+	var out T
+	a, err := jsonObject(ours)
+	if err != nil {
+		return out, err
+	}
+	b, err := jsonObject(theirs)
+	if err != nil {
+		return out, err
+	}
+	for _, k := range slices.Sorted(maps.Keys(b)) {
+		tv := b[k]
+		if ov, ok := a[k]; ok {
+			a[k] = resolve(m, ov, tv, kind, k)
+		} else {
+			a[k] = tv
+		}
+	}
+	raw, err := json.Marshal(a)
+	if err != nil {
+		return out, err
+	}
+	return out, json.Unmarshal(raw, &out)
+}
+
+func jsonObject(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	return m, json.Unmarshal(b, &m)
+}
+
+type merger struct {
+	strategy MergeStrategy
+	err      error // first conflict under MergeError
+}
+
+// resolve picks between two differing definitions of the same item.
+func resolve[T any](m *merger, ours, theirs T, kind, name string) T {
+	if sameJSON(ours, theirs) {
+		return ours
+	}
+	switch m.strategy {
+	case MergeTheirs:
+		return theirs
+	case MergeOurs:
+		return ours
+	}
+	if m.err == nil {
+		m.err = &ConflictError{Kind: kind, Name: name, Msg: "defined differently in both configs"}
+	}
+	return ours
+}
+
+func mergeNamed[T any](m *merger, ours, theirs []T, kind string, name func(T) string) []T {
+	out := slices.Clone(ours)
+	for _, t := range theirs {
+		i := slices.IndexFunc(out, func(o T) bool { return name(o) == name(t) })
+		if i < 0 {
+			out = append(out, t)
+			continue
+		}
+		out[i] = resolve(m, out[i], t, kind, name(t))
+	}
+	return out
+}
+
+func mergeWhole[T any](m *merger, ours, theirs *T, kind string) *T {
+	switch {
+	case theirs == nil:
+		return ours
+	case ours == nil:
+		return theirs
+	}
+	v := resolve(m, *ours, *theirs, kind, "")
+	return &v
+}
+
+func sameJSON(a, b any) bool {
+	x, err1 := json.Marshal(a)
+	y, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(x, y)
+}
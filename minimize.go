@@ -0,0 +1,125 @@
+// File: pkg/namedzone/minimize.go
+package namedzone
+
+import (
+	"slices"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// OptionDefaults maps options to the value named uses when they are unset,
+// as of BIND 9.18. Minimize removes options set to these values. Options
+// whose default depends on other settings (allow-recursion,
+// allow-query-cache), on the zone type (masterfile-format: text for primary
+// zones, raw for secondaries) or on the platform (pid-file) are left out, as
+// is allow-transfer, whose explicit { any; } Lint reports. Entries can be
+// added or removed to suit the target version.
+var OptionDefaults = map[string]string{
+	"recursion":               "yes",
+	"allow-query":             "{ any; }",
+	"allow-update":            "{ none; }",
+	"blackhole":               "{ none; }",
+	"listen-on":               "{ any; }",
+	"listen-on-v6":            "{ any; }",
+	"forward":                 "first",
+	"dnssec-validation":       "auto",
+	"dnssec-accept-expired":   "no",
+	"masterfile-style":        "relative",
+	"notify":                  "yes",
+	"notify-delay":            "5",
+	"max-cache-size":          "90%",
+	"max-cache-ttl":           "1w",
+	"max-ncache-ttl":          "3h",
+	"auth-nxdomain":           "no",
+	"minimal-responses":       "no-auth-recursive",
+	"minimal-any":             "no",
+	"qname-minimization":      "relaxed",
+	"edns-udp-size":           "1232",
+	"max-udp-size":            "1232",
+	"empty-zones-enable":      "yes",
+	"answer-cookie":           "yes",
+	"send-cookie":             "yes",
+	"require-server-cookie":   "no",
+	"stale-answer-enable":     "no",
+	"prefetch":                "2 9",
+	"synth-from-dnssec":       "yes",
+	"trust-anchor-telemetry":  "yes",
+	"root-key-sentinel":       "yes",
+	"ixfr-from-differences":   "no",
+	"provide-ixfr":            "yes",
+	"request-ixfr":            "yes",
+	"flush-zones-on-shutdown": "no",
+	"zone-statistics":         "terse",
+	"transfers-in":            "10",
+	"transfers-out":           "10",
+	"transfers-per-ns":        "2",
+	"serial-query-rate":       "20",
+	"tcp-clients":             "150",
+	"recursive-clients":       "1000",
+	"clients-per-query":       "10",
+	"max-clients-per-query":   "100",
+	"statistics-file":         `"named.stats"`,
+	"dump-file":               `"named_dump.db"`,
+	"recursing-file":          `"named.recursing"`,
+	"memstatistics-file":      `"named.memstats"`,
+	"secroots-file":           `"named.secroots"`,
+}
+
+// durationOptions are compared as durations, so 604800 matches 1w.
+var durationOptions = map[string]bool{"max-cache-ttl": true, "max-ncache-ttl": true}
+
+// Minimize removes the options (the options block only; view and zone
+// settings override inherited values and are kept) whose value equals
+// OptionDefaults, and reports each as a Rewrite with an empty To. Values
+// are compared by tokens, ignoring layout and quotes; durations by length.
+func (c *Config) Minimize() []Rewrite {
+	o := c.Options
+	if o == nil {
+		return nil
+	}
+	var out []Rewrite
+	built := buildOptions(*o)
+	body := slices.DeleteFunc(slices.Clone(built.Body), func(n nc.Node) bool {
+		st, ok := n.(*nc.Stmt)
+		if !ok {
+			return false
+		}
+		def, ok := OptionDefaults[st.Keyword]
+		if !ok || !sameValue(st.Keyword, stmtValue(st), def) {
+			return false
+		}
+		out = append(out, Rewrite{Path: "options", From: st.Keyword + " " + stmtValue(st)})
+		return true
+	})
+	if len(out) == 0 {
+		return nil
+	}
+	op := (&loader{}).parseOptions(nc.NewBlockStmt("options", body))
+	op.stmt, op.origin = o.stmt, o.origin
+	*o = op
+	c.audit("Minimize", "options", nil)
+	return out
+}
+
+func sameValue(option, a, b string) bool {
+	if durationOptions[option] {
+		da, errA := ParseDuration(a)
+		db, errB := ParseDuration(b)
+		if errA == nil && errB == nil {
+			return da == db
+		}
+	}
+	norm := func(s string) []string {
+		toks := tokenize(s)
+		for i, t := range toks {
+			if u := trimQuotes(t); u != t {
+				toks[i] = u
+			} else {
+				toks[i] = strings.ToLower(t)
+			}
+		}
+		return toks
+	}
+	return slices.Equal(norm(a), norm(b))
+}
@@ -0,0 +1,24 @@
+// File: pkg/namedzone/minimize_test.go
+package namedzone
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMinimizeKeepsMasterfileFormat keeps masterfile-format text: named
+// writes secondary zones in raw format unless told otherwise.
+func TestMinimizeKeepsMasterfileFormat(t *testing.T) {
+	cfg, err := FromReader(strings.NewReader(`options { masterfile-format text; recursion yes; };
+zone "example.com" { type secondary; primaries { 192.0.2.1; }; file "example.com.db"; };`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw := cfg.Minimize()
+	if len(rw) != 1 || rw[0].From != "recursion yes" {
+		t.Errorf("rewrites = %+v, want recursion only", rw)
+	}
+	if cfg.Options.MasterfileFormat != MasterfileText {
+		t.Errorf("masterfile-format = %q, want text", cfg.Options.MasterfileFormat)
+	}
+}
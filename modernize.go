@@ -0,0 +1,169 @@
+// File: pkg/namedzone/modernize.go
+package namedzone
+
+import (
+	"fmt"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// Rewrite is one change made by Modernize. To is empty for a statement that
+// was removed.
+type Rewrite struct {
+	Path string `json:"path"` // GetPath syntax, e.g. view[int].zone[example.com]
+	From string `json:"from"`
+	To   string `json:"to,omitempty"`
+}
+
+// Modernize replaces deprecated spellings in c with their current forms and
+// reports each rewrite:
+//
+//   - masters and primaries blocks become remote-servers, and the masters
+//     option of zones becomes primaries (references to the lists, e.g. in
+//     also-notify, keep working since the names do not change);
+//   - zone types master and slave become primary and secondary;
+//   - trusted-keys and managed-keys, at the top level or in views, become
+//     trust-anchors entries (static-key and initial-key);
+//   - dnssec-enable, which named no longer accepts, is removed from options
+//     and views.
+//
+// The changes are made in memory only, so the report can be reviewed before
+// Save. Running Modernize again reports nothing.
+func (c *Config) Modernize() []Rewrite {
+	var out []Rewrite
+	rewrite := func(path, from, to string) { out = append(out, Rewrite{Path: path, From: from, To: to}) }
+
+	for i := range c.RemoteServers {
+		if rs := &c.RemoteServers[i]; rs.keyword != "" && rs.keyword != "remote-servers" {
+			rewrite("remoteServer["+rs.Name+"]", rs.keyword, "remote-servers")
+		}
+	}
+	for path, z := range c.zonesByPath() {
+		if z.typeWord != "" && legacyZoneTypes[z.typeWord] == z.Type {
+			rewrite(path+".type", "type "+z.typeWord, "type "+string(z.Type))
+		}
+		if z.primariesKW == "masters" {
+			rewrite(path+".primaries", "masters", "primaries")
+		}
+	}
+	c.modernizeKeywords()
+
+	if o := c.Options; o != nil {
+		if removed, _ := o.Delete("dnssec-enable"); removed {
+			rewrite("options", "dnssec-enable", "")
+		}
+	}
+	for _, name := range c.astFiles() {
+		f := c.fileAt(name)
+		var anchors []TrustAnchorItem
+		f.Nodes = without(f.Nodes, func(st *nc.Stmt) bool {
+			items, ok := legacyAnchors(st)
+			if ok {
+				rewrite("trustAnchors", st.Keyword, "trust-anchors")
+				anchors = append(anchors, items...)
+			}
+			return ok
+		})
+		if len(anchors) > 0 {
+			c.addTrustAnchors(name, anchors)
+		}
+	}
+	for i := range c.Views {
+		v := &c.Views[i]
+		if v.stmt == nil {
+			continue
+		}
+		path := "view[" + v.Name + "]"
+		var anchors []TrustAnchorItem
+		body := without(v.stmt.Body, func(st *nc.Stmt) bool {
+			if st.Keyword == "dnssec-enable" {
+				rewrite(path, st.Keyword, "")
+				return true
+			}
+			items, ok := legacyAnchors(st)
+			if ok {
+				rewrite(path+".trustAnchors", st.Keyword, "trust-anchors")
+				anchors = append(anchors, items...)
+			}
+			return ok
+		})
+		if len(body) == len(v.stmt.Body) {
+			continue
+		}
+		v.stmt.Body = body
+		v.stmt.MarkModified()
+		if len(anchors) > 0 {
+			if v.TrustAnchors == nil {
+				v.TrustAnchors = &TrustAnchors{}
+			}
+			v.TrustAnchors.Items = append(v.TrustAnchors.Items, anchors...)
+		}
+	}
+	if len(out) > 0 {
+		c.audit("Modernize", fmt.Sprintf("%d rewrites", len(out)), nil)
+	}
+	return out
+}
+
+// legacyAnchors converts a trusted-keys or managed-keys statement to trust
+// anchor items. It reports false for other statements; malformed entries are
+// dropped, as the loader does for trust-anchors.
+func legacyAnchors(st *nc.Stmt) ([]TrustAnchorItem, bool) {
+	if st.Keyword != "trusted-keys" && st.Keyword != "managed-keys" || !st.HasBlock {
+		return nil, false
+	}
+	var items []TrustAnchorItem
+	for _, n := range st.Body {
+		ss, ok := n.(*nc.Stmt)
+		if !ok {
+			continue
+		}
+		raw := strings.TrimSpace(strings.TrimSuffix(headText(ss), ";"))
+		if st.Keyword == "trusted-keys" {
+			// "name" flags protocol algorithm "key"
+			if name, rest, ok := strings.Cut(raw, " "); ok {
+				raw = name + " " + string(AnchorStaticKey) + " " + rest
+			}
+		}
+		if it, ok := parseTrustAnchorItem(raw); ok {
+			items = append(items, it)
+		}
+	}
+	return items, true
+}
+
+// addTrustAnchors appends items to the first top-level trust-anchors block
+// placed in file, creating one there if there is none.
+func (c *Config) addTrustAnchors(file string, items []TrustAnchorItem) {
+	for i := range c.TrustAnchors {
+		if ta := &c.TrustAnchors[i]; c.files == nil || c.placed(ta.origin) == file {
+			ta.Items = append(ta.Items, items...)
+			return
+		}
+	}
+	ta := TrustAnchors{Items: items}
+	if c.files != nil {
+		ta.origin = file
+	}
+	c.TrustAnchors = append(c.TrustAnchors, ta)
+}
+
+// astFiles returns the names of the parsed files of c: every file of a
+// LoadTree config, or "" for the single AST.
+func (c *Config) astFiles() []string {
+	if c.files != nil {
+		return c.Files()
+	}
+	if c.ast != nil {
+		return []string{""}
+	}
+	return nil
+}
+
+func (c *Config) fileAt(name string) *nc.File {
+	if c.files != nil {
+		return c.files[name]
+	}
+	return c.ast
+}
@@ -0,0 +1,594 @@
+// File: pkg/namedzone/namedzonehttp/namedzonehttp.go
+
+// Package namedzonehttp serves a JSON CRUD API for a config held by a
+// namedzone.Manager, the thin HTTP layer every service embedding the
+// package would otherwise write:
+//
+//	GET                /config                      the whole config
+//	GET                /render                      named.conf text
+//	GET|PUT            /options
+//	GET                /zones                       top-level zones
+//	GET|PUT|DELETE     /zones/{zone}
+//	GET                /views
+//	GET|PUT|DELETE     /views/{view}
+//	GET                /views/{view}/zones
+//	GET|PUT|DELETE     /views/{view}/zones/{zone}
+//	GET                /acls, /keys
+//	GET|PUT|DELETE     /acls/{name}, /keys/{name}
+//	GET                /validate                    Config.Validate issues
+//	POST               /diff                        namedzone.Diff to the posted config
+//	POST               /save                        Manager.Save
+//
+// PUT takes the item in its JSON projection; the name in the path wins
+// over an empty name in the body, and a different one is rejected. A change
+// that adds validation errors is rejected with 422 and the new issues.
+// DELETE of an ACL or key still in use fails with 409 and its references
+// unless ?cascade=true. Errors are {"error": "..."} with a status derived
+// from the namedzone error class.
+//
+// Key secrets are left out of responses unless Options.RevealSecrets is
+// set; a key PUT without a secret keeps the one the key has, so a key read,
+// edited and written back keeps working.
+package namedzonehttp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/dlukt/namedzone"
+)
+
+// Options control NewHandler.
+type Options struct {
+	// AutoSave saves the config after every successful change.
+	AutoSave bool
+	// ReadOnly rejects every change with 405.
+	ReadOnly bool
+	// RevealSecrets includes TSIG secrets in key responses; by default
+	// they are left out.
+	RevealSecrets bool
+	// MaxBodyBytes limits request bodies; 0 means 4 MiB.
+	MaxBodyBytes int64
+}
+
+type handler struct {
+	m    *namedzone.Manager
+	opts Options
+	mux  *http.ServeMux
+}
+
+// NewHandler returns the API for m. Mount it under a prefix with
+// http.StripPrefix.
+func NewHandler(m *namedzone.Manager, opts Options) http.Handler {
+	h := &handler{m: m, opts: opts, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /config", h.config)
+	h.mux.HandleFunc("GET /render", h.render)
+	h.mux.HandleFunc("GET /options", h.getOptions)
+	h.mux.HandleFunc("PUT /options", h.putOptions)
+
+	h.mux.HandleFunc("GET /zones", h.listZones)
+	h.mux.HandleFunc("GET /zones/{zone}", h.getZone)
+	h.mux.HandleFunc("PUT /zones/{zone}", h.putZone)
+	h.mux.HandleFunc("DELETE /zones/{zone}", h.deleteZone)
+	h.mux.HandleFunc("GET /views", h.listViews)
+	h.mux.HandleFunc("GET /views/{view}", h.getView)
+	h.mux.HandleFunc("PUT /views/{view}", h.putView)
+	h.mux.HandleFunc("DELETE /views/{view}", h.deleteView)
+	h.mux.HandleFunc("GET /views/{view}/zones", h.listZones)
+	h.mux.HandleFunc("GET /views/{view}/zones/{zone}", h.getZone)
+	h.mux.HandleFunc("PUT /views/{view}/zones/{zone}", h.putZone)
+	h.mux.HandleFunc("DELETE /views/{view}/zones/{zone}", h.deleteZone)
+
+	h.mux.HandleFunc("GET /acls", h.listACLs)
+	h.mux.HandleFunc("GET /acls/{name}", h.getACL)
+	h.mux.HandleFunc("PUT /acls/{name}", h.putACL)
+	h.mux.HandleFunc("DELETE /acls/{name}", h.deleteACL)
+	h.mux.HandleFunc("GET /keys", h.listKeys)
+	h.mux.HandleFunc("GET /keys/{name}", h.getKey)
+	h.mux.HandleFunc("PUT /keys/{name}", h.putKey)
+	h.mux.HandleFunc("DELETE /keys/{name}", h.deleteKey)
+
+	h.mux.HandleFunc("GET /validate", h.validate)
+	h.mux.HandleFunc("POST /diff", h.diff)
+	h.mux.HandleFunc("POST /save", h.save)
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.opts.ReadOnly && r.Method != http.MethodGet && r.Method != http.MethodHead && r.URL.Path != "/diff" {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("namedzone: read-only API"))
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// ---- responses ----
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// fail writes err with the status of its class.
+func fail(w http.ResponseWriter, err error) {
+	var inUse *namedzone.InUseError
+	var invalid *invalidChange
+	switch {
+	case errors.As(err, &invalid):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": err.Error(), "issues": invalid.issues})
+	case errors.As(err, &inUse):
+		writeJSON(w, http.StatusConflict, map[string]any{"error": err.Error(), "references": inUse.Refs})
+	case errors.Is(err, errNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, errBadRequest):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, namedzone.ErrConflict), errors.Is(err, namedzone.ErrLocked):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, namedzone.ErrInvalidValue), errors.Is(err, namedzone.ErrReference),
+		errors.Is(err, namedzone.ErrParse), errors.Is(err, namedzone.ErrCheckFailed):
+		writeError(w, http.StatusUnprocessableEntity, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+var (
+	errNotFound   = errors.New("namedzone: not found")
+	errBadRequest = errors.New("namedzone: bad request")
+)
+
+// requestError is a client error of class errNotFound or errBadRequest.
+type requestError struct {
+	class error
+	msg   string
+}
+
+func (e *requestError) Error() string        { return "namedzone: " + e.msg }
+func (e *requestError) Is(target error) bool { return target == e.class }
+
+func notFound(kind, name string) error {
+	return &requestError{errNotFound, kind + " " + `"` + name + `"` + " not found"}
+}
+
+func badRequest(msg string) error { return &requestError{errBadRequest, msg} }
+
+// invalidChange is a change rejected because it adds validation errors.
+type invalidChange struct {
+	issues []namedzone.Issue
+}
+
+func (e *invalidChange) Error() string {
+	return "namedzone: change rejected: " + e.issues[0].String()
+}
+
+// decode reads the JSON body of r into v.
+func (h *handler) decode(w http.ResponseWriter, r *http.Request, v any) error {
+	limit := h.opts.MaxBodyBytes
+	if limit <= 0 {
+		limit = 4 << 20
+	}
+	b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		return badRequest(err.Error())
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return badRequest("invalid JSON: " + err.Error())
+	}
+	return nil
+}
+
+// ---- changes ----
+
+// change applies fn to the config, first on a copy to check that it adds
+// no validation errors, then saves when AutoSave is on.
+func (h *handler) change(fn func(c *namedzone.Config) error) error {
+	err := h.m.Update(func(c *namedzone.Config) error {
+		before := errorIssues(c)
+		trial := c.Clone()
+		if err := fn(trial); err != nil {
+			return err
+		}
+		var added []namedzone.Issue
+		for _, is := range errorIssues(trial) {
+			if !slices.ContainsFunc(before, func(o namedzone.Issue) bool { return o.Path == is.Path && o.Message == is.Message }) {
+				added = append(added, is)
+			}
+		}
+		if len(added) > 0 {
+			return &invalidChange{added}
+		}
+		return fn(c)
+	})
+	if err != nil || !h.opts.AutoSave {
+		return err
+	}
+	return h.m.Save()
+}
+
+func errorIssues(c *namedzone.Config) []namedzone.Issue {
+	var out []namedzone.Issue
+	for _, is := range c.Validate() {
+		if is.Severity == namedzone.SeverityError {
+			out = append(out, is)
+		}
+	}
+	return out
+}
+
+// named checks the name of a PUT body against the path.
+func named(body *string, path string) error {
+	switch *body {
+	case "":
+		*body = path
+	case path:
+	default:
+		return badRequest("name " + `"` + *body + `"` + " does not match the path")
+	}
+	return nil
+}
+
+// ---- handlers ----
+
+func (h *handler) read(w http.ResponseWriter, fn func(c *namedzone.Config) (any, error)) {
+	var out any
+	err := h.m.Read(func(c *namedzone.Config) error {
+		v, err := fn(c)
+		if err != nil {
+			return err
+		}
+		// encode under the lock: v may point into the live config
+		b, err := json.Marshal(v)
+		out = json.RawMessage(b)
+		return err
+	})
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (h *handler) config(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		return h.redacted(c), nil
+	})
+}
+
+// redacted returns c, or a copy without key secrets unless RevealSecrets
+// is set.
+func (h *handler) redacted(c *namedzone.Config) *namedzone.Config {
+	if h.opts.RevealSecrets {
+		return c
+	}
+	cp := c.Clone()
+	for i := range cp.Keys {
+		cp.Keys[i].Secret = ""
+	}
+	return cp
+}
+
+func (h *handler) render(w http.ResponseWriter, r *http.Request) {
+	text, err := h.m.Render()
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, text)
+}
+
+func (h *handler) getOptions(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		if c.Options == nil {
+			return &namedzone.Options{}, nil
+		}
+		return c.Options, nil
+	})
+}
+
+func (h *handler) putOptions(w http.ResponseWriter, r *http.Request) {
+	var o namedzone.Options
+	if err := h.decode(w, r, &o); err != nil {
+		fail(w, err)
+		return
+	}
+	h.respond(w, h.change(func(c *namedzone.Config) error {
+		cp := o
+		c.Options = &cp
+		return nil
+	}), o)
+}
+
+// respond writes v after a successful change, or the error.
+func (h *handler) respond(w http.ResponseWriter, err error, v any) {
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	if v == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// zones returns the zone list of the view in the path, or the top-level
+// zones.
+func zones(c *namedzone.Config, r *http.Request) ([]namedzone.Zone, error) {
+	view := r.PathValue("view")
+	if view == "" {
+		return c.Zones, nil
+	}
+	v := c.FindView(view)
+	if v == nil {
+		return nil, notFound("view", view)
+	}
+	return v.Zones, nil
+}
+
+func (h *handler) listZones(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		zs, err := zones(c, r)
+		return nonNil(zs), err
+	})
+}
+
+func (h *handler) getZone(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		if _, err := zones(c, r); err != nil {
+			return nil, err
+		}
+		z := c.GetZoneInView(r.PathValue("view"), r.PathValue("zone"))
+		if z == nil {
+			return nil, notFound("zone", r.PathValue("zone"))
+		}
+		return z, nil
+	})
+}
+
+func (h *handler) putZone(w http.ResponseWriter, r *http.Request) {
+	var z namedzone.Zone
+	if err := h.decode(w, r, &z); err != nil {
+		fail(w, err)
+		return
+	}
+	if err := named(&z.Name, r.PathValue("zone")); err != nil {
+		fail(w, err)
+		return
+	}
+	view := r.PathValue("view")
+	h.respond(w, h.change(func(c *namedzone.Config) error {
+		if view == "" {
+			c.UpsertZone(z)
+			return nil
+		}
+		if c.FindView(view) == nil {
+			return notFound("view", view)
+		}
+		c.UpsertZoneInView(view, z)
+		return nil
+	}), z)
+}
+
+func (h *handler) deleteZone(w http.ResponseWriter, r *http.Request) {
+	view, name := r.PathValue("view"), r.PathValue("zone")
+	h.respond(w, h.change(func(c *namedzone.Config) error {
+		removed := false
+		if view == "" {
+			removed = c.RemoveZone(name)
+		} else {
+			removed = c.RemoveZoneInView(view, name)
+		}
+		if !removed {
+			return notFound("zone", name)
+		}
+		return nil
+	}), nil)
+}
+
+func (h *handler) listViews(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) { return nonNil(c.Views), nil })
+}
+
+func (h *handler) getView(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		v := c.FindView(r.PathValue("view"))
+		if v == nil {
+			return nil, notFound("view", r.PathValue("view"))
+		}
+		return v, nil
+	})
+}
+
+// putView keeps the zones of an existing view when the body has none; they
+// are managed under /views/{view}/zones.
+func (h *handler) putView(w http.ResponseWriter, r *http.Request) {
+	var v namedzone.View
+	if err := h.decode(w, r, &v); err != nil {
+		fail(w, err)
+		return
+	}
+	if err := named(&v.Name, r.PathValue("view")); err != nil {
+		fail(w, err)
+		return
+	}
+	h.respond(w, h.change(func(c *namedzone.Config) error {
+		nv := v
+		if old := c.FindView(nv.Name); old != nil && nv.Zones == nil {
+			nv.Zones = old.Zones
+		}
+		c.UpsertView(nv)
+		return nil
+	}), v)
+}
+
+func (h *handler) deleteView(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("view")
+	h.respond(w, h.change(func(c *namedzone.Config) error {
+		if !c.RemoveView(name) {
+			return notFound("view", name)
+		}
+		return nil
+	}), nil)
+}
+
+func (h *handler) listACLs(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) { return nonNil(c.ACLs), nil })
+}
+
+func (h *handler) getACL(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		i := slices.IndexFunc(c.ACLs, func(a namedzone.ACL) bool { return a.Name == r.PathValue("name") })
+		if i < 0 {
+			return nil, notFound("acl", r.PathValue("name"))
+		}
+		return c.ACLs[i], nil
+	})
+}
+
+func (h *handler) putACL(w http.ResponseWriter, r *http.Request) {
+	var a namedzone.ACL
+	if err := h.decode(w, r, &a); err != nil {
+		fail(w, err)
+		return
+	}
+	if err := named(&a.Name, r.PathValue("name")); err != nil {
+		fail(w, err)
+		return
+	}
+	h.respond(w, h.change(func(c *namedzone.Config) error {
+		c.ACLs = upsert(c.ACLs, a, func(x namedzone.ACL) string { return x.Name })
+		return nil
+	}), a)
+}
+
+func (h *handler) deleteACL(w http.ResponseWriter, r *http.Request) {
+	policy := removePolicy(r)
+	h.respond(w, h.change(func(c *namedzone.Config) error {
+		_, err := c.RemoveACL(r.PathValue("name"), policy)
+		return removeError("acl", r.PathValue("name"), err)
+	}), nil)
+}
+
+func (h *handler) keyOut(k namedzone.Key) namedzone.Key {
+	if !h.opts.RevealSecrets {
+		k.Secret = ""
+	}
+	return k
+}
+
+func (h *handler) listKeys(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		out := []namedzone.Key{}
+		for _, k := range c.Keys {
+			out = append(out, h.keyOut(k))
+		}
+		return out, nil
+	})
+}
+
+func (h *handler) getKey(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		i := slices.IndexFunc(c.Keys, func(k namedzone.Key) bool { return k.Name == r.PathValue("name") })
+		if i < 0 {
+			return nil, notFound("key", r.PathValue("name"))
+		}
+		return h.keyOut(c.Keys[i]), nil
+	})
+}
+
+func (h *handler) putKey(w http.ResponseWriter, r *http.Request) {
+	var k namedzone.Key
+	if err := h.decode(w, r, &k); err != nil {
+		fail(w, err)
+		return
+	}
+	if err := named(&k.Name, r.PathValue("name")); err != nil {
+		fail(w, err)
+		return
+	}
+	stored := k
+	err := h.change(func(c *namedzone.Config) error {
+		nk := k
+		if nk.Secret == "" {
+			i := slices.IndexFunc(c.Keys, func(x namedzone.Key) bool { return x.Name == nk.Name })
+			if i < 0 {
+				return badRequest("key " + `"` + nk.Name + `"` + " needs a secret")
+			}
+			nk.Secret = c.Keys[i].Secret
+		}
+		c.Keys = upsert(c.Keys, nk, func(x namedzone.Key) string { return x.Name })
+		stored = nk
+		return nil
+	})
+	h.respond(w, err, h.keyOut(stored))
+}
+
+func (h *handler) deleteKey(w http.ResponseWriter, r *http.Request) {
+	policy := removePolicy(r)
+	h.respond(w, h.change(func(c *namedzone.Config) error {
+		_, err := c.RemoveKey(r.PathValue("name"), policy)
+		return removeError("key", r.PathValue("name"), err)
+	}), nil)
+}
+
+func removePolicy(r *http.Request) namedzone.RemovePolicy {
+	if r.URL.Query().Get("cascade") == "true" {
+		return namedzone.RemoveCascade
+	}
+	return namedzone.RemoveRefuse
+}
+
+// removeError turns the *ReferenceError of a missing block into a 404.
+func removeError(kind, name string, err error) error {
+	var ref *namedzone.ReferenceError
+	if errors.As(err, &ref) && ref.Kind == kind && ref.Name == name {
+		return notFound(kind, name)
+	}
+	return err
+}
+
+func (h *handler) validate(w http.ResponseWriter, r *http.Request) {
+	h.read(w, func(c *namedzone.Config) (any, error) { return nonNil(c.Validate()), nil })
+}
+
+func (h *handler) diff(w http.ResponseWriter, r *http.Request) {
+	var other namedzone.Config
+	if err := h.decode(w, r, &other); err != nil {
+		fail(w, err)
+		return
+	}
+	h.read(w, func(c *namedzone.Config) (any, error) {
+		return namedzone.Diff(h.redacted(c), h.redacted(&other)), nil
+	})
+}
+
+func (h *handler) save(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, h.m.Save(), nil)
+}
+
+func upsert[T any](items []T, v T, name func(T) string) []T {
+	if i := slices.IndexFunc(items, func(x T) bool { return name(x) == name(v) }); i >= 0 {
+		items[i] = v
+		return items
+	}
+	return append(items, v)
+}
+
+// nonNil makes empty lists encode as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
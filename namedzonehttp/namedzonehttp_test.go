@@ -0,0 +1,54 @@
+// File: pkg/namedzone/namedzonehttp/namedzonehttp_test.go
+package namedzonehttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dlukt/namedzone"
+)
+
+// TestPutKeyKeepsSecret writes a key back as GET returned it, secret
+// redacted, and checks the secret survives.
+func TestPutKeyKeepsSecret(t *testing.T) {
+	cfg, err := namedzone.FromReader(strings.NewReader(`key k { algorithm hmac-sha256; secret "c2VjcmV0"; };`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := namedzone.NewManager(cfg, "")
+	srv := httptest.NewServer(NewHandler(m, Options{}))
+	defer srv.Close()
+	do := func(method, path string, body []byte) (int, []byte) {
+		req, _ := http.NewRequest(method, srv.URL+path, bytes.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, b
+	}
+
+	status, key := do("GET", "/keys/k", nil)
+	if status != http.StatusOK || strings.Contains(string(key), "c2VjcmV0") {
+		t.Fatalf("GET: %d %s", status, key)
+	}
+	edited := bytes.Replace(key, []byte("hmac-sha256"), []byte("hmac-sha512"), 1)
+	if status, b := do("PUT", "/keys/k", edited); status != http.StatusOK {
+		t.Fatalf("PUT: %d %s", status, b)
+	}
+	m.Read(func(c *namedzone.Config) error {
+		if k := c.Keys[0]; k.Secret != "c2VjcmV0" || k.Algorithm != "hmac-sha512" {
+			t.Errorf("key after PUT: %+v", k)
+		}
+		return nil
+	})
+
+	if status, b := do("PUT", "/keys/new", []byte(`{"algorithm":"hmac-sha256"}`)); status != http.StatusBadRequest {
+		t.Errorf("PUT of a new key without secret: %d %s", status, b)
+	}
+}
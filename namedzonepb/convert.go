@@ -0,0 +1,532 @@
+// File: pkg/namedzone/namedzonepb/convert.go
+
+// Package namedzonepb carries the namedzone typed model over protobuf.
+// namedzone.proto mirrors namedzone.Config; ToProto and FromProto convert
+// between the two. The AST is not transferred: a Config rebuilt with
+// FromProto renders from scratch.
+package namedzonepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative namedzone.proto
+
+import (
+	"time"
+
+	nz "github.com/dlukt/namedzone"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ToProto converts c to its protobuf message.
+func ToProto(c *nz.Config) *Config {
+	if c == nil {
+		return nil
+	}
+	return &Config{
+		Includes:  each(c.Includes, includeTo),
+		Acls:      each(c.ACLs, func(a nz.ACL) *ACL { return &ACL{Name: a.Name, Elements: each(a.Elements, matchTo)} }),
+		Keys:      each(c.Keys, func(k nz.Key) *Key { return &Key{Name: k.Name, Algorithm: k.Algorithm, Secret: k.Secret} }),
+		KeyStores: each(c.KeyStores, func(k nz.KeyStore) *KeyStore { return &KeyStore{Name: k.Name, Pkcs11Uri: k.PKCS11URI} }),
+		RemoteServers: each(c.RemoteServers, func(r nz.RemoteServers) *RemoteServers {
+			return &RemoteServers{Name: r.Name, Servers: each(r.Servers, serverTo)}
+		}),
+		Tls:               each(c.TLS, tlsTo),
+		Http:              each(c.HTTP, httpTo),
+		Controls:          ptrTo(c.Controls, controlsTo),
+		Logging:           ptrTo(c.Logging, loggingTo),
+		Options:           ptrTo(c.Options, optionsTo),
+		TrustAnchors:      each(c.TrustAnchors, anchorsTo),
+		Views:             each(c.Views, viewTo),
+		Zones:             each(c.Zones, zoneTo),
+		ModernizeKeywords: c.ModernizeKeywords,
+	}
+}
+
+// FromProto converts a protobuf message back to a Config.
+func FromProto(p *Config) *nz.Config {
+	if p == nil {
+		return nil
+	}
+	return &nz.Config{
+		Includes:  each(p.Includes, includeFrom),
+		ACLs:      each(p.Acls, func(a *ACL) nz.ACL { return nz.ACL{Name: a.Name, Elements: each(a.Elements, matchFrom)} }),
+		Keys:      each(p.Keys, func(k *Key) nz.Key { return nz.Key{Name: k.Name, Algorithm: k.Algorithm, Secret: k.Secret} }),
+		KeyStores: each(p.KeyStores, func(k *KeyStore) nz.KeyStore { return nz.KeyStore{Name: k.Name, PKCS11URI: k.Pkcs11Uri} }),
+		RemoteServers: each(p.RemoteServers, func(r *RemoteServers) nz.RemoteServers {
+			return nz.RemoteServers{Name: r.Name, Servers: each(r.Servers, serverFrom)}
+		}),
+		TLS:               each(p.Tls, tlsFrom),
+		HTTP:              each(p.Http, httpFrom),
+		Controls:          ptrFrom(p.Controls, controlsFrom),
+		Logging:           ptrFrom(p.Logging, loggingFrom),
+		Options:           ptrFrom(p.Options, optionsFrom),
+		TrustAnchors:      each(p.TrustAnchors, anchorsFrom),
+		Views:             each(p.Views, viewFrom),
+		Zones:             each(p.Zones, zoneFrom),
+		ModernizeKeywords: p.ModernizeKeywords,
+	}
+}
+
+// ---- generic helpers ----
+
+func each[S, T any](in []S, f func(S) T) []T {
+	if in == nil {
+		return nil
+	}
+	out := make([]T, len(in))
+	for i, v := range in {
+		out[i] = f(v)
+	}
+	return out
+}
+
+func ptrTo[S, T any](v *S, f func(S) *T) *T {
+	if v == nil {
+		return nil
+	}
+	return f(*v)
+}
+
+func ptrFrom[S, T any](v *S, f func(*S) T) *T {
+	if v == nil {
+		return nil
+	}
+	out := f(v)
+	return &out
+}
+
+func int32p(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
+
+func intp(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
+
+func boolp(v *bool) *bool {
+	if v == nil {
+		return nil
+	}
+	b := *v
+	return &b
+}
+
+func sizeTo(s *nz.Size) *Size {
+	if s == nil {
+		return nil
+	}
+	return &Size{Bytes: s.Bytes, Percent: int32(s.Percent), Keyword: s.Keyword}
+}
+
+func sizeFrom(s *Size) *nz.Size {
+	if s == nil {
+		return nil
+	}
+	return &nz.Size{Bytes: s.Bytes, Percent: int(s.Percent), Keyword: s.Keyword}
+}
+
+func durationTo(d *nz.Duration) *durationpb.Duration {
+	if d == nil {
+		return nil
+	}
+	return durationpb.New(time.Duration(*d))
+}
+
+func durationFrom(d *durationpb.Duration) *nz.Duration {
+	if d == nil {
+		return nil
+	}
+	v := nz.Duration(d.AsDuration())
+	return &v
+}
+
+func identTo(id *nz.ServerIdent) *ServerIdent {
+	if id == nil {
+		return nil
+	}
+	return &ServerIdent{Keyword: id.Keyword, Value: id.Value}
+}
+
+func identFrom(id *ServerIdent) *nz.ServerIdent {
+	if id == nil {
+		return nil
+	}
+	return &nz.ServerIdent{Keyword: id.Keyword, Value: id.Value}
+}
+
+// ---- per-type conversions ----
+
+func includeTo(i nz.Include) *Include   { return &Include{Path: i.Path} }
+func includeFrom(i *Include) nz.Include { return nz.Include{Path: i.Path} }
+
+func matchTo(m nz.MatchTerm) *MatchTerm {
+	return &MatchTerm{Not: m.Not, Address: m.Address, Key: m.Key, AclRef: m.ACLRef, Nested: each(m.Nested, matchTo)}
+}
+
+func matchFrom(m *MatchTerm) nz.MatchTerm {
+	return nz.MatchTerm{Not: m.Not, Address: m.Address, Key: m.Key, ACLRef: m.AclRef, Nested: each(m.Nested, matchFrom)}
+}
+
+func serverTo(s nz.RemoteServerItem) *RemoteServerItem {
+	return &RemoteServerItem{Address: s.Address, Port: int32p(s.Port), Key: s.Key, Tls: s.TLS}
+}
+
+func serverFrom(s *RemoteServerItem) nz.RemoteServerItem {
+	return nz.RemoteServerItem{Address: s.Address, Port: intp(s.Port), Key: s.Key, TLS: s.Tls}
+}
+
+func forwarderTo(f nz.Forwarder) *Forwarder {
+	return &Forwarder{Address: f.Address, Port: int32p(f.Port), Tls: f.TLS}
+}
+
+func forwarderFrom(f *Forwarder) nz.Forwarder {
+	return nz.Forwarder{Address: f.Address, Port: intp(f.Port), TLS: f.Tls}
+}
+
+func tlsTo(t nz.TLS) *TLS {
+	return &TLS{
+		Name: t.Name, CaFile: t.CAFile, CertFile: t.CertFile, KeyFile: t.KeyFile,
+		CipherSuites: t.CipherSuites, Ciphers: t.Ciphers, DhparamFile: t.DHParamFile,
+		PreferServerCiphers: boolp(t.PreferServer), Protocols: t.Protocols,
+		RemoteHostname: t.RemoteHost, SessionTickets: boolp(t.SessionTickets),
+	}
+}
+
+func tlsFrom(t *TLS) nz.TLS {
+	return nz.TLS{
+		Name: t.Name, CAFile: t.CaFile, CertFile: t.CertFile, KeyFile: t.KeyFile,
+		CipherSuites: t.CipherSuites, Ciphers: t.Ciphers, DHParamFile: t.DhparamFile,
+		PreferServer: boolp(t.PreferServerCiphers), Protocols: t.Protocols,
+		RemoteHost: t.RemoteHostname, SessionTickets: boolp(t.SessionTickets),
+	}
+}
+
+func httpTo(h nz.HTTP) *HTTP {
+	return &HTTP{Name: h.Name, Endpoints: h.Endpoints, ListenerClients: int32p(h.ListenerClients), StreamsPerConnection: int32p(h.StreamsPerConnection)}
+}
+
+func httpFrom(h *HTTP) nz.HTTP {
+	return nz.HTTP{Name: h.Name, Endpoints: h.Endpoints, ListenerClients: intp(h.ListenerClients), StreamsPerConnection: intp(h.StreamsPerConnection)}
+}
+
+func controlsTo(c nz.Controls) *Controls {
+	return &Controls{
+		Inet: each(c.Inet, func(ci nz.ControlInet) *ControlInet {
+			return &ControlInet{Address: ci.Address, Port: int32p(ci.Port), Allow: each(ci.Allow, matchTo), Keys: ci.Keys, ReadOnly: boolp(ci.ReadOnly)}
+		}),
+		Unix: each(c.Unix, func(cu nz.ControlUnix) *ControlUnix {
+			return &ControlUnix{Path: cu.Path, Perm: int32(cu.Perm), Owner: int32(cu.Owner), Group: int32(cu.Group), Keys: cu.Keys, ReadOnly: boolp(cu.ReadOnly)}
+		}),
+		Disabled: c.Disabled,
+	}
+}
+
+func controlsFrom(c *Controls) nz.Controls {
+	return nz.Controls{
+		Inet: each(c.Inet, func(ci *ControlInet) nz.ControlInet {
+			return nz.ControlInet{Address: ci.Address, Port: intp(ci.Port), Allow: each(ci.Allow, matchFrom), Keys: ci.Keys, ReadOnly: boolp(ci.ReadOnly)}
+		}),
+		Unix: each(c.Unix, func(cu *ControlUnix) nz.ControlUnix {
+			return nz.ControlUnix{Path: cu.Path, Perm: int(cu.Perm), Owner: int(cu.Owner), Group: int(cu.Group), Keys: cu.Keys, ReadOnly: boolp(cu.ReadOnly)}
+		}),
+		Disabled: c.Disabled,
+	}
+}
+
+func loggingTo(l nz.Logging) *Logging {
+	return &Logging{
+		Channels: each(l.Channels, channelTo),
+		Categories: each(l.Categories, func(c nz.LogCategory) *LogCategory {
+			return &LogCategory{Name: c.Name, Channels: c.Channels}
+		}),
+		AllowUnknownCategories: l.AllowUnknownCategories,
+	}
+}
+
+func loggingFrom(l *Logging) nz.Logging {
+	return nz.Logging{
+		Channels: each(l.Channels, channelFrom),
+		Categories: each(l.Categories, func(c *LogCategory) nz.LogCategory {
+			return nz.LogCategory{Name: c.Name, Channels: c.Channels}
+		}),
+		AllowUnknownCategories: l.AllowUnknownCategories,
+	}
+}
+
+func channelTo(ch nz.LogChannel) *LogChannel {
+	p := &LogChannel{
+		Name: ch.Name, Stderr: ch.Stderr, Null: ch.Null, PrintTime: string(ch.PrintTime),
+		PrintCategory: boolp(ch.PrintCategory), PrintSeverity: boolp(ch.PrintSeverity), Buffered: boolp(ch.Buffered),
+	}
+	if f := ch.File; f != nil {
+		p.File = &LogFileDest{Path: f.Path, Versions: int32p(f.Versions), Size: sizeTo(f.Size), Suffix: f.Suffix, Severity: f.Severity}
+	}
+	if s := ch.Syslog; s != nil {
+		p.Syslog = &LogSyslogDest{Facility: s.Facility}
+	}
+	if s := ch.Severity; s != nil {
+		p.Severity = &LogSeverity{Level: s.Level, DebugLevel: int32p(s.DebugLevel)}
+	}
+	return p
+}
+
+func channelFrom(p *LogChannel) nz.LogChannel {
+	ch := nz.LogChannel{
+		Name: p.Name, Stderr: p.Stderr, Null: p.Null, PrintTime: nz.PrintTime(p.PrintTime),
+		PrintCategory: boolp(p.PrintCategory), PrintSeverity: boolp(p.PrintSeverity), Buffered: boolp(p.Buffered),
+	}
+	if f := p.File; f != nil {
+		ch.File = &nz.LogFileDest{Path: f.Path, Versions: intp(f.Versions), Size: sizeFrom(f.Size), Suffix: f.Suffix, Severity: f.Severity}
+	}
+	if s := p.Syslog; s != nil {
+		ch.Syslog = &nz.LogSyslogDest{Facility: s.Facility}
+	}
+	if s := p.Severity; s != nil {
+		ch.Severity = &nz.LogSeverity{Level: s.Level, DebugLevel: intp(s.DebugLevel)}
+	}
+	return ch
+}
+
+func prefetchTo(pf *nz.Prefetch) *Prefetch {
+	if pf == nil {
+		return nil
+	}
+	return &Prefetch{Trigger: int32(pf.Trigger), Eligibility: int32p(pf.Eligibility)}
+}
+
+func prefetchFrom(pf *Prefetch) *nz.Prefetch {
+	if pf == nil {
+		return nil
+	}
+	return &nz.Prefetch{Trigger: int(pf.Trigger), Eligibility: intp(pf.Eligibility)}
+}
+
+func paddingTo(r *nz.ResponsePadding) *ResponsePadding {
+	if r == nil {
+		return nil
+	}
+	return &ResponsePadding{Clients: each(r.Clients, matchTo), BlockSize: int32(r.BlockSize)}
+}
+
+func paddingFrom(r *ResponsePadding) *nz.ResponsePadding {
+	if r == nil {
+		return nil
+	}
+	return &nz.ResponsePadding{Clients: each(r.Clients, matchFrom), BlockSize: int(r.BlockSize)}
+}
+
+func listenTo(l *nz.Listen) *Listen {
+	if l == nil {
+		return nil
+	}
+	return &Listen{Port: int32p(l.Port), Tls: l.TLS, Http: l.HTTP, Addrs: each(l.Addrs, matchTo)}
+}
+
+func listenFrom(l *Listen) *nz.Listen {
+	if l == nil {
+		return nil
+	}
+	return &nz.Listen{Port: intp(l.Port), TLS: l.Tls, HTTP: l.Http, Addrs: each(l.Addrs, matchFrom)}
+}
+
+func optionsTo(o nz.Options) *Options {
+	return &Options{
+		Directory:        o.Directory,
+		Recursion:        boolp(o.Recursion),
+		AllowQuery:       each(o.AllowQuery, matchTo),
+		AllowTransfer:    each(o.AllowTransfer, matchTo),
+		AllowUpdate:      each(o.AllowUpdate, matchTo),
+		ListenOn:         listenTo(o.ListenOn),
+		ListenOnV6:       listenTo(o.ListenOnV6),
+		Forwarders:       each(o.Forwarders, forwarderTo),
+		Forward:          o.Forward,
+		DnssecValidation: o.DNSSECValidation,
+		MasterfileFormat: string(o.MasterfileFormat),
+		MasterfileStyle:  string(o.MasterfileStyle),
+		RrsetOrder: each(o.RRsetOrder, func(r nz.RRsetOrder) *RRsetOrder {
+			return &RRsetOrder{Name: r.Name, Type: r.Type, Order: r.Order}
+		}),
+		Prefetch:             prefetchTo(o.Prefetch),
+		QnameMinimization:    string(o.QNameMinimization),
+		MinimalResponses:     string(o.MinimalResponses),
+		MinimalAny:           boolp(o.MinimalAny),
+		ResponsePadding:      paddingTo(o.ResponsePadding),
+		TkeyGssapiKeytab:     o.TKeyGSSAPIKeytab,
+		TkeyGssapiCredential: o.TKeyGSSAPICredential,
+		TkeyDomain:           o.TKeyDomain,
+		SessionKeyfile:       o.SessionKeyFile,
+		SessionKeyname:       o.SessionKeyName,
+		SessionKeyalg:        o.SessionKeyAlg,
+		MaxCacheSize:         sizeTo(o.MaxCacheSize),
+		MaxCacheTtl:          durationTo(o.MaxCacheTTL),
+		MaxNcacheTtl:         durationTo(o.MaxNCacheTTL),
+		MaxJournalSize:       sizeTo(o.MaxJournalSize),
+		LmdbMapsize:          sizeTo(o.LMDBMapSize),
+		MaxRecords:           int32p(o.MaxRecords),
+		AllowNewZones:        boolp(o.AllowNewZones),
+		Version:              identTo(o.Version),
+		Hostname:             identTo(o.Hostname),
+		ServerId:             identTo(o.ServerID),
+		PidFile:              o.PIDFile,
+		StatisticsFile:       o.StatisticsFile,
+		DumpFile:             o.DumpFile,
+		SecrootsFile:         o.SecrootsFile,
+		RecursingFile:        o.RecursingFile,
+		MemstatisticsFile:    o.MemstatisticsFile,
+		LockFile:             o.LockFile,
+		Other:                each(o.Other, func(kv nz.RawKV) *RawKV { return &RawKV{Name: kv.Name, Raw: kv.Raw} }),
+	}
+}
+
+func optionsFrom(p *Options) nz.Options {
+	return nz.Options{
+		Directory:        p.Directory,
+		Recursion:        boolp(p.Recursion),
+		AllowQuery:       each(p.AllowQuery, matchFrom),
+		AllowTransfer:    each(p.AllowTransfer, matchFrom),
+		AllowUpdate:      each(p.AllowUpdate, matchFrom),
+		ListenOn:         listenFrom(p.ListenOn),
+		ListenOnV6:       listenFrom(p.ListenOnV6),
+		Forwarders:       each(p.Forwarders, forwarderFrom),
+		Forward:          p.Forward,
+		DNSSECValidation: p.DnssecValidation,
+		MasterfileFormat: nz.MasterfileFormat(p.MasterfileFormat),
+		MasterfileStyle:  nz.MasterfileStyle(p.MasterfileStyle),
+		RRsetOrder: each(p.RrsetOrder, func(r *RRsetOrder) nz.RRsetOrder {
+			return nz.RRsetOrder{Name: r.Name, Type: r.Type, Order: r.Order}
+		}),
+		Prefetch:             prefetchFrom(p.Prefetch),
+		QNameMinimization:    nz.QNameMinimization(p.QnameMinimization),
+		MinimalResponses:     nz.MinimalResponses(p.MinimalResponses),
+		MinimalAny:           boolp(p.MinimalAny),
+		ResponsePadding:      paddingFrom(p.ResponsePadding),
+		TKeyGSSAPIKeytab:     p.TkeyGssapiKeytab,
+		TKeyGSSAPICredential: p.TkeyGssapiCredential,
+		TKeyDomain:           p.TkeyDomain,
+		SessionKeyFile:       p.SessionKeyfile,
+		SessionKeyName:       p.SessionKeyname,
+		SessionKeyAlg:        p.SessionKeyalg,
+		MaxCacheSize:         sizeFrom(p.MaxCacheSize),
+		MaxCacheTTL:          durationFrom(p.MaxCacheTtl),
+		MaxNCacheTTL:         durationFrom(p.MaxNcacheTtl),
+		MaxJournalSize:       sizeFrom(p.MaxJournalSize),
+		LMDBMapSize:          sizeFrom(p.LmdbMapsize),
+		MaxRecords:           intp(p.MaxRecords),
+		AllowNewZones:        boolp(p.AllowNewZones),
+		Version:              identFrom(p.Version),
+		Hostname:             identFrom(p.Hostname),
+		ServerID:             identFrom(p.ServerId),
+		PIDFile:              p.PidFile,
+		StatisticsFile:       p.StatisticsFile,
+		DumpFile:             p.DumpFile,
+		SecrootsFile:         p.SecrootsFile,
+		RecursingFile:        p.RecursingFile,
+		MemstatisticsFile:    p.MemstatisticsFile,
+		LockFile:             p.LockFile,
+		Other:                each(p.Other, func(kv *RawKV) nz.RawKV { return nz.RawKV{Name: kv.Name, Raw: kv.Raw} }),
+	}
+}
+
+func anchorsTo(t nz.TrustAnchors) *TrustAnchors {
+	return &TrustAnchors{Items: each(t.Items, func(it nz.TrustAnchorItem) *TrustAnchorItem {
+		return &TrustAnchorItem{
+			Name: it.Name, Kind: string(it.Kind), Flags: int32(it.Flags), Protocol: int32(it.Protocol),
+			KeyTag: int32(it.KeyTag), Algorithm: int32(it.Algorithm), DigestType: int32(it.DigestType), Data: it.Data,
+		}
+	})}
+}
+
+func anchorsFrom(t *TrustAnchors) nz.TrustAnchors {
+	return nz.TrustAnchors{Items: each(t.Items, func(it *TrustAnchorItem) nz.TrustAnchorItem {
+		return nz.TrustAnchorItem{
+			Name: it.Name, Kind: nz.TrustAnchorKind(it.Kind), Flags: int(it.Flags), Protocol: int(it.Protocol),
+			KeyTag: int(it.KeyTag), Algorithm: int(it.Algorithm), DigestType: int(it.DigestType), Data: it.Data,
+		}
+	})}
+}
+
+func viewTo(v nz.View) *View {
+	return &View{
+		Name: v.Name, Class: v.Class,
+		MatchClients:       each(v.MatchClients, matchTo),
+		MatchDestinations:  each(v.MatchDestinations, matchTo),
+		MatchRecursiveOnly: boolp(v.MatchRecursive),
+		Recursion:          boolp(v.Recursion),
+		TrustAnchors:       ptrTo(v.TrustAnchors, anchorsTo),
+		Prefetch:           prefetchTo(v.Prefetch),
+		QnameMinimization:  string(v.QNameMinimization),
+		MinimalResponses:   string(v.MinimalResponses),
+		MinimalAny:         boolp(v.MinimalAny),
+		ResponsePadding:    paddingTo(v.ResponsePadding),
+		LmdbMapsize:        sizeTo(v.LMDBMapSize),
+		MaxRecords:         int32p(v.MaxRecords),
+		AllowNewZones:      boolp(v.AllowNewZones),
+		Zones:              each(v.Zones, zoneTo),
+		Includes:           each(v.Includes, includeTo),
+	}
+}
+
+func viewFrom(p *View) nz.View {
+	return nz.View{
+		Name: p.Name, Class: p.Class,
+		MatchClients:      each(p.MatchClients, matchFrom),
+		MatchDestinations: each(p.MatchDestinations, matchFrom),
+		MatchRecursive:    boolp(p.MatchRecursiveOnly),
+		Recursion:         boolp(p.Recursion),
+		TrustAnchors:      ptrFrom(p.TrustAnchors, anchorsFrom),
+		Prefetch:          prefetchFrom(p.Prefetch),
+		QNameMinimization: nz.QNameMinimization(p.QnameMinimization),
+		MinimalResponses:  nz.MinimalResponses(p.MinimalResponses),
+		MinimalAny:        boolp(p.MinimalAny),
+		ResponsePadding:   paddingFrom(p.ResponsePadding),
+		LMDBMapSize:       sizeFrom(p.LmdbMapsize),
+		MaxRecords:        intp(p.MaxRecords),
+		AllowNewZones:     boolp(p.AllowNewZones),
+		Zones:             each(p.Zones, zoneFrom),
+		Includes:          each(p.Includes, includeFrom),
+	}
+}
+
+func zoneTo(z nz.Zone) *Zone {
+	return &Zone{
+		Name: z.Name, Class: z.Class, Type: string(z.Type), File: z.File,
+		InView:           z.InView,
+		PrimariesRef:     z.PrimariesRef,
+		Primaries:        each(z.Primaries, serverTo),
+		Forwarders:       each(z.Forwarders, forwarderTo),
+		Forward:          z.Forward,
+		AllowUpdate:      each(z.AllowUpdate, matchTo),
+		AllowTransfer:    each(z.AllowTransfer, matchTo),
+		AlsoNotify:       each(z.AlsoNotify, serverTo),
+		Notify:           z.Notify,
+		DnssecPolicy:     z.DNSSECPolicy,
+		MasterfileFormat: string(z.MasterfileFormat),
+		MasterfileStyle:  string(z.MasterfileStyle),
+		MaxRecords:       int32p(z.MaxRecords),
+	}
+}
+
+func zoneFrom(p *Zone) nz.Zone {
+	return nz.Zone{
+		Name: p.Name, Class: p.Class, Type: nz.ZoneType(p.Type), File: p.File,
+		InView:           p.InView,
+		PrimariesRef:     p.PrimariesRef,
+		Primaries:        each(p.Primaries, serverFrom),
+		Forwarders:       each(p.Forwarders, forwarderFrom),
+		Forward:          p.Forward,
+		AllowUpdate:      each(p.AllowUpdate, matchFrom),
+		AllowTransfer:    each(p.AllowTransfer, matchFrom),
+		AlsoNotify:       each(p.AlsoNotify, serverFrom),
+		Notify:           p.Notify,
+		DNSSECPolicy:     p.DnssecPolicy,
+		MasterfileFormat: nz.MasterfileFormat(p.MasterfileFormat),
+		MasterfileStyle:  nz.MasterfileStyle(p.MasterfileStyle),
+		MaxRecords:       intp(p.MaxRecords),
+	}
+}
@@ -0,0 +1,47 @@
+// File: pkg/namedzone/namedzonepb/convert_test.go
+package namedzonepb
+
+import (
+	"strings"
+	"testing"
+
+	nz "github.com/dlukt/namedzone"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestRoundTrip converts loaded configs to protobuf and back, through the
+// wire encoding, and expects the same configuration.
+func TestRoundTrip(t *testing.T) {
+	for name, src := range map[string]string{
+		"resolver tuning": `options { prefetch 2 9; qname-minimization relaxed; minimal-responses no-auth; minimal-any yes; };
+view "v" { match-clients { any; }; prefetch 3; qname-minimization strict; minimal-responses yes; minimal-any no; };`,
+		"response padding": `options { response-padding { 10.0.0.0/8; !192.0.2.1; } block-size 468; };
+view "v" { match-clients { any; }; response-padding { any; } block-size 128; };`,
+		"database limits": `options { lmdb-mapsize 64M; max-records 100000; };
+view "v" { match-clients { any; }; lmdb-mapsize 1G; max-records 5000;
+	zone "example.com" { type primary; file "example.com.db"; max-records 200; }; };`,
+		"allow-new-zones": `options { allow-new-zones yes; };
+view "v" { match-clients { any; }; allow-new-zones no; };`,
+		"notify": `zone "example.com" { type primary; file "example.com.db"; notify explicit; also-notify { 192.0.2.1; }; };`,
+		"in-view": `view "a" { match-clients { 10.0.0.0/8; }; zone "example.com" { type primary; file "example.com.db"; }; };
+view "b" { match-clients { any; }; zone "example.com" { in-view "a"; }; };`,
+		"match-recursive-only": `view "r" { match-clients { 10.0.0.0/8; }; match-recursive-only yes; };
+view "other" { match-clients { any; }; };`,
+	} {
+		c, err := nz.FromReader(strings.NewReader(src))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		b, err := proto.Marshal(ToProto(c))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var p Config
+		if err := proto.Unmarshal(b, &p); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := FromProto(&p); !c.Equal(got) {
+			t.Errorf("%s: round trip differs:\n%s", name, nz.Diff(c, got))
+		}
+	}
+}
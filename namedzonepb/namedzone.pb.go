@@ -0,0 +1,3151 @@
+// Protobuf mirror of the namedzone typed model. Field names follow the JSON
+// projection; optional scalars correspond to pointer fields in Go.
+//
+// Regenerate namedzone.pb.go with: go generate ./namedzonepb
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: namedzone.proto
+
+package namedzonepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Config struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Includes          []*Include             `protobuf:"bytes,1,rep,name=includes,proto3" json:"includes,omitempty"`
+	Acls              []*ACL                 `protobuf:"bytes,2,rep,name=acls,proto3" json:"acls,omitempty"`
+	Keys              []*Key                 `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	KeyStores         []*KeyStore            `protobuf:"bytes,4,rep,name=key_stores,json=keyStores,proto3" json:"key_stores,omitempty"`
+	RemoteServers     []*RemoteServers       `protobuf:"bytes,5,rep,name=remote_servers,json=remoteServers,proto3" json:"remote_servers,omitempty"`
+	Tls               []*TLS                 `protobuf:"bytes,6,rep,name=tls,proto3" json:"tls,omitempty"`
+	Http              []*HTTP                `protobuf:"bytes,7,rep,name=http,proto3" json:"http,omitempty"`
+	Controls          *Controls              `protobuf:"bytes,8,opt,name=controls,proto3" json:"controls,omitempty"`
+	Logging           *Logging               `protobuf:"bytes,9,opt,name=logging,proto3" json:"logging,omitempty"`
+	Options           *Options               `protobuf:"bytes,10,opt,name=options,proto3" json:"options,omitempty"`
+	TrustAnchors      []*TrustAnchors        `protobuf:"bytes,11,rep,name=trust_anchors,json=trustAnchors,proto3" json:"trust_anchors,omitempty"`
+	Views             []*View                `protobuf:"bytes,12,rep,name=views,proto3" json:"views,omitempty"`
+	Zones             []*Zone                `protobuf:"bytes,13,rep,name=zones,proto3" json:"zones,omitempty"`
+	ModernizeKeywords bool                   `protobuf:"varint,14,opt,name=modernize_keywords,json=modernizeKeywords,proto3" json:"modernize_keywords,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_namedzone_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Config) GetIncludes() []*Include {
+	if x != nil {
+		return x.Includes
+	}
+	return nil
+}
+
+func (x *Config) GetAcls() []*ACL {
+	if x != nil {
+		return x.Acls
+	}
+	return nil
+}
+
+func (x *Config) GetKeys() []*Key {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *Config) GetKeyStores() []*KeyStore {
+	if x != nil {
+		return x.KeyStores
+	}
+	return nil
+}
+
+func (x *Config) GetRemoteServers() []*RemoteServers {
+	if x != nil {
+		return x.RemoteServers
+	}
+	return nil
+}
+
+func (x *Config) GetTls() []*TLS {
+	if x != nil {
+		return x.Tls
+	}
+	return nil
+}
+
+func (x *Config) GetHttp() []*HTTP {
+	if x != nil {
+		return x.Http
+	}
+	return nil
+}
+
+func (x *Config) GetControls() *Controls {
+	if x != nil {
+		return x.Controls
+	}
+	return nil
+}
+
+func (x *Config) GetLogging() *Logging {
+	if x != nil {
+		return x.Logging
+	}
+	return nil
+}
+
+func (x *Config) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Config) GetTrustAnchors() []*TrustAnchors {
+	if x != nil {
+		return x.TrustAnchors
+	}
+	return nil
+}
+
+func (x *Config) GetViews() []*View {
+	if x != nil {
+		return x.Views
+	}
+	return nil
+}
+
+func (x *Config) GetZones() []*Zone {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+func (x *Config) GetModernizeKeywords() bool {
+	if x != nil {
+		return x.ModernizeKeywords
+	}
+	return false
+}
+
+type Include struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Include) Reset() {
+	*x = Include{}
+	mi := &file_namedzone_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Include) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Include) ProtoMessage() {}
+
+func (x *Include) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Include.ProtoReflect.Descriptor instead.
+func (*Include) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Include) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ACL struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Elements      []*MatchTerm           `protobuf:"bytes,2,rep,name=elements,proto3" json:"elements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ACL) Reset() {
+	*x = ACL{}
+	mi := &file_namedzone_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ACL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACL) ProtoMessage() {}
+
+func (x *ACL) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACL.ProtoReflect.Descriptor instead.
+func (*ACL) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ACL) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ACL) GetElements() []*MatchTerm {
+	if x != nil {
+		return x.Elements
+	}
+	return nil
+}
+
+type MatchTerm struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Not           bool                   `protobuf:"varint,1,opt,name=not,proto3" json:"not,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Key           string                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	AclRef        string                 `protobuf:"bytes,4,opt,name=acl_ref,json=aclRef,proto3" json:"acl_ref,omitempty"`
+	Nested        []*MatchTerm           `protobuf:"bytes,5,rep,name=nested,proto3" json:"nested,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchTerm) Reset() {
+	*x = MatchTerm{}
+	mi := &file_namedzone_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchTerm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchTerm) ProtoMessage() {}
+
+func (x *MatchTerm) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchTerm.ProtoReflect.Descriptor instead.
+func (*MatchTerm) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MatchTerm) GetNot() bool {
+	if x != nil {
+		return x.Not
+	}
+	return false
+}
+
+func (x *MatchTerm) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *MatchTerm) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MatchTerm) GetAclRef() string {
+	if x != nil {
+		return x.AclRef
+	}
+	return ""
+}
+
+func (x *MatchTerm) GetNested() []*MatchTerm {
+	if x != nil {
+		return x.Nested
+	}
+	return nil
+}
+
+type Key struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Algorithm     string                 `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Secret        string                 `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Key) Reset() {
+	*x = Key{}
+	mi := &file_namedzone_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Key) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Key) ProtoMessage() {}
+
+func (x *Key) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Key.ProtoReflect.Descriptor instead.
+func (*Key) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Key) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Key) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *Key) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type KeyStore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Pkcs11Uri     string                 `protobuf:"bytes,2,opt,name=pkcs11_uri,json=pkcs11Uri,proto3" json:"pkcs11_uri,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyStore) Reset() {
+	*x = KeyStore{}
+	mi := &file_namedzone_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyStore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyStore) ProtoMessage() {}
+
+func (x *KeyStore) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyStore.ProtoReflect.Descriptor instead.
+func (*KeyStore) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *KeyStore) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *KeyStore) GetPkcs11Uri() string {
+	if x != nil {
+		return x.Pkcs11Uri
+	}
+	return ""
+}
+
+type RemoteServers struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Servers       []*RemoteServerItem    `protobuf:"bytes,2,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoteServers) Reset() {
+	*x = RemoteServers{}
+	mi := &file_namedzone_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoteServers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteServers) ProtoMessage() {}
+
+func (x *RemoteServers) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteServers.ProtoReflect.Descriptor instead.
+func (*RemoteServers) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RemoteServers) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RemoteServers) GetServers() []*RemoteServerItem {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+type RemoteServerItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Port          *int32                 `protobuf:"varint,2,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	Key           string                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Tls           string                 `protobuf:"bytes,4,opt,name=tls,proto3" json:"tls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoteServerItem) Reset() {
+	*x = RemoteServerItem{}
+	mi := &file_namedzone_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoteServerItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteServerItem) ProtoMessage() {}
+
+func (x *RemoteServerItem) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteServerItem.ProtoReflect.Descriptor instead.
+func (*RemoteServerItem) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RemoteServerItem) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *RemoteServerItem) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *RemoteServerItem) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RemoteServerItem) GetTls() string {
+	if x != nil {
+		return x.Tls
+	}
+	return ""
+}
+
+type TLS struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Name                string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CaFile              string                 `protobuf:"bytes,2,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`
+	CertFile            string                 `protobuf:"bytes,3,opt,name=cert_file,json=certFile,proto3" json:"cert_file,omitempty"`
+	KeyFile             string                 `protobuf:"bytes,4,opt,name=key_file,json=keyFile,proto3" json:"key_file,omitempty"`
+	CipherSuites        string                 `protobuf:"bytes,5,opt,name=cipher_suites,json=cipherSuites,proto3" json:"cipher_suites,omitempty"`
+	Ciphers             string                 `protobuf:"bytes,6,opt,name=ciphers,proto3" json:"ciphers,omitempty"`
+	DhparamFile         string                 `protobuf:"bytes,7,opt,name=dhparam_file,json=dhparamFile,proto3" json:"dhparam_file,omitempty"`
+	PreferServerCiphers *bool                  `protobuf:"varint,8,opt,name=prefer_server_ciphers,json=preferServerCiphers,proto3,oneof" json:"prefer_server_ciphers,omitempty"`
+	Protocols           []string               `protobuf:"bytes,9,rep,name=protocols,proto3" json:"protocols,omitempty"`
+	RemoteHostname      string                 `protobuf:"bytes,10,opt,name=remote_hostname,json=remoteHostname,proto3" json:"remote_hostname,omitempty"`
+	SessionTickets      *bool                  `protobuf:"varint,11,opt,name=session_tickets,json=sessionTickets,proto3,oneof" json:"session_tickets,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TLS) Reset() {
+	*x = TLS{}
+	mi := &file_namedzone_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TLS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLS) ProtoMessage() {}
+
+func (x *TLS) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLS.ProtoReflect.Descriptor instead.
+func (*TLS) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TLS) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TLS) GetCaFile() string {
+	if x != nil {
+		return x.CaFile
+	}
+	return ""
+}
+
+func (x *TLS) GetCertFile() string {
+	if x != nil {
+		return x.CertFile
+	}
+	return ""
+}
+
+func (x *TLS) GetKeyFile() string {
+	if x != nil {
+		return x.KeyFile
+	}
+	return ""
+}
+
+func (x *TLS) GetCipherSuites() string {
+	if x != nil {
+		return x.CipherSuites
+	}
+	return ""
+}
+
+func (x *TLS) GetCiphers() string {
+	if x != nil {
+		return x.Ciphers
+	}
+	return ""
+}
+
+func (x *TLS) GetDhparamFile() string {
+	if x != nil {
+		return x.DhparamFile
+	}
+	return ""
+}
+
+func (x *TLS) GetPreferServerCiphers() bool {
+	if x != nil && x.PreferServerCiphers != nil {
+		return *x.PreferServerCiphers
+	}
+	return false
+}
+
+func (x *TLS) GetProtocols() []string {
+	if x != nil {
+		return x.Protocols
+	}
+	return nil
+}
+
+func (x *TLS) GetRemoteHostname() string {
+	if x != nil {
+		return x.RemoteHostname
+	}
+	return ""
+}
+
+func (x *TLS) GetSessionTickets() bool {
+	if x != nil && x.SessionTickets != nil {
+		return *x.SessionTickets
+	}
+	return false
+}
+
+type HTTP struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Endpoints            []string               `protobuf:"bytes,2,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	ListenerClients      *int32                 `protobuf:"varint,3,opt,name=listener_clients,json=listenerClients,proto3,oneof" json:"listener_clients,omitempty"`
+	StreamsPerConnection *int32                 `protobuf:"varint,4,opt,name=streams_per_connection,json=streamsPerConnection,proto3,oneof" json:"streams_per_connection,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *HTTP) Reset() {
+	*x = HTTP{}
+	mi := &file_namedzone_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HTTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HTTP) ProtoMessage() {}
+
+func (x *HTTP) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HTTP.ProtoReflect.Descriptor instead.
+func (*HTTP) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *HTTP) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HTTP) GetEndpoints() []string {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+func (x *HTTP) GetListenerClients() int32 {
+	if x != nil && x.ListenerClients != nil {
+		return *x.ListenerClients
+	}
+	return 0
+}
+
+func (x *HTTP) GetStreamsPerConnection() int32 {
+	if x != nil && x.StreamsPerConnection != nil {
+		return *x.StreamsPerConnection
+	}
+	return 0
+}
+
+type Controls struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Inet          []*ControlInet         `protobuf:"bytes,1,rep,name=inet,proto3" json:"inet,omitempty"`
+	Unix          []*ControlUnix         `protobuf:"bytes,2,rep,name=unix,proto3" json:"unix,omitempty"`
+	Disabled      bool                   `protobuf:"varint,3,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Controls) Reset() {
+	*x = Controls{}
+	mi := &file_namedzone_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Controls) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Controls) ProtoMessage() {}
+
+func (x *Controls) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Controls.ProtoReflect.Descriptor instead.
+func (*Controls) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Controls) GetInet() []*ControlInet {
+	if x != nil {
+		return x.Inet
+	}
+	return nil
+}
+
+func (x *Controls) GetUnix() []*ControlUnix {
+	if x != nil {
+		return x.Unix
+	}
+	return nil
+}
+
+func (x *Controls) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type ControlInet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Port          *int32                 `protobuf:"varint,2,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	Allow         []*MatchTerm           `protobuf:"bytes,3,rep,name=allow,proto3" json:"allow,omitempty"`
+	Keys          []string               `protobuf:"bytes,4,rep,name=keys,proto3" json:"keys,omitempty"`
+	ReadOnly      *bool                  `protobuf:"varint,5,opt,name=read_only,json=readOnly,proto3,oneof" json:"read_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControlInet) Reset() {
+	*x = ControlInet{}
+	mi := &file_namedzone_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControlInet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlInet) ProtoMessage() {}
+
+func (x *ControlInet) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlInet.ProtoReflect.Descriptor instead.
+func (*ControlInet) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ControlInet) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ControlInet) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *ControlInet) GetAllow() []*MatchTerm {
+	if x != nil {
+		return x.Allow
+	}
+	return nil
+}
+
+func (x *ControlInet) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *ControlInet) GetReadOnly() bool {
+	if x != nil && x.ReadOnly != nil {
+		return *x.ReadOnly
+	}
+	return false
+}
+
+type ControlUnix struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Perm          int32                  `protobuf:"varint,2,opt,name=perm,proto3" json:"perm,omitempty"`
+	Owner         int32                  `protobuf:"varint,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Group         int32                  `protobuf:"varint,4,opt,name=group,proto3" json:"group,omitempty"`
+	Keys          []string               `protobuf:"bytes,5,rep,name=keys,proto3" json:"keys,omitempty"`
+	ReadOnly      *bool                  `protobuf:"varint,6,opt,name=read_only,json=readOnly,proto3,oneof" json:"read_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControlUnix) Reset() {
+	*x = ControlUnix{}
+	mi := &file_namedzone_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControlUnix) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlUnix) ProtoMessage() {}
+
+func (x *ControlUnix) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlUnix.ProtoReflect.Descriptor instead.
+func (*ControlUnix) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ControlUnix) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ControlUnix) GetPerm() int32 {
+	if x != nil {
+		return x.Perm
+	}
+	return 0
+}
+
+func (x *ControlUnix) GetOwner() int32 {
+	if x != nil {
+		return x.Owner
+	}
+	return 0
+}
+
+func (x *ControlUnix) GetGroup() int32 {
+	if x != nil {
+		return x.Group
+	}
+	return 0
+}
+
+func (x *ControlUnix) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *ControlUnix) GetReadOnly() bool {
+	if x != nil && x.ReadOnly != nil {
+		return *x.ReadOnly
+	}
+	return false
+}
+
+type Logging struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Channels               []*LogChannel          `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	Categories             []*LogCategory         `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	AllowUnknownCategories bool                   `protobuf:"varint,3,opt,name=allow_unknown_categories,json=allowUnknownCategories,proto3" json:"allow_unknown_categories,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Logging) Reset() {
+	*x = Logging{}
+	mi := &file_namedzone_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Logging) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Logging) ProtoMessage() {}
+
+func (x *Logging) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Logging.ProtoReflect.Descriptor instead.
+func (*Logging) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Logging) GetChannels() []*LogChannel {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *Logging) GetCategories() []*LogCategory {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Logging) GetAllowUnknownCategories() bool {
+	if x != nil {
+		return x.AllowUnknownCategories
+	}
+	return false
+}
+
+type LogChannel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	File          *LogFileDest           `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Syslog        *LogSyslogDest         `protobuf:"bytes,3,opt,name=syslog,proto3" json:"syslog,omitempty"`
+	Stderr        bool                   `protobuf:"varint,4,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	Null          bool                   `protobuf:"varint,5,opt,name=null,proto3" json:"null,omitempty"`
+	Severity      *LogSeverity           `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
+	PrintTime     string                 `protobuf:"bytes,7,opt,name=print_time,json=printTime,proto3" json:"print_time,omitempty"`
+	PrintCategory *bool                  `protobuf:"varint,8,opt,name=print_category,json=printCategory,proto3,oneof" json:"print_category,omitempty"`
+	PrintSeverity *bool                  `protobuf:"varint,9,opt,name=print_severity,json=printSeverity,proto3,oneof" json:"print_severity,omitempty"`
+	Buffered      *bool                  `protobuf:"varint,10,opt,name=buffered,proto3,oneof" json:"buffered,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogChannel) Reset() {
+	*x = LogChannel{}
+	mi := &file_namedzone_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogChannel) ProtoMessage() {}
+
+func (x *LogChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogChannel.ProtoReflect.Descriptor instead.
+func (*LogChannel) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LogChannel) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LogChannel) GetFile() *LogFileDest {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+func (x *LogChannel) GetSyslog() *LogSyslogDest {
+	if x != nil {
+		return x.Syslog
+	}
+	return nil
+}
+
+func (x *LogChannel) GetStderr() bool {
+	if x != nil {
+		return x.Stderr
+	}
+	return false
+}
+
+func (x *LogChannel) GetNull() bool {
+	if x != nil {
+		return x.Null
+	}
+	return false
+}
+
+func (x *LogChannel) GetSeverity() *LogSeverity {
+	if x != nil {
+		return x.Severity
+	}
+	return nil
+}
+
+func (x *LogChannel) GetPrintTime() string {
+	if x != nil {
+		return x.PrintTime
+	}
+	return ""
+}
+
+func (x *LogChannel) GetPrintCategory() bool {
+	if x != nil && x.PrintCategory != nil {
+		return *x.PrintCategory
+	}
+	return false
+}
+
+func (x *LogChannel) GetPrintSeverity() bool {
+	if x != nil && x.PrintSeverity != nil {
+		return *x.PrintSeverity
+	}
+	return false
+}
+
+func (x *LogChannel) GetBuffered() bool {
+	if x != nil && x.Buffered != nil {
+		return *x.Buffered
+	}
+	return false
+}
+
+type LogSeverity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	DebugLevel    *int32                 `protobuf:"varint,2,opt,name=debug_level,json=debugLevel,proto3,oneof" json:"debug_level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogSeverity) Reset() {
+	*x = LogSeverity{}
+	mi := &file_namedzone_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogSeverity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogSeverity) ProtoMessage() {}
+
+func (x *LogSeverity) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogSeverity.ProtoReflect.Descriptor instead.
+func (*LogSeverity) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LogSeverity) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogSeverity) GetDebugLevel() int32 {
+	if x != nil && x.DebugLevel != nil {
+		return *x.DebugLevel
+	}
+	return 0
+}
+
+type LogFileDest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Versions      *int32                 `protobuf:"varint,2,opt,name=versions,proto3,oneof" json:"versions,omitempty"`
+	Size          *Size                  `protobuf:"bytes,3,opt,name=size,proto3" json:"size,omitempty"`
+	Suffix        string                 `protobuf:"bytes,4,opt,name=suffix,proto3" json:"suffix,omitempty"`
+	Severity      string                 `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogFileDest) Reset() {
+	*x = LogFileDest{}
+	mi := &file_namedzone_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogFileDest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogFileDest) ProtoMessage() {}
+
+func (x *LogFileDest) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogFileDest.ProtoReflect.Descriptor instead.
+func (*LogFileDest) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LogFileDest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LogFileDest) GetVersions() int32 {
+	if x != nil && x.Versions != nil {
+		return *x.Versions
+	}
+	return 0
+}
+
+func (x *LogFileDest) GetSize() *Size {
+	if x != nil {
+		return x.Size
+	}
+	return nil
+}
+
+func (x *LogFileDest) GetSuffix() string {
+	if x != nil {
+		return x.Suffix
+	}
+	return ""
+}
+
+func (x *LogFileDest) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+type LogSyslogDest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Facility      string                 `protobuf:"bytes,1,opt,name=facility,proto3" json:"facility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogSyslogDest) Reset() {
+	*x = LogSyslogDest{}
+	mi := &file_namedzone_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogSyslogDest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogSyslogDest) ProtoMessage() {}
+
+func (x *LogSyslogDest) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogSyslogDest.ProtoReflect.Descriptor instead.
+func (*LogSyslogDest) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LogSyslogDest) GetFacility() string {
+	if x != nil {
+		return x.Facility
+	}
+	return ""
+}
+
+type LogCategory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Channels      []string               `protobuf:"bytes,2,rep,name=channels,proto3" json:"channels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogCategory) Reset() {
+	*x = LogCategory{}
+	mi := &file_namedzone_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogCategory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogCategory) ProtoMessage() {}
+
+func (x *LogCategory) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogCategory.ProtoReflect.Descriptor instead.
+func (*LogCategory) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LogCategory) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LogCategory) GetChannels() []string {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+// Size is a byte count, a percentage, or the keyword unlimited/default.
+type Size struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bytes         uint64                 `protobuf:"varint,1,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Percent       int32                  `protobuf:"varint,2,opt,name=percent,proto3" json:"percent,omitempty"`
+	Keyword       string                 `protobuf:"bytes,3,opt,name=keyword,proto3" json:"keyword,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Size) Reset() {
+	*x = Size{}
+	mi := &file_namedzone_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Size) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Size) ProtoMessage() {}
+
+func (x *Size) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Size.ProtoReflect.Descriptor instead.
+func (*Size) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Size) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *Size) GetPercent() int32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *Size) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+type ServerIdent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keyword       string                 `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerIdent) Reset() {
+	*x = ServerIdent{}
+	mi := &file_namedzone_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerIdent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerIdent) ProtoMessage() {}
+
+func (x *ServerIdent) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerIdent.ProtoReflect.Descriptor instead.
+func (*ServerIdent) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ServerIdent) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+func (x *ServerIdent) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type Options struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Directory            string                 `protobuf:"bytes,1,opt,name=directory,proto3" json:"directory,omitempty"`
+	Recursion            *bool                  `protobuf:"varint,2,opt,name=recursion,proto3,oneof" json:"recursion,omitempty"`
+	AllowQuery           []*MatchTerm           `protobuf:"bytes,3,rep,name=allow_query,json=allowQuery,proto3" json:"allow_query,omitempty"`
+	AllowTransfer        []*MatchTerm           `protobuf:"bytes,4,rep,name=allow_transfer,json=allowTransfer,proto3" json:"allow_transfer,omitempty"`
+	AllowUpdate          []*MatchTerm           `protobuf:"bytes,5,rep,name=allow_update,json=allowUpdate,proto3" json:"allow_update,omitempty"`
+	ListenOn             *Listen                `protobuf:"bytes,6,opt,name=listen_on,json=listenOn,proto3" json:"listen_on,omitempty"`
+	ListenOnV6           *Listen                `protobuf:"bytes,7,opt,name=listen_on_v6,json=listenOnV6,proto3" json:"listen_on_v6,omitempty"`
+	Forwarders           []*Forwarder           `protobuf:"bytes,8,rep,name=forwarders,proto3" json:"forwarders,omitempty"`
+	Forward              string                 `protobuf:"bytes,9,opt,name=forward,proto3" json:"forward,omitempty"`
+	DnssecValidation     string                 `protobuf:"bytes,10,opt,name=dnssec_validation,json=dnssecValidation,proto3" json:"dnssec_validation,omitempty"`
+	MasterfileFormat     string                 `protobuf:"bytes,11,opt,name=masterfile_format,json=masterfileFormat,proto3" json:"masterfile_format,omitempty"`
+	MasterfileStyle      string                 `protobuf:"bytes,12,opt,name=masterfile_style,json=masterfileStyle,proto3" json:"masterfile_style,omitempty"`
+	RrsetOrder           []*RRsetOrder          `protobuf:"bytes,13,rep,name=rrset_order,json=rrsetOrder,proto3" json:"rrset_order,omitempty"`
+	Prefetch             *Prefetch              `protobuf:"bytes,35,opt,name=prefetch,proto3" json:"prefetch,omitempty"`
+	QnameMinimization    string                 `protobuf:"bytes,36,opt,name=qname_minimization,json=qnameMinimization,proto3" json:"qname_minimization,omitempty"`
+	MinimalResponses     string                 `protobuf:"bytes,37,opt,name=minimal_responses,json=minimalResponses,proto3" json:"minimal_responses,omitempty"`
+	MinimalAny           *bool                  `protobuf:"varint,38,opt,name=minimal_any,json=minimalAny,proto3,oneof" json:"minimal_any,omitempty"`
+	ResponsePadding      *ResponsePadding       `protobuf:"bytes,39,opt,name=response_padding,json=responsePadding,proto3" json:"response_padding,omitempty"`
+	TkeyGssapiKeytab     string                 `protobuf:"bytes,14,opt,name=tkey_gssapi_keytab,json=tkeyGssapiKeytab,proto3" json:"tkey_gssapi_keytab,omitempty"`
+	TkeyGssapiCredential string                 `protobuf:"bytes,15,opt,name=tkey_gssapi_credential,json=tkeyGssapiCredential,proto3" json:"tkey_gssapi_credential,omitempty"`
+	TkeyDomain           string                 `protobuf:"bytes,16,opt,name=tkey_domain,json=tkeyDomain,proto3" json:"tkey_domain,omitempty"`
+	SessionKeyfile       string                 `protobuf:"bytes,17,opt,name=session_keyfile,json=sessionKeyfile,proto3" json:"session_keyfile,omitempty"`
+	SessionKeyname       string                 `protobuf:"bytes,18,opt,name=session_keyname,json=sessionKeyname,proto3" json:"session_keyname,omitempty"`
+	SessionKeyalg        string                 `protobuf:"bytes,19,opt,name=session_keyalg,json=sessionKeyalg,proto3" json:"session_keyalg,omitempty"`
+	MaxCacheSize         *Size                  `protobuf:"bytes,20,opt,name=max_cache_size,json=maxCacheSize,proto3" json:"max_cache_size,omitempty"`
+	MaxCacheTtl          *durationpb.Duration   `protobuf:"bytes,21,opt,name=max_cache_ttl,json=maxCacheTtl,proto3" json:"max_cache_ttl,omitempty"`
+	MaxNcacheTtl         *durationpb.Duration   `protobuf:"bytes,22,opt,name=max_ncache_ttl,json=maxNcacheTtl,proto3" json:"max_ncache_ttl,omitempty"`
+	MaxJournalSize       *Size                  `protobuf:"bytes,23,opt,name=max_journal_size,json=maxJournalSize,proto3" json:"max_journal_size,omitempty"`
+	LmdbMapsize          *Size                  `protobuf:"bytes,40,opt,name=lmdb_mapsize,json=lmdbMapsize,proto3" json:"lmdb_mapsize,omitempty"`
+	MaxRecords           *int32                 `protobuf:"varint,41,opt,name=max_records,json=maxRecords,proto3,oneof" json:"max_records,omitempty"`
+	AllowNewZones        *bool                  `protobuf:"varint,42,opt,name=allow_new_zones,json=allowNewZones,proto3,oneof" json:"allow_new_zones,omitempty"`
+	Version              *ServerIdent           `protobuf:"bytes,24,opt,name=version,proto3" json:"version,omitempty"`
+	Hostname             *ServerIdent           `protobuf:"bytes,25,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	ServerId             *ServerIdent           `protobuf:"bytes,26,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	PidFile              string                 `protobuf:"bytes,27,opt,name=pid_file,json=pidFile,proto3" json:"pid_file,omitempty"`
+	StatisticsFile       string                 `protobuf:"bytes,28,opt,name=statistics_file,json=statisticsFile,proto3" json:"statistics_file,omitempty"`
+	DumpFile             string                 `protobuf:"bytes,29,opt,name=dump_file,json=dumpFile,proto3" json:"dump_file,omitempty"`
+	SecrootsFile         string                 `protobuf:"bytes,30,opt,name=secroots_file,json=secrootsFile,proto3" json:"secroots_file,omitempty"`
+	RecursingFile        string                 `protobuf:"bytes,31,opt,name=recursing_file,json=recursingFile,proto3" json:"recursing_file,omitempty"`
+	MemstatisticsFile    string                 `protobuf:"bytes,32,opt,name=memstatistics_file,json=memstatisticsFile,proto3" json:"memstatistics_file,omitempty"`
+	LockFile             string                 `protobuf:"bytes,33,opt,name=lock_file,json=lockFile,proto3" json:"lock_file,omitempty"`
+	Other                []*RawKV               `protobuf:"bytes,34,rep,name=other,proto3" json:"other,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_namedzone_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Options) GetDirectory() string {
+	if x != nil {
+		return x.Directory
+	}
+	return ""
+}
+
+func (x *Options) GetRecursion() bool {
+	if x != nil && x.Recursion != nil {
+		return *x.Recursion
+	}
+	return false
+}
+
+func (x *Options) GetAllowQuery() []*MatchTerm {
+	if x != nil {
+		return x.AllowQuery
+	}
+	return nil
+}
+
+func (x *Options) GetAllowTransfer() []*MatchTerm {
+	if x != nil {
+		return x.AllowTransfer
+	}
+	return nil
+}
+
+func (x *Options) GetAllowUpdate() []*MatchTerm {
+	if x != nil {
+		return x.AllowUpdate
+	}
+	return nil
+}
+
+func (x *Options) GetListenOn() *Listen {
+	if x != nil {
+		return x.ListenOn
+	}
+	return nil
+}
+
+func (x *Options) GetListenOnV6() *Listen {
+	if x != nil {
+		return x.ListenOnV6
+	}
+	return nil
+}
+
+func (x *Options) GetForwarders() []*Forwarder {
+	if x != nil {
+		return x.Forwarders
+	}
+	return nil
+}
+
+func (x *Options) GetForward() string {
+	if x != nil {
+		return x.Forward
+	}
+	return ""
+}
+
+func (x *Options) GetDnssecValidation() string {
+	if x != nil {
+		return x.DnssecValidation
+	}
+	return ""
+}
+
+func (x *Options) GetMasterfileFormat() string {
+	if x != nil {
+		return x.MasterfileFormat
+	}
+	return ""
+}
+
+func (x *Options) GetMasterfileStyle() string {
+	if x != nil {
+		return x.MasterfileStyle
+	}
+	return ""
+}
+
+func (x *Options) GetRrsetOrder() []*RRsetOrder {
+	if x != nil {
+		return x.RrsetOrder
+	}
+	return nil
+}
+
+func (x *Options) GetPrefetch() *Prefetch {
+	if x != nil {
+		return x.Prefetch
+	}
+	return nil
+}
+
+func (x *Options) GetQnameMinimization() string {
+	if x != nil {
+		return x.QnameMinimization
+	}
+	return ""
+}
+
+func (x *Options) GetMinimalResponses() string {
+	if x != nil {
+		return x.MinimalResponses
+	}
+	return ""
+}
+
+func (x *Options) GetMinimalAny() bool {
+	if x != nil && x.MinimalAny != nil {
+		return *x.MinimalAny
+	}
+	return false
+}
+
+func (x *Options) GetResponsePadding() *ResponsePadding {
+	if x != nil {
+		return x.ResponsePadding
+	}
+	return nil
+}
+
+func (x *Options) GetTkeyGssapiKeytab() string {
+	if x != nil {
+		return x.TkeyGssapiKeytab
+	}
+	return ""
+}
+
+func (x *Options) GetTkeyGssapiCredential() string {
+	if x != nil {
+		return x.TkeyGssapiCredential
+	}
+	return ""
+}
+
+func (x *Options) GetTkeyDomain() string {
+	if x != nil {
+		return x.TkeyDomain
+	}
+	return ""
+}
+
+func (x *Options) GetSessionKeyfile() string {
+	if x != nil {
+		return x.SessionKeyfile
+	}
+	return ""
+}
+
+func (x *Options) GetSessionKeyname() string {
+	if x != nil {
+		return x.SessionKeyname
+	}
+	return ""
+}
+
+func (x *Options) GetSessionKeyalg() string {
+	if x != nil {
+		return x.SessionKeyalg
+	}
+	return ""
+}
+
+func (x *Options) GetMaxCacheSize() *Size {
+	if x != nil {
+		return x.MaxCacheSize
+	}
+	return nil
+}
+
+func (x *Options) GetMaxCacheTtl() *durationpb.Duration {
+	if x != nil {
+		return x.MaxCacheTtl
+	}
+	return nil
+}
+
+func (x *Options) GetMaxNcacheTtl() *durationpb.Duration {
+	if x != nil {
+		return x.MaxNcacheTtl
+	}
+	return nil
+}
+
+func (x *Options) GetMaxJournalSize() *Size {
+	if x != nil {
+		return x.MaxJournalSize
+	}
+	return nil
+}
+
+func (x *Options) GetLmdbMapsize() *Size {
+	if x != nil {
+		return x.LmdbMapsize
+	}
+	return nil
+}
+
+func (x *Options) GetMaxRecords() int32 {
+	if x != nil && x.MaxRecords != nil {
+		return *x.MaxRecords
+	}
+	return 0
+}
+
+func (x *Options) GetAllowNewZones() bool {
+	if x != nil && x.AllowNewZones != nil {
+		return *x.AllowNewZones
+	}
+	return false
+}
+
+func (x *Options) GetVersion() *ServerIdent {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *Options) GetHostname() *ServerIdent {
+	if x != nil {
+		return x.Hostname
+	}
+	return nil
+}
+
+func (x *Options) GetServerId() *ServerIdent {
+	if x != nil {
+		return x.ServerId
+	}
+	return nil
+}
+
+func (x *Options) GetPidFile() string {
+	if x != nil {
+		return x.PidFile
+	}
+	return ""
+}
+
+func (x *Options) GetStatisticsFile() string {
+	if x != nil {
+		return x.StatisticsFile
+	}
+	return ""
+}
+
+func (x *Options) GetDumpFile() string {
+	if x != nil {
+		return x.DumpFile
+	}
+	return ""
+}
+
+func (x *Options) GetSecrootsFile() string {
+	if x != nil {
+		return x.SecrootsFile
+	}
+	return ""
+}
+
+func (x *Options) GetRecursingFile() string {
+	if x != nil {
+		return x.RecursingFile
+	}
+	return ""
+}
+
+func (x *Options) GetMemstatisticsFile() string {
+	if x != nil {
+		return x.MemstatisticsFile
+	}
+	return ""
+}
+
+func (x *Options) GetLockFile() string {
+	if x != nil {
+		return x.LockFile
+	}
+	return ""
+}
+
+func (x *Options) GetOther() []*RawKV {
+	if x != nil {
+		return x.Other
+	}
+	return nil
+}
+
+type Prefetch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trigger       int32                  `protobuf:"varint,1,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	Eligibility   *int32                 `protobuf:"varint,2,opt,name=eligibility,proto3,oneof" json:"eligibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Prefetch) Reset() {
+	*x = Prefetch{}
+	mi := &file_namedzone_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Prefetch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prefetch) ProtoMessage() {}
+
+func (x *Prefetch) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prefetch.ProtoReflect.Descriptor instead.
+func (*Prefetch) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Prefetch) GetTrigger() int32 {
+	if x != nil {
+		return x.Trigger
+	}
+	return 0
+}
+
+func (x *Prefetch) GetEligibility() int32 {
+	if x != nil && x.Eligibility != nil {
+		return *x.Eligibility
+	}
+	return 0
+}
+
+type ResponsePadding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clients       []*MatchTerm           `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	BlockSize     int32                  `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponsePadding) Reset() {
+	*x = ResponsePadding{}
+	mi := &file_namedzone_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponsePadding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponsePadding) ProtoMessage() {}
+
+func (x *ResponsePadding) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponsePadding.ProtoReflect.Descriptor instead.
+func (*ResponsePadding) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ResponsePadding) GetClients() []*MatchTerm {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *ResponsePadding) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+type Listen struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Port          *int32                 `protobuf:"varint,1,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	Tls           string                 `protobuf:"bytes,2,opt,name=tls,proto3" json:"tls,omitempty"`
+	Http          string                 `protobuf:"bytes,3,opt,name=http,proto3" json:"http,omitempty"`
+	Addrs         []*MatchTerm           `protobuf:"bytes,4,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Listen) Reset() {
+	*x = Listen{}
+	mi := &file_namedzone_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Listen) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Listen) ProtoMessage() {}
+
+func (x *Listen) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Listen.ProtoReflect.Descriptor instead.
+func (*Listen) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Listen) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *Listen) GetTls() string {
+	if x != nil {
+		return x.Tls
+	}
+	return ""
+}
+
+func (x *Listen) GetHttp() string {
+	if x != nil {
+		return x.Http
+	}
+	return ""
+}
+
+func (x *Listen) GetAddrs() []*MatchTerm {
+	if x != nil {
+		return x.Addrs
+	}
+	return nil
+}
+
+type Forwarder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Port          *int32                 `protobuf:"varint,2,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	Tls           string                 `protobuf:"bytes,3,opt,name=tls,proto3" json:"tls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Forwarder) Reset() {
+	*x = Forwarder{}
+	mi := &file_namedzone_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Forwarder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Forwarder) ProtoMessage() {}
+
+func (x *Forwarder) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Forwarder.ProtoReflect.Descriptor instead.
+func (*Forwarder) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Forwarder) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Forwarder) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *Forwarder) GetTls() string {
+	if x != nil {
+		return x.Tls
+	}
+	return ""
+}
+
+type TrustAnchors struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TrustAnchorItem     `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrustAnchors) Reset() {
+	*x = TrustAnchors{}
+	mi := &file_namedzone_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustAnchors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustAnchors) ProtoMessage() {}
+
+func (x *TrustAnchors) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustAnchors.ProtoReflect.Descriptor instead.
+func (*TrustAnchors) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TrustAnchors) GetItems() []*TrustAnchorItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type TrustAnchorItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Flags         int32                  `protobuf:"varint,3,opt,name=flags,proto3" json:"flags,omitempty"`
+	Protocol      int32                  `protobuf:"varint,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	KeyTag        int32                  `protobuf:"varint,5,opt,name=key_tag,json=keyTag,proto3" json:"key_tag,omitempty"`
+	Algorithm     int32                  `protobuf:"varint,6,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	DigestType    int32                  `protobuf:"varint,7,opt,name=digest_type,json=digestType,proto3" json:"digest_type,omitempty"`
+	Data          string                 `protobuf:"bytes,8,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrustAnchorItem) Reset() {
+	*x = TrustAnchorItem{}
+	mi := &file_namedzone_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustAnchorItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustAnchorItem) ProtoMessage() {}
+
+func (x *TrustAnchorItem) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustAnchorItem.ProtoReflect.Descriptor instead.
+func (*TrustAnchorItem) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TrustAnchorItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TrustAnchorItem) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TrustAnchorItem) GetFlags() int32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+func (x *TrustAnchorItem) GetProtocol() int32 {
+	if x != nil {
+		return x.Protocol
+	}
+	return 0
+}
+
+func (x *TrustAnchorItem) GetKeyTag() int32 {
+	if x != nil {
+		return x.KeyTag
+	}
+	return 0
+}
+
+func (x *TrustAnchorItem) GetAlgorithm() int32 {
+	if x != nil {
+		return x.Algorithm
+	}
+	return 0
+}
+
+func (x *TrustAnchorItem) GetDigestType() int32 {
+	if x != nil {
+		return x.DigestType
+	}
+	return 0
+}
+
+func (x *TrustAnchorItem) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+type RRsetOrder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Order         string                 `protobuf:"bytes,3,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RRsetOrder) Reset() {
+	*x = RRsetOrder{}
+	mi := &file_namedzone_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RRsetOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RRsetOrder) ProtoMessage() {}
+
+func (x *RRsetOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RRsetOrder.ProtoReflect.Descriptor instead.
+func (*RRsetOrder) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RRsetOrder) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RRsetOrder) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RRsetOrder) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+type RawKV struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Raw           string                 `protobuf:"bytes,2,opt,name=raw,proto3" json:"raw,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RawKV) Reset() {
+	*x = RawKV{}
+	mi := &file_namedzone_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RawKV) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RawKV) ProtoMessage() {}
+
+func (x *RawKV) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RawKV.ProtoReflect.Descriptor instead.
+func (*RawKV) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RawKV) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RawKV) GetRaw() string {
+	if x != nil {
+		return x.Raw
+	}
+	return ""
+}
+
+type View struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Class              string                 `protobuf:"bytes,2,opt,name=class,proto3" json:"class,omitempty"`
+	MatchClients       []*MatchTerm           `protobuf:"bytes,3,rep,name=match_clients,json=matchClients,proto3" json:"match_clients,omitempty"`
+	MatchDestinations  []*MatchTerm           `protobuf:"bytes,4,rep,name=match_destinations,json=matchDestinations,proto3" json:"match_destinations,omitempty"`
+	Recursion          *bool                  `protobuf:"varint,5,opt,name=recursion,proto3,oneof" json:"recursion,omitempty"`
+	TrustAnchors       *TrustAnchors          `protobuf:"bytes,6,opt,name=trust_anchors,json=trustAnchors,proto3" json:"trust_anchors,omitempty"`
+	Zones              []*Zone                `protobuf:"bytes,7,rep,name=zones,proto3" json:"zones,omitempty"`
+	Includes           []*Include             `protobuf:"bytes,8,rep,name=includes,proto3" json:"includes,omitempty"`
+	Prefetch           *Prefetch              `protobuf:"bytes,9,opt,name=prefetch,proto3" json:"prefetch,omitempty"`
+	QnameMinimization  string                 `protobuf:"bytes,10,opt,name=qname_minimization,json=qnameMinimization,proto3" json:"qname_minimization,omitempty"`
+	MinimalResponses   string                 `protobuf:"bytes,11,opt,name=minimal_responses,json=minimalResponses,proto3" json:"minimal_responses,omitempty"`
+	MinimalAny         *bool                  `protobuf:"varint,12,opt,name=minimal_any,json=minimalAny,proto3,oneof" json:"minimal_any,omitempty"`
+	ResponsePadding    *ResponsePadding       `protobuf:"bytes,13,opt,name=response_padding,json=responsePadding,proto3" json:"response_padding,omitempty"`
+	LmdbMapsize        *Size                  `protobuf:"bytes,14,opt,name=lmdb_mapsize,json=lmdbMapsize,proto3" json:"lmdb_mapsize,omitempty"`
+	MaxRecords         *int32                 `protobuf:"varint,15,opt,name=max_records,json=maxRecords,proto3,oneof" json:"max_records,omitempty"`
+	AllowNewZones      *bool                  `protobuf:"varint,16,opt,name=allow_new_zones,json=allowNewZones,proto3,oneof" json:"allow_new_zones,omitempty"`
+	MatchRecursiveOnly *bool                  `protobuf:"varint,17,opt,name=match_recursive_only,json=matchRecursiveOnly,proto3,oneof" json:"match_recursive_only,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *View) Reset() {
+	*x = View{}
+	mi := &file_namedzone_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *View) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*View) ProtoMessage() {}
+
+func (x *View) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use View.ProtoReflect.Descriptor instead.
+func (*View) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *View) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *View) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *View) GetMatchClients() []*MatchTerm {
+	if x != nil {
+		return x.MatchClients
+	}
+	return nil
+}
+
+func (x *View) GetMatchDestinations() []*MatchTerm {
+	if x != nil {
+		return x.MatchDestinations
+	}
+	return nil
+}
+
+func (x *View) GetRecursion() bool {
+	if x != nil && x.Recursion != nil {
+		return *x.Recursion
+	}
+	return false
+}
+
+func (x *View) GetTrustAnchors() *TrustAnchors {
+	if x != nil {
+		return x.TrustAnchors
+	}
+	return nil
+}
+
+func (x *View) GetZones() []*Zone {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+func (x *View) GetIncludes() []*Include {
+	if x != nil {
+		return x.Includes
+	}
+	return nil
+}
+
+func (x *View) GetPrefetch() *Prefetch {
+	if x != nil {
+		return x.Prefetch
+	}
+	return nil
+}
+
+func (x *View) GetQnameMinimization() string {
+	if x != nil {
+		return x.QnameMinimization
+	}
+	return ""
+}
+
+func (x *View) GetMinimalResponses() string {
+	if x != nil {
+		return x.MinimalResponses
+	}
+	return ""
+}
+
+func (x *View) GetMinimalAny() bool {
+	if x != nil && x.MinimalAny != nil {
+		return *x.MinimalAny
+	}
+	return false
+}
+
+func (x *View) GetResponsePadding() *ResponsePadding {
+	if x != nil {
+		return x.ResponsePadding
+	}
+	return nil
+}
+
+func (x *View) GetLmdbMapsize() *Size {
+	if x != nil {
+		return x.LmdbMapsize
+	}
+	return nil
+}
+
+func (x *View) GetMaxRecords() int32 {
+	if x != nil && x.MaxRecords != nil {
+		return *x.MaxRecords
+	}
+	return 0
+}
+
+func (x *View) GetAllowNewZones() bool {
+	if x != nil && x.AllowNewZones != nil {
+		return *x.AllowNewZones
+	}
+	return false
+}
+
+func (x *View) GetMatchRecursiveOnly() bool {
+	if x != nil && x.MatchRecursiveOnly != nil {
+		return *x.MatchRecursiveOnly
+	}
+	return false
+}
+
+type Zone struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Class            string                 `protobuf:"bytes,2,opt,name=class,proto3" json:"class,omitempty"`
+	Type             string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	File             string                 `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	PrimariesRef     string                 `protobuf:"bytes,5,opt,name=primaries_ref,json=primariesRef,proto3" json:"primaries_ref,omitempty"`
+	Primaries        []*RemoteServerItem    `protobuf:"bytes,6,rep,name=primaries,proto3" json:"primaries,omitempty"`
+	Forwarders       []*Forwarder           `protobuf:"bytes,7,rep,name=forwarders,proto3" json:"forwarders,omitempty"`
+	Forward          string                 `protobuf:"bytes,8,opt,name=forward,proto3" json:"forward,omitempty"`
+	AllowUpdate      []*MatchTerm           `protobuf:"bytes,9,rep,name=allow_update,json=allowUpdate,proto3" json:"allow_update,omitempty"`
+	AllowTransfer    []*MatchTerm           `protobuf:"bytes,10,rep,name=allow_transfer,json=allowTransfer,proto3" json:"allow_transfer,omitempty"`
+	AlsoNotify       []*RemoteServerItem    `protobuf:"bytes,11,rep,name=also_notify,json=alsoNotify,proto3" json:"also_notify,omitempty"`
+	DnssecPolicy     string                 `protobuf:"bytes,12,opt,name=dnssec_policy,json=dnssecPolicy,proto3" json:"dnssec_policy,omitempty"`
+	MasterfileFormat string                 `protobuf:"bytes,13,opt,name=masterfile_format,json=masterfileFormat,proto3" json:"masterfile_format,omitempty"`
+	MasterfileStyle  string                 `protobuf:"bytes,14,opt,name=masterfile_style,json=masterfileStyle,proto3" json:"masterfile_style,omitempty"`
+	MaxRecords       *int32                 `protobuf:"varint,15,opt,name=max_records,json=maxRecords,proto3,oneof" json:"max_records,omitempty"`
+	Notify           string                 `protobuf:"bytes,16,opt,name=notify,proto3" json:"notify,omitempty"`
+	InView           string                 `protobuf:"bytes,17,opt,name=in_view,json=inView,proto3" json:"in_view,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Zone) Reset() {
+	*x = Zone{}
+	mi := &file_namedzone_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Zone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Zone) ProtoMessage() {}
+
+func (x *Zone) ProtoReflect() protoreflect.Message {
+	mi := &file_namedzone_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Zone.ProtoReflect.Descriptor instead.
+func (*Zone) Descriptor() ([]byte, []int) {
+	return file_namedzone_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Zone) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Zone) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *Zone) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Zone) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Zone) GetPrimariesRef() string {
+	if x != nil {
+		return x.PrimariesRef
+	}
+	return ""
+}
+
+func (x *Zone) GetPrimaries() []*RemoteServerItem {
+	if x != nil {
+		return x.Primaries
+	}
+	return nil
+}
+
+func (x *Zone) GetForwarders() []*Forwarder {
+	if x != nil {
+		return x.Forwarders
+	}
+	return nil
+}
+
+func (x *Zone) GetForward() string {
+	if x != nil {
+		return x.Forward
+	}
+	return ""
+}
+
+func (x *Zone) GetAllowUpdate() []*MatchTerm {
+	if x != nil {
+		return x.AllowUpdate
+	}
+	return nil
+}
+
+func (x *Zone) GetAllowTransfer() []*MatchTerm {
+	if x != nil {
+		return x.AllowTransfer
+	}
+	return nil
+}
+
+func (x *Zone) GetAlsoNotify() []*RemoteServerItem {
+	if x != nil {
+		return x.AlsoNotify
+	}
+	return nil
+}
+
+func (x *Zone) GetDnssecPolicy() string {
+	if x != nil {
+		return x.DnssecPolicy
+	}
+	return ""
+}
+
+func (x *Zone) GetMasterfileFormat() string {
+	if x != nil {
+		return x.MasterfileFormat
+	}
+	return ""
+}
+
+func (x *Zone) GetMasterfileStyle() string {
+	if x != nil {
+		return x.MasterfileStyle
+	}
+	return ""
+}
+
+func (x *Zone) GetMaxRecords() int32 {
+	if x != nil && x.MaxRecords != nil {
+		return *x.MaxRecords
+	}
+	return 0
+}
+
+func (x *Zone) GetNotify() string {
+	if x != nil {
+		return x.Notify
+	}
+	return ""
+}
+
+func (x *Zone) GetInView() string {
+	if x != nil {
+		return x.InView
+	}
+	return ""
+}
+
+var File_namedzone_proto protoreflect.FileDescriptor
+
+const file_namedzone_proto_rawDesc = "" +
+	"\n" +
+	"\x0fnamedzone.proto\x12\fnamedzone.v1\x1a\x1egoogle/protobuf/duration.proto\"\xab\x05\n" +
+	"\x06Config\x121\n" +
+	"\bincludes\x18\x01 \x03(\v2\x15.namedzone.v1.IncludeR\bincludes\x12%\n" +
+	"\x04acls\x18\x02 \x03(\v2\x11.namedzone.v1.ACLR\x04acls\x12%\n" +
+	"\x04keys\x18\x03 \x03(\v2\x11.namedzone.v1.KeyR\x04keys\x125\n" +
+	"\n" +
+	"key_stores\x18\x04 \x03(\v2\x16.namedzone.v1.KeyStoreR\tkeyStores\x12B\n" +
+	"\x0eremote_servers\x18\x05 \x03(\v2\x1b.namedzone.v1.RemoteServersR\rremoteServers\x12#\n" +
+	"\x03tls\x18\x06 \x03(\v2\x11.namedzone.v1.TLSR\x03tls\x12&\n" +
+	"\x04http\x18\a \x03(\v2\x12.namedzone.v1.HTTPR\x04http\x122\n" +
+	"\bcontrols\x18\b \x01(\v2\x16.namedzone.v1.ControlsR\bcontrols\x12/\n" +
+	"\alogging\x18\t \x01(\v2\x15.namedzone.v1.LoggingR\alogging\x12/\n" +
+	"\aoptions\x18\n" +
+	" \x01(\v2\x15.namedzone.v1.OptionsR\aoptions\x12?\n" +
+	"\rtrust_anchors\x18\v \x03(\v2\x1a.namedzone.v1.TrustAnchorsR\ftrustAnchors\x12(\n" +
+	"\x05views\x18\f \x03(\v2\x12.namedzone.v1.ViewR\x05views\x12(\n" +
+	"\x05zones\x18\r \x03(\v2\x12.namedzone.v1.ZoneR\x05zones\x12-\n" +
+	"\x12modernize_keywords\x18\x0e \x01(\bR\x11modernizeKeywords\"\x1d\n" +
+	"\aInclude\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"N\n" +
+	"\x03ACL\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x123\n" +
+	"\belements\x18\x02 \x03(\v2\x17.namedzone.v1.MatchTermR\belements\"\x93\x01\n" +
+	"\tMatchTerm\x12\x10\n" +
+	"\x03not\x18\x01 \x01(\bR\x03not\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12\x17\n" +
+	"\aacl_ref\x18\x04 \x01(\tR\x06aclRef\x12/\n" +
+	"\x06nested\x18\x05 \x03(\v2\x17.namedzone.v1.MatchTermR\x06nested\"O\n" +
+	"\x03Key\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\x12\x16\n" +
+	"\x06secret\x18\x03 \x01(\tR\x06secret\"=\n" +
+	"\bKeyStore\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"pkcs11_uri\x18\x02 \x01(\tR\tpkcs11Uri\"]\n" +
+	"\rRemoteServers\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x128\n" +
+	"\aservers\x18\x02 \x03(\v2\x1e.namedzone.v1.RemoteServerItemR\aservers\"r\n" +
+	"\x10RemoteServerItem\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x17\n" +
+	"\x04port\x18\x02 \x01(\x05H\x00R\x04port\x88\x01\x01\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12\x10\n" +
+	"\x03tls\x18\x04 \x01(\tR\x03tlsB\a\n" +
+	"\x05_port\"\xa8\x03\n" +
+	"\x03TLS\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
+	"\aca_file\x18\x02 \x01(\tR\x06caFile\x12\x1b\n" +
+	"\tcert_file\x18\x03 \x01(\tR\bcertFile\x12\x19\n" +
+	"\bkey_file\x18\x04 \x01(\tR\akeyFile\x12#\n" +
+	"\rcipher_suites\x18\x05 \x01(\tR\fcipherSuites\x12\x18\n" +
+	"\aciphers\x18\x06 \x01(\tR\aciphers\x12!\n" +
+	"\fdhparam_file\x18\a \x01(\tR\vdhparamFile\x127\n" +
+	"\x15prefer_server_ciphers\x18\b \x01(\bH\x00R\x13preferServerCiphers\x88\x01\x01\x12\x1c\n" +
+	"\tprotocols\x18\t \x03(\tR\tprotocols\x12'\n" +
+	"\x0fremote_hostname\x18\n" +
+	" \x01(\tR\x0eremoteHostname\x12,\n" +
+	"\x0fsession_tickets\x18\v \x01(\bH\x01R\x0esessionTickets\x88\x01\x01B\x18\n" +
+	"\x16_prefer_server_ciphersB\x12\n" +
+	"\x10_session_tickets\"\xd3\x01\n" +
+	"\x04HTTP\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\tendpoints\x18\x02 \x03(\tR\tendpoints\x12.\n" +
+	"\x10listener_clients\x18\x03 \x01(\x05H\x00R\x0flistenerClients\x88\x01\x01\x129\n" +
+	"\x16streams_per_connection\x18\x04 \x01(\x05H\x01R\x14streamsPerConnection\x88\x01\x01B\x13\n" +
+	"\x11_listener_clientsB\x19\n" +
+	"\x17_streams_per_connection\"\x84\x01\n" +
+	"\bControls\x12-\n" +
+	"\x04inet\x18\x01 \x03(\v2\x19.namedzone.v1.ControlInetR\x04inet\x12-\n" +
+	"\x04unix\x18\x02 \x03(\v2\x19.namedzone.v1.ControlUnixR\x04unix\x12\x1a\n" +
+	"\bdisabled\x18\x03 \x01(\bR\bdisabled\"\xbc\x01\n" +
+	"\vControlInet\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x17\n" +
+	"\x04port\x18\x02 \x01(\x05H\x00R\x04port\x88\x01\x01\x12-\n" +
+	"\x05allow\x18\x03 \x03(\v2\x17.namedzone.v1.MatchTermR\x05allow\x12\x12\n" +
+	"\x04keys\x18\x04 \x03(\tR\x04keys\x12 \n" +
+	"\tread_only\x18\x05 \x01(\bH\x01R\breadOnly\x88\x01\x01B\a\n" +
+	"\x05_portB\f\n" +
+	"\n" +
+	"_read_only\"\xa5\x01\n" +
+	"\vControlUnix\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04perm\x18\x02 \x01(\x05R\x04perm\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\x05R\x05owner\x12\x14\n" +
+	"\x05group\x18\x04 \x01(\x05R\x05group\x12\x12\n" +
+	"\x04keys\x18\x05 \x03(\tR\x04keys\x12 \n" +
+	"\tread_only\x18\x06 \x01(\bH\x00R\breadOnly\x88\x01\x01B\f\n" +
+	"\n" +
+	"_read_only\"\xb4\x01\n" +
+	"\aLogging\x124\n" +
+	"\bchannels\x18\x01 \x03(\v2\x18.namedzone.v1.LogChannelR\bchannels\x129\n" +
+	"\n" +
+	"categories\x18\x02 \x03(\v2\x19.namedzone.v1.LogCategoryR\n" +
+	"categories\x128\n" +
+	"\x18allow_unknown_categories\x18\x03 \x01(\bR\x16allowUnknownCategories\"\xb2\x03\n" +
+	"\n" +
+	"LogChannel\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\x04file\x18\x02 \x01(\v2\x19.namedzone.v1.LogFileDestR\x04file\x123\n" +
+	"\x06syslog\x18\x03 \x01(\v2\x1b.namedzone.v1.LogSyslogDestR\x06syslog\x12\x16\n" +
+	"\x06stderr\x18\x04 \x01(\bR\x06stderr\x12\x12\n" +
+	"\x04null\x18\x05 \x01(\bR\x04null\x125\n" +
+	"\bseverity\x18\x06 \x01(\v2\x19.namedzone.v1.LogSeverityR\bseverity\x12\x1d\n" +
+	"\n" +
+	"print_time\x18\a \x01(\tR\tprintTime\x12*\n" +
+	"\x0eprint_category\x18\b \x01(\bH\x00R\rprintCategory\x88\x01\x01\x12*\n" +
+	"\x0eprint_severity\x18\t \x01(\bH\x01R\rprintSeverity\x88\x01\x01\x12\x1f\n" +
+	"\bbuffered\x18\n" +
+	" \x01(\bH\x02R\bbuffered\x88\x01\x01B\x11\n" +
+	"\x0f_print_categoryB\x11\n" +
+	"\x0f_print_severityB\v\n" +
+	"\t_buffered\"Y\n" +
+	"\vLogSeverity\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\x12$\n" +
+	"\vdebug_level\x18\x02 \x01(\x05H\x00R\n" +
+	"debugLevel\x88\x01\x01B\x0e\n" +
+	"\f_debug_level\"\xab\x01\n" +
+	"\vLogFileDest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1f\n" +
+	"\bversions\x18\x02 \x01(\x05H\x00R\bversions\x88\x01\x01\x12&\n" +
+	"\x04size\x18\x03 \x01(\v2\x12.namedzone.v1.SizeR\x04size\x12\x16\n" +
+	"\x06suffix\x18\x04 \x01(\tR\x06suffix\x12\x1a\n" +
+	"\bseverity\x18\x05 \x01(\tR\bseverityB\v\n" +
+	"\t_versions\"+\n" +
+	"\rLogSyslogDest\x12\x1a\n" +
+	"\bfacility\x18\x01 \x01(\tR\bfacility\"=\n" +
+	"\vLogCategory\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bchannels\x18\x02 \x03(\tR\bchannels\"P\n" +
+	"\x04Size\x12\x14\n" +
+	"\x05bytes\x18\x01 \x01(\x04R\x05bytes\x12\x18\n" +
+	"\apercent\x18\x02 \x01(\x05R\apercent\x12\x18\n" +
+	"\akeyword\x18\x03 \x01(\tR\akeyword\"=\n" +
+	"\vServerIdent\x12\x18\n" +
+	"\akeyword\x18\x01 \x01(\tR\akeyword\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\x88\x10\n" +
+	"\aOptions\x12\x1c\n" +
+	"\tdirectory\x18\x01 \x01(\tR\tdirectory\x12!\n" +
+	"\trecursion\x18\x02 \x01(\bH\x00R\trecursion\x88\x01\x01\x128\n" +
+	"\vallow_query\x18\x03 \x03(\v2\x17.namedzone.v1.MatchTermR\n" +
+	"allowQuery\x12>\n" +
+	"\x0eallow_transfer\x18\x04 \x03(\v2\x17.namedzone.v1.MatchTermR\rallowTransfer\x12:\n" +
+	"\fallow_update\x18\x05 \x03(\v2\x17.namedzone.v1.MatchTermR\vallowUpdate\x121\n" +
+	"\tlisten_on\x18\x06 \x01(\v2\x14.namedzone.v1.ListenR\blistenOn\x126\n" +
+	"\flisten_on_v6\x18\a \x01(\v2\x14.namedzone.v1.ListenR\n" +
+	"listenOnV6\x127\n" +
+	"\n" +
+	"forwarders\x18\b \x03(\v2\x17.namedzone.v1.ForwarderR\n" +
+	"forwarders\x12\x18\n" +
+	"\aforward\x18\t \x01(\tR\aforward\x12+\n" +
+	"\x11dnssec_validation\x18\n" +
+	" \x01(\tR\x10dnssecValidation\x12+\n" +
+	"\x11masterfile_format\x18\v \x01(\tR\x10masterfileFormat\x12)\n" +
+	"\x10masterfile_style\x18\f \x01(\tR\x0fmasterfileStyle\x129\n" +
+	"\vrrset_order\x18\r \x03(\v2\x18.namedzone.v1.RRsetOrderR\n" +
+	"rrsetOrder\x122\n" +
+	"\bprefetch\x18# \x01(\v2\x16.namedzone.v1.PrefetchR\bprefetch\x12-\n" +
+	"\x12qname_minimization\x18$ \x01(\tR\x11qnameMinimization\x12+\n" +
+	"\x11minimal_responses\x18% \x01(\tR\x10minimalResponses\x12$\n" +
+	"\vminimal_any\x18& \x01(\bH\x01R\n" +
+	"minimalAny\x88\x01\x01\x12H\n" +
+	"\x10response_padding\x18' \x01(\v2\x1d.namedzone.v1.ResponsePaddingR\x0fresponsePadding\x12,\n" +
+	"\x12tkey_gssapi_keytab\x18\x0e \x01(\tR\x10tkeyGssapiKeytab\x124\n" +
+	"\x16tkey_gssapi_credential\x18\x0f \x01(\tR\x14tkeyGssapiCredential\x12\x1f\n" +
+	"\vtkey_domain\x18\x10 \x01(\tR\n" +
+	"tkeyDomain\x12'\n" +
+	"\x0fsession_keyfile\x18\x11 \x01(\tR\x0esessionKeyfile\x12'\n" +
+	"\x0fsession_keyname\x18\x12 \x01(\tR\x0esessionKeyname\x12%\n" +
+	"\x0esession_keyalg\x18\x13 \x01(\tR\rsessionKeyalg\x128\n" +
+	"\x0emax_cache_size\x18\x14 \x01(\v2\x12.namedzone.v1.SizeR\fmaxCacheSize\x12=\n" +
+	"\rmax_cache_ttl\x18\x15 \x01(\v2\x19.google.protobuf.DurationR\vmaxCacheTtl\x12?\n" +
+	"\x0emax_ncache_ttl\x18\x16 \x01(\v2\x19.google.protobuf.DurationR\fmaxNcacheTtl\x12<\n" +
+	"\x10max_journal_size\x18\x17 \x01(\v2\x12.namedzone.v1.SizeR\x0emaxJournalSize\x125\n" +
+	"\flmdb_mapsize\x18( \x01(\v2\x12.namedzone.v1.SizeR\vlmdbMapsize\x12$\n" +
+	"\vmax_records\x18) \x01(\x05H\x02R\n" +
+	"maxRecords\x88\x01\x01\x12+\n" +
+	"\x0fallow_new_zones\x18* \x01(\bH\x03R\rallowNewZones\x88\x01\x01\x123\n" +
+	"\aversion\x18\x18 \x01(\v2\x19.namedzone.v1.ServerIdentR\aversion\x125\n" +
+	"\bhostname\x18\x19 \x01(\v2\x19.namedzone.v1.ServerIdentR\bhostname\x126\n" +
+	"\tserver_id\x18\x1a \x01(\v2\x19.namedzone.v1.ServerIdentR\bserverId\x12\x19\n" +
+	"\bpid_file\x18\x1b \x01(\tR\apidFile\x12'\n" +
+	"\x0fstatistics_file\x18\x1c \x01(\tR\x0estatisticsFile\x12\x1b\n" +
+	"\tdump_file\x18\x1d \x01(\tR\bdumpFile\x12#\n" +
+	"\rsecroots_file\x18\x1e \x01(\tR\fsecrootsFile\x12%\n" +
+	"\x0erecursing_file\x18\x1f \x01(\tR\rrecursingFile\x12-\n" +
+	"\x12memstatistics_file\x18  \x01(\tR\x11memstatisticsFile\x12\x1b\n" +
+	"\tlock_file\x18! \x01(\tR\blockFile\x12)\n" +
+	"\x05other\x18\" \x03(\v2\x13.namedzone.v1.RawKVR\x05otherB\f\n" +
+	"\n" +
+	"_recursionB\x0e\n" +
+	"\f_minimal_anyB\x0e\n" +
+	"\f_max_recordsB\x12\n" +
+	"\x10_allow_new_zones\"[\n" +
+	"\bPrefetch\x12\x18\n" +
+	"\atrigger\x18\x01 \x01(\x05R\atrigger\x12%\n" +
+	"\veligibility\x18\x02 \x01(\x05H\x00R\veligibility\x88\x01\x01B\x0e\n" +
+	"\f_eligibility\"c\n" +
+	"\x0fResponsePadding\x121\n" +
+	"\aclients\x18\x01 \x03(\v2\x17.namedzone.v1.MatchTermR\aclients\x12\x1d\n" +
+	"\n" +
+	"block_size\x18\x02 \x01(\x05R\tblockSize\"\x7f\n" +
+	"\x06Listen\x12\x17\n" +
+	"\x04port\x18\x01 \x01(\x05H\x00R\x04port\x88\x01\x01\x12\x10\n" +
+	"\x03tls\x18\x02 \x01(\tR\x03tls\x12\x12\n" +
+	"\x04http\x18\x03 \x01(\tR\x04http\x12-\n" +
+	"\x05addrs\x18\x04 \x03(\v2\x17.namedzone.v1.MatchTermR\x05addrsB\a\n" +
+	"\x05_port\"Y\n" +
+	"\tForwarder\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x17\n" +
+	"\x04port\x18\x02 \x01(\x05H\x00R\x04port\x88\x01\x01\x12\x10\n" +
+	"\x03tls\x18\x03 \x01(\tR\x03tlsB\a\n" +
+	"\x05_port\"C\n" +
+	"\fTrustAnchors\x123\n" +
+	"\x05items\x18\x01 \x03(\v2\x1d.namedzone.v1.TrustAnchorItemR\x05items\"\xd7\x01\n" +
+	"\x0fTrustAnchorItem\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05flags\x18\x03 \x01(\x05R\x05flags\x12\x1a\n" +
+	"\bprotocol\x18\x04 \x01(\x05R\bprotocol\x12\x17\n" +
+	"\akey_tag\x18\x05 \x01(\x05R\x06keyTag\x12\x1c\n" +
+	"\talgorithm\x18\x06 \x01(\x05R\talgorithm\x12\x1f\n" +
+	"\vdigest_type\x18\a \x01(\x05R\n" +
+	"digestType\x12\x12\n" +
+	"\x04data\x18\b \x01(\tR\x04data\"J\n" +
+	"\n" +
+	"RRsetOrder\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05order\x18\x03 \x01(\tR\x05order\"-\n" +
+	"\x05RawKV\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03raw\x18\x02 \x01(\tR\x03raw\"\x93\a\n" +
+	"\x04View\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05class\x18\x02 \x01(\tR\x05class\x12<\n" +
+	"\rmatch_clients\x18\x03 \x03(\v2\x17.namedzone.v1.MatchTermR\fmatchClients\x12F\n" +
+	"\x12match_destinations\x18\x04 \x03(\v2\x17.namedzone.v1.MatchTermR\x11matchDestinations\x12!\n" +
+	"\trecursion\x18\x05 \x01(\bH\x00R\trecursion\x88\x01\x01\x12?\n" +
+	"\rtrust_anchors\x18\x06 \x01(\v2\x1a.namedzone.v1.TrustAnchorsR\ftrustAnchors\x12(\n" +
+	"\x05zones\x18\a \x03(\v2\x12.namedzone.v1.ZoneR\x05zones\x121\n" +
+	"\bincludes\x18\b \x03(\v2\x15.namedzone.v1.IncludeR\bincludes\x122\n" +
+	"\bprefetch\x18\t \x01(\v2\x16.namedzone.v1.PrefetchR\bprefetch\x12-\n" +
+	"\x12qname_minimization\x18\n" +
+	" \x01(\tR\x11qnameMinimization\x12+\n" +
+	"\x11minimal_responses\x18\v \x01(\tR\x10minimalResponses\x12$\n" +
+	"\vminimal_any\x18\f \x01(\bH\x01R\n" +
+	"minimalAny\x88\x01\x01\x12H\n" +
+	"\x10response_padding\x18\r \x01(\v2\x1d.namedzone.v1.ResponsePaddingR\x0fresponsePadding\x125\n" +
+	"\flmdb_mapsize\x18\x0e \x01(\v2\x12.namedzone.v1.SizeR\vlmdbMapsize\x12$\n" +
+	"\vmax_records\x18\x0f \x01(\x05H\x02R\n" +
+	"maxRecords\x88\x01\x01\x12+\n" +
+	"\x0fallow_new_zones\x18\x10 \x01(\bH\x03R\rallowNewZones\x88\x01\x01\x125\n" +
+	"\x14match_recursive_only\x18\x11 \x01(\bH\x04R\x12matchRecursiveOnly\x88\x01\x01B\f\n" +
+	"\n" +
+	"_recursionB\x0e\n" +
+	"\f_minimal_anyB\x0e\n" +
+	"\f_max_recordsB\x12\n" +
+	"\x10_allow_new_zonesB\x17\n" +
+	"\x15_match_recursive_only\"\xaf\x05\n" +
+	"\x04Zone\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05class\x18\x02 \x01(\tR\x05class\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
+	"\x04file\x18\x04 \x01(\tR\x04file\x12#\n" +
+	"\rprimaries_ref\x18\x05 \x01(\tR\fprimariesRef\x12<\n" +
+	"\tprimaries\x18\x06 \x03(\v2\x1e.namedzone.v1.RemoteServerItemR\tprimaries\x127\n" +
+	"\n" +
+	"forwarders\x18\a \x03(\v2\x17.namedzone.v1.ForwarderR\n" +
+	"forwarders\x12\x18\n" +
+	"\aforward\x18\b \x01(\tR\aforward\x12:\n" +
+	"\fallow_update\x18\t \x03(\v2\x17.namedzone.v1.MatchTermR\vallowUpdate\x12>\n" +
+	"\x0eallow_transfer\x18\n" +
+	" \x03(\v2\x17.namedzone.v1.MatchTermR\rallowTransfer\x12?\n" +
+	"\valso_notify\x18\v \x03(\v2\x1e.namedzone.v1.RemoteServerItemR\n" +
+	"alsoNotify\x12#\n" +
+	"\rdnssec_policy\x18\f \x01(\tR\fdnssecPolicy\x12+\n" +
+	"\x11masterfile_format\x18\r \x01(\tR\x10masterfileFormat\x12)\n" +
+	"\x10masterfile_style\x18\x0e \x01(\tR\x0fmasterfileStyle\x12$\n" +
+	"\vmax_records\x18\x0f \x01(\x05H\x00R\n" +
+	"maxRecords\x88\x01\x01\x12\x16\n" +
+	"\x06notify\x18\x10 \x01(\tR\x06notify\x12\x17\n" +
+	"\ain_view\x18\x11 \x01(\tR\x06inViewB\x0e\n" +
+	"\f_max_recordsB(Z&github.com/dlukt/namedzone/namedzonepbb\x06proto3"
+
+var (
+	file_namedzone_proto_rawDescOnce sync.Once
+	file_namedzone_proto_rawDescData []byte
+)
+
+func file_namedzone_proto_rawDescGZIP() []byte {
+	file_namedzone_proto_rawDescOnce.Do(func() {
+		file_namedzone_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_namedzone_proto_rawDesc), len(file_namedzone_proto_rawDesc)))
+	})
+	return file_namedzone_proto_rawDescData
+}
+
+var file_namedzone_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_namedzone_proto_goTypes = []any{
+	(*Config)(nil),              // 0: namedzone.v1.Config
+	(*Include)(nil),             // 1: namedzone.v1.Include
+	(*ACL)(nil),                 // 2: namedzone.v1.ACL
+	(*MatchTerm)(nil),           // 3: namedzone.v1.MatchTerm
+	(*Key)(nil),                 // 4: namedzone.v1.Key
+	(*KeyStore)(nil),            // 5: namedzone.v1.KeyStore
+	(*RemoteServers)(nil),       // 6: namedzone.v1.RemoteServers
+	(*RemoteServerItem)(nil),    // 7: namedzone.v1.RemoteServerItem
+	(*TLS)(nil),                 // 8: namedzone.v1.TLS
+	(*HTTP)(nil),                // 9: namedzone.v1.HTTP
+	(*Controls)(nil),            // 10: namedzone.v1.Controls
+	(*ControlInet)(nil),         // 11: namedzone.v1.ControlInet
+	(*ControlUnix)(nil),         // 12: namedzone.v1.ControlUnix
+	(*Logging)(nil),             // 13: namedzone.v1.Logging
+	(*LogChannel)(nil),          // 14: namedzone.v1.LogChannel
+	(*LogSeverity)(nil),         // 15: namedzone.v1.LogSeverity
+	(*LogFileDest)(nil),         // 16: namedzone.v1.LogFileDest
+	(*LogSyslogDest)(nil),       // 17: namedzone.v1.LogSyslogDest
+	(*LogCategory)(nil),         // 18: namedzone.v1.LogCategory
+	(*Size)(nil),                // 19: namedzone.v1.Size
+	(*ServerIdent)(nil),         // 20: namedzone.v1.ServerIdent
+	(*Options)(nil),             // 21: namedzone.v1.Options
+	(*Prefetch)(nil),            // 22: namedzone.v1.Prefetch
+	(*ResponsePadding)(nil),     // 23: namedzone.v1.ResponsePadding
+	(*Listen)(nil),              // 24: namedzone.v1.Listen
+	(*Forwarder)(nil),           // 25: namedzone.v1.Forwarder
+	(*TrustAnchors)(nil),        // 26: namedzone.v1.TrustAnchors
+	(*TrustAnchorItem)(nil),     // 27: namedzone.v1.TrustAnchorItem
+	(*RRsetOrder)(nil),          // 28: namedzone.v1.RRsetOrder
+	(*RawKV)(nil),               // 29: namedzone.v1.RawKV
+	(*View)(nil),                // 30: namedzone.v1.View
+	(*Zone)(nil),                // 31: namedzone.v1.Zone
+	(*durationpb.Duration)(nil), // 32: google.protobuf.Duration
+}
+var file_namedzone_proto_depIdxs = []int32{
+	1,  // 0: namedzone.v1.Config.includes:type_name -> namedzone.v1.Include
+	2,  // 1: namedzone.v1.Config.acls:type_name -> namedzone.v1.ACL
+	4,  // 2: namedzone.v1.Config.keys:type_name -> namedzone.v1.Key
+	5,  // 3: namedzone.v1.Config.key_stores:type_name -> namedzone.v1.KeyStore
+	6,  // 4: namedzone.v1.Config.remote_servers:type_name -> namedzone.v1.RemoteServers
+	8,  // 5: namedzone.v1.Config.tls:type_name -> namedzone.v1.TLS
+	9,  // 6: namedzone.v1.Config.http:type_name -> namedzone.v1.HTTP
+	10, // 7: namedzone.v1.Config.controls:type_name -> namedzone.v1.Controls
+	13, // 8: namedzone.v1.Config.logging:type_name -> namedzone.v1.Logging
+	21, // 9: namedzone.v1.Config.options:type_name -> namedzone.v1.Options
+	26, // 10: namedzone.v1.Config.trust_anchors:type_name -> namedzone.v1.TrustAnchors
+	30, // 11: namedzone.v1.Config.views:type_name -> namedzone.v1.View
+	31, // 12: namedzone.v1.Config.zones:type_name -> namedzone.v1.Zone
+	3,  // 13: namedzone.v1.ACL.elements:type_name -> namedzone.v1.MatchTerm
+	3,  // 14: namedzone.v1.MatchTerm.nested:type_name -> namedzone.v1.MatchTerm
+	7,  // 15: namedzone.v1.RemoteServers.servers:type_name -> namedzone.v1.RemoteServerItem
+	11, // 16: namedzone.v1.Controls.inet:type_name -> namedzone.v1.ControlInet
+	12, // 17: namedzone.v1.Controls.unix:type_name -> namedzone.v1.ControlUnix
+	3,  // 18: namedzone.v1.ControlInet.allow:type_name -> namedzone.v1.MatchTerm
+	14, // 19: namedzone.v1.Logging.channels:type_name -> namedzone.v1.LogChannel
+	18, // 20: namedzone.v1.Logging.categories:type_name -> namedzone.v1.LogCategory
+	16, // 21: namedzone.v1.LogChannel.file:type_name -> namedzone.v1.LogFileDest
+	17, // 22: namedzone.v1.LogChannel.syslog:type_name -> namedzone.v1.LogSyslogDest
+	15, // 23: namedzone.v1.LogChannel.severity:type_name -> namedzone.v1.LogSeverity
+	19, // 24: namedzone.v1.LogFileDest.size:type_name -> namedzone.v1.Size
+	3,  // 25: namedzone.v1.Options.allow_query:type_name -> namedzone.v1.MatchTerm
+	3,  // 26: namedzone.v1.Options.allow_transfer:type_name -> namedzone.v1.MatchTerm
+	3,  // 27: namedzone.v1.Options.allow_update:type_name -> namedzone.v1.MatchTerm
+	24, // 28: namedzone.v1.Options.listen_on:type_name -> namedzone.v1.Listen
+	24, // 29: namedzone.v1.Options.listen_on_v6:type_name -> namedzone.v1.Listen
+	25, // 30: namedzone.v1.Options.forwarders:type_name -> namedzone.v1.Forwarder
+	28, // 31: namedzone.v1.Options.rrset_order:type_name -> namedzone.v1.RRsetOrder
+	22, // 32: namedzone.v1.Options.prefetch:type_name -> namedzone.v1.Prefetch
+	23, // 33: namedzone.v1.Options.response_padding:type_name -> namedzone.v1.ResponsePadding
+	19, // 34: namedzone.v1.Options.max_cache_size:type_name -> namedzone.v1.Size
+	32, // 35: namedzone.v1.Options.max_cache_ttl:type_name -> google.protobuf.Duration
+	32, // 36: namedzone.v1.Options.max_ncache_ttl:type_name -> google.protobuf.Duration
+	19, // 37: namedzone.v1.Options.max_journal_size:type_name -> namedzone.v1.Size
+	19, // 38: namedzone.v1.Options.lmdb_mapsize:type_name -> namedzone.v1.Size
+	20, // 39: namedzone.v1.Options.version:type_name -> namedzone.v1.ServerIdent
+	20, // 40: namedzone.v1.Options.hostname:type_name -> namedzone.v1.ServerIdent
+	20, // 41: namedzone.v1.Options.server_id:type_name -> namedzone.v1.ServerIdent
+	29, // 42: namedzone.v1.Options.other:type_name -> namedzone.v1.RawKV
+	3,  // 43: namedzone.v1.ResponsePadding.clients:type_name -> namedzone.v1.MatchTerm
+	3,  // 44: namedzone.v1.Listen.addrs:type_name -> namedzone.v1.MatchTerm
+	27, // 45: namedzone.v1.TrustAnchors.items:type_name -> namedzone.v1.TrustAnchorItem
+	3,  // 46: namedzone.v1.View.match_clients:type_name -> namedzone.v1.MatchTerm
+	3,  // 47: namedzone.v1.View.match_destinations:type_name -> namedzone.v1.MatchTerm
+	26, // 48: namedzone.v1.View.trust_anchors:type_name -> namedzone.v1.TrustAnchors
+	31, // 49: namedzone.v1.View.zones:type_name -> namedzone.v1.Zone
+	1,  // 50: namedzone.v1.View.includes:type_name -> namedzone.v1.Include
+	22, // 51: namedzone.v1.View.prefetch:type_name -> namedzone.v1.Prefetch
+	23, // 52: namedzone.v1.View.response_padding:type_name -> namedzone.v1.ResponsePadding
+	19, // 53: namedzone.v1.View.lmdb_mapsize:type_name -> namedzone.v1.Size
+	7,  // 54: namedzone.v1.Zone.primaries:type_name -> namedzone.v1.RemoteServerItem
+	25, // 55: namedzone.v1.Zone.forwarders:type_name -> namedzone.v1.Forwarder
+	3,  // 56: namedzone.v1.Zone.allow_update:type_name -> namedzone.v1.MatchTerm
+	3,  // 57: namedzone.v1.Zone.allow_transfer:type_name -> namedzone.v1.MatchTerm
+	7,  // 58: namedzone.v1.Zone.also_notify:type_name -> namedzone.v1.RemoteServerItem
+	59, // [59:59] is the sub-list for method output_type
+	59, // [59:59] is the sub-list for method input_type
+	59, // [59:59] is the sub-list for extension type_name
+	59, // [59:59] is the sub-list for extension extendee
+	0,  // [0:59] is the sub-list for field type_name
+}
+
+func init() { file_namedzone_proto_init() }
+func file_namedzone_proto_init() {
+	if File_namedzone_proto != nil {
+		return
+	}
+	file_namedzone_proto_msgTypes[7].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[8].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[9].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[11].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[12].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[14].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[15].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[16].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[21].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[22].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[24].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[25].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[30].OneofWrappers = []any{}
+	file_namedzone_proto_msgTypes[31].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_namedzone_proto_rawDesc), len(file_namedzone_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   32,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_namedzone_proto_goTypes,
+		DependencyIndexes: file_namedzone_proto_depIdxs,
+		MessageInfos:      file_namedzone_proto_msgTypes,
+	}.Build()
+	File_namedzone_proto = out.File
+	file_namedzone_proto_goTypes = nil
+	file_namedzone_proto_depIdxs = nil
+}
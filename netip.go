@@ -0,0 +1,218 @@
+// File: pkg/namedzone/netip.go
+package namedzone
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// The IP* types are an opt-in view of the address-bearing types with
+// addresses held as net/netip values. Convert with the IP() methods, which
+// fail on malformed addresses, and back with the matching methods on the IP*
+// types once edits are done.
+
+// IPMatchTerm is MatchTerm with Address parsed into a prefix. A bare address
+// becomes a full-length prefix (/32 or /128).
+type IPMatchTerm struct {
+	Not    bool          `json:"not,omitempty"`
+	Prefix netip.Prefix  `json:"prefix,omitzero"`
+	Key    string        `json:"key,omitempty"`
+	ACLRef string        `json:"aclRef,omitempty"`
+	Nested []IPMatchTerm `json:"nested,omitempty"`
+}
+
+// IPForwarder is Forwarder with a parsed address.
+type IPForwarder struct {
+	Addr netip.Addr `json:"addr"`
+	Port *int       `json:"port,omitempty"`
+	TLS  string     `json:"tls,omitempty"`
+}
+
+// IPRemoteServer is RemoteServerItem with a parsed address. Entries that name
+// another remote-servers list instead of an address carry it in Ref.
+type IPRemoteServer struct {
+	Addr netip.Addr `json:"addr,omitzero"`
+	Ref  string     `json:"ref,omitempty"`
+	Port *int       `json:"port,omitempty"`
+	Key  string     `json:"key,omitempty"`
+	TLS  string     `json:"tls,omitempty"`
+}
+
+// IPControlInet is ControlInet with a parsed address. The wildcard "*" is
+// represented by the zero Addr.
+type IPControlInet struct {
+	Addr     netip.Addr    `json:"addr,omitzero"`
+	Port     *int          `json:"port,omitempty"`
+	Allow    []IPMatchTerm `json:"allow"`
+	Keys     []string      `json:"keys,omitempty"`
+	ReadOnly *bool         `json:"readOnly,omitempty"`
+}
+
+// IPListen is Listen with a parsed address match list.
+type IPListen struct {
+	Port  *int          `json:"port,omitempty"`
+	TLS   string        `json:"tls,omitempty"`
+	HTTP  string        `json:"http,omitempty"`
+	Addrs []IPMatchTerm `json:"addrs"`
+}
+
+// ParseMatchPrefix parses an address or prefix as written in an
+// address_match_list, including BIND's abbreviated IPv4 forms ("10/8").
+func ParseMatchPrefix(s string) (netip.Prefix, error) {
+	addr, bits, hasBits := strings.Cut(strings.TrimSpace(s), "/")
+	if !strings.Contains(addr, ":") {
+		if n := strings.Count(addr, "."); n < 3 {
+			addr += strings.Repeat(".0", 3-n)
+		}
+	}
+	a, err := netip.ParseAddr(addr)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("namedzone: invalid address %q", s)
+	}
+	if !hasBits {
+		return netip.PrefixFrom(a, a.BitLen()), nil
+	}
+	p, err := netip.ParsePrefix(a.String() + "/" + bits)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("namedzone: invalid prefix %q", s)
+	}
+	return p, nil
+}
+
+// formatMatchPrefix writes full-length prefixes as plain addresses.
+func formatMatchPrefix(p netip.Prefix) string {
+	if p.Bits() == p.Addr().BitLen() {
+		return p.Addr().String()
+	}
+	return p.String()
+}
+
+// IP converts the term (and nested terms) to its netip form.
+func (t MatchTerm) IP() (IPMatchTerm, error) {
+	out := IPMatchTerm{Not: t.Not, Key: t.Key, ACLRef: t.ACLRef}
+	if t.Address != "" {
+		p, err := ParseMatchPrefix(t.Address)
+		if err != nil {
+			return IPMatchTerm{}, err
+		}
+		out.Prefix = p
+	}
+	nested, err := ipMatchList(t.Nested)
+	if err != nil {
+		return IPMatchTerm{}, err
+	}
+	out.Nested = nested
+	return out, nil
+}
+
+// MatchTerm converts back to the string form.
+func (t IPMatchTerm) MatchTerm() MatchTerm {
+	out := MatchTerm{Not: t.Not, Key: t.Key, ACLRef: t.ACLRef}
+	if t.Prefix.IsValid() {
+		out.Address = formatMatchPrefix(t.Prefix)
+	}
+	for _, n := range t.Nested {
+		out.Nested = append(out.Nested, n.MatchTerm())
+	}
+	return out
+}
+
+func ipMatchList(terms []MatchTerm) ([]IPMatchTerm, error) {
+	var out []IPMatchTerm
+	for _, t := range terms {
+		it, err := t.IP()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, nil
+}
+
+func matchList(terms []IPMatchTerm) []MatchTerm {
+	var out []MatchTerm
+	for _, t := range terms {
+		out = append(out, t.MatchTerm())
+	}
+	return out
+}
+
+// IP converts the forwarder to its netip form.
+func (f Forwarder) IP() (IPForwarder, error) {
+	a, err := netip.ParseAddr(f.Address)
+	if err != nil {
+		return IPForwarder{}, fmt.Errorf("namedzone: invalid forwarder address %q", f.Address)
+	}
+	return IPForwarder{Addr: a, Port: f.Port, TLS: f.TLS}, nil
+}
+
+// Forwarder converts back to the string form.
+func (f IPForwarder) Forwarder() Forwarder {
+	return Forwarder{Address: f.Addr.String(), Port: f.Port, TLS: f.TLS}
+}
+
+// IP converts the item to its netip form. Anything that does not look like an
+// address is treated as a reference to a remote-servers list.
+func (it RemoteServerItem) IP() (IPRemoteServer, error) {
+	out := IPRemoteServer{Port: it.Port, Key: it.Key, TLS: it.TLS}
+	if a, err := netip.ParseAddr(it.Address); err == nil {
+		out.Addr = a
+		return out, nil
+	}
+	if strings.Contains(it.Address, ":") || strings.Trim(it.Address, "0123456789.") == "" {
+		return IPRemoteServer{}, fmt.Errorf("namedzone: invalid server address %q", it.Address)
+	}
+	out.Ref = trimQuotes(it.Address)
+	return out, nil
+}
+
+// RemoteServerItem converts back to the string form.
+func (it IPRemoteServer) RemoteServerItem() RemoteServerItem {
+	out := RemoteServerItem{Address: it.Ref, Port: it.Port, Key: it.Key, TLS: it.TLS}
+	if it.Addr.IsValid() {
+		out.Address = it.Addr.String()
+	}
+	return out
+}
+
+// IP converts the channel to its netip form.
+func (ci ControlInet) IP() (IPControlInet, error) {
+	out := IPControlInet{Port: ci.Port, Keys: ci.Keys, ReadOnly: ci.ReadOnly}
+	if ci.Address != "*" {
+		a, err := netip.ParseAddr(ci.Address)
+		if err != nil {
+			return IPControlInet{}, fmt.Errorf("namedzone: invalid control address %q", ci.Address)
+		}
+		out.Addr = a
+	}
+	allow, err := ipMatchList(ci.Allow)
+	if err != nil {
+		return IPControlInet{}, err
+	}
+	out.Allow = allow
+	return out, nil
+}
+
+// ControlInet converts back to the string form.
+func (ci IPControlInet) ControlInet() ControlInet {
+	out := ControlInet{Address: "*", Port: ci.Port, Allow: matchList(ci.Allow), Keys: ci.Keys, ReadOnly: ci.ReadOnly}
+	if ci.Addr.IsValid() {
+		out.Address = ci.Addr.String()
+	}
+	return out
+}
+
+// IP converts the listener to its netip form.
+func (l Listen) IP() (IPListen, error) {
+	addrs, err := ipMatchList(l.Addrs)
+	if err != nil {
+		return IPListen{}, err
+	}
+	return IPListen{Port: l.Port, TLS: l.TLS, HTTP: l.HTTP, Addrs: addrs}, nil
+}
+
+// Listen converts back to the string form.
+func (l IPListen) Listen() Listen {
+	return Listen{Port: l.Port, TLS: l.TLS, HTTP: l.HTTP, Addrs: matchList(l.Addrs)}
+}
@@ -0,0 +1,219 @@
+// File: pkg/namedzone/nzf.go
+package namedzone
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// NewZoneFile is a new-zones file (<view>.nzf) in which named keeps the
+// zones added with `rndc addzone` when allow-new-zones is on. Builds of
+// named with LMDB keep them in a .nzd database instead, which is not read.
+type NewZoneFile struct {
+	View  string `json:"view"` // "_default" for the default view
+	Path  string `json:"path"`
+	Zones []Zone `json:"zones,omitempty"`
+}
+
+const nzfHeader = "# New zone file for view: %s\n" +
+	"# This file contains configuration for zones added by\n" +
+	"# the 'rndc addzone' command. DO NOT EDIT BY HAND.\n"
+
+var safeFileName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// NZFName returns the file name named uses for the new zones of view: the
+// view name when it is safe as a file name, else the hex SHA-256 of it.
+func NZFName(view string) string {
+	if view == "" {
+		view = "_default"
+	}
+	if safeFileName.MatchString(view) && view != "." && view != ".." {
+		return view + ".nzf"
+	}
+	sum := sha256.Sum256([]byte(view))
+	return hex.EncodeToString(sum[:]) + ".nzf"
+}
+
+// ReadNZF parses the new-zones file at path. The view is taken from the
+// header named writes; view may be given for files without one.
+func ReadNZF(path, view string) (*NewZoneFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := nc.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	n := &NewZoneFile{View: view, Path: path}
+	for _, line := range strings.Split(string(b), "\n") {
+		if v, ok := strings.CutPrefix(line, "# New zone file for view: "); ok {
+			n.View = strings.TrimSpace(v)
+			break
+		}
+	}
+	ld := &loader{src: f.Bytes()}
+	for _, node := range f.Nodes {
+		if st, ok := node.(*nc.Stmt); ok && st.Keyword == "zone" {
+			z := ld.parseZone(st)
+			z.stmt, z.origin = nil, ""
+			n.Zones = append(n.Zones, z)
+		}
+	}
+	return n, nil
+}
+
+// Bytes renders the file as named writes it.
+func (n *NewZoneFile) Bytes() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, nzfHeader, n.View)
+	for _, z := range n.Zones {
+		b.WriteString(strings.Join(strings.Fields(source(buildZone(z))), " "))
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+// Save writes the file to Path, replacing it atomically. named reads it only
+// on startup or reconfig, and rewrites it on addzone and delzone, so edit it
+// only while named is stopped or not adding zones.
+func (n *NewZoneFile) Save() error {
+	return osFS{}.WriteFile(n.Path, n.Bytes(), filePerm(n.Path))
+}
+
+// NZFDir returns the directory named keeps new-zones files in:
+// new-zones-directory, else the working directory.
+func (c *Config) NZFDir() string {
+	if c.Options != nil {
+		if raw, ok := c.Options.Get("new-zones-directory"); ok {
+			return c.inDirectory(trimQuotes(raw))
+		}
+		return c.Options.Directory
+	}
+	return ""
+}
+
+// AllowsNewZones reports whether named accepts rndc addzone in view (the
+// default view when empty): the view's allow-new-zones, else the option's,
+// else no.
+func (c *Config) AllowsNewZones(view string) bool {
+	if v := c.FindView(view); view != "" && v != nil && v.AllowNewZones != nil {
+		return *v.AllowNewZones
+	}
+	return c.Options != nil && c.Options.AllowNewZones != nil && *c.Options.AllowNewZones
+}
+
+// HasRuntimeZones reports whether zones may exist that named.conf does not
+// define: allow-new-zones is on for some view (or, without views, the
+// default view), so rndc addzone may have added zones at runtime.
+func (c *Config) HasRuntimeZones() bool {
+	if len(c.Views) == 0 {
+		return c.AllowsNewZones("")
+	}
+	for _, v := range c.Views {
+		if c.AllowsNewZones(v.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewZones reads the new-zones file of every view (of the default view when
+// there are none) from NZFDir. Views without a file are skipped, as are
+// views that do not allow new zones: named does not load their file.
+func (c *Config) NewZones() ([]NewZoneFile, error) {
+	views := []string{"_default"}
+	if len(c.Views) > 0 {
+		views = views[:0]
+		for _, v := range c.Views {
+			views = append(views, v.Name)
+		}
+	}
+	var out []NewZoneFile
+	for _, v := range views {
+		if !c.AllowsNewZones(NewZoneFile{View: v}.inView()) {
+			continue
+		}
+		n, err := ReadNZF(filepath.Join(c.NZFDir(), NZFName(v)), v)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *n)
+	}
+	return out, nil
+}
+
+// WithNewZones returns a clone of c with the zones of its new-zones files
+// added, i.e. the zones named actually serves. c is not changed.
+func (c *Config) WithNewZones() (*Config, error) {
+	nzfs, err := c.NewZones()
+	if err != nil {
+		return nil, err
+	}
+	k := c.Clone()
+	for _, n := range nzfs {
+		k.addNewZones(n)
+	}
+	return k, nil
+}
+
+// MigrateNewZones moves the zones of the new-zones files into c and empties
+// the files, so the zones are managed in named.conf from then on. It fails
+// with a *ConflictError, changing nothing, when a zone is already defined.
+// Save c before named next reads its config (reconfig or restart): until
+// then the zones are defined nowhere.
+func (c *Config) MigrateNewZones() ([]ZoneChange, error) {
+	nzfs, err := c.NewZones()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nzfs {
+		for _, z := range n.Zones {
+			if c.GetZoneInView(n.inView(), z.Name) != nil {
+				return nil, &ConflictError{Kind: "zone", Name: z.Name, Msg: "defined in " + n.Path + " and in the config"}
+			}
+		}
+	}
+	var out []ZoneChange
+	for _, n := range nzfs {
+		for _, z := range n.Zones {
+			out = append(out, ZoneChange{View: n.inView(), Name: z.Name, Change: Added})
+		}
+		c.addNewZones(n)
+		n.Zones = nil
+		if err := n.Save(); err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// inView is the view of c the file belongs to; "" for top-level zones.
+func (n NewZoneFile) inView() string {
+	if n.View == "_default" {
+		return ""
+	}
+	return n.View
+}
+
+func (c *Config) addNewZones(n NewZoneFile) {
+	for _, z := range n.Zones {
+		if v := n.inView(); v != "" {
+			c.UpsertZoneInView(v, z)
+		} else {
+			c.UpsertZone(z)
+		}
+	}
+}
@@ -2,7 +2,11 @@
 package namedzone
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,6 +22,9 @@ func boolWord(b bool) string {
 	return "no"
 }
 
+// quote wraps s in double quotes; trimQuotes removes them again.
+func quote(s string) string { return "\"" + s + "\"" }
+
 func quoteEach(ss []string) []string {
 	out := make([]string, len(ss))
 	for i, s := range ss {
@@ -26,16 +33,150 @@ func quoteEach(ss []string) []string {
 	return out
 }
 
+// stmtValue returns everything after the statement keyword with the trailing
+// ';' removed. Block bodies are re-inlined as "{ ... }" so list-valued
+// statements (allow-query { ... }) read the same as simple ones. Comments
+// are dropped.
+func stmtValue(st *namedconf.Stmt) string {
+	head := headText(st)
+	// keywords may be written quoted
+	kw, h := st.Keyword, strings.TrimPrefix(head, "\"")
+	if kw != "" && len(h) >= len(kw) && strings.EqualFold(h[:len(kw)], kw) {
+		rest := h[len(kw):]
+		if len(h) < len(head) {
+			rest = strings.TrimPrefix(rest, "\"")
+		}
+		head = rest
+	}
+	head = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stripComments(head)), ";"))
+	if !st.HasBlock {
+		return head
+	}
+	var b strings.Builder
+	for _, n := range st.Body {
+		switch x := n.(type) {
+		case *namedconf.Raw:
+			b.WriteString(x.Text)
+		case *namedconf.Stmt:
+			if !x.Modified && x.RawText != "" {
+				b.WriteString(x.RawText)
+			} else {
+				b.WriteString(strings.TrimSpace(x.HeadRaw) + ";")
+			}
+		}
+	}
+	body := "{ " + strings.Join(strings.Fields(stripComments(b.String())), " ") + " }"
+	if t := strings.TrimSpace(stripComments(st.TrailingAfterR)); t != "" {
+		body += " " + t
+	}
+	if head == "" {
+		return body
+	}
+	return head + " " + body
+}
+
+// headText returns HeadRaw without the comments and blank lines the parser
+// attaches in front of a statement.
+func headText(st *namedconf.Stmt) string {
+	h := strings.TrimSpace(st.HeadRaw)
+	for {
+		switch {
+		case strings.HasPrefix(h, "#"), strings.HasPrefix(h, "//"):
+			i := strings.IndexByte(h, '\n')
+			if i < 0 {
+				return ""
+			}
+			h = strings.TrimSpace(h[i+1:])
+		case strings.HasPrefix(h, "/*"):
+			i := strings.Index(h, "*/")
+			if i < 0 {
+				return ""
+			}
+			h = strings.TrimSpace(h[i+2:])
+		default:
+			return h
+		}
+	}
+}
+
+// tokenize splits raw named.conf text into words, quoted strings (quotes
+// kept), and the punctuation tokens "{", "}", ";" and "!".
+func tokenize(raw string) []string {
+	var toks []string
+	i := 0
+	for i < len(raw) {
+		c := raw[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '{' || c == '}' || c == ';' || c == '!':
+			toks = append(toks, string(c))
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(raw) && raw[j] != '"' {
+				if raw[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(raw))
+			toks = append(toks, raw[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(raw) && !strings.ContainsRune(" \t\n\r{};\"", rune(raw[j])) {
+				j++
+			}
+			toks = append(toks, raw[i:j])
+			i = j
+		}
+	}
+	return toks
+}
+
+// groupEnd returns the index of the "}" closing the group that opens at
+// toks[i]. If toks[i] is not "{", i is returned (a single-token value).
+func groupEnd(toks []string, i int) int {
+	if i >= len(toks) {
+		return len(toks) - 1
+	}
+	if toks[i] != "{" {
+		return i
+	}
+	depth := 0
+	for j := i; j < len(toks); j++ {
+		switch toks[j] {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(toks) - 1
+}
+
+func firstField(raw string) string {
+	if f := strings.Fields(raw); len(f) > 0 {
+		return f[0]
+	}
+	return ""
+}
+
+// parseBoolPtr accepts named's boolean spellings: yes/no, true/false, 1/0.
 func parseBoolPtr(raw string) *bool {
 	w := strings.Fields(raw)
 	if len(w) == 0 {
 		return nil
 	}
 	switch strings.ToLower(w[0]) {
-	case "yes":
+	case "yes", "true", "1":
 		t := true
 		return &t
-	case "no":
+	case "no", "false", "0":
 		f := false
 		return &f
 	}
@@ -74,7 +215,7 @@ var rxHeadName = regexp.MustCompile(`^[a-z-]+\s+\"([^\"]+)\"`)
 var rxHeadClass = regexp.MustCompile(`^[a-z-]+\s+\"[^\"]+\"\s+([A-Za-z]+)`)
 
 func headNameAfter(s *namedconf.Stmt, kw string) string {
-	h := strings.TrimSpace(s.HeadRaw)
+	h := headText(s)
 	if m := rxHeadName.FindStringSubmatch(h); len(m) == 2 {
 		return m[1]
 	}
@@ -86,13 +227,143 @@ func headNameAfter(s *namedconf.Stmt, kw string) string {
 }
 
 func headClassAfter(s *namedconf.Stmt, kw string) string {
-	h := strings.TrimSpace(s.HeadRaw)
+	h := headText(s)
 	if m := rxHeadClass.FindStringSubmatch(h); len(m) == 2 {
 		return m[1]
 	}
 	return ""
 }
 
+// --- runtime file paths ---
+
+// pathOptions lists the path-valued options in render order.
+var pathOptions = []string{
+	"pid-file", "statistics-file", "dump-file", "secroots-file",
+	"recursing-file", "memstatistics-file", "lock-file",
+}
+
+// pathField returns the Options field holding the path option kw.
+func (o *Options) pathField(kw string) *string {
+	switch kw {
+	case "pid-file":
+		return &o.PIDFile
+	case "statistics-file":
+		return &o.StatisticsFile
+	case "dump-file":
+		return &o.DumpFile
+	case "secroots-file":
+		return &o.SecrootsFile
+	case "recursing-file":
+		return &o.RecursingFile
+	case "memstatistics-file":
+		return &o.MemstatisticsFile
+	case "lock-file":
+		return &o.LockFile
+	}
+	panic("namedzone: not a path option: " + kw)
+}
+
+// --- server identity ---
+
+func parseServerIdent(raw string) *ServerIdent {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "\"") {
+		return &ServerIdent{Value: trimQuotes(raw)}
+	}
+	return &ServerIdent{Keyword: firstField(raw)}
+}
+
+func serializeServerIdent(id ServerIdent) string {
+	if id.Keyword != "" {
+		return id.Keyword
+	}
+	return quote(id.Value)
+}
+
+// --- resolver tuning ---
+
+// parsePrefetch parses "trigger [eligibility]"; nil when malformed.
+func parsePrefetch(raw string) *Prefetch {
+	w := strings.Fields(raw)
+	if len(w) == 0 || len(w) > 2 {
+		return nil
+	}
+	t, err := strconv.Atoi(w[0])
+	if err != nil {
+		return nil
+	}
+	p := &Prefetch{Trigger: t}
+	if len(w) == 2 {
+		e, err := strconv.Atoi(w[1])
+		if err != nil {
+			return nil
+		}
+		p.Eligibility = &e
+	}
+	return p
+}
+
+// parseMinimalResponses normalizes the boolean spellings of
+// minimal-responses to yes and no.
+func parseMinimalResponses(raw string) MinimalResponses {
+	w := firstField(raw)
+	switch strings.ToLower(w) {
+	case "yes", "true", "1":
+		return MinimalResponsesYes
+	case "no", "false", "0":
+		return MinimalResponsesNo
+	}
+	return MinimalResponses(w)
+}
+
+// parseResponsePadding parses "{ acl } block-size N"; nil when malformed.
+func parseResponsePadding(raw string) *ResponsePadding {
+	toks := tokenize(raw)
+	i := slices.Index(toks, "{")
+	if i < 0 {
+		return nil
+	}
+	end := groupEnd(toks, i)
+	rp := &ResponsePadding{Clients: matchTerms(toks[i+1 : end])}
+	rest := toks[end+1:]
+	if len(rest) < 2 || rest[0] != "block-size" {
+		return nil
+	}
+	n, err := strconv.Atoi(rest[1])
+	if err != nil {
+		return nil
+	}
+	rp.BlockSize = n
+	return rp
+}
+
+func serializeResponsePadding(rp ResponsePadding) string {
+	return serializeMatchList(rp.Clients) + " block-size " + strconv.Itoa(rp.BlockSize)
+}
+
+// resolverTuning returns the statements for the resolver tuning fields of
+// options or a view.
+func resolverTuning(p *Prefetch, q QNameMinimization, m MinimalResponses, minAny *bool) []string {
+	var out []string
+	if p != nil {
+		s := "prefetch " + strconv.Itoa(p.Trigger)
+		if p.Eligibility != nil {
+			s += " " + strconv.Itoa(*p.Eligibility)
+		}
+		out = append(out, s)
+	}
+	if q != "" {
+		out = append(out, "qname-minimization "+string(q))
+	}
+	if m != "" {
+		out = append(out, "minimal-responses "+string(m))
+	}
+	if minAny != nil {
+		out = append(out, "minimal-any "+boolWord(*minAny))
+	}
+	return out
+}
+
 // --- RRset order ---
 
 func parseRRsetOrder(st *namedconf.Stmt) []RRsetOrder {
@@ -164,55 +435,56 @@ func parseMatchList(raw string) []MatchTerm {
 	return parseMatchListFromBodyRaw(raw)
 }
 
+// parseMatchListFromBody reads the address match list of a block statement
+// such as acl.
 func parseMatchListFromBody(s *namedconf.Stmt) []MatchTerm {
-	if len(s.Body) == 0 {
+	if !s.HasBlock {
 		return nil
 	}
-	if r, ok := s.Body[0].(*namedconf.Raw); ok {
-		return parseMatchListFromBodyRaw(r.Text)
-	}
-	var out []MatchTerm
-	for _, n := range s.Body {
-		if st, ok := n.(*namedconf.Stmt); ok {
-			out = append(out, MatchTerm{Address: strings.TrimSpace(strings.TrimSuffix(st.HeadRaw, ";"))})
-		}
-	}
-	return out
+	return parseMatchListFromBodyRaw(stmtValue(s))
 }
 
+// parseMatchListFromBodyRaw parses the first { ... } group in raw, or raw
+// itself as a bare element list when it has no braces.
 func parseMatchListFromBodyRaw(raw string) []MatchTerm {
-	raw = strings.TrimSpace(raw)
-	if strings.HasPrefix(raw, "{") {
-		raw = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(raw, "{"), "}"))
+	toks := tokenize(stripComments(raw))
+	if i := slices.Index(toks, "{"); i >= 0 {
+		toks = toks[i+1 : groupEnd(toks, i)]
 	}
-	parts := strings.Split(raw, ";")
+	return matchTerms(toks)
+}
+
+func matchTerms(toks []string) []MatchTerm {
 	var out []MatchTerm
-	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		if p == "" {
+	for i := 0; i < len(toks); i++ {
+		if toks[i] == ";" {
 			continue
 		}
 		mt := MatchTerm{}
-		if strings.HasPrefix(p, "!") {
+		if toks[i] == "!" {
 			mt.Not = true
-			p = strings.TrimSpace(strings.TrimPrefix(p, "!"))
-		}
-		if strings.HasPrefix(p, "key ") {
-			mt.Key = trimQuotes(strings.TrimPrefix(p, "key "))
-			out = append(out, mt)
-			continue
+			i++
 		}
-		if strings.HasPrefix(p, "{") {
-			mt.Nested = parseMatchListFromBodyRaw(p)
-			out = append(out, mt)
-			continue
+		if i >= len(toks) {
+			break
 		}
-		if strings.Contains(p, "/") || strings.Count(p, ":") > 1 || strings.Count(p, ".") == 3 {
+		switch p := toks[i]; {
+		case p == "{":
+			end := groupEnd(toks, i)
+			mt.Nested = matchTerms(toks[i+1 : end])
+			i = end
+		case p == "key" && i+1 < len(toks):
+			i++
+			mt.Key = trimQuotes(toks[i])
+		case strings.Contains(p, "/") || strings.Count(p, ":") > 1 || strings.Count(p, ".") == 3:
 			mt.Address = p
-		} else {
+		default:
 			mt.ACLRef = trimQuotes(p)
 		}
 		out = append(out, mt)
+		for i+1 < len(toks) && toks[i+1] != ";" {
+			i++
+		}
 	}
 	return out
 }
@@ -224,33 +496,40 @@ func serializeMatchList(terms []MatchTerm) string {
 		if i > 0 {
 			b.WriteString(" ")
 		}
-		if t.Not {
-			b.WriteString("!")
-		}
-		switch {
-		case len(t.Nested) > 0:
-			b.WriteString(serializeMatchList(t.Nested))
-		case t.Key != "":
-			b.WriteString("key \"")
-			b.WriteString(t.Key)
-			b.WriteString("\"")
-		case t.Address != "":
-			b.WriteString(t.Address)
-		case t.ACLRef != "":
-			if needsQuotes(t.ACLRef) {
-				b.WriteString("\"")
-				b.WriteString(t.ACLRef)
-				b.WriteString("\"")
-			} else {
-				b.WriteString(t.ACLRef)
-			}
-		}
+		b.WriteString(serializeMatchTerm(t))
 		b.WriteString(";")
 	}
 	b.WriteString(" }")
 	return b.String()
 }
 
+// serializeMatchTerm renders one list element without the trailing ';'.
+func serializeMatchTerm(t MatchTerm) string {
+	var b strings.Builder
+	if t.Not {
+		b.WriteString("!")
+	}
+	switch {
+	case len(t.Nested) > 0:
+		b.WriteString(serializeMatchList(t.Nested))
+	case t.Key != "":
+		b.WriteString("key \"")
+		b.WriteString(t.Key)
+		b.WriteString("\"")
+	case t.Address != "":
+		b.WriteString(t.Address)
+	case t.ACLRef != "":
+		if needsQuotes(t.ACLRef) {
+			b.WriteString("\"")
+			b.WriteString(t.ACLRef)
+			b.WriteString("\"")
+		} else {
+			b.WriteString(t.ACLRef)
+		}
+	}
+	return b.String()
+}
+
 func needsQuotes(s string) bool { return strings.ContainsAny(s, ".-* ") }
 
 // --- listen/forwarders helpers ---
@@ -426,35 +705,36 @@ func serializeRemoteServerList(items []RemoteServerItem) string {
 
 // --- controls ---
 
-func parseControlInet(raw string) ControlInet {
+// parseControlInet parses the arguments of an inet control channel:
+// address [port n] allow { ... } [keys { ... }] [read-only bool].
+func parseControlInet(args string) ControlInet {
 	ci := ControlInet{}
-	raw = strings.TrimPrefix(raw, "inet ")
-	if idx := strings.Index(raw, " allow "); idx >= 0 {
-		allow := raw[idx+len(" allow "):]
-		ci.Allow = parseMatchList(allow)
-		raw = strings.TrimSpace(raw[:idx])
-	}
-	if idx := strings.Index(raw, " keys "); idx >= 0 {
-		keys := raw[idx+len(" keys "):]
-		ci.Keys = parseStringList(keys)
-		raw = strings.TrimSpace(raw[:idx])
-	}
-	if strings.Contains(raw, " read-only ") {
-		parts := strings.Split(raw, " read-only ")
-		b := parseBoolPtr(strings.TrimSpace(parts[1]))
-		ci.ReadOnly = b
-		raw = strings.TrimSpace(parts[0])
-	}
-	fields := strings.Fields(raw)
-	if len(fields) > 0 {
-		ci.Address = fields[0]
+	toks := tokenize(args)
+	if len(toks) > 0 {
+		ci.Address = toks[0]
 	}
-	for i := 1; i < len(fields); i++ {
-		if fields[i] == "port" && i+1 < len(fields) {
-			if n, err := strconv.Atoi(fields[i+1]); err == nil {
-				ci.Port = &n
+	for i := 1; i < len(toks); i++ {
+		switch toks[i] {
+		case "port":
+			if i+1 < len(toks) {
+				if n, err := strconv.Atoi(toks[i+1]); err == nil {
+					ci.Port = &n
+				}
+				i++
+			}
+		case "allow":
+			end := groupEnd(toks, i+1)
+			ci.Allow = parseMatchList(strings.Join(toks[i+1:end+1], " "))
+			i = end
+		case "keys":
+			end := groupEnd(toks, i+1)
+			ci.Keys = parseStringList(strings.Join(toks[i+1:end+1], " "))
+			i = end
+		case "read-only":
+			if i+1 < len(toks) {
+				ci.ReadOnly = parseBoolPtr(toks[i+1])
+				i++
 			}
-			i++
 		}
 	}
 	return ci
@@ -475,32 +755,47 @@ func serializeControlInet(ci ControlInet) string {
 	return s
 }
 
-func parseControlUnix(raw string) ControlUnix {
+// parseControlUnix parses the arguments of a unix control channel:
+// "path" perm n owner n group n [keys { ... }] [read-only bool].
+func parseControlUnix(args string) ControlUnix {
 	cu := ControlUnix{}
-	raw = strings.TrimPrefix(raw, "unix ")
-	if idx := strings.Index(raw, " keys "); idx >= 0 {
-		keys := raw[idx+len(" keys "):]
-		cu.Keys = parseStringList(keys)
-		raw = strings.TrimSpace(raw[:idx])
-	}
-	if strings.Contains(raw, " read-only ") {
-		parts := strings.Split(raw, " read-only ")
-		b := parseBoolPtr(strings.TrimSpace(parts[1]))
-		cu.ReadOnly = b
-		raw = strings.TrimSpace(parts[0])
-	}
-	fields := strings.Fields(raw)
-	if len(fields) >= 8 {
-		cu.Path = trimQuotes(fields[0])
-		cu.Perm, _ = strconv.Atoi(fields[2])
-		cu.Owner, _ = strconv.Atoi(fields[4])
-		cu.Group, _ = strconv.Atoi(fields[6])
+	toks := tokenize(args)
+	if len(toks) > 0 {
+		cu.Path = trimQuotes(toks[0])
+	}
+	for i := 1; i < len(toks); i++ {
+		switch toks[i] {
+		case "perm", "owner", "group":
+			if i+1 < len(toks) {
+				n, _ := strconv.Atoi(toks[i+1])
+				switch toks[i] {
+				case "perm":
+					// perm is conventionally octal (0600); base 0 honours the prefix.
+					p, _ := strconv.ParseInt(toks[i+1], 0, 32)
+					cu.Perm = int(p)
+				case "owner":
+					cu.Owner = n
+				case "group":
+					cu.Group = n
+				}
+				i++
+			}
+		case "keys":
+			end := groupEnd(toks, i+1)
+			cu.Keys = parseStringList(strings.Join(toks[i+1:end+1], " "))
+			i = end
+		case "read-only":
+			if i+1 < len(toks) {
+				cu.ReadOnly = parseBoolPtr(toks[i+1])
+				i++
+			}
+		}
 	}
 	return cu
 }
 
 func serializeControlUnix(cu ControlUnix) string {
-	s := "unix \"" + cu.Path + "\" perm " + strconv.Itoa(cu.Perm) + " owner " + strconv.Itoa(cu.Owner) + " group " + strconv.Itoa(cu.Group)
+	s := "unix \"" + cu.Path + "\" perm " + fmt.Sprintf("%#o", cu.Perm) + " owner " + strconv.Itoa(cu.Owner) + " group " + strconv.Itoa(cu.Group)
 	if len(cu.Keys) > 0 {
 		s += " keys { " + strings.Join(quoteEach(cu.Keys), "; ") + "; }"
 	}
@@ -509,3 +804,59 @@ func serializeControlUnix(cu ControlUnix) string {
 	}
 	return s
 }
+
+// --- trust anchors ---
+
+// dnssecAlgorithms names the DNSSEC algorithm numbers named supports.
+var dnssecAlgorithms = map[int]string{
+	5: "RSASHA1", 7: "NSEC3RSASHA1", 8: "RSASHA256", 10: "RSASHA512",
+	13: "ECDSAP256SHA256", 14: "ECDSAP384SHA384", 15: "ED25519", 16: "ED448",
+}
+
+// dsDigestLengths maps DS digest types to the hex length of their digest.
+var dsDigestLengths = map[int]int{
+	1: 40, // SHA-1
+	2: 64, // SHA-256
+	4: 96, // SHA-384
+}
+
+// Validate checks the anchor kind, algorithm and digest numbers and the
+// shape of the key/digest data.
+func (it TrustAnchorItem) Validate() error {
+	if it.Name == "" {
+		return &ValueError{Path: "trust anchor", Msg: "empty name"}
+	}
+	if dnssecAlgorithms[it.Algorithm] == "" {
+		return &ValueError{Path: "trust anchor " + quote(it.Name), Msg: fmt.Sprintf("unsupported algorithm %d", it.Algorithm)}
+	}
+	switch it.Kind {
+	case AnchorStaticKey, AnchorInitialKey:
+		if it.Protocol != 3 {
+			return &ValueError{Path: "trust anchor " + quote(it.Name), Msg: fmt.Sprintf("protocol must be 3, got %d", it.Protocol)}
+		}
+		if _, err := base64.StdEncoding.DecodeString(it.Data); err != nil || it.Data == "" {
+			return &ValueError{Path: "trust anchor " + quote(it.Name), Msg: "key data is not valid base64"}
+		}
+	case AnchorStaticDS, AnchorInitialDS:
+		want, ok := dsDigestLengths[it.DigestType]
+		if !ok {
+			return &ValueError{Path: "trust anchor " + quote(it.Name), Msg: fmt.Sprintf("unsupported digest type %d", it.DigestType)}
+		}
+		if _, err := hex.DecodeString(it.Data); err != nil || len(it.Data) != want {
+			return &ValueError{Path: "trust anchor " + quote(it.Name), Msg: fmt.Sprintf("digest must be %d hex characters", want)}
+		}
+	default:
+		return &ValueError{Path: "trust anchor " + quote(it.Name), Msg: fmt.Sprintf("unknown kind %q", it.Kind)}
+	}
+	return nil
+}
+
+func serializeTrustAnchorItem(it TrustAnchorItem) string {
+	var a, b, c int
+	if it.Kind.IsDS() {
+		a, b, c = it.KeyTag, it.Algorithm, it.DigestType
+	} else {
+		a, b, c = it.Flags, it.Protocol, it.Algorithm
+	}
+	return fmt.Sprintf("\"%s\" %s %d %d %d \"%s\"", it.Name, it.Kind, a, b, c, it.Data)
+}
@@ -0,0 +1,511 @@
+// File: pkg/namedzone/patch.go
+package namedzone
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// patchOp is one RFC 6902 operation. Value is empty when the member is
+// absent and holds "null" for an explicit null.
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch to the JSON projection of c
+// and loads the result back into the typed fields. All operations (add,
+// remove, replace, move, copy, test) are supported; the patch is applied
+// atomically, so c is unchanged when any operation fails. Items keep their
+// origin and legacy spellings when they survive the patch under the same
+// name. The AST picks up the changes on the next Apply or Save.
+func (c *Config) ApplyJSONPatch(patch []byte) error {
+	var ops []patchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return &ValueError{Path: "patch", Value: string(patch), Msg: err.Error()}
+	}
+	doc, err := c.jsonDoc()
+	if err != nil {
+		return err
+	}
+	for i, op := range ops {
+		if doc, err = applyPatchOp(doc, op); err != nil {
+			return &ValueError{Path: "patch[" + strconv.Itoa(i) + "] " + op.Op + " " + op.Path, Msg: err.Error()}
+		}
+	}
+	return c.recordIf(c.loadJSONDoc(doc), "ApplyJSONPatch", "")
+}
+
+// mergeKeyed lists the JSON fields holding collections that ApplyMergePatch
+// merges by name instead of replacing wholesale.
+var mergeKeyed = map[string]bool{
+	"acls": true, "keys": true, "keyStores": true, "remoteServers": true,
+	"tls": true, "http": true, "views": true, "zones": true,
+	"channels": true, "categories": true,
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch to the JSON projection
+// of c. Objects merge recursively and null deletes a member, as in the RFC.
+// Named collections (acls, keys, keyStores, remoteServers, tls, http, views,
+// zones, and logging channels and categories) are merged by "name" rather
+// than replaced: a patch element updates the item with the same name or is
+// appended, and an element with "$delete": true removes it. Other arrays are
+// replaced. The patch is atomic, like ApplyJSONPatch.
+func (c *Config) ApplyMergePatch(patch []byte) error {
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return &ValueError{Path: "patch", Value: string(patch), Msg: err.Error()}
+	}
+	doc, err := c.jsonDoc()
+	if err != nil {
+		return err
+	}
+	doc, err = mergePatch(doc, p, "")
+	if err != nil {
+		return &ValueError{Path: "patch", Msg: err.Error()}
+	}
+	return c.recordIf(c.loadJSONDoc(doc), "ApplyMergePatch", "")
+}
+
+func mergePatch(target, patch any, field string) (any, error) {
+	if arr, ok := patch.([]any); ok && mergeKeyed[field] {
+		cur, _ := target.([]any)
+		return mergeByName(cur, arr, field)
+	}
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch, nil
+	}
+	tm, ok := target.(map[string]any)
+	if !ok {
+		tm = map[string]any{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+			continue
+		}
+		merged, err := mergePatch(tm[k], v, k)
+		if err != nil {
+			return nil, err
+		}
+		tm[k] = merged
+	}
+	return tm, nil
+}
+
+func mergeByName(cur, patch []any, field string) ([]any, error) {
+	for _, e := range patch {
+		pe, ok := e.(map[string]any)
+		name, named := pe["name"].(string)
+		if !ok || !named {
+			return nil, fmt.Errorf("%s: elements must be objects with a name", field)
+		}
+		i := slices.IndexFunc(cur, func(x any) bool {
+			m, ok := x.(map[string]any)
+			return ok && m["name"] == name
+		})
+		if del, _ := pe["$delete"].(bool); del {
+			if i >= 0 {
+				cur = slices.Delete(cur, i, i+1)
+			}
+			continue
+		}
+		delete(pe, "$delete")
+		if i < 0 {
+			merged, err := mergePatch(nil, pe, "")
+			if err != nil {
+				return nil, err
+			}
+			cur = append(cur, merged)
+			continue
+		}
+		merged, err := mergePatch(cur[i], pe, "")
+		if err != nil {
+			return nil, err
+		}
+		cur[i] = merged
+	}
+	return cur, nil
+}
+
+// jsonDoc returns the JSON projection of c as generic values.
+func (c *Config) jsonDoc() (any, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(b)
+}
+
+func decodeJSON(b []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// loadJSONDoc replaces the typed fields of c with doc.
+func (c *Config) loadJSONDoc(doc any) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var n Config
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&n); err != nil {
+		return &ValueError{Path: "config", Msg: err.Error()}
+	}
+	c.replaceTyped(&n)
+	return nil
+}
+
+func applyPatchOp(doc any, op patchOp) (any, error) {
+	value := func() (any, error) {
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("missing value")
+		}
+		return decodeJSON(op.Value)
+	}
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, op.Path, v)
+	case "remove":
+		doc, _, err := pointerRemove(doc, op.Path)
+		return doc, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = pointerRemove(doc, op.Path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, op.Path, v)
+	case "move":
+		if op.Path == op.From || strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %q into itself", op.From)
+		}
+		doc, v, err := pointerRemove(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, op.Path, v)
+	case "copy":
+		v, err := pointerGet(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, op.Path, deepCopyJSON(v))
+	case "test":
+		want, err := value()
+		if err != nil {
+			return nil, err
+		}
+		got, err := pointerGet(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(got, want) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// splitPointer parses an RFC 6901 JSON Pointer.
+func splitPointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("invalid pointer %q", p)
+	}
+	parts := strings.Split(p[1:], "/")
+	for i, s := range parts {
+		parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+	}
+	return parts, nil
+}
+
+func arrayIndex(tok string, n int, appendOK bool) (int, error) {
+	if appendOK && tok == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(tok)
+	if err != nil || i < 0 || (tok != "0" && tok[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+	limit := n - 1
+	if appendOK {
+		limit = n
+	}
+	if i > limit {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+func pointerGet(doc any, p string) (any, error) {
+	toks, err := splitPointer(p)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, t := range toks {
+		switch x := cur.(type) {
+		case map[string]any:
+			v, ok := x[t]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", p)
+			}
+			cur = v
+		case []any:
+			i, err := arrayIndex(t, len(x), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = x[i]
+		default:
+			return nil, fmt.Errorf("path %q not found", p)
+		}
+	}
+	return cur, nil
+}
+
+// pointerAdd returns doc with v added at p, per RFC 6902 "add".
+func pointerAdd(doc any, p string, v any) (any, error) {
+	toks, err := splitPointer(p)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return v, nil
+	}
+	return setIn(doc, toks, func(parent any, last string) (any, error) {
+		switch x := parent.(type) {
+		case map[string]any:
+			x[last] = v
+			return x, nil
+		case []any:
+			i, err := arrayIndex(last, len(x), true)
+			if err != nil {
+				return nil, err
+			}
+			x = append(x, nil)
+			copy(x[i+1:], x[i:])
+			x[i] = v
+			return x, nil
+		}
+		return nil, fmt.Errorf("path %q not found", p)
+	})
+}
+
+// pointerRemove returns doc without the value at p, and that value.
+func pointerRemove(doc any, p string) (any, any, error) {
+	toks, err := splitPointer(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(toks) == 0 {
+		return nil, doc, nil
+	}
+	var removed any
+	doc, err = setIn(doc, toks, func(parent any, last string) (any, error) {
+		switch x := parent.(type) {
+		case map[string]any:
+			v, ok := x[last]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", p)
+			}
+			removed = v
+			delete(x, last)
+			return x, nil
+		case []any:
+			i, err := arrayIndex(last, len(x), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = x[i]
+			return append(x[:i], x[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("path %q not found", p)
+	})
+	return doc, removed, err
+}
+
+// setIn walks to the parent of toks and replaces it with edit's result, so
+// that slices can grow or shrink.
+func setIn(cur any, toks []string, edit func(parent any, last string) (any, error)) (any, error) {
+	if len(toks) == 1 {
+		return edit(cur, toks[0])
+	}
+	switch x := cur.(type) {
+	case map[string]any:
+		child, ok := x[toks[0]]
+		if !ok {
+			return nil, fmt.Errorf("path element %q not found", toks[0])
+		}
+		v, err := setIn(child, toks[1:], edit)
+		if err != nil {
+			return nil, err
+		}
+		x[toks[0]] = v
+		return x, nil
+	case []any:
+		i, err := arrayIndex(toks[0], len(x), false)
+		if err != nil {
+			return nil, err
+		}
+		v, err := setIn(x[i], toks[1:], edit)
+		if err != nil {
+			return nil, err
+		}
+		x[i] = v
+		return x, nil
+	}
+	return nil, fmt.Errorf("path element %q not found", toks[0])
+}
+
+func deepCopyJSON(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(x))
+		for k, e := range x {
+			m[k] = deepCopyJSON(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(x))
+		for i, e := range x {
+			s[i] = deepCopyJSON(e)
+		}
+		return s
+	}
+	return v
+}
+
+func jsonEqual(a, b any) bool {
+	na, aok := a.(json.Number)
+	nb, bok := b.(json.Number)
+	if aok && bok {
+		fa, _ := na.Float64()
+		fb, _ := nb.Float64()
+		return fa == fb
+	}
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// replaceTyped installs the typed fields of n in c. Hidden per-item state
+// (origin file, legacy spellings, source statement) is carried over from the
+// item of the same name in c.
+func (c *Config) replaceTyped(n *Config) {
+	carry(c.Includes, n.Includes, func(i Include) string { return i.Path }, func(dst *Include, src Include) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+	})
+	carry(c.ACLs, n.ACLs, func(a ACL) string { return a.Name }, func(dst *ACL, src ACL) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+	})
+	carry(c.Keys, n.Keys, func(k Key) string { return k.Name }, func(dst *Key, src Key) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+	})
+	carry(c.KeyStores, n.KeyStores, func(k KeyStore) string { return k.Name }, func(dst *KeyStore, src KeyStore) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+	})
+	carry(c.RemoteServers, n.RemoteServers, func(r RemoteServers) string { return r.Name }, func(dst *RemoteServers, src RemoteServers) {
+		dst.stmt, dst.origin, dst.keyword = src.stmt, src.origin, src.keyword
+	})
+	carry(c.TLS, n.TLS, func(t TLS) string { return t.Name }, func(dst *TLS, src TLS) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+	})
+	carry(c.HTTP, n.HTTP, func(h HTTP) string { return h.Name }, func(dst *HTTP, src HTTP) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+	})
+	carry(c.TrustAnchors, n.TrustAnchors, func(TrustAnchors) string { return "" }, func(dst *TrustAnchors, src TrustAnchors) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+	})
+	carry(c.Views, n.Views, func(v View) string { return v.Name }, func(dst *View, src View) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+		carryZones(src.Zones, dst.Zones)
+	})
+	carryZones(c.Zones, n.Zones)
+	if c.Controls != nil && n.Controls != nil {
+		n.Controls.stmt, n.Controls.origin = c.Controls.stmt, c.Controls.origin
+	}
+	if c.Logging != nil && n.Logging != nil {
+		n.Logging.stmt, n.Logging.origin = c.Logging.stmt, c.Logging.origin
+	}
+	if c.Options != nil && n.Options != nil {
+		n.Options.stmt, n.Options.origin = c.Options.stmt, c.Options.origin
+		n.Options.quotedNone = c.Options.quotedNone
+	}
+
+	c.Includes, c.ACLs, c.Keys, c.KeyStores = n.Includes, n.ACLs, n.Keys, n.KeyStores
+	c.RemoteServers, c.TLS, c.HTTP = n.RemoteServers, n.TLS, n.HTTP
+	c.Controls, c.Logging, c.Options = n.Controls, n.Logging, n.Options
+	c.TrustAnchors, c.Views, c.Zones = n.TrustAnchors, n.Views, n.Zones
+	c.ModernizeKeywords = n.ModernizeKeywords
+}
+
+func carryZones(old, cur []Zone) {
+	carry(old, cur, func(z Zone) string { return z.Name }, func(dst *Zone, src Zone) {
+		dst.stmt, dst.origin = src.stmt, src.origin
+		dst.typeWord, dst.primariesKW = src.typeWord, src.primariesKW
+	})
+}
+
+// carry copies hidden state from old items to the first unclaimed item of
+// cur with the same key.
+func carry[T any](old, cur []T, key func(T) string, keep func(dst *T, src T)) {
+	byKey := map[string][]T{}
+	for _, it := range old {
+		byKey[key(it)] = append(byKey[key(it)], it)
+	}
+	for i := range cur {
+		k := key(cur[i])
+		if q := byKey[k]; len(q) > 0 {
+			keep(&cur[i], q[0])
+			byKey[k] = q[1:]
+		}
+	}
+}
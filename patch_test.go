@@ -0,0 +1,69 @@
+// File: pkg/namedzone/patch_test.go
+package namedzone
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestJSONPatchNullValue tells an explicit null value apart from a missing
+// one.
+func TestJSONPatchNullValue(t *testing.T) {
+	c, err := FromReader(strings.NewReader(`options { version "9.18"; directory "/var/named"; };`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ApplyJSONPatch([]byte(`[{"op":"add","path":"/options/version","value":null}]`)); err != nil {
+		t.Fatalf("null value: %v", err)
+	}
+	if c.Options.Version != nil {
+		t.Errorf("version = %+v, want nil", c.Options.Version)
+	}
+	if err := c.ApplyJSONPatch([]byte(`[{"op":"replace","path":"/options/directory"}]`)); err == nil || !strings.Contains(err.Error(), "missing value") {
+		t.Errorf("missing value: err = %v", err)
+	}
+	if c.Options.Directory != "/var/named" {
+		t.Errorf("directory = %q after a failed patch", c.Options.Directory)
+	}
+}
+
+// TestPatchKeepsQuotedNone keeps a quoted "none" path, a file of that name,
+// quoted through edits that go through the JSON projection.
+func TestPatchKeepsQuotedNone(t *testing.T) {
+	const src = `options { directory "/var/named"; pid-file "none"; lock-file "none"; };
+zone "a" { type hint; file "a"; };`
+	for name, edit := range map[string]func(c *Config) error{
+		"json patch":  func(c *Config) error { return c.ApplyJSONPatch([]byte(`[{"op":"remove","path":"/zones/0"}]`)) },
+		"merge patch": func(c *Config) error { return c.ApplyMergePatch([]byte(`{"options":{"directory":"/srv/named"}}`)) },
+		"set path":    func(c *Config) error { return c.SetPath("options.directory", "/srv/named") },
+		"merge": func(c *Config) error {
+			return c.Merge(&Config{Zones: []Zone{{Name: "b", Type: ZoneHint, File: "b"}}}, MergeError)
+		},
+		"tx": func(c *Config) error {
+			tx, err := c.Begin()
+			if err != nil {
+				return err
+			}
+			tx.Config().Options.Directory = "/srv/named"
+			return tx.Commit()
+		},
+	} {
+		c, err := FromReader(strings.NewReader(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := edit(c); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		c.Options.Directory = "/srv/named/" // rebuild the options block
+		text, err := c.Render()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, w := range []string{`pid-file "none";`, `lock-file "none";`} {
+			if !strings.Contains(text, w) {
+				t.Errorf("%s: missing %s in\n%s", name, w, text)
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// File: pkg/namedzone/path.go
+package namedzone
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Paths address values in the JSON projection of a Config:
+//
+//	path     = segment { "." segment }
+//	segment  = field [ "[" selector "]" ]
+//	selector = name | "#" index
+//
+// field is a JSON field name ("options", "dnssecPolicy"); the singular forms
+// zone, view, acl, key, keyStore, remoteServer, channel and category are
+// accepted for their collections. A selector picks the element whose "name"
+// equals it (names may contain dots), or, with "#", the element at a
+// zero-based index. Examples:
+//
+//	options.recursion
+//	zone[example.com].dnssecPolicy
+//	view[internal].zone[example.com].allowTransfer
+//	options.forwarders[#0].address
+
+var pathAliases = map[string]string{
+	"zone": "zones", "view": "views", "acl": "acls", "key": "keys",
+	"keyStore": "keyStores", "remoteServer": "remoteServers",
+	"channel": "channels", "category": "categories",
+}
+
+type pathSeg struct {
+	field   string
+	sel     string
+	hasSel  bool
+	isIndex bool
+	index   int
+}
+
+func parsePath(p string) ([]pathSeg, error) {
+	var segs []pathSeg
+	for p != "" {
+		var s pathSeg
+		i := strings.IndexAny(p, ".[")
+		if i < 0 {
+			i = len(p)
+		}
+		s.field = p[:i]
+		if a, ok := pathAliases[s.field]; ok {
+			s.field = a
+		}
+		p = p[i:]
+		if strings.HasPrefix(p, "[") {
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [")
+			}
+			s.sel, s.hasSel = p[1:end], true
+			if n, ok := strings.CutPrefix(s.sel, "#"); ok {
+				idx, err := strconv.Atoi(n)
+				if err != nil || idx < 0 {
+					return nil, fmt.Errorf("invalid index %q", s.sel)
+				}
+				s.isIndex, s.index = true, idx
+			}
+			p = p[end+1:]
+		}
+		if s.field == "" {
+			return nil, fmt.Errorf("empty field name")
+		}
+		segs = append(segs, s)
+		if p != "" {
+			if p[0] != '.' || len(p) == 1 {
+				return nil, fmt.Errorf("expected '.' before %q", p)
+			}
+			p = p[1:]
+		}
+	}
+	if len(segs) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return segs, nil
+}
+
+// resolvePath maps a path to a JSON Pointer into doc. With create, missing
+// objects are added and a missing named element is appended.
+func resolvePath(doc any, segs []pathSeg, create bool) (string, error) {
+	var ptr strings.Builder
+	cur := doc
+	for n, s := range segs {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("%s: not an object", s.field)
+		}
+		ptr.WriteString("/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(s.field))
+		next, ok := m[s.field]
+		last := n == len(segs)-1
+		if !s.hasSel {
+			if !ok && !last {
+				if !create {
+					return "", fmt.Errorf("%s: not set", s.field)
+				}
+				next = map[string]any{}
+				m[s.field] = next
+			}
+			cur = next
+			continue
+		}
+		arr, _ := next.([]any)
+		i := -1
+		if s.isIndex {
+			if s.index < len(arr) {
+				i = s.index
+			}
+		} else {
+			i = slices.IndexFunc(arr, func(x any) bool {
+				e, ok := x.(map[string]any)
+				return ok && e["name"] == s.sel
+			})
+		}
+		if i < 0 {
+			if !create || s.isIndex {
+				return "", fmt.Errorf("%s[%s]: not found", s.field, s.sel)
+			}
+			arr = append(arr, map[string]any{"name": s.sel})
+			m[s.field] = arr
+			i = len(arr) - 1
+		}
+		ptr.WriteString("/" + strconv.Itoa(i))
+		cur = arr[i]
+	}
+	return ptr.String(), nil
+}
+
+// GetPath returns the value at path in the JSON projection of c, as
+// encoding/json would decode it into an any, except that integers are int64.
+func (c *Config) GetPath(path string) (any, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, &ValueError{Path: path, Msg: err.Error()}
+	}
+	doc, err := c.jsonDoc()
+	if err != nil {
+		return nil, err
+	}
+	ptr, err := resolvePath(doc, segs, false)
+	if err != nil {
+		return nil, &ValueError{Path: path, Msg: err.Error()}
+	}
+	v, err := pointerGet(doc, ptr)
+	if err != nil {
+		return nil, &ValueError{Path: path, Msg: "not set"}
+	}
+	return plainNumbers(v), nil
+}
+
+// SetPath stores value (anything encoding/json can marshal) at path,
+// creating intermediate objects and named collection elements as needed.
+// The change is applied to the typed fields like ApplyJSONPatch.
+func (c *Config) SetPath(path string, value any) error {
+	segs, err := parsePath(path)
+	if err != nil {
+		return &ValueError{Path: path, Msg: err.Error()}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return &ValueError{Path: path, Msg: err.Error()}
+	}
+	v, err := decodeJSON(b)
+	if err != nil {
+		return err
+	}
+	doc, err := c.jsonDoc()
+	if err != nil {
+		return err
+	}
+	ptr, err := resolvePath(doc, segs, true)
+	if err != nil {
+		return &ValueError{Path: path, Msg: err.Error()}
+	}
+	if last := segs[len(segs)-1]; last.hasSel {
+		if m, ok := v.(map[string]any); ok && !last.isIndex {
+			// keep the element addressable by the name used in the path
+			m["name"] = last.sel
+		}
+		// "add" inserts into arrays; drop the element it replaces
+		if doc, _, err = pointerRemove(doc, ptr); err != nil {
+			return &ValueError{Path: path, Msg: err.Error()}
+		}
+	}
+	if doc, err = pointerAdd(doc, ptr, v); err != nil {
+		return &ValueError{Path: path, Msg: err.Error()}
+	}
+	return c.recordIf(c.loadJSONDoc(doc), "SetPath", path)
+}
+
+// DeletePath removes the value at path. It reports whether anything was
+// removed; a missing path is not an error.
+func (c *Config) DeletePath(path string) (bool, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return false, &ValueError{Path: path, Msg: err.Error()}
+	}
+	doc, err := c.jsonDoc()
+	if err != nil {
+		return false, err
+	}
+	ptr, err := resolvePath(doc, segs, false)
+	if err != nil {
+		return false, nil
+	}
+	if doc, _, err = pointerRemove(doc, ptr); err != nil {
+		return false, nil
+	}
+	return true, c.recordIf(c.loadJSONDoc(doc), "DeletePath", path)
+}
+
+func plainNumbers(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]any:
+		for k, e := range x {
+			x[k] = plainNumbers(e)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = plainNumbers(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,173 @@
+// File: pkg/namedzone/position.go
+package namedzone
+
+import (
+	"bytes"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// Position locates a statement in the text of a config file as it currently
+// renders: unchanged since load, that is the file on disk.
+type Position struct {
+	File   string `json:"file,omitempty"` // empty for configs loaded from one file
+	Offset int    `json:"offset"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// PositionOf returns where the item addressed by path (GetPath syntax) is
+// written: the statement of the innermost zone, view or other block on the
+// path, at its keyword. It reports false for paths that name no item and
+// for items not yet written by Apply.
+func (c *Config) PositionOf(path string) (Position, bool) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return Position{}, false
+	}
+	st, origin := c.stmtAt(segs)
+	if st == nil {
+		return Position{}, false
+	}
+	return c.position(st, origin)
+}
+
+// stmtAt returns the statement and origin of the innermost item on a path.
+func (c *Config) stmtAt(segs []pathSeg) (*nc.Stmt, string) {
+	s := segs[0]
+	byName := func(name string) bool { return s.hasSel && !s.isIndex && s.sel == name }
+	at := func(i int) bool { return s.hasSel && s.isIndex && s.index == i }
+	switch s.field {
+	case "zones":
+		if i := firstMatch(len(c.Zones), func(i int) bool { return byName(c.Zones[i].Name) || at(i) }); i >= 0 {
+			return c.Zones[i].stmt, c.Zones[i].origin
+		}
+	case "views":
+		i := firstMatch(len(c.Views), func(i int) bool { return byName(c.Views[i].Name) || at(i) })
+		if i < 0 {
+			return nil, ""
+		}
+		v := &c.Views[i]
+		if len(segs) > 1 && segs[1].field == "zones" {
+			s = segs[1]
+			if j := firstMatch(len(v.Zones), func(j int) bool { return byName(v.Zones[j].Name) || at(j) }); j >= 0 {
+				z := v.Zones[j]
+				if z.origin == "" {
+					z.origin = v.origin
+				}
+				return z.stmt, z.origin
+			}
+		}
+		return v.stmt, v.origin
+	case "acls":
+		if i := firstMatch(len(c.ACLs), func(i int) bool { return byName(c.ACLs[i].Name) || at(i) }); i >= 0 {
+			return c.ACLs[i].stmt, c.ACLs[i].origin
+		}
+	case "keys":
+		if i := firstMatch(len(c.Keys), func(i int) bool { return byName(c.Keys[i].Name) || at(i) }); i >= 0 {
+			return c.Keys[i].stmt, c.Keys[i].origin
+		}
+	case "keyStores":
+		if i := firstMatch(len(c.KeyStores), func(i int) bool { return byName(c.KeyStores[i].Name) || at(i) }); i >= 0 {
+			return c.KeyStores[i].stmt, c.KeyStores[i].origin
+		}
+	case "remoteServers":
+		if i := firstMatch(len(c.RemoteServers), func(i int) bool { return byName(c.RemoteServers[i].Name) || at(i) }); i >= 0 {
+			return c.RemoteServers[i].stmt, c.RemoteServers[i].origin
+		}
+	case "tls":
+		if i := firstMatch(len(c.TLS), func(i int) bool { return byName(c.TLS[i].Name) || at(i) }); i >= 0 {
+			return c.TLS[i].stmt, c.TLS[i].origin
+		}
+	case "http":
+		if i := firstMatch(len(c.HTTP), func(i int) bool { return byName(c.HTTP[i].Name) || at(i) }); i >= 0 {
+			return c.HTTP[i].stmt, c.HTTP[i].origin
+		}
+	case "trustAnchors":
+		if i := firstMatch(len(c.TrustAnchors), at); i >= 0 {
+			return c.TrustAnchors[i].stmt, c.TrustAnchors[i].origin
+		}
+	case "includes":
+		if i := firstMatch(len(c.Includes), at); i >= 0 {
+			return c.Includes[i].stmt, c.Includes[i].origin
+		}
+	case "options":
+		if c.Options != nil {
+			return c.Options.stmt, c.Options.origin
+		}
+	case "controls":
+		if c.Controls != nil {
+			return c.Controls.stmt, c.Controls.origin
+		}
+	case "logging":
+		if c.Logging != nil {
+			return c.Logging.stmt, c.Logging.origin
+		}
+	}
+	return nil, ""
+}
+
+func firstMatch(n int, match func(i int) bool) int {
+	for i := range n {
+		if match(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// position finds st in the file it was placed in and returns where its
+// keyword is written.
+func (c *Config) position(st *nc.Stmt, origin string) (Position, bool) {
+	f, name := c.ast, ""
+	if c.files != nil {
+		name = c.placed(origin)
+		f = c.files[name]
+	}
+	if f == nil {
+		return Position{}, false
+	}
+	for i, n := range f.Nodes {
+		top, ok := n.(*nc.Stmt)
+		if !ok {
+			continue
+		}
+		rel, ok := offsetIn(top, st)
+		if !ok {
+			continue
+		}
+		before := (&nc.File{Nodes: f.Nodes[:i]}).Bytes()
+		text := append(before, source(top)...)
+		off := len(before) + rel
+		line := bytes.Count(text[:off], []byte("\n")) + 1
+		col := off - bytes.LastIndexByte(text[:off], '\n')
+		return Position{File: name, Offset: off, Line: line, Column: col}, true
+	}
+	return Position{}, false
+}
+
+// offsetIn returns the offset of the keyword of st within the text of top,
+// when st is top or a statement nested in it. A nested statement is only
+// located exactly while top keeps its original text; otherwise top's
+// position stands in for it.
+func offsetIn(top, st *nc.Stmt) (int, bool) {
+	if top == st {
+		return len(leadingComments(st)), true
+	}
+	if !top.HasBlock || !contains(top.Body, st) {
+		return 0, false
+	}
+	if top.Modified || st.Modified || st.Start() < top.Start() {
+		return len(leadingComments(top)), true
+	}
+	return st.Start() - top.Start() + len(leadingComments(st)), true
+}
+
+func contains(nodes []nc.Node, st *nc.Stmt) bool {
+	for _, n := range nodes {
+		if s, ok := n.(*nc.Stmt); ok && (s == st || s.HasBlock && contains(s.Body, st)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,361 @@
+// File: pkg/namedzone/project.go
+package namedzone
+
+import (
+	"bytes"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// tree tracks the files reached while following include statements.
+type tree struct {
+	fsys  fs.FS  // nil reads the OS filesystem
+	dir   string // base for relative include paths
+	files map[string]*nc.File
+	err   error
+}
+
+// resolve maps an include path to the key of the file in the tree. Within an
+// fs.FS, absolute include paths are taken relative to the FS root.
+func (t *tree) resolve(p string) string {
+	if t.fsys != nil {
+		if !path.IsAbs(p) {
+			p = path.Join(t.dir, p)
+		}
+		return strings.TrimPrefix(path.Clean(p), "/")
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(t.dir, p)
+	}
+	return filepath.Clean(p)
+}
+
+func (t *tree) parse(name string) (*nc.File, error) {
+	if t.fsys == nil {
+		return nc.ParseFile(name)
+	}
+	b, err := fs.ReadFile(t.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return nc.Parse(b)
+}
+
+// LoadTree parses the named.conf at path and every file it includes,
+// recursively, into a single Config. Statements from included files appear in
+// the typed model like any other; Origin() on each item reports the file it
+// came from. Relative include paths are resolved against the directory of
+// the root file. Include cycles are skipped with a warning.
+func LoadTree(path string) (*Config, error) {
+	cfg, _, err := LoadTreeWith(path, LoadOptions{})
+	return cfg, err
+}
+
+// LoadTreeWith is LoadTree with the warnings and strict mode of Load.
+func LoadTreeWith(path string, opts LoadOptions) (*Config, []Warning, error) {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !opts.Lock {
+		return loadTree(&tree{dir: filepath.Dir(root)}, root, opts)
+	}
+	lock, err := lockFile(root, opts.LockTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, warns, err := loadTree(&tree{dir: filepath.Dir(root)}, root, opts)
+	if err != nil {
+		_ = unlock(lock)
+		lock.Close()
+		return nil, warns, err
+	}
+	cfg.lock = lock
+	return cfg, warns, nil
+}
+
+func loadTree(t *tree, root string, opts LoadOptions) (*Config, []Warning, error) {
+	f, err := t.parse(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	t.files = map[string]*nc.File{root: f}
+	ld := &loader{file: root, src: f.Bytes(), log: opts.Logger, tree: t}
+	cfg := &Config{ast: f, logger: opts.Logger, root: root, files: t.files, fsys: t.fsys}
+	if t.fsys == nil {
+		cfg.savedAs = root
+	}
+	ld.loadNodes(cfg, f.Nodes)
+	if t.err != nil {
+		return nil, ld.warnings, t.err
+	}
+	return ld.finish(cfg, opts)
+}
+
+// follow parses the file named by an include statement and hands it to body
+// with the loader's origin switched to that file.
+func (ld *loader) follow(st *nc.Stmt, path string, body func(*nc.File)) {
+	t := ld.tree
+	if t.err != nil {
+		return
+	}
+	name := t.resolve(path)
+	if _, seen := t.files[name]; seen {
+		ld.warn(st, "include %q already loaded; skipped", path)
+		return
+	}
+	f, err := t.parse(name)
+	if err != nil {
+		t.err = err
+		return
+	}
+	t.files[name] = f
+	ld.logger().Debug("following include", "path", name, "from", ld.file)
+
+	file, src := ld.file, ld.src
+	ld.file, ld.src = name, f.Bytes()
+	body(f)
+	ld.file, ld.src = file, src
+}
+
+// Files lists the files a LoadTree config spans, root first. It is empty for
+// configs built with FromFile or Load.
+func (c *Config) Files() []string {
+	if c.files == nil {
+		return nil
+	}
+	out := []string{c.root}
+	for p := range c.files {
+		if p != c.root {
+			out = append(out, p)
+		}
+	}
+	slices.Sort(out[1:])
+	return out
+}
+
+// Origin reports the file an item was loaded from. It is empty for items
+// created in code and for configs loaded from a single file.
+func (z Zone) Origin() string          { return z.origin }
+func (v View) Origin() string          { return v.origin }
+func (a ACL) Origin() string           { return a.origin }
+func (k Key) Origin() string           { return k.origin }
+func (k KeyStore) Origin() string      { return k.origin }
+func (r RemoteServers) Origin() string { return r.origin }
+func (t TLS) Origin() string           { return t.origin }
+func (h HTTP) Origin() string          { return h.origin }
+func (i Include) Origin() string       { return i.origin }
+func (t TrustAnchors) Origin() string  { return t.origin }
+func (c Controls) Origin() string      { return c.origin }
+func (l Logging) Origin() string       { return l.origin }
+func (o Options) Origin() string       { return o.origin }
+
+func (z *Zone) ref() **nc.Stmt          { return &z.stmt }
+func (v *View) ref() **nc.Stmt          { return &v.stmt }
+func (a *ACL) ref() **nc.Stmt           { return &a.stmt }
+func (k *Key) ref() **nc.Stmt           { return &k.stmt }
+func (k *KeyStore) ref() **nc.Stmt      { return &k.stmt }
+func (r *RemoteServers) ref() **nc.Stmt { return &r.stmt }
+func (t *TLS) ref() **nc.Stmt           { return &t.stmt }
+func (h *HTTP) ref() **nc.Stmt          { return &h.stmt }
+func (i *Include) ref() **nc.Stmt       { return &i.stmt }
+func (t *TrustAnchors) ref() **nc.Stmt  { return &t.stmt }
+func (c *Controls) ref() **nc.Stmt      { return &c.stmt }
+func (l *Logging) ref() **nc.Stmt       { return &l.stmt }
+func (o *Options) ref() **nc.Stmt       { return &o.stmt }
+
+// SetTarget selects the file that receives new items (those without an
+// origin) when a LoadTree config is applied. It defaults to the root file.
+func (c *Config) SetTarget(name string) error {
+	key := path.Clean(name)
+	if c.fsys == nil {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return err
+		}
+		key = abs
+	}
+	if _, ok := c.files[key]; !ok {
+		return &ValueError{Path: "target", Value: name, Msg: "not a file of the loaded tree"}
+	}
+	c.target = key
+	return nil
+}
+
+// placed maps an item's origin to the file it is written to.
+func (c *Config) placed(origin string) string {
+	if origin != "" {
+		return origin
+	}
+	if c.target != "" {
+		return c.target
+	}
+	return c.root
+}
+
+// applyTree syncs every file of a LoadTree config with the items placed in it.
+func (c *Config) applyTree() {
+	c.settle()
+	for path, f := range c.files {
+		c.part(path).sync(c.syncer(f))
+	}
+	c.log().Debug("applied typed config to tree", "files", len(c.files), "zones", len(c.Zones), "views", len(c.Views))
+}
+
+// settle records the current target as the origin of items created in code,
+// so they stay in that file when the target changes later.
+func (c *Config) settle() {
+	t := c.placed("")
+	c.eachOrigin(func(origin *string) {
+		if *origin == "" {
+			*origin = t
+		}
+	})
+}
+
+// eachOrigin calls fn with the origin of every top-level item. View zones are
+// left alone: without an origin they live in their view.
+func (c *Config) eachOrigin(fn func(origin *string)) {
+	for i := range c.Includes {
+		fn(&c.Includes[i].origin)
+	}
+	for i := range c.ACLs {
+		fn(&c.ACLs[i].origin)
+	}
+	for i := range c.Keys {
+		fn(&c.Keys[i].origin)
+	}
+	for i := range c.KeyStores {
+		fn(&c.KeyStores[i].origin)
+	}
+	for i := range c.RemoteServers {
+		fn(&c.RemoteServers[i].origin)
+	}
+	for i := range c.TLS {
+		fn(&c.TLS[i].origin)
+	}
+	for i := range c.HTTP {
+		fn(&c.HTTP[i].origin)
+	}
+	for i := range c.TrustAnchors {
+		fn(&c.TrustAnchors[i].origin)
+	}
+	for i := range c.Zones {
+		fn(&c.Zones[i].origin)
+	}
+	for i := range c.Views {
+		fn(&c.Views[i].origin)
+	}
+	if c.Controls != nil {
+		fn(&c.Controls.origin)
+	}
+	if c.Logging != nil {
+		fn(&c.Logging.origin)
+	}
+	if c.Options != nil {
+		fn(&c.Options.origin)
+	}
+	for _, items := range c.custom {
+		for i := range items {
+			fn(&items[i].origin)
+		}
+	}
+}
+
+// part returns the subset of c that lives in path. Zones of a view that came
+// from a file included inside the view are top-level statements of that file.
+func (c *Config) part(path string) *Config {
+	p := &Config{
+		logger:        c.logger,
+		Includes:      inFile(c, c.Includes, path),
+		ACLs:          inFile(c, c.ACLs, path),
+		Keys:          inFile(c, c.Keys, path),
+		KeyStores:     inFile(c, c.KeyStores, path),
+		RemoteServers: inFile(c, c.RemoteServers, path),
+		TLS:           inFile(c, c.TLS, path),
+		HTTP:          inFile(c, c.HTTP, path),
+		TrustAnchors:  inFile(c, c.TrustAnchors, path),
+		Zones:         inFile(c, c.Zones, path),
+	}
+	if c.Controls != nil && c.placed(c.Controls.origin) == path {
+		p.Controls = c.Controls
+	}
+	if c.Logging != nil && c.placed(c.Logging.origin) == path {
+		p.Logging = c.Logging
+	}
+	if c.Options != nil && c.placed(c.Options.origin) == path {
+		p.Options = c.Options
+	}
+	for kw, items := range c.custom {
+		if mine := inFile(c, items, path); len(mine) > 0 {
+			if p.custom == nil {
+				p.custom = map[string][]customItem{}
+			}
+			p.custom[kw] = mine
+		}
+	}
+	for _, v := range c.Views {
+		home := c.placed(v.origin)
+		var own []Zone
+		for _, z := range v.Zones {
+			switch where := z.origin; {
+			case where == "" || where == home:
+				own = append(own, z)
+			case where == path:
+				p.Zones = append(p.Zones, z)
+			}
+		}
+		if home == path {
+			v.Zones = own
+			p.Views = append(p.Views, v)
+		}
+	}
+	return p
+}
+
+func inFile[T interface{ Origin() string }](c *Config, items []T, path string) []T {
+	var out []T
+	for _, it := range items {
+		if c.placed(it.Origin()) == path {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// output is a file Save writes.
+type output struct {
+	name string
+	data []byte
+}
+
+// outputs lists the files SaveFS writes to w for a root named name: the root
+// file first, then, for a LoadTree config, every included file whose content
+// changed, under the name it was loaded from. OS paths are written relative
+// to the root of any other WriteFS.
+func (c *Config) outputs(w fs.FS, name string) []output {
+	out := []output{{name: name, data: render(c.ast)}}
+	if c.files == nil {
+		return out
+	}
+	_, native := w.(osFS)
+	for _, key := range c.Files() {
+		if key == c.root {
+			continue
+		}
+		dst, data := key, render(c.files[key])
+		if c.fsys == nil && !native {
+			dst = strings.TrimPrefix(filepath.ToSlash(dst), "/")
+		}
+		if old, err := fs.ReadFile(w, dst); err == nil && bytes.Equal(old, data) {
+			continue
+		}
+		out = append(out, output{name: dst, data: data})
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+// File: pkg/namedzone/provision.go
+package namedzone
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	nc "github.com/dlukt/namedconf"
+	"github.com/dlukt/namedzone/zonefile"
+)
+
+// ProvisionOptions describe a new primary zone for ProvisionPrimaryZone.
+type ProvisionOptions struct {
+	// View receives the zone; empty for a top-level zone.
+	View string
+	// Zone holds the settings of the zone statement (allow-transfer,
+	// also-notify, dnssec-policy, ...). Name and Type are set by
+	// ProvisionPrimaryZone; an empty File becomes "db.<name>".
+	Zone Zone
+	// ConfigFile is the file of a LoadTree config that gets the zone
+	// statement, e.g. "zones.conf". A file not yet in the tree is created
+	// and included from the root file. Top-level zones only.
+	ConfigFile string
+
+	// NameServers of the zone, relative to it or absolute. The first one is
+	// the SOA primary. In-zone name servers need an address in Records.
+	NameServers []string
+	// Hostmaster is the SOA contact, as a mailbox ("hostmaster@example.com")
+	// or in SOA form; empty means hostmaster.<zone>.
+	Hostmaster string
+	// TTL is the $TTL of the zone file; 0 means 3600.
+	TTL uint32
+	// SOA timers; zero values take the RIPE-203 recommendations (refresh
+	// 86400, retry 7200, expire 3600000) and a negative TTL of 3600.
+	Refresh, Retry, Expire, Minimum uint32
+	// Records are added to the zone file after the SOA and NS records.
+	Records []zonefile.Record
+
+	// FS receives the zone file, at File resolved against the options
+	// directory; nil writes to the OS filesystem.
+	FS WriteFS
+	// Overwrite replaces an existing zone file instead of failing.
+	Overwrite bool
+}
+
+// ProvisionPrimaryZone onboards a primary zone in one call: it builds the
+// zone file (SOA, NS records and opts.Records, with a YYYYMMDD00 serial),
+// checks the zone data and the config with the zone added, writes the zone
+// file and then adds the zone statement, to opts.ConfigFile when set. The
+// config is not saved. Nothing is changed when a check fails; an existing
+// zone or zone file yields a *ConflictError.
+func (c *Config) ProvisionPrimaryZone(name string, opts ProvisionOptions) (*Zone, error) {
+	name = strings.TrimSuffix(c.zoneKey(name), ".")
+	if c.GetZoneInView(opts.View, name) != nil {
+		return nil, &ConflictError{Kind: "zone", Name: name, Msg: "already defined"}
+	}
+	if opts.ConfigFile != "" && opts.View != "" {
+		return nil, &ValueError{Path: "configFile", Value: opts.ConfigFile, Msg: "only top-level zones go to a separate file"}
+	}
+	z := opts.Zone
+	z.Name, z.Type = name, ZonePrimary
+	z.File = cmp.Or(z.File, "db."+name)
+
+	data, err := primaryZoneData(name, opts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	work := c.Clone()
+	if opts.View == "" {
+		work.UpsertZone(z)
+	} else {
+		work.UpsertZoneInView(opts.View, z)
+	}
+	var errs []error
+	for _, is := range work.Validate() {
+		if is.Severity == SeverityError {
+			errs = append(errs, is.Err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	w := opts.FS
+	if w == nil {
+		w = osFS{}
+	}
+	dst := c.inDirectory(z.File)
+	if _, err := fs.Stat(w, dst); err == nil && !opts.Overwrite {
+		return nil, &ConflictError{Kind: "zone file", Name: dst, Msg: "already exists"}
+	}
+	key := ""
+	if opts.ConfigFile != "" {
+		if key, err = c.treeFile(opts.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.WriteFile(dst, data.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+	if key != "" {
+		if _, ok := c.files[key]; !ok {
+			c.files[key] = &nc.File{}
+			c.Includes = append(c.Includes, Include{Path: opts.ConfigFile, origin: c.root})
+		}
+		z.origin = key
+	}
+	if opts.View == "" {
+		c.UpsertZone(z)
+	} else {
+		c.UpsertZoneInView(opts.View, z)
+	}
+	return c.GetZoneInView(opts.View, name), nil
+}
+
+// primaryZoneData builds and checks the initial zone file of a new zone.
+func primaryZoneData(name string, opts ProvisionOptions, now time.Time) (*zonefile.Zone, error) {
+	if len(opts.NameServers) == 0 {
+		return nil, &ValueError{Path: "nameServers", Value: name, Msg: "a zone needs at least one name server"}
+	}
+	origin := name + "."
+	abs := func(n string) string {
+		switch {
+		case n == "@":
+			return origin
+		case strings.HasSuffix(n, "."):
+			return n
+		}
+		return n + "." + origin
+	}
+	contact := cmp.Or(opts.Hostmaster, "hostmaster."+origin)
+	if local, domain, ok := strings.Cut(contact, "@"); ok {
+		contact = strings.ReplaceAll(local, ".", `\.`) + "." + fqdn(domain)
+	}
+	y, m, d := now.Date()
+	serial := uint32(y*1000000 + int(m)*10000 + d*100)
+	soa := fmt.Sprintf("%s %s %d %d %d %d %d", abs(opts.NameServers[0]), fqdn(contact), serial,
+		cmp.Or(opts.Refresh, 86400), cmp.Or(opts.Retry, 7200), cmp.Or(opts.Expire, 3600000), cmp.Or(opts.Minimum, 3600))
+
+	zf := zonefile.New(origin, cmp.Or(opts.TTL, 3600))
+	records := []zonefile.Record{{Name: "@", Type: "SOA", Data: soa}}
+	for _, ns := range opts.NameServers {
+		records = append(records, zonefile.Record{Name: "@", Type: "NS", Data: abs(ns)})
+	}
+	for _, r := range append(records, opts.Records...) {
+		if err := zf.AddRecord(r); err != nil {
+			return nil, &ValueError{Path: "records", Value: r.String(), Msg: err.Error()}
+		}
+	}
+	zf.SetSerial(serial)
+
+	for _, ns := range opts.NameServers {
+		host := strings.ToLower(abs(ns))
+		if host != origin && !strings.HasSuffix(host, "."+origin) {
+			continue
+		}
+		if len(zf.Lookup(host, "A")) == 0 && len(zf.Lookup(host, "AAAA")) == 0 {
+			return nil, &ValueError{Path: "nameServers", Value: ns, Msg: "in-zone name server has no A or AAAA record"}
+		}
+	}
+	return zf, nil
+}
+
+// treeFile returns the key of the config file p (as written in an include
+// statement) in the loaded tree.
+func (c *Config) treeFile(p string) (string, error) {
+	if c.files == nil {
+		return "", &ValueError{Path: "configFile", Value: p, Msg: "needs a config loaded with LoadTree"}
+	}
+	if c.fsys != nil {
+		t := &tree{fsys: c.fsys, dir: path.Dir(c.root)}
+		return t.resolve(p), nil
+	}
+	t := &tree{dir: filepath.Dir(c.root)}
+	return t.resolve(p), nil
+}
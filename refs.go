@@ -0,0 +1,281 @@
+// File: pkg/namedzone/refs.go
+package namedzone
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+)
+
+// Reference is a use of a named block somewhere in the config.
+type Reference struct {
+	Kind string // "acl", "key", "tls", "http", "remote-servers"
+	Name string
+	// Path is the GetPath path of the field holding the name. For key
+	// fields, which GetPath reads as the keys collection, it is the path of
+	// the element that has the field (a match term or server).
+	Path string
+}
+
+// ReferencesOf lists every place that uses the acl, key, tls, http or
+// remote-servers block name: match lists (options, views, zones, controls,
+// listen-on, ACLs), controls keys, listen-on tls/http, forwarders, and the
+// primaries and also-notify lists of zones and remote-servers blocks. The
+// definition itself is not included.
+func (c *Config) ReferencesOf(kind, name string) []Reference {
+	var out []Reference
+	c.eachRef(func(r Reference, _ *string) {
+		if r.Kind == kind && r.Name == name {
+			out = append(out, r)
+		}
+	})
+	return out
+}
+
+// eachRef calls fn with every reference in c and the string holding the
+// referenced name, so callers can rewrite it.
+func (c *Config) eachRef(fn func(r Reference, name *string)) {
+	ref := func(path, kind string, name *string) {
+		if *name != "" {
+			fn(Reference{Kind: kind, Name: *name, Path: path}, name)
+		}
+	}
+	keys := func(path string, names []string) {
+		for i := range names {
+			ref(fmt.Sprintf("%s[#%d]", path, i), "key", &names[i])
+		}
+	}
+	servers := func(path string, items []RemoteServerItem) {
+		for i := range items {
+			it := &items[i]
+			p := fmt.Sprintf("%s[#%d]", path, i)
+			if _, err := netip.ParseAddr(it.Address); err != nil {
+				ref(p+".address", "remote-servers", &it.Address)
+			}
+			ref(p, "key", &it.Key)
+			ref(p+".tls", "tls", &it.TLS)
+		}
+	}
+	forwarders := func(path string, ff []Forwarder) {
+		for i := range ff {
+			ref(fmt.Sprintf("%s[#%d].tls", path, i), "tls", &ff[i].TLS)
+		}
+	}
+	listen := func(path string, l *Listen) {
+		if l != nil {
+			ref(path+".tls", "tls", &l.TLS)
+			ref(path+".http", "http", &l.HTTP)
+		}
+	}
+	zone := func(path string, z *Zone) {
+		ref(path+".primariesRef", "remote-servers", &z.PrimariesRef)
+		servers(path+".primaries", z.Primaries)
+		servers(path+".alsoNotify", z.AlsoNotify)
+		forwarders(path+".forwarders", z.Forwarders)
+	}
+
+	for path, l := range c.AllMatchLists() {
+		termRefs(path, *l, ref)
+	}
+	for i := range c.RemoteServers {
+		servers(fmt.Sprintf("remoteServer[%s].servers", c.RemoteServers[i].Name), c.RemoteServers[i].Servers)
+	}
+	if ct := c.Controls; ct != nil {
+		for i := range ct.Inet {
+			keys(fmt.Sprintf("controls.inet[#%d].keys", i), ct.Inet[i].Keys)
+		}
+		for i := range ct.Unix {
+			keys(fmt.Sprintf("controls.unix[#%d].keys", i), ct.Unix[i].Keys)
+		}
+	}
+	if o := c.Options; o != nil {
+		listen("options.listenOn", o.ListenOn)
+		listen("options.listenOnV6", o.ListenOnV6)
+		forwarders("options.forwarders", o.Forwarders)
+	}
+	for v, z := range c.AllZones() {
+		p := "zone[" + z.Name + "]"
+		if v != nil {
+			p = "view[" + v.Name + "]." + p
+		}
+		zone(p, z)
+	}
+}
+
+// termRefs reports the ACL and key names used in a match list.
+func termRefs(path string, terms []MatchTerm, ref func(path, kind string, name *string)) {
+	for i := range terms {
+		t := &terms[i]
+		p := fmt.Sprintf("%s[#%d]", path, i)
+		termRefs(p+".nested", t.Nested, ref)
+		ref(p+".aclRef", "acl", &t.ACLRef)
+		ref(p, "key", &t.Key)
+	}
+}
+
+// RenameACL renames the acl old to new and rewrites every reference to it.
+// It fails without changes if old is not defined or new already is.
+func (c *Config) RenameACL(old, new string) error {
+	return rename(c, c.ACLs, "acl", old, new, func(a *ACL) *string { return &a.Name })
+}
+
+// RenameKey renames the key old to new and rewrites every reference to it.
+func (c *Config) RenameKey(old, new string) error {
+	return rename(c, c.Keys, "key", old, new, func(k *Key) *string { return &k.Name })
+}
+
+// RenameTLS renames the tls block old to new and rewrites every reference
+// to it.
+func (c *Config) RenameTLS(old, new string) error {
+	return rename(c, c.TLS, "tls", old, new, func(t *TLS) *string { return &t.Name })
+}
+
+// RenameHTTP renames the http block old to new and rewrites every reference
+// to it.
+func (c *Config) RenameHTTP(old, new string) error {
+	return rename(c, c.HTTP, "http", old, new, func(h *HTTP) *string { return &h.Name })
+}
+
+// RenameRemoteServers renames the remote-servers block old to new and
+// rewrites every reference to it.
+func (c *Config) RenameRemoteServers(old, new string) error {
+	return rename(c, c.RemoteServers, "remote-servers", old, new, func(r *RemoteServers) *string { return &r.Name })
+}
+
+func rename[T any](c *Config, defs []T, kind, old, new string, name func(*T) *string) error {
+	if new == "" {
+		return &ValueError{Path: kind, Msg: "empty name"}
+	}
+	at := -1
+	for i := range defs {
+		switch *name(&defs[i]) {
+		case new:
+			return &ConflictError{Kind: kind, Name: new, Msg: "already defined"}
+		case old:
+			if at < 0 {
+				at = i
+			}
+		}
+	}
+	if at < 0 {
+		return &ReferenceError{Kind: kind, Name: old, Path: kind}
+	}
+	for i := range defs {
+		if n := name(&defs[i]); *n == old {
+			*n = new
+		}
+	}
+	c.eachRef(func(r Reference, n *string) {
+		if r.Kind == kind && r.Name == old {
+			*n = new
+		}
+	})
+	c.audit("Rename", kind+" "+old+" -> "+new, nil)
+	return nil
+}
+
+// RemovePolicy decides what the dependency-checked Remove methods do with a
+// block that is still referenced.
+type RemovePolicy int
+
+const (
+	// RemoveRefuse leaves the config alone and fails with an *InUseError.
+	RemoveRefuse RemovePolicy = iota
+	// RemoveCascade drops the references too: match terms and server
+	// entries naming the block are removed, and key, tls, http and
+	// primaries fields are cleared. A match list left empty becomes
+	// { none; } rather than unset, so access never widens to named's
+	// default; a negated term removed from a list can still widen it.
+	RemoveCascade
+)
+
+// RemoveACL removes the acl name. References to it are handled by policy;
+// the returned list holds them either way.
+func (c *Config) RemoveACL(name string, policy RemovePolicy) ([]Reference, error) {
+	return remove(c, &c.ACLs, "acl", name, policy, func(a *ACL) string { return a.Name })
+}
+
+// RemoveKey removes the key name; see RemoveACL.
+func (c *Config) RemoveKey(name string, policy RemovePolicy) ([]Reference, error) {
+	return remove(c, &c.Keys, "key", name, policy, func(k *Key) string { return k.Name })
+}
+
+// RemoveTLS removes the tls block name; see RemoveACL.
+func (c *Config) RemoveTLS(name string, policy RemovePolicy) ([]Reference, error) {
+	return remove(c, &c.TLS, "tls", name, policy, func(t *TLS) string { return t.Name })
+}
+
+// RemoveHTTP removes the http block name; see RemoveACL.
+func (c *Config) RemoveHTTP(name string, policy RemovePolicy) ([]Reference, error) {
+	return remove(c, &c.HTTP, "http", name, policy, func(h *HTTP) string { return h.Name })
+}
+
+// RemoveRemoteServers removes the remote-servers block name; see RemoveACL.
+func (c *Config) RemoveRemoteServers(name string, policy RemovePolicy) ([]Reference, error) {
+	return remove(c, &c.RemoteServers, "remote-servers", name, policy, func(r *RemoteServers) string { return r.Name })
+}
+
+func remove[T any](c *Config, defs *[]T, kind, name string, policy RemovePolicy, nameOf func(*T) string) ([]Reference, error) {
+	if !slices.ContainsFunc(*defs, func(d T) bool { return nameOf(&d) == name }) {
+		return nil, &ReferenceError{Kind: kind, Name: name, Path: kind}
+	}
+	refs := c.ReferencesOf(kind, name)
+	if len(refs) > 0 {
+		if policy != RemoveCascade {
+			return refs, &InUseError{Kind: kind, Name: name, Refs: refs}
+		}
+		c.eachRef(func(r Reference, n *string) {
+			if r.Kind == kind && r.Name == name {
+				*n = ""
+			}
+		})
+		c.pruneRefs()
+	}
+	*defs = slices.DeleteFunc(*defs, func(d T) bool { return nameOf(&d) == name })
+	c.audit("Remove", kind+" "+name, nil)
+	return refs, nil
+}
+
+// pruneRefs drops the match terms, server entries and controls keys whose
+// reference was cleared by a cascading removal.
+func (c *Config) pruneRefs() {
+	for _, l := range c.AllMatchLists() {
+		if *l = pruneTerms(*l); len(*l) == 0 {
+			*l = []MatchTerm{{ACLRef: "none"}}
+		}
+	}
+	servers := func(items *[]RemoteServerItem) {
+		*items = slices.DeleteFunc(*items, func(it RemoteServerItem) bool { return it.Address == "" })
+	}
+	for i := range c.RemoteServers {
+		servers(&c.RemoteServers[i].Servers)
+	}
+	for _, z := range c.AllZones() {
+		servers(&z.Primaries)
+		servers(&z.AlsoNotify)
+	}
+	if ct := c.Controls; ct != nil {
+		blank := func(k string) bool { return k == "" }
+		for i := range ct.Inet {
+			ct.Inet[i].Keys = slices.DeleteFunc(ct.Inet[i].Keys, blank)
+		}
+		for i := range ct.Unix {
+			ct.Unix[i].Keys = slices.DeleteFunc(ct.Unix[i].Keys, blank)
+		}
+	}
+}
+
+func pruneTerms(terms []MatchTerm) []MatchTerm {
+	out := terms[:0]
+	for _, t := range terms {
+		if len(t.Nested) > 0 {
+			if t.Nested = pruneTerms(t.Nested); len(t.Nested) == 0 {
+				continue
+			}
+		} else if t.Address == "" && t.Key == "" && t.ACLRef == "" {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
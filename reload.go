@@ -0,0 +1,155 @@
+// File: pkg/namedzone/reload.go
+package namedzone
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ReloadRequest tells a ReloadStrategy what changed.
+type ReloadRequest struct {
+	// Full asks for the whole config and every zone to be reloaded. It is
+	// set when anything besides zone statements changed, or when there was
+	// no previous config to compare with.
+	Full bool `json:"full"`
+	// Zones lists the zone statements that changed when Full is false.
+	Zones []ZoneChange `json:"zones,omitempty"`
+}
+
+// ZoneChange is a zone statement that was added, removed or modified.
+type ZoneChange struct {
+	View   string     `json:"view,omitempty"`
+	Name   string     `json:"name"`
+	Change ChangeKind `json:"change"`
+}
+
+// ReloadStrategy makes a running named pick up a saved config.
+type ReloadStrategy func(r ReloadRequest) error
+
+// ReloadOptions tunes SaveAndReload.
+type ReloadOptions struct {
+	Save SaveOptions // passed to SaveWith
+	// Strategy triggers the reload; nil means RNDC().
+	Strategy ReloadStrategy
+}
+
+// SaveAndReload saves c to path with SaveWith and then has named load the
+// change through opts.Strategy. The saved config is compared with the one
+// on disk before the save: when nothing changed named is left alone, when
+// only zone statements changed the strategy gets just those zones, and
+// otherwise it is asked for a full reload. It returns the request it passed
+// on. A failed reload, after a successful save, is a *ReloadError.
+func (c *Config) SaveAndReload(path string, opts ReloadOptions) (ReloadRequest, error) {
+	req := ReloadRequest{Full: true}
+	if old, err := LoadTree(path); err == nil {
+		d := Diff(old, c)
+		if d.Empty() {
+			req.Full = false
+		} else if zones, ok := zoneChanges(d); ok {
+			req = ReloadRequest{Zones: zones}
+		}
+	}
+	if err := c.SaveWith(path, opts.Save); err != nil {
+		return req, err
+	}
+	if !req.Full && len(req.Zones) == 0 {
+		return req, nil
+	}
+	strategy := opts.Strategy
+	if strategy == nil {
+		strategy = RNDC()
+	}
+	if err := strategy(req); err != nil {
+		return req, &ReloadError{Path: path, Err: err}
+	}
+	c.audit("Reload", path, nil)
+	return req, nil
+}
+
+// zoneChanges returns the zones d touches and whether d touches nothing
+// else.
+func zoneChanges(d *ConfigDiff) ([]ZoneChange, bool) {
+	if len(d.Settings) > 0 {
+		return nil, false
+	}
+	var out []ZoneChange
+	for _, it := range d.Items {
+		if it.Kind != "zone" {
+			return nil, false
+		}
+		zc := ZoneChange{Name: it.Name, Change: it.Change}
+		if segs, err := parsePath(it.Path); err == nil && len(segs) > 1 && segs[0].field == "views" {
+			zc.View = segs[0].sel
+		}
+		out = append(out, zc)
+	}
+	return out, true
+}
+
+// RNDC returns a strategy that runs rndc from PATH with args (e.g. "-s",
+// host, "-k", keyfile) before each command. A full reload runs
+// `rndc reload`. For zone changes it runs `rndc reconfig`, which adds and
+// removes zones and applies changed zone statements without reloading the
+// data of every zone, then `rndc reload zone IN view` for each modified
+// zone so a changed file is read.
+func RNDC(args ...string) ReloadStrategy {
+	run := func(cmd ...string) error {
+		c := exec.Command("rndc", append(append([]string(nil), args...), cmd...)...)
+		var out bytes.Buffer
+		c.Stdout, c.Stderr = &out, &out
+		if err := c.Run(); err != nil {
+			if msg := strings.TrimSpace(out.String()); msg != "" {
+				return fmt.Errorf("rndc %s: %s", strings.Join(cmd, " "), msg)
+			}
+			return fmt.Errorf("rndc %s: %w", strings.Join(cmd, " "), err)
+		}
+		return nil
+	}
+	return func(r ReloadRequest) error {
+		if r.Full {
+			return run("reload")
+		}
+		if err := run("reconfig"); err != nil {
+			return err
+		}
+		var errs []error
+		for _, z := range r.Zones {
+			if z.Change != Modified {
+				continue
+			}
+			cmd := []string{"reload", z.Name}
+			if z.View != "" {
+				cmd = append(cmd, "IN", z.View)
+			}
+			errs = append(errs, run(cmd...))
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// Signal returns a strategy that sends SIGHUP to the named whose process ID
+// is in pidFile, which reloads the config and every zone. Zone changes get
+// a full reload too. It is not supported on Windows.
+func Signal(pidFile string) ReloadStrategy {
+	return func(ReloadRequest) error {
+		b, err := os.ReadFile(pidFile)
+		if err != nil {
+			return err
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+		if err != nil {
+			return fmt.Errorf("%s: invalid pid %q", pidFile, strings.TrimSpace(string(b)))
+		}
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			return err
+		}
+		return p.Signal(syscall.SIGHUP)
+	}
+}
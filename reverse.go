@@ -0,0 +1,177 @@
+// File: pkg/namedzone/reverse.go
+package namedzone
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/dlukt/namedzone/zonefile"
+)
+
+// ReverseZoneFor returns the in-addr.arpa or ip6.arpa zone that holds the
+// PTR records of p, e.g. "2.0.192.in-addr.arpa" for 192.0.2.0/24. The
+// prefix length must fall on a label boundary: a multiple of 8 for IPv4 and
+// of 4 for IPv6.
+func ReverseZoneFor(p netip.Prefix) (string, error) {
+	if !p.IsValid() {
+		return "", &ValueError{Path: "prefix", Value: p.String(), Msg: "invalid prefix"}
+	}
+	p = p.Masked()
+	labels := reverseLabels(p.Addr())
+	per := 4
+	if p.Addr().Is4() {
+		per = 8
+	}
+	if p.Bits()%per != 0 {
+		return "", &ValueError{Path: "prefix", Value: p.String(), Msg: fmt.Sprintf("length is not a multiple of %d", per)}
+	}
+	labels = labels[len(labels)-p.Bits()/per:]
+	return strings.Join(append(labels, reverseSuffix(p.Addr())), "."), nil
+}
+
+// ReverseName returns the absolute owner name of the PTR record for a,
+// e.g. "1.2.0.192.in-addr.arpa.".
+func ReverseName(a netip.Addr) string {
+	return strings.Join(append(reverseLabels(a.Unmap()), reverseSuffix(a.Unmap())), ".") + "."
+}
+
+// reverseLabels returns the octets (IPv4) or nibbles (IPv6) of a, least
+// significant first.
+func reverseLabels(a netip.Addr) []string {
+	a = a.Unmap()
+	var out []string
+	for _, b := range a.AsSlice() {
+		if a.Is4() {
+			out = append(out, fmt.Sprint(b))
+		} else {
+			out = append(out, fmt.Sprintf("%x", b>>4), fmt.Sprintf("%x", b&0xf))
+		}
+	}
+	slices.Reverse(out)
+	return out
+}
+
+func reverseSuffix(a netip.Addr) string {
+	if a.Unmap().Is4() {
+		return "in-addr.arpa"
+	}
+	return "ip6.arpa"
+}
+
+// AddReverseZone adds the reverse zone for p to view (top level when view
+// is empty), with the settings of z: its Name is set from ReverseZoneFor
+// and an empty Type becomes primary. A zone of that name already there
+// yields a *ConflictError.
+func (c *Config) AddReverseZone(view string, p netip.Prefix, z Zone) (*Zone, error) {
+	name, err := ReverseZoneFor(p)
+	if err != nil {
+		return nil, err
+	}
+	if c.GetZoneInView(view, name) != nil {
+		return nil, &ConflictError{Kind: "zone", Name: name, Msg: "already defined"}
+	}
+	z.Name = name
+	if z.Type == "" {
+		z.Type = ZonePrimary
+	}
+	if view == "" {
+		c.UpsertZone(z)
+	} else {
+		c.UpsertZoneInView(view, z)
+	}
+	return c.GetZoneInView(view, name), nil
+}
+
+// GeneratePTR returns the PTR records matching the A and AAAA records of
+// forward, one per address and owner, in order. The PTR TTL is the forward
+// record's.
+func GeneratePTR(forward []zonefile.Record) []zonefile.Record {
+	var out []zonefile.Record
+	for _, r := range forward {
+		a, ok := forwardAddr(r)
+		if !ok {
+			continue
+		}
+		ptr := zonefile.Record{Name: ReverseName(a), TTL: r.TTL, Class: r.Class, Type: "PTR", Data: fqdn(r.Name)}
+		if !slices.ContainsFunc(out, func(p zonefile.Record) bool { return samePTR(p, ptr) }) {
+			out = append(out, ptr)
+		}
+	}
+	return out
+}
+
+// PTRSyncOptions control SyncPTR.
+type PTRSyncOptions struct {
+	// TTL of added PTR records; nil takes the $TTL of the reverse zone.
+	TTL *uint32
+	// Prune removes PTR records of the reverse zone that no forward record
+	// backs. Without it SyncPTR only adds.
+	Prune bool
+}
+
+// SyncPTR brings the PTR records written in the reverse zone file rev in
+// line with the A and AAAA records of forward: it adds the PTR records of
+// addresses inside rev.Origin that are missing and, with Prune, removes
+// the ones no forward record backs. It returns the records added and
+// removed; the SOA serial is bumped by the edits.
+func SyncPTR(rev *zonefile.Zone, forward []zonefile.Record, opts PTRSyncOptions) (added, removed []zonefile.Record, err error) {
+	origin := strings.ToLower(rev.Origin)
+	inZone := func(name string) bool {
+		name = strings.ToLower(name)
+		return name == origin || strings.HasSuffix(name, "."+origin)
+	}
+	var want []zonefile.Record
+	for _, p := range GeneratePTR(forward) {
+		if inZone(p.Name) {
+			p.TTL, p.Class = opts.TTL, ""
+			want = append(want, p)
+		}
+	}
+	have := rev.Records()
+	for _, p := range want {
+		if slices.ContainsFunc(have, func(h zonefile.Record) bool { return samePTR(h, p) }) {
+			continue
+		}
+		if err := rev.AddRecord(p); err != nil {
+			return added, removed, err
+		}
+		added = append(added, p)
+	}
+	if !opts.Prune {
+		return added, removed, nil
+	}
+	for _, h := range have {
+		if h.Type != "PTR" || slices.ContainsFunc(want, func(p zonefile.Record) bool { return samePTR(h, p) }) {
+			continue
+		}
+		if err := rev.DeleteRecord(h); err != nil {
+			return added, removed, err
+		}
+		removed = append(removed, h)
+	}
+	return added, removed, nil
+}
+
+func forwardAddr(r zonefile.Record) (netip.Addr, bool) {
+	if r.Type != "A" && r.Type != "AAAA" {
+		return netip.Addr{}, false
+	}
+	a, err := netip.ParseAddr(strings.TrimSpace(r.Data))
+	if err != nil || a.Is4() != (r.Type == "A") {
+		return netip.Addr{}, false
+	}
+	return a, true
+}
+
+func samePTR(a, b zonefile.Record) bool {
+	return strings.EqualFold(a.Name, b.Name) && strings.EqualFold(a.Type, b.Type) && strings.EqualFold(fqdn(a.Data), fqdn(b.Data))
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
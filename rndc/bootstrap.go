@@ -0,0 +1,115 @@
+// File: pkg/namedzone/rndc/bootstrap.go
+package rndc
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+)
+
+// BootstrapOptions configures EnsureRNDC. Zero values pick the defaults of
+// rndc-confgen.
+type BootstrapOptions struct {
+	KeyName   string // default "rndc-key"
+	Algorithm string // default: that of the existing key, or "hmac-sha256"
+	// KeyFile is where the key is written as a named.conf key statement,
+	// readable by named and rndc alike; empty skips writing it.
+	KeyFile string
+	Address string // control channel address, default "127.0.0.1"
+	Port    int    // default 953
+	// Rotate generates a new secret even when the key already exists.
+	Rotate bool
+}
+
+// EnsureRNDC sets cfg up for rndc: it adds the key (generating a random
+// secret, or keeping the existing one unless Rotate is set), writes it to
+// KeyFile, and makes sure controls has an inet channel on Address and Port
+// that allows the address and accepts the key. It returns the matching
+// rndc.conf. Running it again changes nothing.
+func EnsureRNDC(cfg *namedzone.Config, opts BootstrapOptions) (*Config, error) {
+	name := cmp.Or(opts.KeyName, "rndc-key")
+	addr := cmp.Or(opts.Address, "127.0.0.1")
+	port := cmp.Or(opts.Port, 953)
+
+	i := slices.IndexFunc(cfg.Keys, func(k namedzone.Key) bool { return k.Name == name })
+	if i < 0 {
+		cfg.Keys = append(cfg.Keys, namedzone.Key{Name: name})
+		i = len(cfg.Keys) - 1
+	}
+	k := &cfg.Keys[i]
+	alg := cmp.Or(opts.Algorithm, k.Algorithm, "hmac-sha256")
+	if k.Secret == "" || k.Algorithm != alg || opts.Rotate {
+		secret, err := newSecret(alg)
+		if err != nil {
+			return nil, err
+		}
+		k.Algorithm, k.Secret = alg, secret
+	}
+
+	if cfg.Controls == nil || cfg.Controls.Disabled {
+		cfg.Controls = &namedzone.Controls{}
+	}
+	ct := cfg.Controls
+	j := slices.IndexFunc(ct.Inet, func(in namedzone.ControlInet) bool {
+		return in.Address == addr && cmp.Or(ptrValue(in.Port), 953) == port
+	})
+	if j < 0 {
+		in := namedzone.ControlInet{Address: addr, Allow: []namedzone.MatchTerm{{Address: addr}}}
+		if port != 953 {
+			in.Port = &port
+		}
+		ct.Inet = append(ct.Inet, in)
+		j = len(ct.Inet) - 1
+	}
+	if in := &ct.Inet[j]; !slices.Contains(in.Keys, name) {
+		in.Keys = append(in.Keys, name)
+	}
+
+	if opts.KeyFile != "" {
+		text := fmt.Sprintf("key %q {\n\talgorithm %s;\n\tsecret %q;\n};\n", name, k.Algorithm, k.Secret)
+		if err := os.WriteFile(opts.KeyFile, []byte(text), 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	rc := &Config{
+		Options: &Options{DefaultServer: addr, DefaultKey: name},
+		Servers: []Server{{Name: addr, Key: name}},
+		Keys:    []Key{{Name: name, Algorithm: k.Algorithm, Secret: k.Secret}},
+	}
+	if port != 953 {
+		rc.Options.DefaultPort = &port
+	}
+	return rc, nil
+}
+
+// secretBits are the key lengths rndc-confgen uses per algorithm.
+var secretBits = map[string]int{
+	"hmac-md5": 128, "hmac-sha1": 160, "hmac-sha224": 224,
+	"hmac-sha256": 256, "hmac-sha384": 384, "hmac-sha512": 512,
+}
+
+func newSecret(alg string) (string, error) {
+	bits, ok := secretBits[strings.ToLower(alg)]
+	if !ok {
+		return "", &namedzone.ValueError{Path: "algorithm", Value: alg, Msg: "unsupported TSIG algorithm"}
+	}
+	b := make([]byte, bits/8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func ptrValue(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
@@ -0,0 +1,463 @@
+// File: pkg/namedzone/rndc/rndc.go
+
+// Package rndc is a typed model of rndc.conf, the client side of the named
+// control channel: the options block (default server, key and port), server
+// blocks and key blocks. Like namedzone it edits the parsed file in place:
+// statements whose typed value did not change keep their original text and
+// comments, and statements it does not model (include) are left alone.
+package rndc
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+	"github.com/dlukt/namedzone"
+)
+
+// Config is a typed rndc.conf.
+type Config struct {
+	Options *Options `json:"options,omitempty"`
+	Servers []Server `json:"servers,omitempty"`
+	Keys    []Key    `json:"keys,omitempty"`
+
+	ast *nc.File
+	// build output of every loaded or written statement, to detect edits
+	clean map[*nc.Stmt]string
+}
+
+// Options is the options block.
+type Options struct {
+	DefaultServer          string `json:"defaultServer,omitempty"`
+	DefaultKey             string `json:"defaultKey,omitempty"`
+	DefaultPort            *int   `json:"defaultPort,omitempty"`
+	DefaultSourceAddress   string `json:"defaultSourceAddress,omitempty"`
+	DefaultSourceAddressV6 string `json:"defaultSourceAddressV6,omitempty"`
+
+	Other []namedzone.RawKV `json:"other,omitempty"`
+	stmt  *nc.Stmt
+}
+
+// Server is a server block: how rndc reaches one named.
+type Server struct {
+	Name            string   `json:"name"`
+	Key             string   `json:"key,omitempty"`
+	Port            *int     `json:"port,omitempty"`
+	Addresses       []string `json:"addresses,omitempty"` // each "addr" or "addr port n"
+	SourceAddress   string   `json:"sourceAddress,omitempty"`
+	SourceAddressV6 string   `json:"sourceAddressV6,omitempty"`
+
+	Other []namedzone.RawKV `json:"other,omitempty"`
+	stmt  *nc.Stmt
+}
+
+// Key is a key block, the shared secret of a controls channel.
+type Key struct {
+	Name      string `json:"name"`
+	Algorithm string `json:"algorithm"`
+	Secret    string `json:"secret"`
+	stmt      *nc.Stmt
+}
+
+// FromFile builds a typed Config from a parsed rndc.conf.
+func FromFile(f *nc.File) (*Config, error) {
+	c := &Config{ast: f, clean: map[*nc.Stmt]string{}}
+	for _, n := range f.Nodes {
+		st, ok := n.(*nc.Stmt)
+		if !ok {
+			continue
+		}
+		switch st.Keyword {
+		case "options":
+			o := parseOptions(st)
+			c.Options = &o
+			c.clean[st] = text(buildOptions(o))
+		case "server":
+			s := parseServer(st)
+			c.Servers = append(c.Servers, s)
+			c.clean[st] = text(buildServer(s))
+		case "key":
+			k := parseKey(st)
+			c.Keys = append(c.Keys, k)
+			c.clean[st] = text(buildKey(k))
+		}
+	}
+	return c, nil
+}
+
+// FromReader parses an rndc.conf from r.
+func FromReader(r io.Reader) (*Config, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f, err := nc.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	return FromFile(f)
+}
+
+// Load reads and parses the rndc.conf at path.
+func Load(path string) (*Config, error) {
+	f, err := nc.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return FromFile(f)
+}
+
+// Apply writes the typed config into f, or into the Config's own AST when
+// f is nil (an empty one for a Config built in code). Unchanged statements
+// keep their text; changed ones are rewritten in place, removed ones
+// dropped and new ones appended.
+func (c *Config) Apply(f *nc.File) error {
+	if f == nil {
+		if c.ast == nil {
+			c.ast = &nc.File{}
+		}
+		f = c.ast
+	}
+	if c.clean == nil {
+		c.clean = map[*nc.Stmt]string{}
+	}
+	var opts []Options
+	if c.Options != nil {
+		opts = []Options{*c.Options}
+	}
+	c.sync(f, "options", len(opts), func(i int) **nc.Stmt { return &opts[i].stmt }, func(i int) *nc.Stmt { return buildOptions(opts[i]) })
+	if c.Options != nil {
+		*c.Options = opts[0]
+	}
+	c.sync(f, "server", len(c.Servers), func(i int) **nc.Stmt { return &c.Servers[i].stmt }, func(i int) *nc.Stmt { return buildServer(c.Servers[i]) })
+	c.sync(f, "key", len(c.Keys), func(i int) **nc.Stmt { return &c.Keys[i].stmt }, func(i int) *nc.Stmt { return buildKey(c.Keys[i]) })
+	c.ast = f
+	return nil
+}
+
+// sync makes the keyword statements of f match the n typed items.
+func (c *Config) sync(f *nc.File, keyword string, n int, ref func(int) **nc.Stmt, build func(int) *nc.Stmt) {
+	owner := map[*nc.Stmt]int{}
+	for i := range n {
+		if st := *ref(i); st != nil {
+			owner[st] = i
+		}
+	}
+	var out []nc.Node
+	skipSpace := false
+	for _, node := range f.Nodes {
+		st, ok := node.(*nc.Stmt)
+		if !ok {
+			if r, raw := node.(*nc.Raw); !(raw && skipSpace && strings.TrimSpace(r.Text) == "") {
+				out = append(out, node)
+			}
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		if st.Keyword != keyword {
+			out = append(out, st)
+			continue
+		}
+		i, ok := owner[st]
+		if !ok {
+			skipSpace = true // removed
+			continue
+		}
+		delete(owner, st)
+		b := build(i)
+		if t := text(b); t != c.clean[st] {
+			b.HeadRaw = leadingComments(st) + b.HeadRaw
+			*ref(i), c.clean[b] = b, t
+			st = b
+		}
+		out = append(out, st)
+	}
+	for i := range n {
+		if st := *ref(i); st != nil && slices.Contains(out, nc.Node(st)) {
+			continue
+		}
+		b := build(i)
+		if len(out) > 0 {
+			if r, ok := out[len(out)-1].(*nc.Raw); !ok || !strings.HasSuffix(r.Text, "\n") {
+				out = append(out, &nc.Raw{Text: "\n"})
+			}
+		}
+		out = append(out, b, &nc.Raw{Text: "\n"})
+		*ref(i), c.clean[b] = b, text(b)
+	}
+	f.Nodes = out
+}
+
+// WriteTo applies the typed config and writes the rendered file to w.
+func (c *Config) WriteTo(w io.Writer) (int64, error) {
+	if err := c.Apply(nil); err != nil {
+		return 0, err
+	}
+	n, err := w.Write(c.ast.Bytes())
+	return int64(n), err
+}
+
+// Render returns the rndc.conf text WriteTo would write.
+func (c *Config) Render() (string, error) {
+	var sb strings.Builder
+	if _, err := c.WriteTo(&sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Save applies the typed config and writes it to path, with mode 0640 for a
+// new file since rndc.conf holds secrets.
+func (c *Config) Save(path string) error {
+	text, err := c.Render()
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0o640)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	return os.WriteFile(path, []byte(text), mode)
+}
+
+// FindServer returns the server block name, or nil.
+func (c *Config) FindServer(name string) *Server {
+	if i := slices.IndexFunc(c.Servers, func(s Server) bool { return s.Name == name }); i >= 0 {
+		return &c.Servers[i]
+	}
+	return nil
+}
+
+// UpsertServer adds s or replaces the server block of the same name.
+func (c *Config) UpsertServer(s Server) {
+	if cur := c.FindServer(s.Name); cur != nil {
+		s.stmt = cur.stmt
+		*cur = s
+		return
+	}
+	c.Servers = append(c.Servers, s)
+}
+
+// FindKey returns the key block name, or nil.
+func (c *Config) FindKey(name string) *Key {
+	if i := slices.IndexFunc(c.Keys, func(k Key) bool { return k.Name == name }); i >= 0 {
+		return &c.Keys[i]
+	}
+	return nil
+}
+
+// UpsertKey adds k or replaces the key block of the same name.
+func (c *Config) UpsertKey(k Key) {
+	if cur := c.FindKey(k.Name); cur != nil {
+		k.stmt = cur.stmt
+		*cur = k
+		return
+	}
+	c.Keys = append(c.Keys, k)
+}
+
+// ---- parsing ----
+
+func parseOptions(st *nc.Stmt) Options {
+	o := Options{stmt: st}
+	for _, s := range children(st) {
+		v := value(s)
+		switch s.Keyword {
+		case "default-server":
+			o.DefaultServer = unquote(v)
+		case "default-key":
+			o.DefaultKey = unquote(v)
+		case "default-port":
+			o.DefaultPort = intPtr(v)
+		case "default-source-address":
+			o.DefaultSourceAddress = v
+		case "default-source-address-v6":
+			o.DefaultSourceAddressV6 = v
+		default:
+			o.Other = append(o.Other, namedzone.RawKV{Name: s.Keyword, Raw: v})
+		}
+	}
+	return o
+}
+
+func parseServer(st *nc.Stmt) Server {
+	s := Server{Name: name(st), stmt: st}
+	for _, ch := range children(st) {
+		v := value(ch)
+		switch ch.Keyword {
+		case "key":
+			s.Key = unquote(v)
+		case "port":
+			s.Port = intPtr(v)
+		case "addresses":
+			for _, a := range strings.Split(strings.Trim(v, "{} \t\n"), ";") {
+				if a = strings.Join(strings.Fields(a), " "); a != "" {
+					s.Addresses = append(s.Addresses, unquote(a))
+				}
+			}
+		case "source-address":
+			s.SourceAddress = v
+		case "source-address-v6":
+			s.SourceAddressV6 = v
+		default:
+			s.Other = append(s.Other, namedzone.RawKV{Name: ch.Keyword, Raw: v})
+		}
+	}
+	return s
+}
+
+func parseKey(st *nc.Stmt) Key {
+	k := Key{Name: name(st), stmt: st}
+	for _, ch := range children(st) {
+		switch ch.Keyword {
+		case "algorithm":
+			k.Algorithm = unquote(value(ch))
+		case "secret":
+			k.Secret = unquote(value(ch))
+		}
+	}
+	return k
+}
+
+// ---- building ----
+
+func buildOptions(o Options) *nc.Stmt {
+	var body []nc.Node
+	add := func(s string) { body = append(body, nc.NewSimpleStmt(s)) }
+	if o.DefaultServer != "" {
+		add("default-server " + o.DefaultServer)
+	}
+	if o.DefaultKey != "" {
+		add("default-key " + quote(o.DefaultKey))
+	}
+	if o.DefaultPort != nil {
+		add("default-port " + strconv.Itoa(*o.DefaultPort))
+	}
+	if o.DefaultSourceAddress != "" {
+		add("default-source-address " + o.DefaultSourceAddress)
+	}
+	if o.DefaultSourceAddressV6 != "" {
+		add("default-source-address-v6 " + o.DefaultSourceAddressV6)
+	}
+	for _, kv := range o.Other {
+		add(kv.Name + " " + kv.Raw)
+	}
+	return nc.NewBlockStmt("options", body)
+}
+
+func buildServer(s Server) *nc.Stmt {
+	var body []nc.Node
+	add := func(s string) { body = append(body, nc.NewSimpleStmt(s)) }
+	if s.Key != "" {
+		add("key " + quote(s.Key))
+	}
+	if s.Port != nil {
+		add("port " + strconv.Itoa(*s.Port))
+	}
+	if len(s.Addresses) > 0 {
+		var b strings.Builder
+		for _, a := range s.Addresses {
+			b.WriteString(" " + a + ";")
+		}
+		add("addresses {" + b.String() + " }")
+	}
+	if s.SourceAddress != "" {
+		add("source-address " + s.SourceAddress)
+	}
+	if s.SourceAddressV6 != "" {
+		add("source-address-v6 " + s.SourceAddressV6)
+	}
+	for _, kv := range s.Other {
+		add(kv.Name + " " + kv.Raw)
+	}
+	return nc.NewBlockStmt("server "+s.Name, body)
+}
+
+func buildKey(k Key) *nc.Stmt {
+	return nc.NewBlockStmt("key "+quote(k.Name), []nc.Node{
+		nc.NewSimpleStmt("algorithm " + k.Algorithm),
+		nc.NewSimpleStmt("secret " + quote(k.Secret)),
+	})
+}
+
+// ---- helpers ----
+
+func text(st *nc.Stmt) string {
+	return string((&nc.File{Nodes: []nc.Node{st}}).Bytes())
+}
+
+func children(st *nc.Stmt) []*nc.Stmt {
+	var out []*nc.Stmt
+	for _, n := range st.Body {
+		if s, ok := n.(*nc.Stmt); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// head returns the head of st without its leading comments.
+func head(st *nc.Stmt) string {
+	return strings.TrimSpace(strings.TrimPrefix(st.HeadRaw, leadingComments(st)))
+}
+
+// leadingComments returns the comments written before the head of st.
+func leadingComments(st *nc.Stmt) string {
+	h := st.HeadRaw
+	for {
+		t := strings.TrimLeft(h, " \t\r\n")
+		switch {
+		case strings.HasPrefix(t, "#"), strings.HasPrefix(t, "//"):
+			i := strings.IndexByte(t, '\n')
+			if i < 0 {
+				return st.HeadRaw
+			}
+			h = t[i+1:]
+		case strings.HasPrefix(t, "/*"):
+			i := strings.Index(t, "*/")
+			if i < 0 {
+				return st.HeadRaw
+			}
+			h = t[i+2:]
+		default:
+			return st.HeadRaw[:len(st.HeadRaw)-len(t)]
+		}
+	}
+}
+
+// value returns what follows the keyword of st, with a block body included.
+func value(st *nc.Stmt) string {
+	v := strings.TrimSpace(strings.TrimPrefix(head(st), st.Keyword))
+	if st.HasBlock {
+		t := text(st)
+		if i := strings.IndexByte(t, '{'); i >= 0 {
+			v = strings.TrimSpace(v + " " + strings.TrimSuffix(strings.TrimSpace(t[i:]), ";"))
+		}
+	}
+	return strings.TrimSpace(strings.TrimSuffix(v, ";"))
+}
+
+// name returns the first word after the keyword of a block head, unquoted.
+func name(st *nc.Stmt) string {
+	f := strings.Fields(head(st))
+	if len(f) < 2 {
+		return ""
+	}
+	return unquote(f[1])
+}
+
+func quote(s string) string { return strconv.Quote(s) }
+
+func unquote(s string) string { return strings.Trim(strings.TrimSpace(s), "\"") }
+
+func intPtr(s string) *int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	return &n
+}
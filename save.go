@@ -0,0 +1,128 @@
+// File: pkg/namedzone/save.go
+package namedzone
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// SaveOptions tunes SaveWith.
+type SaveOptions struct {
+	// Check vets the new root file before anything goes live. It gets the
+	// path of a temporary file next to the destination; an error aborts the
+	// save and leaves every live file untouched. NamedCheckconf returns a
+	// Check that runs named-checkconf.
+	Check func(path string) error
+
+	// Backup keeps each file being replaced as <name>.<UTC time>.bak, e.g.
+	// named.conf.20240102T150405Z.bak.
+	Backup bool
+}
+
+// SaveWith is Save for the OS filesystem with a validation gate and
+// backups. Every file is first written in full to a temporary file and
+// synced; only when all are written and Check accepts the root are the
+// temporary files renamed into place, included files before the root.
+//
+// For a LoadTree config Check sees the new root file, but its includes
+// resolve to the files on disk, i.e. to their previous content.
+func (c *Config) SaveWith(path string, opts SaveOptions) error {
+	cs, err := c.applyForSave()
+	if err != nil {
+		return err
+	}
+	outs := c.outputs(osFS{}, path)
+	tmps := make([]string, 0, len(outs))
+	cleanup := func() {
+		for _, t := range tmps {
+			_ = os.Remove(t)
+		}
+	}
+	for _, o := range outs {
+		tmp, err := stage(o.name, o.data, filePerm(o.name))
+		if err != nil {
+			cleanup()
+			return err
+		}
+		tmps = append(tmps, tmp)
+	}
+	if opts.Check != nil {
+		if err := opts.Check(tmps[0]); err != nil {
+			cleanup()
+			return &CheckError{Path: path, Err: err}
+		}
+	}
+	if err := c.snapshot(path); err != nil {
+		cleanup()
+		return err
+	}
+	if opts.Backup {
+		stamp := time.Now().UTC().Format("20060102T150405Z")
+		for _, o := range outs {
+			if err := backup(o.name, o.name+"."+stamp+".bak"); err != nil {
+				cleanup()
+				return err
+			}
+		}
+	}
+	// recorded before anything goes live, so an unrecorded save fails
+	if err := c.audit("Save", path, cs); err != nil {
+		cleanup()
+		return err
+	}
+	for i := len(outs) - 1; i >= 0; i-- {
+		if err := os.Rename(tmps[i], outs[i].name); err != nil {
+			cleanup()
+			return err
+		}
+		tmps = tmps[:i]
+		c.log().Debug("saved config", "path", outs[i].name)
+	}
+	return nil
+}
+
+// filePerm returns the permissions of name, or 0644 if it does not exist.
+func filePerm(name string) fs.FileMode {
+	if fi, err := os.Stat(name); err == nil {
+		return fi.Mode().Perm()
+	}
+	return 0o644
+}
+
+// backup copies name to dst, keeping its mode. A missing name is fine.
+func backup(name, dst string) error {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, fi.Mode().Perm())
+}
+
+// NamedCheckconf returns a SaveOptions.Check that runs named-checkconf from
+// PATH with args and the candidate file. Its output becomes the error.
+func NamedCheckconf(args ...string) func(path string) error {
+	return func(path string) error {
+		cmd := exec.Command("named-checkconf", append(args, path)...)
+		var out bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &out, &out
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(out.String()); msg != "" {
+				return errors.New(msg)
+			}
+			return err
+		}
+		return nil
+	}
+}
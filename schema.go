@@ -0,0 +1,149 @@
+// File: pkg/namedzone/schema.go
+package namedzone
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// schemaEnums lists the allowed values of the string enum types.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeFor[ZoneType](): {
+		string(ZonePrimary), string(ZoneSecondary), string(ZoneStub), string(ZoneMirror),
+		string(ZoneRedirect), string(ZoneForward), string(ZoneStaticStub), string(ZoneHint),
+	},
+	reflect.TypeFor[PrintTime](): {
+		string(PrintTimeYes), string(PrintTimeNo), string(PrintTimeISO8601),
+		string(PrintTimeISO8601UTC), string(PrintTimeLocal),
+	},
+	reflect.TypeFor[TrustAnchorKind](): {
+		string(AnchorStaticKey), string(AnchorInitialKey), string(AnchorStaticDS), string(AnchorInitialDS),
+	},
+	reflect.TypeFor[MasterfileFormat](): {string(MasterfileText), string(MasterfileRaw), string(MasterfileMap)},
+	reflect.TypeFor[MasterfileStyle]():  {string(MasterfileStyleFull), string(MasterfileStyleRelative)},
+	reflect.TypeFor[QNameMinimization](): {
+		string(QNameMinStrict), string(QNameMinRelaxed), string(QNameMinDisabled), string(QNameMinOff),
+	},
+	reflect.TypeFor[MinimalResponses](): {
+		string(MinimalResponsesYes), string(MinimalResponsesNo),
+		string(MinimalResponsesNoAuth), string(MinimalResponsesNoAuthRecursive),
+	},
+}
+
+// schemaText describes the types that marshal as text.
+var schemaText = map[reflect.Type]map[string]any{
+	reflect.TypeFor[Size](): {
+		"type":        "string",
+		"pattern":     `^([0-9]+[kKmMgG]?|[0-9]{1,3}%|unlimited|default)$`,
+		"description": "size: bytes with optional K/M/G suffix, a percentage, unlimited or default",
+	},
+	reflect.TypeFor[Duration](): {
+		"type":        "string",
+		"description": "duration: seconds, unit groups such as 1w2d or 90m, or ISO 8601 (P1D)",
+	},
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) for the JSON projection
+// of Config. It is generated from the struct definitions, so it always
+// matches what encoding/json produces and accepts.
+func JSONSchema() []byte {
+	g := &schemaGen{prefix: "#/$defs/", defs: map[string]any{}}
+	root := map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref":    g.of(reflect.TypeFor[Config]())["$ref"],
+		"$defs":   g.defs,
+	}
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		panic("namedzone: JSONSchema: " + err.Error())
+	}
+	return b
+}
+
+// OpenAPISchemas returns OpenAPI 3.1 component schemas for Config and every
+// type it contains, keyed by Go type name, for use under
+// components.schemas.
+func OpenAPISchemas() map[string]any {
+	g := &schemaGen{prefix: "#/components/schemas/", defs: map[string]any{}}
+	g.of(reflect.TypeFor[Config]())
+	return g.defs
+}
+
+type schemaGen struct {
+	prefix string
+	defs   map[string]any
+}
+
+// of returns the schema for t; structs are emitted once into defs and
+// referenced by name.
+func (g *schemaGen) of(t reflect.Type) map[string]any {
+	if s, ok := schemaText[t]; ok {
+		return s
+	}
+	if vals, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": vals}
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.of(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.of(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.of(t.Elem())}
+	case reflect.Struct:
+		ref := map[string]any{"$ref": g.prefix + t.Name()}
+		if _, done := g.defs[t.Name()]; done {
+			return ref
+		}
+		g.defs[t.Name()] = nil // placeholder for recursive types (MatchTerm)
+		g.defs[t.Name()] = g.object(t)
+		return ref
+	}
+	return map[string]any{}
+}
+
+func (g *schemaGen) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		ps := g.of(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+			if k := f.Type.Kind(); k == reflect.Slice || k == reflect.Map {
+				// nil encodes as null
+				ps["type"] = []string{ps["type"].(string), "null"}
+			}
+		}
+		props[name] = ps
+	}
+	s := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
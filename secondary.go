@@ -0,0 +1,145 @@
+// File: pkg/namedzone/secondary.go
+package namedzone
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// SecondaryOptions control GenerateSecondaryConfig.
+type SecondaryOptions struct {
+	// Key is the TSIG key the secondary signs transfer requests with; its
+	// key block is copied from the primary config.
+	Key string
+	// TLS is the tls block for zone transfers over TLS (XoT); copied from
+	// the primary config unless built in.
+	TLS string
+	// RemoteServers names the remote-servers list of the primary
+	// addresses; empty means "primaries".
+	RemoteServers string
+	// File maps a zone to its file on the secondary; nil means
+	// "secondary/db.<name>".
+	File func(z Zone) string
+	// Options of the secondary; nil copies directory, listen-on,
+	// listen-on-v6, recursion and allow-transfer from the primary.
+	Options *Options
+}
+
+// GenerateSecondaryConfig derives the config of a secondary server from the
+// config of its primary: every primary zone (in views too) becomes a
+// secondary zone transferring from primaryAddrs through a remote-servers
+// list, with opts.Key and opts.TLS set on addresses that name none. Views
+// keep their match lists, zones keep their allow-transfer, and the ACLs and
+// keys those refer to are copied. The result is validated; issues of
+// SeverityError are returned joined.
+func GenerateSecondaryConfig(primary *Config, primaryAddrs []RemoteServerItem, opts SecondaryOptions) (*Config, error) {
+	if len(primaryAddrs) == 0 {
+		return nil, &ValueError{Path: "primaryAddrs", Msg: "no primary addresses"}
+	}
+	src := primary.Clone()
+	out := &Config{}
+	list := cmp.Or(opts.RemoteServers, "primaries")
+	file := opts.File
+	if file == nil {
+		file = func(z Zone) string { return "secondary/db." + z.Name }
+	}
+
+	copied := map[string]bool{}
+	var copyRefs func(terms []MatchTerm)
+	copyKey := func(name string) error {
+		if name == "" || copied["key "+name] {
+			return nil
+		}
+		i := slices.IndexFunc(src.Keys, func(k Key) bool { return k.Name == name })
+		if i < 0 {
+			return &ReferenceError{Kind: "key", Name: name, Path: "keys"}
+		}
+		copied["key "+name] = true
+		out.Keys = append(out.Keys, src.Keys[i])
+		return nil
+	}
+	var missing []error
+	copyRefs = func(terms []MatchTerm) {
+		for _, t := range terms {
+			if err := copyKey(t.Key); err != nil {
+				missing = append(missing, err)
+			}
+			copyRefs(t.Nested)
+			if t.ACLRef == "" || builtinACLs[t.ACLRef] || copied["acl "+t.ACLRef] {
+				continue
+			}
+			copied["acl "+t.ACLRef] = true
+			if i := slices.IndexFunc(src.ACLs, func(a ACL) bool { return a.Name == t.ACLRef }); i >= 0 {
+				copyRefs(src.ACLs[i].Elements)
+				out.ACLs = append(out.ACLs, src.ACLs[i])
+			}
+		}
+	}
+
+	servers := slices.Clone(primaryAddrs)
+	for i := range servers {
+		servers[i].Key = cmp.Or(servers[i].Key, opts.Key)
+		servers[i].TLS = cmp.Or(servers[i].TLS, opts.TLS)
+		if err := copyKey(servers[i].Key); err != nil {
+			return nil, err
+		}
+		if t := servers[i].TLS; t != "" && !builtinTLS[t] && !copied["tls "+t] {
+			j := slices.IndexFunc(src.TLS, func(b TLS) bool { return b.Name == t })
+			if j < 0 {
+				return nil, &ReferenceError{Kind: "tls", Name: t, Path: "tls"}
+			}
+			copied["tls "+t] = true
+			out.TLS = append(out.TLS, src.TLS[j])
+		}
+	}
+	out.RemoteServers = []RemoteServers{{Name: list, Servers: servers}}
+
+	secondary := func(z Zone) Zone {
+		copyRefs(z.AllowTransfer)
+		return Zone{Name: z.Name, Class: z.Class, Type: ZoneSecondary, File: file(z), PrimariesRef: list,
+			AllowTransfer: z.AllowTransfer, MasterfileFormat: z.MasterfileFormat, MasterfileStyle: z.MasterfileStyle}
+	}
+	for _, z := range src.Zones {
+		if z.Type == ZonePrimary {
+			out.Zones = append(out.Zones, secondary(z))
+		}
+	}
+	for _, v := range src.Views {
+		sv := View{Name: v.Name, Class: v.Class, MatchClients: v.MatchClients, MatchDestinations: v.MatchDestinations,
+			MatchRecursive: v.MatchRecursive, Recursion: v.Recursion}
+		copyRefs(v.MatchClients)
+		copyRefs(v.MatchDestinations)
+		for _, z := range v.Zones {
+			if z.Type == ZonePrimary {
+				sv.Zones = append(sv.Zones, secondary(z))
+			}
+		}
+		out.Views = append(out.Views, sv)
+	}
+
+	out.Options = opts.Options
+	if out.Options == nil && src.Options != nil {
+		o := src.Options
+		out.Options = &Options{Directory: o.Directory, ListenOn: o.ListenOn, ListenOnV6: o.ListenOnV6,
+			Recursion: o.Recursion, AllowTransfer: o.AllowTransfer}
+	}
+	if out.Options != nil {
+		copyRefs(out.Options.AllowTransfer)
+	}
+	if len(missing) > 0 {
+		return nil, errors.Join(missing...)
+	}
+
+	var errs []error
+	for _, is := range out.Validate() {
+		if is.Severity == SeverityError {
+			errs = append(errs, is.Err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("namedzone: generated secondary config is invalid: %w", err)
+	}
+	return out, nil
+}
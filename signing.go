@@ -0,0 +1,282 @@
+// File: pkg/namedzone/signing.go
+package namedzone
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dlukt/namedzone/zonefile"
+)
+
+// SigningInspectOptions configures InspectSigning.
+type SigningInspectOptions struct {
+	// FS reads zone and key files; nil reads the OS filesystem. Within an
+	// FS, absolute paths are taken relative to its root, as for InspectTLS.
+	FS fs.FS
+	// Now is the time signatures are checked at; zero means time.Now.
+	Now time.Time
+	// Warn is how close to expiry a signature is reported as stale; named
+	// refreshes signatures well before that. Zero means two days.
+	Warn time.Duration
+}
+
+// SigningStatus summarizes a SigningReport.
+type SigningStatus string
+
+const (
+	SigningOK         SigningStatus = "signed"
+	SigningUnsigned   SigningStatus = "unsigned"   // a policy is set but there is no signed data or no keys
+	SigningStale      SigningStatus = "stale"      // signatures expire within Warn, or the source is newer than the signed file
+	SigningUnexpected SigningStatus = "unexpected" // no policy, but signed data or keys are on disk
+	SigningUnknown    SigningStatus = "unknown"    // the data could not be read, e.g. a raw or map format file
+)
+
+// SigningReport correlates the signing settings of one zone with the files
+// named keeps for it.
+type SigningReport struct {
+	View   string        `json:"view,omitempty"`
+	Zone   string        `json:"zone"`
+	Status SigningStatus `json:"status"`
+
+	Policy        Inherited[string] `json:"policy"`
+	InlineSigning bool              `json:"inlineSigning"`
+
+	// Files as opened, resolved against the options directory. SignedFile
+	// is the file plus ".signed" for inline signing, else the file itself.
+	File         string   `json:"file,omitempty"`
+	SignedFile   string   `json:"signedFile,omitempty"`
+	Journal      string   `json:"journal,omitempty"` // set when it exists
+	KeyDirectory string   `json:"keyDirectory"`
+	KeyFiles     []string `json:"keyFiles,omitempty"` // K<zone>+<alg>+<tag>.key files
+
+	// Signatures counts the RRSIG records of the signed data; Expires is
+	// the earliest of their expiration times.
+	Signatures int       `json:"signatures"`
+	Expires    time.Time `json:"expires,omitzero"`
+
+	Problems []string `json:"problems,omitempty"`
+}
+
+// InspectSigning reports, for every primary zone and every zone that sets
+// inline-signing, whether what is on disk matches the configured intent: a
+// zone whose effective dnssec-policy is not "none" should have key files in
+// its key-directory and a signed file with current signatures; a zone
+// without a policy should have neither. Inline signing is the explicit
+// inline-signing option, or, as in named, implied by a policy on a zone
+// that does not accept dynamic updates. Only text format files are parsed.
+func (c *Config) InspectSigning(opts SigningInspectOptions) []SigningReport {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	warn := cmp.Or(opts.Warn, 48*time.Hour)
+	sfs := diskFS{fsys: opts.FS}
+
+	var out []SigningReport
+	for v, z := range c.AllZones() {
+		inline := zoneOption(z.stmt, "inline-signing")
+		if z.Type != ZonePrimary && inline == "" {
+			continue
+		}
+		view := ""
+		if v != nil {
+			view = v.Name
+		}
+		e, err := c.EffectiveZoneSettings(view, z.Name)
+		if err != nil {
+			continue
+		}
+		r := SigningReport{View: view, Zone: z.Name, Policy: e.DNSSECPolicy}
+		r.inspect(c, z, e, inline, sfs, now, warn)
+		out = append(out, r)
+	}
+	return out
+}
+
+func (r *SigningReport) inspect(c *Config, z *Zone, e *EffectiveSettings, inline string, sfs diskFS, now time.Time, warn time.Duration) {
+	problem := func(format string, args ...any) { r.Problems = append(r.Problems, fmt.Sprintf(format, args...)) }
+	signed := r.Policy.Value != "none"
+	dynamic := serializeMatchList(e.AllowUpdate.Value) != "{ none; }" || zoneOption(z.stmt, "update-policy") != ""
+	switch inline {
+	case "":
+		r.InlineSigning = signed && !dynamic
+	default:
+		r.InlineSigning = inline == "yes" || inline == "true"
+	}
+
+	r.KeyDirectory = c.keyDirectory(z)
+	name := keyOwner(z.Name)
+	if entries, err := sfs.readDir(r.KeyDirectory); err == nil {
+		prefix := "k" + name + ".+"
+		if name == "." {
+			prefix = "k.+"
+		}
+		for _, de := range entries {
+			if n := de.Name(); strings.HasPrefix(strings.ToLower(n), prefix) && strings.HasSuffix(n, ".key") {
+				r.KeyFiles = append(r.KeyFiles, filepath.Join(r.KeyDirectory, n))
+			}
+		}
+	} else if signed {
+		problem("key-directory: %v", err)
+	}
+
+	if z.File != "" {
+		r.File = c.inDirectory(z.File)
+		r.SignedFile = r.File
+		if r.InlineSigning {
+			r.SignedFile += ".signed"
+		}
+		if _, err := sfs.stat(r.SignedFile + ".jnl"); err == nil {
+			r.Journal = r.SignedFile + ".jnl"
+		}
+	}
+	r.readSigned(sfs, z, e.MasterfileFormat.Value, problem)
+
+	switch {
+	case !signed && (r.Signatures > 0 || len(r.KeyFiles) > 0):
+		r.Status = SigningUnexpected
+		problem("dnssec-policy is none, but signed data or key files exist")
+	case !signed:
+		r.Status = SigningUnsigned
+	case r.Status == SigningUnknown:
+	case len(r.KeyFiles) == 0 && r.Signatures == 0:
+		r.Status = SigningUnsigned
+		problem("dnssec-policy %q is set, but there are no keys and no signatures", r.Policy.Value)
+	case r.SignedFile == "":
+		r.Status = SigningUnknown
+	case r.Signatures == 0:
+		r.Status = SigningUnsigned
+		problem("no RRSIG records in %s", r.SignedFile)
+	case len(r.KeyFiles) == 0:
+		r.Status = SigningStale
+		problem("no key files for the zone in %s", r.KeyDirectory)
+	case !r.Expires.After(now):
+		r.Status = SigningStale
+		problem("signatures expired %s", r.Expires.UTC().Format(time.RFC3339))
+	case r.Expires.Sub(now) < warn:
+		r.Status = SigningStale
+		problem("signatures expire %s", r.Expires.UTC().Format(time.RFC3339))
+	case r.Status == SigningStale:
+	default:
+		r.Status = SigningOK
+	}
+}
+
+// readSigned counts the signatures of the signed file and, for inline
+// signing, checks that it is not older than the file it is made from.
+func (r *SigningReport) readSigned(sfs diskFS, z *Zone, format MasterfileFormat, problem func(string, ...any)) {
+	if r.SignedFile == "" {
+		return
+	}
+	data, err := sfs.read(r.SignedFile)
+	if err != nil {
+		if r.Policy.Value != "none" {
+			problem("signed file: %v", err)
+		}
+		return
+	}
+	if format == MasterfileRaw || format == MasterfileMap || !isText(data) {
+		r.Status = SigningUnknown
+		problem("%s is not a text zone file", r.SignedFile)
+		return
+	}
+	zf, err := zonefile.Parse(bytes.NewReader(data), z.Name)
+	if err != nil {
+		r.Status = SigningUnknown
+		problem("signed file: %v", err)
+		return
+	}
+	for _, rec := range zf.Records() {
+		if !strings.EqualFold(rec.Type, "RRSIG") {
+			continue
+		}
+		r.Signatures++
+		f := strings.Fields(rec.Data)
+		if len(f) < 5 {
+			continue
+		}
+		if t, ok := sigTime(f[4]); ok && (r.Expires.IsZero() || t.Before(r.Expires)) {
+			r.Expires = t
+		}
+	}
+	if r.InlineSigning && r.Signatures > 0 {
+		src, err1 := sfs.stat(r.File)
+		dst, err2 := sfs.stat(r.SignedFile)
+		if err1 == nil && err2 == nil && src.ModTime().After(dst.ModTime()) {
+			r.Status = SigningStale
+			problem("%s changed after %s was last written", r.File, r.SignedFile)
+		}
+	}
+}
+
+// keyDirectory returns the key-directory of z: the zone's own, else the
+// options', else the options directory, resolved as named does.
+func (c *Config) keyDirectory(z *Zone) string {
+	dir := zoneOption(z.stmt, "key-directory")
+	if dir == "" && c.Options != nil {
+		raw, _ := c.Options.Get("key-directory")
+		dir = trimQuotes(raw)
+	}
+	if dir == "" {
+		if c.Options != nil && c.Options.Directory != "" {
+			return c.Options.Directory
+		}
+		return "."
+	}
+	return c.inDirectory(dir)
+}
+
+// sigTime parses an RRSIG inception or expiration field: YYYYMMDDHHmmSS, or
+// seconds since the epoch.
+func sigTime(s string) (time.Time, bool) {
+	if len(s) == 14 {
+		t, err := time.Parse("20060102150405", s)
+		return t, err == nil
+	}
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(n), 0).UTC(), true
+}
+
+// isText reports whether data looks like a text zone file rather than the
+// raw or map format.
+func isText(data []byte) bool {
+	head := data[:min(len(data), 512)]
+	return !bytes.ContainsRune(head, 0)
+}
+
+// diskFS reads from an fs.FS, or the OS filesystem when it is nil.
+type diskFS struct{ fsys fs.FS }
+
+func (s diskFS) name(p string) string { return strings.TrimPrefix(path.Clean(p), "/") }
+
+func (s diskFS) read(p string) ([]byte, error) {
+	if s.fsys == nil {
+		return os.ReadFile(p)
+	}
+	return fs.ReadFile(s.fsys, s.name(p))
+}
+
+func (s diskFS) stat(p string) (fs.FileInfo, error) {
+	if s.fsys == nil {
+		return os.Stat(p)
+	}
+	return fs.Stat(s.fsys, s.name(p))
+}
+
+func (s diskFS) readDir(p string) ([]fs.DirEntry, error) {
+	if s.fsys == nil {
+		return os.ReadDir(p)
+	}
+	return fs.ReadDir(s.fsys, s.name(p))
+}
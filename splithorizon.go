@@ -0,0 +1,100 @@
+// File: pkg/namedzone/splithorizon.go
+package namedzone
+
+import "slices"
+
+// ZoneOverride holds the settings one view's instance of a mirrored zone
+// sets differently; empty fields take the source zone's value.
+type ZoneOverride struct {
+	File          string             `json:"file,omitempty"`
+	AllowTransfer []MatchTerm        `json:"allowTransfer,omitempty"`
+	AllowUpdate   []MatchTerm        `json:"allowUpdate,omitempty"`
+	AlsoNotify    []RemoteServerItem `json:"alsoNotify,omitempty"`
+}
+
+func (o ZoneOverride) apply(z *Zone) {
+	if o.File != "" {
+		z.File = o.File
+	}
+	if len(o.AllowTransfer) > 0 {
+		z.AllowTransfer = slices.Clone(o.AllowTransfer)
+	}
+	if len(o.AllowUpdate) > 0 {
+		z.AllowUpdate = slices.Clone(o.AllowUpdate)
+	}
+	if len(o.AlsoNotify) > 0 {
+		z.AlsoNotify = slices.Clone(o.AlsoNotify)
+	}
+}
+
+// MirrorZone adds or replaces z in each of views, with that view's entry
+// of overrides applied, for split-horizon setups where e.g. an internal
+// and an external view serve their own copy of a zone. Every view must
+// exist; a missing one yields a *ReferenceError before anything changes.
+func (c *Config) MirrorZone(z Zone, views []string, overrides map[string]ZoneOverride) error {
+	for _, v := range views {
+		if c.FindView(v) == nil {
+			return &ReferenceError{Kind: "view", Name: v, Path: "views"}
+		}
+	}
+	for _, v := range views {
+		c.UpsertZoneInView(v, mirrored(z, overrides[v]))
+	}
+	return nil
+}
+
+// SyncMirroredZone makes the instances of zone name in the other views
+// consistent with the one in view from after it was edited: each is
+// replaced by a copy of the source with its view's entry of overrides
+// applied. Instances that link with in-view are left alone. It returns the
+// instances that changed.
+func (c *Config) SyncMirroredZone(from, name string, overrides map[string]ZoneOverride) ([]ZoneRef, error) {
+	src := c.GetZoneInView(from, name)
+	if src == nil {
+		return nil, &ReferenceError{Kind: "zone", Name: name, Path: zonePath(from, name)}
+	}
+	var out []ZoneRef
+	for i := range c.Views {
+		v := &c.Views[i]
+		if v.Name == from {
+			continue
+		}
+		j := c.zonePosIn(v, src.Name)
+		if j < 0 || v.Zones[j].InView != "" {
+			continue
+		}
+		want := mirrored(*src, overrides[v.Name])
+		if v.Zones[j].Equal(want) {
+			continue
+		}
+		c.UpsertZoneInView(v.Name, want)
+		out = append(out, ZoneRef{View: v.Name, Zone: c.GetZoneInView(v.Name, src.Name)})
+	}
+	return out, nil
+}
+
+// LinkZone makes each of views serve the zone name of view from with an
+// in-view zone instead of a copy, so the instances cannot drift.
+func (c *Config) LinkZone(from, name string, views ...string) error {
+	src := c.GetZoneInView(from, name)
+	if src == nil {
+		return &ReferenceError{Kind: "zone", Name: name, Path: zonePath(from, name)}
+	}
+	for _, v := range views {
+		if c.FindView(v) == nil {
+			return &ReferenceError{Kind: "view", Name: v, Path: "views"}
+		}
+	}
+	for _, v := range views {
+		c.UpsertZoneInView(v, Zone{Name: src.Name, InView: from})
+	}
+	return nil
+}
+
+// mirrored is a deep copy of z with o applied.
+func mirrored(z Zone, o ZoneOverride) Zone {
+	n := &Config{Zones: []Zone{z}}
+	z = n.Clone().Zones[0]
+	o.apply(&z)
+	return z
+}
@@ -0,0 +1,117 @@
+// File: pkg/namedzone/summary.go
+package namedzone
+
+import "cmp"
+
+// Summary is an overview of a config for dashboards and reports.
+type Summary struct {
+	Zones       int              `json:"zones"`
+	ZonesByType map[ZoneType]int `json:"zonesByType"`
+	// ZonesByView counts zones per view; top-level zones are under "".
+	ZonesByView map[string]int `json:"zonesByView"`
+
+	Views         int `json:"views"`
+	ACLs          int `json:"acls"`
+	Keys          int `json:"keys"`
+	KeyStores     int `json:"keyStores"`
+	RemoteServers int `json:"remoteServers"`
+	TLS           int `json:"tls"`
+	HTTP          int `json:"http"`
+	Includes      int `json:"includes"`
+
+	Listeners  []ListenerSummary `json:"listeners,omitempty"`
+	Forwarders []ForwardTarget   `json:"forwarders,omitempty"`
+}
+
+// ListenerSummary is one listen-on or listen-on-v6 statement.
+type ListenerSummary struct {
+	Family    string   `json:"family"`    // "ipv4" or "ipv6"
+	Transport string   `json:"transport"` // "dns", "tls" (DoT), "https" (DoH) or "http"
+	Port      int      `json:"port"`      // explicit, or named's default for the transport
+	TLS       string   `json:"tls,omitempty"`
+	HTTP      string   `json:"http,omitempty"`
+	Addrs     []string `json:"addrs"`
+}
+
+// ForwardTarget is a forwarder and where it is configured.
+type ForwardTarget struct {
+	Scope   string `json:"scope"` // "options" or a zone path such as view[int].zone[example.com]
+	Address string `json:"address"`
+	Port    *int   `json:"port,omitempty"`
+	TLS     string `json:"tls,omitempty"`
+	Forward string `json:"forward,omitempty"` // first or only, when set at that scope
+}
+
+// Summary counts the items of c and lists its listeners and forwarders.
+// Listeners come from options; an unset listen-on or listen-on-v6 is
+// reported as named's default, plain DNS on port 53 with addrs { any; }.
+func (c *Config) Summary() Summary {
+	s := Summary{
+		ZonesByType:   map[ZoneType]int{},
+		ZonesByView:   map[string]int{},
+		Views:         len(c.Views),
+		ACLs:          len(c.ACLs),
+		Keys:          len(c.Keys),
+		KeyStores:     len(c.KeyStores),
+		RemoteServers: len(c.RemoteServers),
+		TLS:           len(c.TLS),
+		HTTP:          len(c.HTTP),
+		Includes:      len(c.Includes),
+	}
+	for v, z := range c.AllZones() {
+		view := ""
+		if v != nil {
+			view = v.Name
+		}
+		s.Zones++
+		s.ZonesByType[z.Type]++
+		s.ZonesByView[view]++
+	}
+
+	o := c.Options
+	if o == nil {
+		o = &Options{}
+	}
+	all := &Listen{Addrs: []MatchTerm{{ACLRef: "any"}}}
+	v4, v6 := cmp.Or(o.ListenOn, all), cmp.Or(o.ListenOnV6, all)
+	for _, l := range []struct {
+		family string
+		listen *Listen
+	}{{"ipv4", v4}, {"ipv6", v6}} {
+		s.Listeners = append(s.Listeners, listenerSummary(l.family, *l.listen))
+	}
+
+	forwarders := func(scope, forward string, ff []Forwarder) {
+		for _, f := range ff {
+			s.Forwarders = append(s.Forwarders, ForwardTarget{Scope: scope, Address: f.Address, Port: f.Port, TLS: f.TLS, Forward: forward})
+		}
+	}
+	forwarders("options", o.Forward, o.Forwarders)
+	for v, z := range c.AllZones() {
+		p := "zone[" + z.Name + "]"
+		if v != nil {
+			p = "view[" + v.Name + "]." + p
+		}
+		forwarders(p, z.Forward, z.Forwarders)
+	}
+	return s
+}
+
+func listenerSummary(family string, l Listen) ListenerSummary {
+	ls := ListenerSummary{Family: family, Transport: "dns", Port: 53, TLS: l.TLS, HTTP: l.HTTP}
+	switch {
+	case l.HTTP != "" && (l.TLS == "" || l.TLS == "none"):
+		ls.Transport, ls.Port = "http", 80
+	case l.HTTP != "":
+		ls.Transport, ls.Port = "https", 443
+	case l.TLS != "" && l.TLS != "none":
+		ls.Transport, ls.Port = "tls", 853
+	}
+	if l.Port != nil {
+		ls.Port = *l.Port
+	}
+	for _, t := range l.Addrs {
+		ls.Addrs = append(ls.Addrs, serializeMatchTerm(t))
+	}
+	return ls
+}
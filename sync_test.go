@@ -0,0 +1,172 @@
+// File: pkg/namedzone/sync_test.go
+package namedzone
+
+import (
+	"strings"
+	"testing"
+)
+
+const syncSrc = `// header
+
+// about a
+acl "a" { 10.0.0.1; };
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+options { directory "/x"; };
+`
+
+// TestSyncBlocks covers where Apply writes edited, new and removed items
+// under both order policies, and which comments go with them.
+func TestSyncBlocks(t *testing.T) {
+	addr := func(a string) []MatchTerm { return []MatchTerm{{Address: a}} }
+	for _, tc := range []struct {
+		name  string
+		order OrderPolicy
+		edit  func(c *Config)
+		want  string
+	}{
+		{"edit middle", OrderInPlace, func(c *Config) { c.ACLs[1].Elements = addr("10.9.9.9") }, `// header
+
+// about a
+acl "a" { 10.0.0.1; };
+// about b
+acl "b" {
+  10.9.9.9;
+};
+acl "c" { 10.0.0.3; };
+options { directory "/x"; };
+`},
+		{"insert between", OrderInPlace, func(c *Config) {
+			c.ACLs = append(c.ACLs[:1:1], append([]ACL{{Name: "n", Elements: addr("10.9.9.9")}}, c.ACLs[1:]...)...)
+		}, `// header
+
+// about a
+acl "a" { 10.0.0.1; };
+acl "n" {
+  10.9.9.9;
+};
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+options { directory "/x"; };
+`},
+		{"insert first", OrderInPlace, func(c *Config) {
+			c.ACLs = append([]ACL{{Name: "n", Elements: addr("10.9.9.9")}}, c.ACLs...)
+		}, `// header
+
+acl "n" {
+  10.9.9.9;
+};
+// about a
+acl "a" { 10.0.0.1; };
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+options { directory "/x"; };
+`},
+		{"delete first", OrderInPlace, func(c *Config) { c.ACLs = c.ACLs[1:] }, `// header
+
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+options { directory "/x"; };
+`},
+		{"shared stmt", OrderInPlace, func(c *Config) {
+			d := c.ACLs[0]
+			d.Name = "d"
+			c.ACLs = append(c.ACLs, d)
+		}, `// header
+
+// about a
+acl "a" { 10.0.0.1; };
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+acl "d" {
+  10.0.0.1;
+};
+options { directory "/x"; };
+`},
+		{"duplicate", OrderInPlace, func(c *Config) { c.ACLs = append(c.ACLs, c.ACLs[0]) }, `// header
+
+// about a
+acl "a" { 10.0.0.1; };
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+acl "a" {
+  10.0.0.1;
+};
+options { directory "/x"; };
+`},
+		{"edit first", OrderInPlace, func(c *Config) { c.ACLs[0].Elements = addr("10.9.9.9") }, `// header
+
+// about a
+acl "a" {
+  10.9.9.9;
+};
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+options { directory "/x"; };
+`},
+		{"reorder in place", OrderInPlace, func(c *Config) {
+			c.ACLs[0], c.ACLs[2] = c.ACLs[2], c.ACLs[0]
+		}, `// header
+
+// about a
+acl "a" { 10.0.0.1; };
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+options { directory "/x"; };
+`},
+		{"reorder items", OrderItems, func(c *Config) {
+			c.ACLs[0], c.ACLs[2] = c.ACLs[2], c.ACLs[0]
+		}, `// header
+
+acl "c" { 10.0.0.3; };
+// about b
+acl "b" { 10.0.0.2; };
+// about a
+acl "a" { 10.0.0.1; };
+options { directory "/x"; };
+`},
+		{"delete first items", OrderItems, func(c *Config) { c.ACLs = c.ACLs[1:] }, `// header
+
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+options { directory "/x"; };
+`},
+		{"insert items", OrderItems, func(c *Config) {
+			c.ACLs = append(c.ACLs, ACL{Name: "n", Elements: addr("10.9.9.9")})
+		}, `// header
+
+// about a
+acl "a" { 10.0.0.1; };
+// about b
+acl "b" { 10.0.0.2; };
+acl "c" { 10.0.0.3; };
+acl "n" {
+  10.9.9.9;
+};
+options { directory "/x"; };
+`},
+	} {
+		c, err := FromReader(strings.NewReader(syncSrc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.SetOrder(tc.order)
+		tc.edit(c)
+		got, err := c.Render()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tc.name, got, tc.want)
+		}
+	}
+}
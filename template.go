@@ -0,0 +1,216 @@
+// File: pkg/namedzone/template.go
+package namedzone
+
+import (
+	"slices"
+)
+
+// ZoneTemplate is a reusable set of zone settings. A template is applied to
+// its members: zones created with AddZoneFromTemplate or attached with
+// AttachTemplate. Settings the template leaves empty are not managed. A
+// member may override a managed setting; ApplyTemplate leaves overridden
+// settings alone and brings every other managed one back to the template.
+//
+// A template keeps its members and their overrides, so it is stored next to
+// named.conf (it marshals to JSON) rather than in it.
+type ZoneTemplate struct {
+	Name             string             `json:"name"`
+	AllowTransfer    []MatchTerm        `json:"allowTransfer,omitempty"`
+	AlsoNotify       []RemoteServerItem `json:"alsoNotify,omitempty"`
+	Notify           string             `json:"notify,omitempty"`
+	DNSSECPolicy     string             `json:"dnssecPolicy,omitempty"`
+	MasterfileFormat MasterfileFormat   `json:"masterfileFormat,omitempty"`
+
+	Members []TemplateMember `json:"members,omitempty"`
+}
+
+// TemplateMember is a zone a template applies to.
+type TemplateMember struct {
+	View string `json:"view,omitempty"`
+	Zone string `json:"zone"`
+	// Overrides lists the settings the zone sets itself, by JSON name
+	// ("allowTransfer", "alsoNotify", "notify", "dnssecPolicy",
+	// "masterfileFormat").
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+// templateField is a setting a template can manage.
+type templateField struct {
+	name string
+	get  func(*Zone) string
+	tmpl func(*ZoneTemplate) string
+	set  func(*Zone, *ZoneTemplate)
+}
+
+var templateFields = []templateField{
+	{"allowTransfer",
+		func(z *Zone) string { return matchListText(z.AllowTransfer) },
+		func(t *ZoneTemplate) string { return matchListText(t.AllowTransfer) },
+		func(z *Zone, t *ZoneTemplate) { z.AllowTransfer = slices.Clone(t.AllowTransfer) }},
+	{"alsoNotify",
+		func(z *Zone) string { return remoteListText(z.AlsoNotify) },
+		func(t *ZoneTemplate) string { return remoteListText(t.AlsoNotify) },
+		func(z *Zone, t *ZoneTemplate) { z.AlsoNotify = slices.Clone(t.AlsoNotify) }},
+	{"notify",
+		func(z *Zone) string { return z.Notify },
+		func(t *ZoneTemplate) string { return t.Notify },
+		func(z *Zone, t *ZoneTemplate) { z.Notify = t.Notify }},
+	{"dnssecPolicy",
+		func(z *Zone) string { return z.DNSSECPolicy },
+		func(t *ZoneTemplate) string { return t.DNSSECPolicy },
+		func(z *Zone, t *ZoneTemplate) { z.DNSSECPolicy = t.DNSSECPolicy }},
+	{"masterfileFormat",
+		func(z *Zone) string { return string(z.MasterfileFormat) },
+		func(t *ZoneTemplate) string { return string(t.MasterfileFormat) },
+		func(z *Zone, t *ZoneTemplate) { z.MasterfileFormat = t.MasterfileFormat }},
+}
+
+func matchListText(terms []MatchTerm) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	return serializeMatchList(terms)
+}
+
+func remoteListText(items []RemoteServerItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return serializeRemoteServerList(items)
+}
+
+// AddZoneFromTemplate adds z to view (top level when view is empty) with
+// the settings of t filled in where z leaves them empty, and makes it a
+// member of t. Settings z sets to other values than t are recorded as
+// overrides. A zone of that name already there yields a *ConflictError.
+func (c *Config) AddZoneFromTemplate(t *ZoneTemplate, view string, z Zone) error {
+	if c.GetZoneInView(view, z.Name) != nil {
+		return &ConflictError{Kind: "zone", Name: z.Name, Msg: "already defined"}
+	}
+	m := TemplateMember{View: view, Zone: c.zoneKey(z.Name)}
+	for _, f := range templateFields {
+		switch want, have := f.tmpl(t), f.get(&z); {
+		case want == "":
+		case have == "":
+			f.set(&z, t)
+		case have != want:
+			m.Overrides = append(m.Overrides, f.name)
+		}
+	}
+	if view == "" {
+		c.UpsertZone(z)
+	} else {
+		c.UpsertZoneInView(view, z)
+	}
+	t.setMember(m)
+	return nil
+}
+
+// AttachTemplate makes an existing zone a member of t. Managed settings the
+// zone sets to other values are recorded as overrides, so attaching changes
+// nothing; drop them with ClearOverride and ApplyTemplate to converge.
+func (c *Config) AttachTemplate(t *ZoneTemplate, view, zone string) error {
+	z := c.GetZoneInView(view, zone)
+	if z == nil {
+		return &ReferenceError{Kind: "zone", Name: zone, Path: zonePath(view, zone)}
+	}
+	m := TemplateMember{View: view, Zone: z.Name}
+	for _, f := range templateFields {
+		if want, have := f.tmpl(t), f.get(z); want != "" && have != "" && have != want {
+			m.Overrides = append(m.Overrides, f.name)
+		}
+	}
+	t.setMember(m)
+	return nil
+}
+
+// ApplyTemplate sets the managed settings of every member of t that the
+// member does not override to the template's values, and reports each
+// change. A member zone missing from c yields a *ReferenceError before
+// anything is changed.
+func (c *Config) ApplyTemplate(t *ZoneTemplate) ([]Rewrite, error) {
+	zones := make([]*Zone, len(t.Members))
+	for i, m := range t.Members {
+		if zones[i] = c.GetZoneInView(m.View, m.Zone); zones[i] == nil {
+			return nil, &ReferenceError{Kind: "zone", Name: m.Zone, Path: zonePath(m.View, m.Zone)}
+		}
+	}
+	var out []Rewrite
+	for i, m := range t.Members {
+		z := zones[i]
+		for _, f := range templateFields {
+			want, have := f.tmpl(t), f.get(z)
+			if want == "" || want == have || slices.Contains(m.Overrides, f.name) {
+				continue
+			}
+			f.set(z, t)
+			out = append(out, Rewrite{Path: zonePath(m.View, m.Zone) + "." + f.name, From: have, To: want})
+		}
+	}
+	if len(out) > 0 {
+		c.audit("ApplyTemplate", t.Name, nil)
+	}
+	return out, nil
+}
+
+// TemplateDrift reports what ApplyTemplate would change, without changing
+// anything: the member zones that drifted from t outside their overrides.
+func (c *Config) TemplateDrift(t *ZoneTemplate) ([]Rewrite, error) {
+	return c.Clone().ApplyTemplate(t)
+}
+
+// Override records that the member zone sets field itself. It reports
+// false when the zone is not a member.
+func (t *ZoneTemplate) Override(view, zone, field string) bool {
+	m := t.member(view, zone)
+	if m == nil {
+		return false
+	}
+	if !slices.Contains(m.Overrides, field) {
+		m.Overrides = append(m.Overrides, field)
+	}
+	return true
+}
+
+// ClearOverride puts field of the member zone back under the template; the
+// next ApplyTemplate sets it. It reports false when the zone is not a member.
+func (t *ZoneTemplate) ClearOverride(view, zone, field string) bool {
+	m := t.member(view, zone)
+	if m == nil {
+		return false
+	}
+	m.Overrides = slices.DeleteFunc(m.Overrides, func(f string) bool { return f == field })
+	return true
+}
+
+// Detach removes the zone from the members of t and reports whether it was
+// one. Its settings stay as they are.
+func (t *ZoneTemplate) Detach(view, zone string) bool {
+	n := len(t.Members)
+	t.Members = slices.DeleteFunc(t.Members, func(m TemplateMember) bool { return m.View == view && m.Zone == zone })
+	return len(t.Members) != n
+}
+
+func (t *ZoneTemplate) member(view, zone string) *TemplateMember {
+	if i := slices.IndexFunc(t.Members, func(m TemplateMember) bool { return m.View == view && m.Zone == zone }); i >= 0 {
+		return &t.Members[i]
+	}
+	return nil
+}
+
+func (t *ZoneTemplate) setMember(m TemplateMember) {
+	if p := t.member(m.View, m.Zone); p != nil {
+		*p = m
+		return
+	}
+	t.Members = append(t.Members, m)
+}
+
+// zonePath is the GetPath path of a zone in view (top level when empty).
+func zonePath(view, zone string) string {
+	p := "zone[" + zone + "]"
+	if view != "" {
+		p = "view[" + view + "]." + p
+	}
+	return p
+}
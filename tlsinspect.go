@@ -0,0 +1,198 @@
+// File: pkg/namedzone/tlsinspect.go
+package namedzone
+
+import (
+	"cmp"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// TLSInspectOptions configures InspectTLS.
+type TLSInspectOptions struct {
+	// FS reads the certificate and key files; nil reads the OS
+	// filesystem. Within an FS, absolute paths are taken relative to its
+	// root, as for includes with FromFS.
+	FS fs.FS
+	// Now is the time certificates are checked at; zero means time.Now.
+	Now time.Time
+	// Warn is how close to expiry a certificate is reported as expiring;
+	// zero means 30 days.
+	Warn time.Duration
+}
+
+// TLSStatus summarizes a TLSReport.
+type TLSStatus string
+
+const (
+	TLSOK       TLSStatus = "ok"
+	TLSExpiring TLSStatus = "expiring" // valid, but expires within Warn
+	TLSExpired  TLSStatus = "expired"  // expired or not yet valid
+	TLSInvalid  TLSStatus = "invalid"  // unreadable files, key mismatch or no valid chain
+)
+
+// TLSReport is the result of inspecting one tls block.
+type TLSReport struct {
+	Name   string    `json:"name"`
+	Status TLSStatus `json:"status"`
+
+	// Files as opened, resolved against the options directory.
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+	CAFile   string `json:"caFile,omitempty"`
+
+	Subject     string    `json:"subject,omitempty"`
+	Issuer      string    `json:"issuer,omitempty"`
+	DNSNames    []string  `json:"dnsNames,omitempty"`
+	IPAddresses []string  `json:"ipAddresses,omitempty"`
+	NotBefore   time.Time `json:"notBefore,omitzero"`
+	NotAfter    time.Time `json:"notAfter,omitzero"`
+
+	KeyMatch bool `json:"keyMatch"`
+	// Chain holds the subjects of the verified chain, leaf first. Roots
+	// come from ca-file, or the system pool when it is unset.
+	Chain []string `json:"chain,omitempty"`
+
+	Problems []string `json:"problems,omitempty"`
+}
+
+// InspectTLS loads the cert-file, key-file and ca-file of every tls block
+// that names a certificate, checks that the key matches the certificate,
+// verifies the chain, and reports subject, SANs and validity. Problems are
+// collected per block rather than returned, so one broken block does not
+// hide the others.
+func (c *Config) InspectTLS(opts TLSInspectOptions) []TLSReport {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	warn := cmp.Or(opts.Warn, 30*24*time.Hour)
+	read := func(name string) ([]byte, error) {
+		if opts.FS == nil {
+			return os.ReadFile(name)
+		}
+		return fs.ReadFile(opts.FS, strings.TrimPrefix(path.Clean(name), "/"))
+	}
+
+	var out []TLSReport
+	for _, t := range c.TLS {
+		if t.CertFile == "" {
+			continue
+		}
+		r := TLSReport{Name: t.Name, CertFile: c.inDirectory(t.CertFile)}
+		r.inspect(c, t, read, now, warn)
+		out = append(out, r)
+	}
+	return out
+}
+
+func (r *TLSReport) inspect(c *Config, t TLS, read func(string) ([]byte, error), now time.Time, warn time.Duration) {
+	problem := func(format string, args ...any) { r.Problems = append(r.Problems, fmt.Sprintf(format, args...)) }
+	r.Status = TLSInvalid
+
+	certPEM, err := read(r.CertFile)
+	if err != nil {
+		problem("cert-file: %v", err)
+		return
+	}
+	certs, err := parseCerts(certPEM)
+	if err != nil {
+		problem("cert-file: %v", err)
+		return
+	}
+	leaf := certs[0]
+	r.Subject, r.Issuer = leaf.Subject.String(), leaf.Issuer.String()
+	r.DNSNames = leaf.DNSNames
+	for _, ip := range leaf.IPAddresses {
+		r.IPAddresses = append(r.IPAddresses, ip.String())
+	}
+	r.NotBefore, r.NotAfter = leaf.NotBefore, leaf.NotAfter
+
+	if t.KeyFile == "" {
+		problem("no key-file")
+	} else {
+		r.KeyFile = c.inDirectory(t.KeyFile)
+		keyPEM, err := read(r.KeyFile)
+		switch {
+		case err != nil:
+			problem("key-file: %v", err)
+		default:
+			if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+				problem("key-file: %v", err)
+			} else {
+				r.KeyMatch = true
+			}
+		}
+	}
+
+	vo := x509.VerifyOptions{Intermediates: x509.NewCertPool(), CurrentTime: now, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
+	for _, ic := range certs[1:] {
+		vo.Intermediates.AddCert(ic)
+	}
+	if t.CAFile != "" {
+		r.CAFile = c.inDirectory(t.CAFile)
+		caPEM, err := read(r.CAFile)
+		if err != nil {
+			problem("ca-file: %v", err)
+			return
+		}
+		cas, err := parseCerts(caPEM)
+		if err != nil {
+			problem("ca-file: %v", err)
+			return
+		}
+		vo.Roots = x509.NewCertPool()
+		for _, ca := range cas {
+			vo.Roots.AddCert(ca)
+		}
+	}
+	if chains, err := leaf.Verify(vo); err != nil {
+		problem("chain: %v", err)
+	} else {
+		for _, ch := range chains[0] {
+			r.Chain = append(r.Chain, ch.Subject.String())
+		}
+	}
+
+	switch {
+	case now.Before(leaf.NotBefore) || !now.Before(leaf.NotAfter):
+		r.Status = TLSExpired
+	case len(r.Problems) > 0:
+		r.Status = TLSInvalid
+	case leaf.NotAfter.Sub(now) < warn:
+		r.Status = TLSExpiring
+		problem("expires %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+	default:
+		r.Status = TLSOK
+	}
+}
+
+// parseCerts returns the certificates in PEM data, in order.
+func parseCerts(data []byte) ([]*x509.Certificate, error) {
+	var out []*x509.Certificate
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+		if b == nil {
+			break
+		}
+		if b.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cert)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no certificate found")
+	}
+	return out, nil
+}
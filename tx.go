@@ -0,0 +1,124 @@
+// File: pkg/namedzone/tx.go
+package namedzone
+
+import (
+	"errors"
+
+	nc "github.com/dlukt/namedconf"
+)
+
+// ErrTxDone is returned by Tx methods after Commit or Rollback.
+var ErrTxDone = errors.New("namedzone: transaction already committed or rolled back")
+
+// Tx is a working copy of a Config for edits that must land together or
+// not at all. Edit Config() like any Config; nothing reaches the Config the
+// transaction was started on until Commit.
+type Tx struct {
+	base *Config
+	work *Config
+	done bool
+}
+
+// Begin starts a transaction on c. Edits made to c directly while the
+// transaction is open are overwritten by Commit.
+func (c *Config) Begin() (*Tx, error) {
+	return &Tx{base: c, work: c.Clone()}, nil
+}
+
+// Config returns the working copy. It has no AST of its own.
+func (tx *Tx) Config() *Config { return tx.work }
+
+// Commit validates the working copy as a whole and, if it holds no
+// SeverityError issues, makes it the content of the original Config and
+// applies it to the AST. On a validation error the transaction stays open
+// so the edits can be fixed.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	if err := tx.check(); err != nil {
+		return err
+	}
+	tx.base.replaceTyped(tx.work)
+	if err := tx.base.Apply(nil); err != nil {
+		return err
+	}
+	tx.done = true
+	tx.base.audit("Commit", "", nil)
+	return nil
+}
+
+// CommitAndSave is Commit followed by Save(path). If the save fails, the
+// original Config and its AST are put back as they were before the commit
+// and the transaction stays open.
+func (tx *Tx) CommitAndSave(path string) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	if err := tx.check(); err != nil {
+		return err
+	}
+	saved := tx.base.capture()
+	tx.base.replaceTyped(tx.work)
+	err := tx.base.Apply(nil)
+	if err == nil {
+		err = tx.base.Save(path)
+	}
+	if err != nil {
+		tx.base.restore(saved)
+		return err
+	}
+	tx.done = true
+	tx.base.audit("Commit", "", nil)
+	return nil
+}
+
+// Rollback discards the working copy.
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	return nil
+}
+
+// check returns the writability error or the SeverityError issues of the
+// working copy, joined.
+func (tx *Tx) check() error {
+	if err := tx.work.checkWritable(); err != nil {
+		return err
+	}
+	var errs []error
+	for _, is := range tx.work.Validate() {
+		if is.Severity == SeverityError {
+			errs = append(errs, is.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// state is a Config and the statement lists of its files at one point in
+// time, enough to undo a commit: the commit swaps in the working copy's
+// items and Apply replaces node lists, leaving the captured ones intact.
+type state struct {
+	cfg   Config
+	nodes map[*nc.File][]nc.Node
+}
+
+func (c *Config) capture() state {
+	s := state{cfg: *c, nodes: map[*nc.File][]nc.Node{}}
+	if c.ast != nil {
+		s.nodes[c.ast] = c.ast.Nodes
+	}
+	for _, f := range c.files {
+		s.nodes[f] = f.Nodes
+	}
+	return s
+}
+
+func (c *Config) restore(s state) {
+	*c = s.cfg
+	for f, nodes := range s.nodes {
+		f.Nodes = nodes
+	}
+}
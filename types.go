@@ -1,7 +1,13 @@
 // File: pkg/namedzone/types.go
 package namedzone
 
-import "github.com/dlukt/namedconf"
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+
+	"github.com/dlukt/namedconf"
+)
 
 // Config is a JSON-friendly projection of named.conf.
 // Unknown statements are preserved via underlying AST references.
@@ -20,13 +26,43 @@ type Config struct {
 	Views         []View          `json:"views,omitempty"`
 	Zones         []Zone          `json:"zones,omitempty"`
 
-	ast *namedconf.File `json:"-"`
+	// ModernizeKeywords makes Apply emit modern spellings (primaries,
+	// remote-servers, type primary/secondary) for statements that were
+	// loaded with their legacy names. By default the original spelling is kept.
+	ModernizeKeywords bool `json:"modernizeKeywords,omitempty"`
+
+	ast    *namedconf.File `json:"-"`
+	logger *slog.Logger    `json:"-"`
+
+	// set by LoadTree: root file path, every parsed file by absolute path,
+	// and the file that receives items without an origin
+	root   string
+	files  map[string]*namedconf.File
+	target string
+	fsys   fs.FS // set by FromFS; nil for the OS filesystem
+
+	// build output of every loaded or written statement, by statement;
+	// see markClean
+	clean  map[*namedconf.Stmt]string
+	order  OrderPolicy
+	format *Format
+	lock   *os.File // held since LoadTreeWith with LoadOptions.Lock
+
+	history *History
+	auditor *auditor
+
+	validators []func(*Config) error
+	custom     map[string][]customItem // by keyword; see RegisterStatement
+	savedAs    string                  // absolute path of the root file on disk, for History
+	index      *index                  // name lookups; see index.go
+	idn        bool                    // see SetIDN
 }
 
 // Include directive.
 type Include struct {
-	Path string          `json:"path"`
-	stmt *namedconf.Stmt `json:"-"`
+	Path   string          `json:"path"`
+	stmt   *namedconf.Stmt `json:"-"`
+	origin string
 }
 
 // ACL block.
@@ -34,6 +70,7 @@ type ACL struct {
 	Name     string          `json:"name"`
 	Elements []MatchTerm     `json:"elements"`
 	stmt     *namedconf.Stmt `json:"-"`
+	origin   string
 }
 
 // MatchTerm is a simplified address_match_element for JSON.
@@ -51,6 +88,7 @@ type Key struct {
 	Algorithm string          `json:"algorithm"`
 	Secret    string          `json:"secret"`
 	stmt      *namedconf.Stmt `json:"-"`
+	origin    string
 }
 
 // KeyStore block (PKCS#11 etc.).
@@ -58,6 +96,7 @@ type KeyStore struct {
 	Name      string          `json:"name"`
 	PKCS11URI string          `json:"pkcs11Uri,omitempty"`
 	stmt      *namedconf.Stmt `json:"-"`
+	origin    string
 }
 
 // RemoteServers block: reusable named server lists.
@@ -65,6 +104,9 @@ type RemoteServers struct {
 	Name    string             `json:"name"`
 	Servers []RemoteServerItem `json:"servers"`
 	stmt    *namedconf.Stmt    `json:"-"`
+	origin  string
+
+	keyword string // "masters"/"primaries" when loaded from a legacy block
 }
 
 type RemoteServerItem struct {
@@ -88,6 +130,7 @@ type TLS struct {
 	RemoteHost     string          `json:"remoteHostname,omitempty"`
 	SessionTickets *bool           `json:"sessionTickets,omitempty"`
 	stmt           *namedconf.Stmt `json:"-"`
+	origin         string
 }
 
 // HTTP block (DoH endpoints).
@@ -97,13 +140,18 @@ type HTTP struct {
 	ListenerClients      *int            `json:"listenerClients,omitempty"`
 	StreamsPerConnection *int            `json:"streamsPerConnection,omitempty"`
 	stmt                 *namedconf.Stmt `json:"-"`
+	origin               string
 }
 
-// Controls channels.
+// Controls channels. A nil Controls means the statement is absent and named
+// opens its default rndc channel; Disabled marks an explicit `controls { };`,
+// which turns rndc off. When Disabled is set, Inet and Unix are ignored.
 type Controls struct {
-	Inet []ControlInet   `json:"inet,omitempty"`
-	Unix []ControlUnix   `json:"unix,omitempty"`
-	stmt *namedconf.Stmt `json:"-"`
+	Inet     []ControlInet   `json:"inet,omitempty"`
+	Unix     []ControlUnix   `json:"unix,omitempty"`
+	Disabled bool            `json:"disabled,omitempty"`
+	stmt     *namedconf.Stmt `json:"-"`
+	origin   string
 }
 
 type ControlInet struct {
@@ -116,7 +164,7 @@ type ControlInet struct {
 
 type ControlUnix struct {
 	Path     string   `json:"path"`
-	Perm     int      `json:"perm"`
+	Perm     int      `json:"perm"` // socket mode, e.g. 0o600
 	Owner    int      `json:"owner"`
 	Group    int      `json:"group"`
 	Keys     []string `json:"keys,omitempty"`
@@ -125,9 +173,16 @@ type ControlUnix struct {
 
 // Logging config.
 type Logging struct {
-	Channels   []LogChannel    `json:"channels,omitempty"`
-	Categories []LogCategory   `json:"categories,omitempty"`
-	stmt       *namedconf.Stmt `json:"-"`
+	Channels   []LogChannel  `json:"channels,omitempty"`
+	Categories []LogCategory `json:"categories,omitempty"`
+
+	// AllowUnknownCategories skips the KnownLogCategories check of
+	// RouteCategory and silences the Validate warning, e.g. for categories
+	// added by a newer BIND than this package knows about.
+	AllowUnknownCategories bool `json:"allowUnknownCategories,omitempty"`
+
+	stmt   *namedconf.Stmt `json:"-"`
+	origin string
 }
 
 type LogChannel struct {
@@ -136,17 +191,35 @@ type LogChannel struct {
 	Syslog        *LogSyslogDest `json:"syslog,omitempty"`
 	Stderr        bool           `json:"stderr,omitempty"`
 	Null          bool           `json:"null,omitempty"`
-	Severity      string         `json:"severity,omitempty"`
-	PrintTime     *bool          `json:"printTime,omitempty"`
+	Severity      *LogSeverity   `json:"severity,omitempty"`
+	PrintTime     PrintTime      `json:"printTime,omitempty"`
 	PrintCategory *bool          `json:"printCategory,omitempty"`
 	PrintSeverity *bool          `json:"printSeverity,omitempty"`
 	Buffered      *bool          `json:"buffered,omitempty"`
 }
 
+// LogSeverity is a channel severity; DebugLevel is only meaningful with
+// Level "debug" (`severity debug 3;`).
+type LogSeverity struct {
+	Level      string `json:"level"`
+	DebugLevel *int   `json:"debugLevel,omitempty"`
+}
+
+// PrintTime is the print-time setting of a channel.
+type PrintTime string
+
+const (
+	PrintTimeYes        PrintTime = "yes"
+	PrintTimeNo         PrintTime = "no"
+	PrintTimeISO8601    PrintTime = "iso8601"
+	PrintTimeISO8601UTC PrintTime = "iso8601-utc"
+	PrintTimeLocal      PrintTime = "local"
+)
+
 type LogFileDest struct {
 	Path     string `json:"path"`
 	Versions *int   `json:"versions,omitempty"`
-	Size     string `json:"size,omitempty"`
+	Size     *Size  `json:"size,omitempty"`
 	Suffix   string `json:"suffix,omitempty"`
 	Severity string `json:"severity,omitempty"`
 }
@@ -163,19 +236,91 @@ type LogCategory struct {
 
 // Options (subset of widely used, non-deprecated settings).
 type Options struct {
-	Directory        string          `json:"directory,omitempty"`
-	Recursion        *bool           `json:"recursion,omitempty"`
-	AllowQuery       []MatchTerm     `json:"allowQuery,omitempty"`
-	AllowTransfer    []MatchTerm     `json:"allowTransfer,omitempty"`
-	AllowUpdate      []MatchTerm     `json:"allowUpdate,omitempty"`
-	ListenOn         *Listen         `json:"listenOn,omitempty"`
-	ListenOnV6       *Listen         `json:"listenOnV6,omitempty"`
-	Forwarders       []Forwarder     `json:"forwarders,omitempty"`
-	Forward          string          `json:"forward,omitempty"`
-	DNSSECValidation string          `json:"dnssecValidation,omitempty"`
-	RRsetOrder       []RRsetOrder    `json:"rrsetOrder,omitempty"`
-	Other            []RawKV         `json:"other,omitempty"`
-	stmt             *namedconf.Stmt `json:"-"`
+	Directory        string           `json:"directory,omitempty"`
+	Recursion        *bool            `json:"recursion,omitempty"`
+	AllowQuery       []MatchTerm      `json:"allowQuery,omitempty"`
+	AllowTransfer    []MatchTerm      `json:"allowTransfer,omitempty"`
+	AllowUpdate      []MatchTerm      `json:"allowUpdate,omitempty"`
+	ListenOn         *Listen          `json:"listenOn,omitempty"`
+	ListenOnV6       *Listen          `json:"listenOnV6,omitempty"`
+	Forwarders       []Forwarder      `json:"forwarders,omitempty"`
+	Forward          string           `json:"forward,omitempty"`
+	DNSSECValidation string           `json:"dnssecValidation,omitempty"`
+	MasterfileFormat MasterfileFormat `json:"masterfileFormat,omitempty"`
+	MasterfileStyle  MasterfileStyle  `json:"masterfileStyle,omitempty"`
+	RRsetOrder       []RRsetOrder     `json:"rrsetOrder,omitempty"`
+
+	// Resolver tuning; also settable per view.
+	Prefetch          *Prefetch         `json:"prefetch,omitempty"`
+	QNameMinimization QNameMinimization `json:"qnameMinimization,omitempty"`
+	MinimalResponses  MinimalResponses  `json:"minimalResponses,omitempty"`
+	MinimalAny        *bool             `json:"minimalAny,omitempty"`
+
+	// Privacy; also settable per view. version, hostname and server-id
+	// are below.
+	ResponsePadding *ResponsePadding `json:"responsePadding,omitempty"`
+
+	// GSS-TSIG / session key settings (Active Directory dynamic updates).
+	TKeyGSSAPIKeytab     string `json:"tkeyGssapiKeytab,omitempty"`
+	TKeyGSSAPICredential string `json:"tkeyGssapiCredential,omitempty"`
+	TKeyDomain           string `json:"tkeyDomain,omitempty"`
+	SessionKeyFile       string `json:"sessionKeyfile,omitempty"`
+	SessionKeyName       string `json:"sessionKeyname,omitempty"`
+	SessionKeyAlg        string `json:"sessionKeyalg,omitempty"`
+
+	// Cache and journal limits.
+	MaxCacheSize   *Size     `json:"maxCacheSize,omitempty"`
+	MaxCacheTTL    *Duration `json:"maxCacheTtl,omitempty"`
+	MaxNCacheTTL   *Duration `json:"maxNcacheTtl,omitempty"`
+	MaxJournalSize *Size     `json:"maxJournalSize,omitempty"`
+
+	// Zone database limits; lmdb-mapsize is also settable per view, and
+	// max-records per view and zone.
+	LMDBMapSize *Size `json:"lmdbMapsize,omitempty"`
+	MaxRecords  *int  `json:"maxRecords,omitempty"`
+
+	// AllowNewZones permits rndc addzone (allow-new-zones); also settable
+	// per view.
+	AllowNewZones *bool `json:"allowNewZones,omitempty"`
+
+	// Identity disclosed via CHAOS TXT / NSID.
+	Version  *ServerIdent `json:"version,omitempty"`
+	Hostname *ServerIdent `json:"hostname,omitempty"`
+	ServerID *ServerIdent `json:"serverId,omitempty"`
+
+	// Paths to files named writes at runtime. PIDFile and LockFile "none"
+	// disable the file and are written as the bare keyword, unless the
+	// loaded file quoted it; other paths are always quoted.
+	PIDFile           string `json:"pidFile,omitempty"`
+	StatisticsFile    string `json:"statisticsFile,omitempty"`
+	DumpFile          string `json:"dumpFile,omitempty"`
+	SecrootsFile      string `json:"secrootsFile,omitempty"`
+	RecursingFile     string `json:"recursingFile,omitempty"`
+	MemstatisticsFile string `json:"memstatisticsFile,omitempty"`
+	LockFile          string `json:"lockFile,omitempty"`
+
+	Other  []RawKV         `json:"other,omitempty"`
+	stmt   *namedconf.Stmt `json:"-"`
+	origin string
+
+	// path options loaded as a quoted "none", i.e. a file named none
+	quotedNone map[string]bool
+}
+
+// ResponsePadding is response-padding: EDNS responses to Clients that asked
+// for padding (RFC 7830) are padded to a multiple of BlockSize bytes.
+type ResponsePadding struct {
+	Clients   []MatchTerm `json:"clients"`
+	BlockSize int         `json:"blockSize"`
+}
+
+// ServerIdent is the value of version, hostname or server-id: either a
+// quoted string or a bare keyword (none, or hostname for server-id).
+// `version none;` and `version "none";` differ: the first hides the answer,
+// the second returns the literal text "none".
+type ServerIdent struct {
+	Keyword string `json:"keyword,omitempty"`
+	Value   string `json:"value,omitempty"`
 }
 
 type Listen struct {
@@ -192,16 +337,39 @@ type Forwarder struct {
 }
 
 type TrustAnchors struct {
-	Items []TrustAnchorItem `json:"items"`
-	stmt  *namedconf.Stmt   `json:"-"`
+	Items  []TrustAnchorItem `json:"items"`
+	stmt   *namedconf.Stmt   `json:"-"`
+	origin string
 }
 
+// TrustAnchorItem is one anchor line inside trust-anchors. DNSKEY-style
+// anchors (static-key/initial-key) use Flags, Protocol, Algorithm and Data
+// (base64 public key); DS-style anchors (static-ds/initial-ds) use KeyTag,
+// Algorithm, DigestType and Data (hex digest).
 type TrustAnchorItem struct {
-	Name   string `json:"name"`
-	DS     string `json:"ds,omitempty"`
-	DNSKey string `json:"dnskey,omitempty"`
+	Name       string          `json:"name"`
+	Kind       TrustAnchorKind `json:"kind"`
+	Flags      int             `json:"flags,omitempty"`
+	Protocol   int             `json:"protocol,omitempty"`
+	KeyTag     int             `json:"keyTag,omitempty"`
+	Algorithm  int             `json:"algorithm"`
+	DigestType int             `json:"digestType,omitempty"`
+	Data       string          `json:"data"`
 }
 
+// TrustAnchorKind selects how an anchor is interpreted by named.
+type TrustAnchorKind string
+
+const (
+	AnchorStaticKey  TrustAnchorKind = "static-key"
+	AnchorInitialKey TrustAnchorKind = "initial-key"
+	AnchorStaticDS   TrustAnchorKind = "static-ds"
+	AnchorInitialDS  TrustAnchorKind = "initial-ds"
+)
+
+// IsDS reports whether the anchor carries a DS digest rather than a DNSKEY.
+func (k TrustAnchorKind) IsDS() bool { return k == AnchorStaticDS || k == AnchorInitialDS }
+
 type RRsetOrder struct {
 	Name  string `json:"name,omitempty"`
 	Type  string `json:"type,omitempty"`
@@ -215,15 +383,29 @@ type RawKV struct {
 
 // View block.
 type View struct {
-	Name              string          `json:"name"`
-	Class             string          `json:"class,omitempty"`
-	MatchClients      []MatchTerm     `json:"matchClients,omitempty"`
-	MatchDestinations []MatchTerm     `json:"matchDestinations,omitempty"`
-	Recursion         *bool           `json:"recursion,omitempty"`
-	TrustAnchors      *TrustAnchors   `json:"trustAnchors,omitempty"`
-	Zones             []Zone          `json:"zones,omitempty"`
-	Includes          []Include       `json:"includes,omitempty"`
-	stmt              *namedconf.Stmt `json:"-"`
+	Name              string        `json:"name"`
+	Class             string        `json:"class,omitempty"`
+	MatchClients      []MatchTerm   `json:"matchClients,omitempty"`
+	MatchDestinations []MatchTerm   `json:"matchDestinations,omitempty"`
+	MatchRecursive    *bool         `json:"matchRecursiveOnly,omitempty"`
+	Recursion         *bool         `json:"recursion,omitempty"`
+	TrustAnchors      *TrustAnchors `json:"trustAnchors,omitempty"`
+
+	// Resolver tuning; unset values are inherited from options.
+	Prefetch          *Prefetch         `json:"prefetch,omitempty"`
+	QNameMinimization QNameMinimization `json:"qnameMinimization,omitempty"`
+	MinimalResponses  MinimalResponses  `json:"minimalResponses,omitempty"`
+	MinimalAny        *bool             `json:"minimalAny,omitempty"`
+	ResponsePadding   *ResponsePadding  `json:"responsePadding,omitempty"`
+
+	LMDBMapSize   *Size `json:"lmdbMapsize,omitempty"`
+	MaxRecords    *int  `json:"maxRecords,omitempty"`
+	AllowNewZones *bool `json:"allowNewZones,omitempty"`
+
+	Zones    []Zone          `json:"zones,omitempty"`
+	Includes []Include       `json:"includes,omitempty"`
+	stmt     *namedconf.Stmt `json:"-"`
+	origin   string
 }
 
 // Zones.
@@ -246,6 +428,14 @@ type Zone struct {
 	Type  ZoneType `json:"type"`
 	File  string   `json:"file,omitempty"`
 
+	// InView serves the zone of the same name from another view (in-view);
+	// such a zone has no type or data of its own.
+	InView string `json:"inView,omitempty"`
+
+	// UnicodeName is the readable form of an internationalized Name, kept
+	// up to date when SetIDN is on. It is never written to named.conf.
+	UnicodeName string `json:"unicodeName,omitempty"`
+
 	PrimariesRef string             `json:"primariesRef,omitempty"`
 	Primaries    []RemoteServerItem `json:"primaries,omitempty"`
 
@@ -255,8 +445,102 @@ type Zone struct {
 	AllowUpdate   []MatchTerm        `json:"allowUpdate,omitempty"`
 	AllowTransfer []MatchTerm        `json:"allowTransfer,omitempty"`
 	AlsoNotify    []RemoteServerItem `json:"alsoNotify,omitempty"`
+	Notify        string             `json:"notify,omitempty"` // yes, no, explicit or primary-only
 
 	DNSSECPolicy string `json:"dnssecPolicy,omitempty"`
 
-	stmt *namedconf.Stmt `json:"-"`
+	MasterfileFormat MasterfileFormat `json:"masterfileFormat,omitempty"`
+	MasterfileStyle  MasterfileStyle  `json:"masterfileStyle,omitempty"`
+
+	// MaxRecords caps the number of records in the zone (max-records).
+	MaxRecords *int `json:"maxRecords,omitempty"`
+
+	stmt   *namedconf.Stmt `json:"-"`
+	origin string
+
+	// legacy spellings seen on load ("master"/"slave", "masters")
+	typeWord    string
+	primariesKW string
+}
+
+// MasterfileFormat is the on-disk zone file format (masterfile-format).
+type MasterfileFormat string
+
+const (
+	MasterfileText MasterfileFormat = "text"
+	MasterfileRaw  MasterfileFormat = "raw"
+	MasterfileMap  MasterfileFormat = "map"
+)
+
+// Valid reports whether f is a format accepted by named.
+func (f MasterfileFormat) Valid() bool {
+	switch f {
+	case MasterfileText, MasterfileRaw, MasterfileMap:
+		return true
+	}
+	return false
+}
+
+// MasterfileStyle controls text zone dump formatting (masterfile-style).
+type MasterfileStyle string
+
+const (
+	MasterfileStyleFull     MasterfileStyle = "full"
+	MasterfileStyleRelative MasterfileStyle = "relative"
+)
+
+// Valid reports whether s is a style accepted by named.
+func (s MasterfileStyle) Valid() bool {
+	switch s {
+	case MasterfileStyleFull, MasterfileStyleRelative:
+		return true
+	}
+	return false
+}
+
+// Prefetch is the prefetch option: records whose remaining TTL falls to
+// Trigger seconds are refreshed before they expire, if their original TTL
+// was at least Eligibility. A Trigger of 0 turns prefetching off.
+type Prefetch struct {
+	Trigger     int  `json:"trigger"`
+	Eligibility *int `json:"eligibility,omitempty"`
+}
+
+// QNameMinimization is the qname-minimization mode.
+type QNameMinimization string
+
+const (
+	QNameMinStrict   QNameMinimization = "strict"
+	QNameMinRelaxed  QNameMinimization = "relaxed"
+	QNameMinDisabled QNameMinimization = "disabled"
+	QNameMinOff      QNameMinimization = "off"
+)
+
+// Valid reports whether m is a mode accepted by named.
+func (m QNameMinimization) Valid() bool {
+	switch m {
+	case QNameMinStrict, QNameMinRelaxed, QNameMinDisabled, QNameMinOff:
+		return true
+	}
+	return false
+}
+
+// MinimalResponses is the minimal-responses setting; boolean spellings are
+// normalized to yes and no on load.
+type MinimalResponses string
+
+const (
+	MinimalResponsesYes             MinimalResponses = "yes"
+	MinimalResponsesNo              MinimalResponses = "no"
+	MinimalResponsesNoAuth          MinimalResponses = "no-auth"
+	MinimalResponsesNoAuthRecursive MinimalResponses = "no-auth-recursive"
+)
+
+// Valid reports whether m is a value accepted by named.
+func (m MinimalResponses) Valid() bool {
+	switch m {
+	case MinimalResponsesYes, MinimalResponsesNo, MinimalResponsesNoAuth, MinimalResponsesNoAuthRecursive:
+		return true
+	}
+	return false
 }
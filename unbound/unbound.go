@@ -0,0 +1,371 @@
+// File: pkg/namedzone/unbound/unbound.go
+
+// Package unbound translates the resolver side of a namedzone.Config into an
+// unbound.conf: listeners, access control, trust anchors, resolver tuning,
+// forward zones (DoT upstreams included) and stub zones. Authoritative zones
+// and anything else without an Unbound equivalent are left out and reported
+// as notes, so the output can be reviewed before it replaces a hand-written
+// file.
+package unbound
+
+import (
+	"cmp"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	nc "github.com/dlukt/namedconf"
+	"github.com/dlukt/namedzone"
+)
+
+// Options control Export.
+type Options struct {
+	// View selects the view whose zones are exported along with the
+	// top-level ones; empty exports top-level zones only.
+	View string
+	// RootKeyFile is the auto-trust-anchor-file written when named
+	// validates with its built-in root key (dnssec-validation auto);
+	// empty means /var/lib/unbound/root.key.
+	RootKeyFile string
+}
+
+// Export renders the resolver-relevant parts of cfg as an unbound.conf.
+// Notes list what could not be translated. ACLs are expanded to prefixes
+// with Config.ExpandMatchList, so localhost and localnets are taken from
+// the interfaces of the machine running Export.
+func Export(cfg *namedzone.Config, opts Options) ([]byte, []string, error) {
+	x := &exporter{cfg: cfg, opts: opts}
+	if opts.View != "" && cfg.FindView(opts.View) == nil {
+		return nil, nil, &namedzone.ReferenceError{Kind: "view", Name: opts.View, Path: "view[" + opts.View + "]"}
+	}
+	// zones first: the forwarders they use decide tls-cert-bundle
+	x.zones()
+	zones := x.b.String()
+	x.b.Reset()
+	if err := x.server(); err != nil {
+		return nil, nil, err
+	}
+	x.b.WriteString(zones)
+	return []byte(x.b.String()), x.notes, nil
+}
+
+type exporter struct {
+	cfg   *namedzone.Config
+	opts  Options
+	b     strings.Builder
+	notes []string
+	tls   []string // tls blocks whose ca-file goes into tls-cert-bundle
+}
+
+func (x *exporter) note(format string, args ...any) {
+	x.notes = append(x.notes, fmt.Sprintf(format, args...))
+}
+
+func (x *exporter) section(name string) { fmt.Fprintf(&x.b, "%s:\n", name) }
+
+func (x *exporter) line(key, value string) { fmt.Fprintf(&x.b, "\t%s: %s\n", key, value) }
+
+func (x *exporter) server() error {
+	o := x.cfg.Options
+	if o == nil {
+		o = &namedzone.Options{}
+	}
+	x.b.WriteString("# Generated from named.conf by namedzone.\n\n")
+	x.section("server")
+	x.listeners()
+	if err := x.access(o); err != nil {
+		return err
+	}
+
+	switch o.QNameMinimization {
+	case namedzone.QNameMinStrict:
+		x.line("qname-minimisation", "yes")
+		x.line("qname-minimisation-strict", "yes")
+	case namedzone.QNameMinRelaxed:
+		x.line("qname-minimisation", "yes")
+	case namedzone.QNameMinDisabled, namedzone.QNameMinOff:
+		x.line("qname-minimisation", "no")
+	}
+	if p := o.Prefetch; p != nil {
+		x.line("prefetch", yesNo(p.Trigger > 0))
+	}
+	if m := o.MinimalResponses; m != "" {
+		x.line("minimal-responses", yesNo(m != namedzone.MinimalResponsesNo))
+	}
+	if o.MaxCacheTTL != nil {
+		x.line("cache-max-ttl", strconv.FormatInt(o.MaxCacheTTL.Seconds(), 10))
+	}
+	if o.MaxNCacheTTL != nil {
+		x.line("cache-max-negative-ttl", strconv.FormatInt(o.MaxNCacheTTL.Seconds(), 10))
+	}
+	if o.Recursion != nil && !*o.Recursion {
+		x.note("options: recursion is off; Unbound always recurses for the clients access-control admits")
+	}
+
+	x.anchors(o)
+	for _, name := range x.tls {
+		if t := x.findTLS(name); t != nil && t.CAFile != "" {
+			x.line("tls-cert-bundle", quote(t.CAFile))
+			break
+		}
+	}
+	return nil
+}
+
+// listeners maps listen-on and listen-on-v6 to interface lines; DoT and
+// DoH listeners also set the service certificate and their port.
+func (x *exporter) listeners() {
+	var cert *namedzone.TLS
+	for _, l := range x.cfg.Summary().Listeners {
+		if l.Transport == "http" {
+			x.note("listen-on %s: DNS over plain HTTP has no Unbound equivalent", l.Family)
+			continue
+		}
+		for _, a := range l.Addrs {
+			addr, ok := listenAddr(l.Family, a)
+			if !ok {
+				x.note("listen-on %s: %q is not an address; not exported", l.Family, a)
+				continue
+			}
+			if l.Port != 53 {
+				addr += "@" + strconv.Itoa(l.Port)
+			}
+			x.line("interface", addr)
+		}
+		switch l.Transport {
+		case "tls":
+			x.line("tls-port", strconv.Itoa(l.Port))
+		case "https":
+			x.line("https-port", strconv.Itoa(l.Port))
+			if h := x.findHTTP(l.HTTP); h != nil && len(h.Endpoints) > 0 {
+				x.line("http-endpoint", quote(h.Endpoints[0]))
+			}
+		}
+		if l.Transport != "dns" {
+			t := x.findTLS(l.TLS)
+			switch {
+			case t == nil:
+				x.note("listen-on %s: tls %q has no certificate; Unbound needs tls-service-key and tls-service-pem", l.Family, l.TLS)
+			case cert == nil:
+				cert = t
+				x.line("tls-service-key", quote(t.KeyFile))
+				x.line("tls-service-pem", quote(t.CertFile))
+			case cert.Name != t.Name:
+				x.note("listen-on %s: tls %q not exported; Unbound serves one certificate (%q)", l.Family, t.Name, cert.Name)
+			}
+		}
+	}
+}
+
+// listenAddr maps a listen-on element to an interface address.
+func listenAddr(family, term string) (string, bool) {
+	if term == "any" {
+		if family == "ipv6" {
+			return "::0", true
+		}
+		return "0.0.0.0", true
+	}
+	a, err := netip.ParseAddr(term)
+	return a.String(), err == nil
+}
+
+// access maps allow-recursion (else allow-query-cache, else allow-query)
+// to access-control lines. Everything else is refused.
+func (x *exporter) access(o *namedzone.Options) error {
+	src, terms := "allow-query", o.AllowQuery
+	for _, name := range []string{"allow-query-cache", "allow-recursion"} {
+		if raw, ok := o.Get(name); ok {
+			src, terms = name, parseList(raw)
+		}
+	}
+	if len(terms) == 0 {
+		x.note("options: no %s; Unbound admits localhost only", src)
+		return nil
+	}
+	prefixes, err := x.cfg.ExpandMatchList(terms)
+	if err != nil {
+		return err
+	}
+	for _, p := range prefixes {
+		x.line("access-control", p.String()+" allow")
+	}
+	if hasKey(terms) {
+		x.note("options: %s key terms not exported; Unbound access-control has no TSIG", src)
+	}
+	return nil
+}
+
+// anchors maps dnssec-validation and trust-anchors.
+func (x *exporter) anchors(o *namedzone.Options) {
+	switch strings.ToLower(o.DNSSECValidation) {
+	case "no", "false":
+		x.line("module-config", quote("iterator"))
+		return
+	case "", "auto":
+		x.line("auto-trust-anchor-file", quote(cmp.Or(x.opts.RootKeyFile, "/var/lib/unbound/root.key")))
+	}
+	var all []namedzone.TrustAnchorItem
+	for _, ta := range x.cfg.TrustAnchors {
+		all = append(all, ta.Items...)
+	}
+	if v := x.cfg.FindView(x.opts.View); x.opts.View != "" && v.TrustAnchors != nil {
+		all = append(all, v.TrustAnchors.Items...)
+	}
+	for _, it := range all {
+		var rr string
+		switch it.Kind {
+		case namedzone.AnchorStaticKey, namedzone.AnchorInitialKey:
+			rr = fmt.Sprintf("%s DNSKEY %d %d %d %s", fqdn(it.Name), it.Flags, it.Protocol, it.Algorithm, it.Data)
+		default:
+			rr = fmt.Sprintf("%s DS %d %d %d %s", fqdn(it.Name), it.KeyTag, it.Algorithm, it.DigestType, it.Data)
+		}
+		x.line("trust-anchor", quote(rr))
+		if it.Kind == namedzone.AnchorInitialKey || it.Kind == namedzone.AnchorInitialDS {
+			x.note("trust-anchors %s: %s exported as a static trust-anchor; use auto-trust-anchor-file for RFC 5011 rollovers", it.Name, it.Kind)
+		}
+	}
+}
+
+// zones writes forward-zone and stub-zone sections: the options forwarders
+// as the root forward zone, then forward, stub and static-stub zones.
+func (x *exporter) zones() {
+	if o := x.cfg.Options; o != nil && len(o.Forwarders) > 0 {
+		x.forwardZone("options", ".", o.Forward, o.Forwarders)
+	}
+	for v, z := range x.cfg.AllZones() {
+		p := "zone[" + z.Name + "]"
+		if v != nil {
+			if v.Name != x.opts.View {
+				continue
+			}
+			p = "view[" + v.Name + "]." + p
+		}
+		switch z.Type {
+		case namedzone.ZoneForward:
+			if len(z.Forwarders) == 0 {
+				x.note("%s: forward zone without forwarders (no forwarding below the name) not exported", p)
+				continue
+			}
+			x.forwardZone(p, z.Name, z.Forward, z.Forwarders)
+		case namedzone.ZoneStub:
+			x.stubZone(p, *z)
+		case namedzone.ZoneStaticStub:
+			x.note("%s: static-stub server-addresses are not modeled; not exported", p)
+		case namedzone.ZoneMirror:
+			x.note("%s: mirror zone not exported; Unbound's auth-zone can serve it", p)
+		}
+	}
+}
+
+func (x *exporter) forwardZone(path, name, forward string, ff []namedzone.Forwarder) {
+	x.b.WriteString("\n")
+	x.section("forward-zone")
+	x.line("name", quote(fqdn(name)))
+	tls := 0
+	for _, f := range ff {
+		addr := f.Address
+		port := 53
+		if f.TLS != "" && f.TLS != "none" {
+			tls++
+			port = 853
+		}
+		if f.Port != nil {
+			port = *f.Port
+		}
+		if port != 53 || f.TLS != "" && f.TLS != "none" {
+			addr += "@" + strconv.Itoa(port)
+		}
+		if f.TLS != "" && f.TLS != "none" {
+			if t := x.findTLS(f.TLS); t != nil && t.RemoteHost != "" {
+				addr += "#" + t.RemoteHost
+			}
+			if !slices.Contains(x.tls, f.TLS) {
+				x.tls = append(x.tls, f.TLS)
+			}
+		}
+		x.line("forward-addr", addr)
+	}
+	switch {
+	case tls == len(ff):
+		x.line("forward-tls-upstream", "yes")
+	case tls > 0:
+		x.note("%s: mixes TLS and plain forwarders; Unbound sets forward-tls-upstream per zone, so TLS is off", path)
+	}
+	x.line("forward-first", yesNo(forward != "only"))
+}
+
+func (x *exporter) stubZone(path string, z namedzone.Zone) {
+	servers := x.cfg.ZonePrimaries(z)
+	if len(servers) == 0 {
+		x.note("%s: stub zone without primaries not exported", path)
+		return
+	}
+	x.b.WriteString("\n")
+	x.section("stub-zone")
+	x.line("name", quote(fqdn(z.Name)))
+	for _, s := range servers {
+		addr := s.Address
+		if s.Port != nil && *s.Port != 53 {
+			addr += "@" + strconv.Itoa(*s.Port)
+		}
+		x.line("stub-addr", addr)
+	}
+}
+
+func (x *exporter) findTLS(name string) *namedzone.TLS {
+	for i, t := range x.cfg.TLS {
+		if t.Name == name && t.CertFile+t.CAFile+t.RemoteHost != "" {
+			return &x.cfg.TLS[i]
+		}
+	}
+	return nil
+}
+
+func (x *exporter) findHTTP(name string) *namedzone.HTTP {
+	for i, h := range x.cfg.HTTP {
+		if h.Name == name {
+			return &x.cfg.HTTP[i]
+		}
+	}
+	return nil
+}
+
+// parseList reads a raw address match list by loading it as an acl.
+func parseList(raw string) []namedzone.MatchTerm {
+	f, err := nc.Parse([]byte("acl x " + raw + ";"))
+	if err != nil {
+		return nil
+	}
+	c, err := namedzone.FromFile(f)
+	if err != nil || len(c.ACLs) == 0 {
+		return nil
+	}
+	return c.ACLs[0].Elements
+}
+
+func hasKey(terms []namedzone.MatchTerm) bool {
+	for _, t := range terms {
+		if t.Key != "" || hasKey(t.Nested) {
+			return true
+		}
+	}
+	return false
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+func quote(s string) string { return strconv.Quote(s) }
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
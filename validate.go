@@ -0,0 +1,297 @@
+// File: pkg/namedzone/validate.go
+package namedzone
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Severity grades an Issue.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Issue is a problem found by Validate. Path locates the offending item,
+// e.g. `views["external"].zones["example.com"]`.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+
+	// Err classifies the issue (*ReferenceError, *ConflictError or
+	// *ValueError) for use with errors.Is/As.
+	Err error `json:"-"`
+}
+
+func (i Issue) String() string { return string(i.Severity) + ": " + i.Path + ": " + i.Message }
+
+// Built-in names that need no definition.
+var (
+	builtinACLs = map[string]bool{"any": true, "none": true, "localhost": true, "localnets": true}
+	builtinTLS  = map[string]bool{"ephemeral": true, "none": true}
+	builtinHTTP = map[string]bool{"default": true}
+)
+
+// Validate checks cross-references (ACLs, keys, tls and http blocks,
+// remote-servers lists) and per-type zone requirements. It never modifies
+// the Config; an empty result means no problems were found.
+func (c *Config) Validate() []Issue {
+	v := validator{c: c, acls: map[string]bool{}, keys: map[string]bool{}, tls: map[string]bool{}, http: map[string]bool{}, remotes: map[string]bool{}}
+	for _, a := range c.ACLs {
+		v.acls[a.Name] = true
+	}
+	for _, k := range c.Keys {
+		v.keys[k.Name] = true
+	}
+	for _, t := range c.TLS {
+		v.tls[t.Name] = true
+	}
+	for _, h := range c.HTTP {
+		v.http[h.Name] = true
+	}
+	for _, rs := range c.RemoteServers {
+		v.remotes[rs.Name] = true
+	}
+	v.run()
+	return v.issues
+}
+
+// AddValidator registers fn to run before every Apply, and so before every
+// Save and Render; a non-nil result aborts the write. Use it to enforce local
+// policy, e.g. that every zone restricts allow-transfer. All validators run;
+// their errors are joined.
+func (c *Config) AddValidator(fn func(*Config) error) {
+	c.validators = append(c.validators, fn)
+}
+
+// runValidators runs the functions registered with AddValidator.
+func (c *Config) runValidators() error {
+	var errs []error
+	for _, fn := range c.validators {
+		if err := fn(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type validator struct {
+	c                              *Config
+	acls, keys, tls, http, remotes map[string]bool
+	issues                         []Issue
+}
+
+func (v *validator) add(sev Severity, path, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	v.issues = append(v.issues, Issue{Severity: sev, Path: path, Message: msg, Err: &ValueError{Path: path, Msg: msg}})
+}
+
+// ref records an unresolved reference of the given kind.
+func (v *validator) ref(path, kind, name string) {
+	err := &ReferenceError{Kind: kind, Name: name, Path: path}
+	v.issues = append(v.issues, Issue{Severity: SeverityError, Path: path, Message: fmt.Sprintf("undefined %s %q", kind, name), Err: err})
+}
+
+// dupes records names defined more than once within one namespace.
+func (v *validator) dupes(path, kind string, names []string) {
+	seen := map[string]bool{}
+	for _, n := range names {
+		if seen[n] {
+			err := &ConflictError{Kind: kind, Name: n, Msg: "defined more than once"}
+			v.issues = append(v.issues, Issue{Severity: SeverityError, Path: path, Message: fmt.Sprintf("%s %q defined more than once", kind, n), Err: err})
+		}
+		seen[n] = true
+	}
+}
+
+// zoneKey identifies a zone for the duplicate check: a name may be used once
+// per class, and an omitted class means IN.
+func zoneKey(z Zone) string {
+	if class := strings.ToUpper(z.Class); class != "" && class != "IN" {
+		return z.Name + " " + class
+	}
+	return z.Name
+}
+
+func names[T any](items []T, name func(T) string) []string {
+	out := make([]string, len(items))
+	for i, it := range items {
+		out[i] = name(it)
+	}
+	return out
+}
+
+func (v *validator) run() {
+	c := v.c
+	v.dupes("acls", "acl", names(c.ACLs, func(a ACL) string { return a.Name }))
+	v.dupes("keys", "key", names(c.Keys, func(k Key) string { return k.Name }))
+	v.dupes("tls", "tls", names(c.TLS, func(t TLS) string { return t.Name }))
+	v.dupes("http", "http", names(c.HTTP, func(h HTTP) string { return h.Name }))
+	v.dupes("remoteServers", "remote-servers", names(c.RemoteServers, func(r RemoteServers) string { return r.Name }))
+	v.dupes("views", "view", names(c.Views, func(vw View) string { return vw.Name }))
+	v.dupes("zones", "zone", names(c.Zones, zoneKey))
+	for _, a := range c.ACLs {
+		v.matchList(fmt.Sprintf("acls[%q]", a.Name), a.Elements)
+	}
+	for _, rs := range c.RemoteServers {
+		v.servers(fmt.Sprintf("remoteServers[%q]", rs.Name), rs.Servers)
+	}
+	if c.Controls != nil {
+		for i, in := range c.Controls.Inet {
+			p := fmt.Sprintf("controls.inet[%d]", i)
+			v.matchList(p+".allow", in.Allow)
+			v.keyRefs(p+".keys", in.Keys)
+		}
+		for i, ux := range c.Controls.Unix {
+			v.keyRefs(fmt.Sprintf("controls.unix[%d].keys", i), ux.Keys)
+		}
+	}
+	if o := c.Options; o != nil {
+		v.matchList("options.allowQuery", o.AllowQuery)
+		v.matchList("options.allowTransfer", o.AllowTransfer)
+		v.matchList("options.allowUpdate", o.AllowUpdate)
+		if o.ListenOn != nil {
+			v.listen("options.listenOn", *o.ListenOn)
+		}
+		if o.ListenOnV6 != nil {
+			v.listen("options.listenOnV6", *o.ListenOnV6)
+		}
+		v.forwarders("options.forwarders", o.Forwarders)
+		if o.ResponsePadding != nil {
+			v.matchList("options.responsePadding", o.ResponsePadding.Clients)
+		}
+	}
+	if c.Logging != nil {
+		for _, name := range c.Logging.unknownCategories() {
+			v.add(SeverityWarning, "logging", "unknown category %q; removed or newer than this package", name)
+		}
+	}
+	for _, z := range c.Zones {
+		v.zone(fmt.Sprintf("zones[%q]", z.Name), z)
+	}
+	for _, vw := range c.Views {
+		p := fmt.Sprintf("views[%q]", vw.Name)
+		if len(vw.MatchClients) == 0 && len(c.Views) > 1 {
+			v.add(SeverityWarning, p, "no match-clients; the view matches every client and shadows later views")
+		}
+		v.matchList(p+".matchClients", vw.MatchClients)
+		v.matchList(p+".matchDestinations", vw.MatchDestinations)
+		if vw.ResponsePadding != nil {
+			v.matchList(p+".responsePadding", vw.ResponsePadding.Clients)
+		}
+		v.dupes(p+".zones", "zone", names(vw.Zones, zoneKey))
+		for _, z := range vw.Zones {
+			v.zone(fmt.Sprintf("%s.zones[%q]", p, z.Name), z)
+		}
+	}
+}
+
+func (v *validator) matchList(path string, terms []MatchTerm) {
+	for _, t := range terms {
+		switch {
+		case len(t.Nested) > 0:
+			v.matchList(path, t.Nested)
+		case t.Key != "":
+			if !v.keys[t.Key] {
+				v.ref(path, "key", t.Key)
+			}
+		case t.ACLRef != "":
+			if !v.acls[t.ACLRef] && !builtinACLs[t.ACLRef] {
+				v.ref(path, "acl", t.ACLRef)
+			}
+		}
+	}
+}
+
+func (v *validator) keyRefs(path string, keys []string) {
+	for _, k := range keys {
+		if !v.keys[k] {
+			v.ref(path, "key", k)
+		}
+	}
+}
+
+func (v *validator) tlsRef(path, name string) {
+	if name != "" && !v.tls[name] && !builtinTLS[name] {
+		v.ref(path, "tls", name)
+	}
+}
+
+func (v *validator) listen(path string, l Listen) {
+	v.tlsRef(path, l.TLS)
+	if l.HTTP != "" && !v.http[l.HTTP] && !builtinHTTP[l.HTTP] {
+		v.ref(path, "http", l.HTTP)
+	}
+	v.matchList(path, l.Addrs)
+}
+
+func (v *validator) forwarders(path string, ff []Forwarder) {
+	for _, f := range ff {
+		v.tlsRef(path, f.TLS)
+	}
+}
+
+// servers checks a server list; entries that are not addresses must name a
+// remote-servers block.
+func (v *validator) servers(path string, items []RemoteServerItem) {
+	for _, it := range items {
+		if _, err := netip.ParseAddr(it.Address); err != nil && !v.remotes[it.Address] {
+			v.ref(path, "remote-servers", it.Address)
+		}
+		if it.Key != "" && !v.keys[it.Key] {
+			v.ref(path, "key", it.Key)
+		}
+		v.tlsRef(path, it.TLS)
+	}
+}
+
+func (v *validator) zone(path string, z Zone) {
+	v.matchList(path+".allowUpdate", z.AllowUpdate)
+	v.matchList(path+".allowTransfer", z.AllowTransfer)
+	v.servers(path+".primaries", z.Primaries)
+	v.servers(path+".alsoNotify", z.AlsoNotify)
+	v.forwarders(path+".forwarders", z.Forwarders)
+	if z.PrimariesRef != "" && !v.remotes[z.PrimariesRef] {
+		v.ref(path+".primariesRef", "remote-servers", z.PrimariesRef)
+	}
+	if z.InView != "" {
+		if src := v.c.GetZoneInView(z.InView, z.Name); src == nil {
+			v.ref(path+".inView", "zone", z.InView+"/"+z.Name)
+		}
+		if z.Type != "" {
+			v.add(SeverityError, path, "in-view zone cannot have a type")
+		}
+		return
+	}
+	hasPrimaries := z.PrimariesRef != "" || len(z.Primaries) > 0
+	switch z.Type {
+	case "":
+		v.add(SeverityError, path, "missing zone type")
+	case ZonePrimary, ZoneHint:
+		if z.File == "" {
+			v.add(SeverityError, path, "%s zone requires a file", z.Type)
+		}
+	case ZoneSecondary, ZoneStub:
+		if !hasPrimaries {
+			v.add(SeverityError, path, "%s zone requires primaries", z.Type)
+		}
+	case ZoneMirror:
+		// mirror zones for the root may rely on built-in root server addresses
+		if !hasPrimaries && z.Name != "." {
+			v.add(SeverityError, path, "mirror zone requires primaries")
+		}
+	case ZoneRedirect:
+		if z.File == "" && !hasPrimaries {
+			v.add(SeverityError, path, "redirect zone requires a file or primaries")
+		}
+	case ZoneForward, ZoneStaticStub:
+	default:
+		v.add(SeverityError, path, "unknown zone type %q", z.Type)
+	}
+}
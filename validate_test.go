@@ -0,0 +1,32 @@
+// File: pkg/namedzone/validate_test.go
+package namedzone
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidateZoneClasses allows one zone name per class.
+func TestValidateZoneClasses(t *testing.T) {
+	for src, dupes := range map[string]int{
+		`zone "x" IN { type hint; file "x"; }; zone "x" CH { type hint; file "x"; };`:                                               0,
+		`zone "x" { type hint; file "x"; }; zone "x" in { type hint; file "x"; };`:                                                  1,
+		`view "v" { zone "x" CH { type hint; file "x"; }; zone "x" { type hint; file "x"; }; zone "x" { type hint; file "x"; }; };`: 1,
+	} {
+		cfg, err := FromReader(strings.NewReader(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, is := range cfg.Validate() {
+			var ce *ConflictError
+			if errors.As(is.Err, &ce) {
+				n++
+			}
+		}
+		if n != dupes {
+			t.Errorf("%s: %d conflicts, want %d", src, n, dupes)
+		}
+	}
+}
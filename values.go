@@ -0,0 +1,215 @@
+// File: pkg/namedzone/values.go
+package namedzone
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Size is a BIND size value: a byte count written with an optional k/m/g
+// suffix, a percentage (max-cache-size), or one of the keywords unlimited
+// and default. It marshals to and from its named.conf spelling.
+type Size struct {
+	Bytes   uint64
+	Percent int
+	Keyword string
+}
+
+var sizeUnits = []struct {
+	suffix string
+	mult   uint64
+}{{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}}
+
+// ParseSize parses "1024", "20m", "2G", "90%", "unlimited" or "default".
+func ParseSize(s string) (Size, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "":
+		return Size{}, fmt.Errorf("namedzone: empty size")
+	case "unlimited", "default":
+		return Size{Keyword: strings.ToLower(s)}, nil
+	}
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 100 {
+			return Size{}, fmt.Errorf("namedzone: invalid size percentage %q", s)
+		}
+		return Size{Percent: n}, nil
+	}
+	mult := uint64(1)
+	num := s
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(strings.ToUpper(s), u.suffix) {
+			mult, num = u.mult, s[:len(s)-1]
+			break
+		}
+	}
+	n, err := strconv.ParseUint(num, 10, 64)
+	if err != nil {
+		return Size{}, fmt.Errorf("namedzone: invalid size %q", s)
+	}
+	return Size{Bytes: n * mult}, nil
+}
+
+// String renders the size using the largest exact unit.
+func (sz Size) String() string {
+	switch {
+	case sz.Keyword != "":
+		return sz.Keyword
+	case sz.Percent != 0:
+		return strconv.Itoa(sz.Percent) + "%"
+	}
+	for _, u := range sizeUnits {
+		if sz.Bytes != 0 && sz.Bytes%u.mult == 0 {
+			return strconv.FormatUint(sz.Bytes/u.mult, 10) + u.suffix
+		}
+	}
+	return strconv.FormatUint(sz.Bytes, 10)
+}
+
+func (sz Size) MarshalText() ([]byte, error) { return []byte(sz.String()), nil }
+
+func (sz *Size) UnmarshalText(b []byte) error {
+	v, err := ParseSize(string(b))
+	if err != nil {
+		return err
+	}
+	*sz = v
+	return nil
+}
+
+// Duration is a BIND time value. It accepts plain seconds ("3600"), unit
+// groups ("1w2d", "90m") and ISO 8601 durations ("P1D", "PT12H"), and
+// renders as compact unit groups.
+type Duration time.Duration
+
+var durationUnits = []struct {
+	r rune
+	d time.Duration
+}{
+	{'w', 7 * 24 * time.Hour},
+	{'d', 24 * time.Hour},
+	{'h', time.Hour},
+	{'m', time.Minute},
+	{'s', time.Second},
+}
+
+// ParseDuration parses a BIND TTL-style or ISO 8601 duration.
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("namedzone: empty duration")
+	}
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return Duration(time.Duration(n) * time.Second), nil
+	}
+	up := strings.ToUpper(s)
+	if strings.HasPrefix(up, "P") {
+		return parseISODuration(up)
+	}
+	var total time.Duration
+	num := ""
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsDigit(r) {
+			num += string(r)
+			continue
+		}
+		unit := time.Duration(0)
+		for _, u := range durationUnits {
+			if u.r == r {
+				unit = u.d
+			}
+		}
+		n, err := strconv.ParseUint(num, 10, 32)
+		if unit == 0 || err != nil {
+			return 0, fmt.Errorf("namedzone: invalid duration %q", s)
+		}
+		total += time.Duration(n) * unit
+		num = ""
+	}
+	if num != "" {
+		return 0, fmt.Errorf("namedzone: invalid duration %q", s)
+	}
+	return Duration(total), nil
+}
+
+// parseISODuration handles the PnYnMnWnDTnHnMnS subset BIND accepts. Years
+// and months use BIND's fixed 365 and 30 day lengths.
+func parseISODuration(s string) (Duration, error) {
+	var total time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s[1:] {
+		switch {
+		case unicode.IsDigit(r):
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.ParseUint(num, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("namedzone: invalid duration %q", s)
+		}
+		var unit time.Duration
+		switch {
+		case r == 'Y' && !inTime:
+			unit = 365 * 24 * time.Hour
+		case r == 'M' && !inTime:
+			unit = 30 * 24 * time.Hour
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("namedzone: invalid duration %q", s)
+		}
+		total += time.Duration(n) * unit
+		num = ""
+	}
+	if num != "" {
+		return 0, fmt.Errorf("namedzone: invalid duration %q", s)
+	}
+	return Duration(total), nil
+}
+
+// String renders d as unit groups, e.g. "1w2d" or "90s"; zero is "0".
+func (d Duration) String() string {
+	rem := time.Duration(d).Truncate(time.Second)
+	if rem <= 0 {
+		return "0"
+	}
+	var b strings.Builder
+	for _, u := range durationUnits {
+		if n := rem / u.d; n > 0 {
+			b.WriteString(strconv.FormatInt(int64(n), 10))
+			b.WriteRune(u.r)
+			rem -= n * u.d
+		}
+	}
+	return b.String()
+}
+
+// Seconds returns d as whole seconds, the unit named stores internally.
+func (d Duration) Seconds() int64 { return int64(time.Duration(d) / time.Second) }
+
+func (d Duration) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
@@ -0,0 +1,191 @@
+// File: pkg/namedzone/walk.go
+package namedzone
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Node is an element of the typed model visited by Walk.
+type Node struct {
+	// Kind is the item kind for blocks and list elements that have one
+	// (zone, view, acl, key, key-store, remote-servers, tls, http,
+	// trust-anchors, include, controls, logging, options, channel,
+	// category, match-term, raw-option) and "field" for everything else.
+	Kind string
+	// Name is the item's name, or the JSON field name for fields. List
+	// elements without a name have an empty Name.
+	Name string
+	// Path addresses the element for GetPath and SetPath.
+	Path string
+	// Value points at the element in the Config (*Zone, *MatchTerm,
+	// *bool, ...), so a visitor can edit it in place.
+	Value any
+	// Parent is the enclosing node; nil for the top-level items.
+	Parent *Node
+}
+
+// Visitor is called by Walk for each node. If Visit returns a non-nil
+// Visitor w, Walk visits the children of n with w; nil skips them.
+type Visitor interface {
+	Visit(n *Node) (w Visitor)
+}
+
+// VisitorFunc adapts a function to a Visitor that descends into every node
+// for which it returns true.
+type VisitorFunc func(n *Node) bool
+
+func (f VisitorFunc) Visit(n *Node) Visitor {
+	if f(n) {
+		return f
+	}
+	return nil
+}
+
+var walkKinds = map[reflect.Type]string{
+	reflect.TypeFor[Zone]():          "zone",
+	reflect.TypeFor[View]():          "view",
+	reflect.TypeFor[ACL]():           "acl",
+	reflect.TypeFor[Key]():           "key",
+	reflect.TypeFor[KeyStore]():      "key-store",
+	reflect.TypeFor[RemoteServers](): "remote-servers",
+	reflect.TypeFor[TLS]():           "tls",
+	reflect.TypeFor[HTTP]():          "http",
+	reflect.TypeFor[TrustAnchors]():  "trust-anchors",
+	reflect.TypeFor[Include]():       "include",
+	reflect.TypeFor[Controls]():      "controls",
+	reflect.TypeFor[Logging]():       "logging",
+	reflect.TypeFor[Options]():       "options",
+	reflect.TypeFor[LogChannel]():    "channel",
+	reflect.TypeFor[LogCategory]():   "category",
+	reflect.TypeFor[MatchTerm]():     "match-term",
+	reflect.TypeFor[RawKV]():         "raw-option",
+}
+
+// singular is the inverse of pathAliases: the field name paths use for
+// each collection.
+var singular = func() map[string]string {
+	m := map[string]string{}
+	for s, p := range pathAliases {
+		m[p] = s
+	}
+	return m
+}()
+
+// Walk visits every element of the typed model that is set, depth first in
+// field order: top-level items, then their fields, list elements and
+// nested blocks down to single match terms and options. Unset fields are
+// not visited. Like AllZones, the slices must keep their length while
+// walking.
+func (c *Config) Walk(v Visitor) {
+	walkStruct(v, nil, "", reflect.ValueOf(c).Elem())
+}
+
+func walkStruct(v Visitor, parent *Node, path string, sv reflect.Value) {
+	t := sv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() || (t == reflect.TypeFor[Config]() && f.Name == "ModernizeKeywords") {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fv := sv.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		p := name
+		if path != "" {
+			p = path + "." + name
+		}
+		if fv.Kind() == reflect.Slice && !leaf(fv) {
+			if s, ok := singular[name]; ok {
+				p = strings.TrimSuffix(p, name) + s
+			}
+			for j := range fv.Len() {
+				walkValue(v, parent, p, "", fv.Index(j), j)
+			}
+			continue
+		}
+		walkValue(v, parent, p, name, fv, -1)
+	}
+}
+
+// walkValue visits one value: a field named name, or element index of the
+// list at path.
+func walkValue(v Visitor, parent *Node, path, name string, fv reflect.Value, index int) {
+	ev := fv
+	if ev.Kind() == reflect.Pointer {
+		ev = ev.Elem()
+	}
+	n := &Node{Kind: "field", Name: name, Path: path, Parent: parent}
+	if k, ok := walkKinds[ev.Type()]; ok {
+		n.Kind = k
+	}
+	if index >= 0 {
+		if id := elemName(ev); id != "" {
+			n.Name = id
+			n.Path = fmt.Sprintf("%s[%s]", path, id)
+		} else {
+			n.Path = fmt.Sprintf("%s[#%d]", path, index)
+		}
+	}
+	if fv.Kind() == reflect.Pointer {
+		n.Value = fv.Interface()
+	} else {
+		n.Value = fv.Addr().Interface()
+	}
+	w := v.Visit(n)
+	if w == nil || leaf(ev) {
+		return
+	}
+	switch ev.Kind() {
+	case reflect.Struct:
+		walkStruct(w, n, n.Path, ev)
+	case reflect.Slice:
+		for j := range ev.Len() {
+			walkValue(w, n, n.Path, "", ev.Index(j), j)
+		}
+	}
+}
+
+// elemName returns the name of a list element that has one.
+func elemName(ev reflect.Value) string {
+	if ev.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := range ev.NumField() {
+		tag, _, _ := strings.Cut(ev.Type().Field(i).Tag.Get("json"), ",")
+		if tag == "name" && ev.Field(i).Kind() == reflect.String {
+			return ev.Field(i).String()
+		}
+	}
+	return ""
+}
+
+// leaf reports whether v is visited without children: scalars, values that
+// marshal as text, and lists of scalars.
+func leaf(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(reflect.TypeFor[encoding.TextMarshaler]()) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return false
+	case reflect.Slice:
+		e := t.Elem()
+		if e.Kind() == reflect.Pointer {
+			e = e.Elem()
+		}
+		return e.Kind() != reflect.Struct
+	}
+	return true
+}
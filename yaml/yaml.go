@@ -0,0 +1,115 @@
+// File: pkg/namedzone/yaml/yaml.go
+
+// Package yaml reads and writes namedzone.Config as YAML. Documents mirror the
+// JSON projection exactly: the same field names, omitted nil pointers (so an
+// unset bool stays distinct from "no"), and nested match lists.
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dlukt/namedzone"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Marshal renders c as a block-style YAML document.
+func Marshal(c *namedzone.Config) ([]byte, error) { return MarshalValue(c) }
+
+// MarshalValue renders any value of the JSON projection, such as a Zone or
+// a list of views, as a block-style YAML document.
+func MarshalValue(v any) ([]byte, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is YAML; decoding into a node keeps the field order.
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(js, &doc); err != nil {
+		return nil, err
+	}
+	blockStyle(&doc)
+	return yamlv3.Marshal(&doc)
+}
+
+// Unmarshal decodes a YAML document into c. Fields absent from the document
+// keep their current values, as with encoding/json.
+func Unmarshal(data []byte, c *namedzone.Config) error { return UnmarshalValue(data, c) }
+
+// UnmarshalValue decodes a YAML document into v, a pointer to a value of the
+// JSON projection.
+func UnmarshalValue(data []byte, v any) error {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	p, err := plain(&doc)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return nil
+	}
+	js, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, v)
+}
+
+// blockStyle undoes the JSON look of a decoded document: collections go
+// block style and strings are quoted only where yaml.v3 would quote them when
+// marshaling a Go string (e.g. "yes", "10").
+func blockStyle(n *yamlv3.Node) {
+	n.Style &^= yamlv3.FlowStyle
+	if n.Kind == yamlv3.ScalarNode && n.Tag == "!!str" {
+		if out, err := yamlv3.Marshal(n.Value); err == nil && !strings.ContainsAny(string(out[:1]), "\"'") {
+			n.Style &^= yamlv3.DoubleQuotedStyle
+		}
+	}
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// plain converts a node to values encoding/json can marshal. Mapping keys
+// are always strings.
+func plain(n *yamlv3.Node) (any, error) {
+	switch n.Kind {
+	case yamlv3.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return plain(n.Content[0])
+	case yamlv3.AliasNode:
+		return plain(n.Alias)
+	case yamlv3.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			v, err := plain(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[n.Content[i].Value] = v
+		}
+		return m, nil
+	case yamlv3.SequenceNode:
+		s := make([]any, len(n.Content))
+		for i, c := range n.Content {
+			v, err := plain(c)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = v
+		}
+		return s, nil
+	case yamlv3.ScalarNode:
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", n.Line)
+}
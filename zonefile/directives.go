@@ -0,0 +1,295 @@
+// File: pkg/namedzone/zonefile/directives.go
+package zonefile
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Include is an $INCLUDE directive.
+type Include struct {
+	Path     string `json:"path"`               // as written
+	Resolved string `json:"resolved,omitempty"` // the file read, with LoadTree
+	Origin   string `json:"origin"`             // origin the included file starts with
+	Line     int    `json:"line"`
+	// Zone is the included file, with LoadTree; its own Includes continue
+	// the include graph. Edit and Save it like any Zone.
+	Zone *Zone `json:"-"`
+}
+
+// LoadTree reads the master file at path for the zone origin and follows
+// its $INCLUDE directives. Relative include paths are resolved against dir,
+// named's working directory (the options directory); an empty dir means
+// the directory of path. An included file starts with the origin the
+// directive gives, or the $ORIGIN in effect, and inherits the $TTL.
+func LoadTree(path, origin, dir string) (*Zone, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ld := &loader{dir: cmp.Or(dir, filepath.Dir(path)), stack: []string{path}}
+	return parse(path, string(data), &state{origin: fqdn(origin)}, ld)
+}
+
+// loader follows $INCLUDE directives for LoadTree.
+type loader struct {
+	dir   string
+	stack []string // files being read, to detect loops
+}
+
+func (ld *loader) load(inc *Include, st *state) error {
+	p := inc.Path
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(ld.dir, p)
+	}
+	inc.Resolved = p
+	if slices.Contains(ld.stack, p) {
+		return &ParseError{File: ld.stack[len(ld.stack)-1], Line: inc.Line, Msg: "$INCLUDE loop through " + p}
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	ld.stack = append(ld.stack, p)
+	defer func() { ld.stack = ld.stack[:len(ld.stack)-1] }()
+	z, err := parse(p, string(data), &state{origin: inc.Origin, defTTL: st.defTTL, lastTTL: st.lastTTL, class: st.class}, ld)
+	if err != nil {
+		return err
+	}
+	inc.Zone = z
+	return nil
+}
+
+// Includes returns the $INCLUDE directives of the file in order.
+func (z *Zone) Includes() []Include {
+	var out []Include
+	for _, e := range z.entries {
+		if e.inc != nil {
+			out = append(out, *e.inc)
+		}
+	}
+	return out
+}
+
+// Generate is a $GENERATE directive: records for each value of a range,
+// with "$" in the owner and rdata templates replaced by the value.
+// A "${offset,width,base}" modifier adds offset and formats the value with
+// at least width digits in base d, o, x, X or n/N (reversed nibbles, as in
+// ip6.arpa names); "\$" is a literal dollar sign. A directive may yield at
+// most 65536 records, and a width may not exceed 255.
+type Generate struct {
+	Start int     `json:"start"`
+	Stop  int     `json:"stop"`
+	Step  int     `json:"step"`
+	Owner string  `json:"owner"`
+	TTL   *uint32 `json:"ttl,omitempty"`
+	Class string  `json:"class,omitempty"`
+	Type  string  `json:"type"`
+	Data  string  `json:"data"`
+	// Origin is the $ORIGIN in effect; relative names are relative to it.
+	Origin string `json:"origin"`
+	Line   int    `json:"line"`
+
+	defTTL uint32 // TTL of the records when TTL is nil
+}
+
+// Limits on $GENERATE: range values are 31-bit, as in named, one directive
+// yields at most maxGenerate records, and a modifier width cannot exceed
+// the length of a domain name.
+const (
+	maxGenerateValue = 1<<31 - 1
+	maxGenerate      = 65536
+	maxGenerateWidth = 255
+)
+
+// parseGenerate reads the arguments of $GENERATE:
+// range owner [ttl] [class] type rdata.
+func parseGenerate(args []string, st *state) (*Generate, error) {
+	if len(args) < 4 {
+		return nil, fmt.Errorf("$GENERATE needs a range, owner, type and rdata")
+	}
+	g := &Generate{Step: 1, Owner: args[1], Origin: st.origin, Class: cmp.Or(st.class, "IN")}
+	rng, step, ok := strings.Cut(args[0], "/")
+	lo, hi, ok2 := strings.Cut(rng, "-")
+	var err error
+	if g.Start, err = strconv.Atoi(lo); err != nil || !ok2 {
+		return nil, fmt.Errorf("bad $GENERATE range %q", args[0])
+	}
+	if g.Stop, err = strconv.Atoi(hi); err != nil || g.Stop < g.Start || g.Start < 0 || g.Stop > maxGenerateValue {
+		return nil, fmt.Errorf("bad $GENERATE range %q", args[0])
+	}
+	if ok {
+		if g.Step, err = strconv.Atoi(step); err != nil || g.Step < 1 || g.Step > maxGenerateValue {
+			return nil, fmt.Errorf("bad $GENERATE step %q", args[0])
+		}
+	}
+	if n := (g.Stop-g.Start)/g.Step + 1; n > maxGenerate {
+		return nil, fmt.Errorf("$GENERATE range %q yields %d records, more than %d", args[0], n, maxGenerate)
+	}
+	rest := args[2:]
+	ttlSet, classSet := false, false
+	for len(rest) > 0 {
+		if ttl, ok := ParseTTL(rest[0]); ok && !ttlSet {
+			g.TTL, ttlSet = &ttl, true
+		} else if isClass(rest[0]) && !classSet {
+			g.Class, classSet = strings.ToUpper(rest[0]), true
+		} else {
+			break
+		}
+		rest = rest[1:]
+	}
+	if len(rest) < 2 {
+		return nil, fmt.Errorf("$GENERATE needs a type and rdata")
+	}
+	g.Type, g.Data = strings.ToUpper(rest[0]), strings.Join(rest[1:], " ")
+	switch ttl := cmp.Or(g.TTL, st.defTTL, st.lastTTL); {
+	case ttl != nil:
+		g.defTTL = *ttl
+	default:
+		return nil, fmt.Errorf("no TTL for $GENERATE and no $TTL")
+	}
+	for _, t := range []string{g.Owner, g.Data} {
+		if _, err := substitute(t, g.Start); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Expand returns the records g generates, with absolute names. At most
+// 65536 records are returned, the limit parsing enforces.
+func (g Generate) Expand() []Record {
+	if g.Stop < g.Start {
+		return nil
+	}
+	step := uint(max(g.Step, 1))
+	n := min((uint(g.Stop)-uint(g.Start))/step, maxGenerate-1) + 1
+	var out []Record
+	for k := range n {
+		i := g.Start + int(k*step) // never past Stop, so no overflow
+		owner, _ := substitute(g.Owner, i)
+		data, _ := substitute(g.Data, i)
+		ttl := g.defTTL
+		if g.TTL != nil {
+			ttl = *g.TTL
+		}
+		out = append(out, Record{
+			Name:  absName(owner, g.Origin),
+			TTL:   &ttl,
+			Class: g.Class,
+			Type:  g.Type,
+			Data:  absData(g.Type, fields(data), g.Origin),
+		})
+	}
+	return out
+}
+
+// substitute expands the "$" placeholders of a $GENERATE template for i.
+func substitute(tmpl string, i int) (string, error) {
+	var sb strings.Builder
+	for k := 0; k < len(tmpl); k++ {
+		switch c := tmpl[k]; {
+		case c == '\\' && k+1 < len(tmpl) && tmpl[k+1] == '$':
+			sb.WriteByte('$')
+			k++
+		case c == '\\' && k+1 < len(tmpl):
+			sb.WriteString(tmpl[k : k+2])
+			k++
+		case c == '$' && k+1 < len(tmpl) && tmpl[k+1] == '{':
+			end := strings.IndexByte(tmpl[k:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated modifier in %q", tmpl)
+			}
+			s, err := modifier(tmpl[k+2:k+end], i)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(s)
+			k += end
+		case c == '$':
+			sb.WriteString(strconv.Itoa(i))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), nil
+}
+
+// modifier formats i per "offset[,width[,base]]".
+func modifier(spec string, i int) (string, error) {
+	parts := strings.Split(spec, ",")
+	if len(parts) > 3 {
+		return "", fmt.Errorf("bad $GENERATE modifier %q", spec)
+	}
+	offset, width, base := 0, 0, "d"
+	var err error
+	if offset, err = strconv.Atoi(parts[0]); err != nil {
+		return "", fmt.Errorf("bad $GENERATE modifier %q", spec)
+	}
+	if len(parts) > 1 {
+		if width, err = strconv.Atoi(parts[1]); err != nil || width < 0 || width > maxGenerateWidth {
+			return "", fmt.Errorf("bad $GENERATE modifier %q", spec)
+		}
+	}
+	if len(parts) > 2 {
+		base = parts[2]
+	}
+	if offset < -maxGenerateValue || offset > maxGenerateValue {
+		return "", fmt.Errorf("bad $GENERATE modifier %q", spec)
+	}
+	v := i + offset
+	if v < 0 {
+		return "", fmt.Errorf("$GENERATE modifier %q gives a negative value", spec)
+	}
+	switch base {
+	case "d", "o", "x", "X":
+		return fmt.Sprintf("%0*"+base, width, v), nil
+	case "n", "N":
+		// width counts the output characters, dots included
+		hex := fmt.Sprintf("%0*x", max((width+1)/2, 1), v)
+		if base == "N" {
+			hex = strings.ToUpper(hex)
+		}
+		nibbles := make([]string, 0, len(hex))
+		for k := len(hex) - 1; k >= 0; k-- {
+			nibbles = append(nibbles, hex[k:k+1])
+		}
+		return strings.Join(nibbles, "."), nil
+	}
+	return "", fmt.Errorf("bad $GENERATE base %q", base)
+}
+
+// Generates returns the $GENERATE directives of the file in order.
+func (z *Zone) Generates() []Generate {
+	var out []Generate
+	for _, e := range z.entries {
+		if e.gen != nil {
+			out = append(out, *e.gen)
+		}
+	}
+	return out
+}
+
+// AllRecords returns the records the zone data holds, in order: the records
+// of the file, those generated by $GENERATE and, for a zone loaded with
+// LoadTree, those of included files. Records and the edit methods only
+// cover the records written in the file itself.
+func (z *Zone) AllRecords() []Record {
+	var out []Record
+	for _, e := range z.entries {
+		switch {
+		case e.kind == entryRecord:
+			out = append(out, e.rec.clone())
+		case e.gen != nil:
+			out = append(out, e.gen.Expand()...)
+		case e.inc != nil && e.inc.Zone != nil:
+			out = append(out, e.inc.Zone.AllRecords()...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+// File: pkg/namedzone/zonefile/directives_test.go
+package zonefile
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGenerateLimits(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		ok   bool
+	}{
+		{"$GENERATE 1-65536 host-$ A 192.0.2.1", true},
+		{"$GENERATE 0-131070/2 host-$ A 192.0.2.1", true},
+		{"$GENERATE 1-65537 host-$ A 192.0.2.1", false},
+		{"$GENERATE 0-9223372036854775807 host-$ A 192.0.2.1", false},
+		{"$GENERATE 2147483600-2147483647/9223372036854775807 host-$ A 192.0.2.1", false},
+		{"$GENERATE 1-2 host-${0,255,d} A 192.0.2.1", true},
+		{"$GENERATE 1-2 host-${0,999999999,d} A 192.0.2.1", false},
+		{"$GENERATE 1-2 host-${9223372036854775807} A 192.0.2.1", false},
+	} {
+		_, err := Parse(strings.NewReader("$TTL 300\n"+tc.line+"\n"), "example.com.")
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v", tc.line, err)
+		}
+	}
+}
+
+func TestGenerateExpandBounds(t *testing.T) {
+	g := Generate{Start: math.MaxInt - 2, Stop: math.MaxInt, Step: 2, Owner: "h", Type: "A", Data: "192.0.2.1", Origin: "example.com."}
+	if got := len(g.Expand()); got != 2 {
+		t.Errorf("near MaxInt: %d records, want 2", got)
+	}
+	g = Generate{Start: 0, Stop: math.MaxInt, Step: 1, Owner: "h-$", Type: "A", Data: "192.0.2.1", Origin: "example.com."}
+	if got := len(g.Expand()); got != maxGenerate {
+		t.Errorf("huge range: %d records, want %d", got, maxGenerate)
+	}
+}
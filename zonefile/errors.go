@@ -0,0 +1,29 @@
+// File: pkg/namedzone/zonefile/errors.go
+package zonefile
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrParse        = errors.New("zonefile: parse error")
+	ErrRecordExists = errors.New("zonefile: record already exists")
+	ErrNoRecord     = errors.New("zonefile: no such record")
+)
+
+// ParseError reports malformed master file text.
+type ParseError struct {
+	File string // empty for text that was not read from a file
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("zonefile: %s:%d: %s", e.File, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("zonefile: %d: %s", e.Line, e.Msg)
+}
+
+func (e *ParseError) Is(target error) bool { return target == ErrParse }
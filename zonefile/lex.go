@@ -0,0 +1,89 @@
+// File: pkg/namedzone/zonefile/lex.go
+package zonefile
+
+import "strings"
+
+// line is one logical line of a master file: a record or directive, which
+// parentheses may spread over several physical lines, or a run of blank
+// space and comments (no tokens).
+type line struct {
+	text  string // source text, through the final newline
+	num   int    // 1-based number of its first physical line
+	toks  []token
+	blank bool // starts with white space: the owner is omitted
+}
+
+// token is a word or quoted string of a line, at off within line.text.
+type token struct {
+	text string
+	off  int
+}
+
+// lex splits src into logical lines. Comments and parentheses are dropped
+// from the tokens but stay in the text.
+func lex(file, src string) ([]line, error) {
+	var out []line
+	num := 1
+	for i := 0; i < len(src); {
+		l := line{num: num, blank: src[i] == ' ' || src[i] == '\t'}
+		start, depth := i, 0
+	scan:
+		for i < len(src) {
+			switch c := src[i]; c {
+			case '\n':
+				num++
+				i++
+				if depth == 0 {
+					break scan
+				}
+			case ' ', '\t', '\r':
+				i++
+			case ';':
+				for i < len(src) && src[i] != '\n' {
+					i++
+				}
+			case '(':
+				depth++
+				i++
+			case ')':
+				if depth == 0 {
+					return nil, &ParseError{File: file, Line: num, Msg: "unbalanced ')'"}
+				}
+				depth--
+				i++
+			case '"':
+				j := i + 1
+				for j < len(src) && src[j] != '"' {
+					if src[j] == '\\' {
+						j++
+					}
+					if j < len(src) && src[j] == '\n' {
+						num++
+					}
+					j++
+				}
+				if j >= len(src) {
+					return nil, &ParseError{File: file, Line: l.num, Msg: "unterminated quoted string"}
+				}
+				l.toks = append(l.toks, token{text: src[i : j+1], off: i - start})
+				i = j + 1
+			default:
+				j := i
+				for j < len(src) && !strings.ContainsRune(" \t\r\n;()\"", rune(src[j])) {
+					if src[j] == '\\' && j+1 < len(src) {
+						j++
+					}
+					j++
+				}
+				l.toks = append(l.toks, token{text: src[i:j], off: i - start})
+				i = j
+			}
+		}
+		if depth > 0 {
+			return nil, &ParseError{File: file, Line: l.num, Msg: "unbalanced '('"}
+		}
+		l.text = src[start:i]
+		out = append(out, l)
+	}
+	return out, nil
+}
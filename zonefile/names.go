@@ -0,0 +1,152 @@
+// File: pkg/namedzone/zonefile/names.go
+package zonefile
+
+import (
+	"strconv"
+	"strings"
+)
+
+// nameFields lists, per type, the rdata fields that hold domain names and
+// so are relative to $ORIGIN when written without a trailing dot.
+var nameFields = map[string][]int{
+	"NS": {0}, "CNAME": {0}, "DNAME": {0}, "PTR": {0}, "MB": {0}, "MG": {0}, "MR": {0},
+	"SOA": {0, 1}, "MINFO": {0, 1}, "RP": {0, 1},
+	"MX": {1}, "AFSDB": {1}, "RT": {1}, "KX": {1}, "SVCB": {1}, "HTTPS": {1},
+	"PX": {1, 2}, "SRV": {3}, "NAPTR": {5}, "NSEC": {0},
+}
+
+// fqdn adds the trailing dot to name.
+func fqdn(name string) string {
+	if name == "" {
+		return "."
+	}
+	if isAbs(name) {
+		return name
+	}
+	return name + "."
+}
+
+// isAbs reports whether name ends in an unescaped dot.
+func isAbs(name string) bool {
+	if !strings.HasSuffix(name, ".") {
+		return false
+	}
+	bs := 0
+	for i := len(name) - 2; i >= 0 && name[i] == '\\'; i-- {
+		bs++
+	}
+	return bs%2 == 0
+}
+
+// absName resolves a name as written against origin.
+func absName(name, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case isAbs(name):
+		return name
+	case origin == ".":
+		return name + "."
+	}
+	return name + "." + origin
+}
+
+// relName writes the absolute name relative to origin where it lies below it.
+func relName(name, origin string) string {
+	switch {
+	case strings.EqualFold(name, origin):
+		return "@"
+	case origin == ".":
+		return name
+	}
+	if n := len(name) - len(origin) - 1; n > 0 && name[n] == '.' && strings.EqualFold(name[n+1:], origin) {
+		return name[:n]
+	}
+	return name
+}
+
+// absData joins the rdata fields, with the name fields made absolute.
+func absData(typ string, fields []string, origin string) string {
+	return mapNames(typ, fields, func(n string) string { return absName(n, origin) })
+}
+
+// relData rewrites the absolute names in data relative to origin.
+func relData(typ, data, origin string) string {
+	return mapNames(typ, fields(data), func(n string) string { return relName(n, origin) })
+}
+
+// canonData is data with absolute, lowercase names and single spaces, for
+// comparing rdata.
+func canonData(typ, data, origin string) string {
+	return mapNames(typ, fields(data), func(n string) string { return strings.ToLower(absName(n, origin)) })
+}
+
+func mapNames(typ string, fields []string, f func(string) string) string {
+	out := make([]string, len(fields))
+	copy(out, fields)
+	for _, i := range nameFields[typ] {
+		if i < len(out) {
+			out[i] = f(out[i])
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func isClass(s string) bool {
+	switch u := strings.ToUpper(s); u {
+	case "IN", "CH", "CS", "HS":
+		return true
+	default:
+		_, err := strconv.ParseUint(strings.TrimPrefix(u, "CLASS"), 10, 16)
+		return strings.HasPrefix(u, "CLASS") && err == nil
+	}
+}
+
+// ParseTTL parses a TTL in seconds or in BIND's unit notation ("1h30m",
+// "2d", "1W").
+func ParseTTL(s string) (uint32, bool) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, false
+	}
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return uint32(n), true
+	}
+	var total, n uint64
+	digits := false
+	for _, c := range strings.ToLower(s) {
+		if c >= '0' && c <= '9' {
+			// stop before n*10 can wrap; any such n is out of range anyway
+			if n, digits = n*10+uint64(c-'0'), true; n > 1<<32-1 {
+				return 0, false
+			}
+			continue
+		}
+		unit := map[rune]uint64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}[c]
+		if unit == 0 || !digits {
+			return 0, false
+		}
+		if total, n, digits = total+n*unit, 0, false; total > 1<<32-1 {
+			return 0, false
+		}
+	}
+	total += n
+	if total > 1<<32-1 {
+		return 0, false
+	}
+	return uint32(total), true
+}
+
+// fields splits rdata text into its words and quoted strings.
+func fields(data string) []string {
+	lines, err := lex("", strings.ReplaceAll(data, "\n", " "))
+	if err != nil {
+		return strings.Fields(data)
+	}
+	var out []string
+	for _, l := range lines {
+		for _, t := range l.toks {
+			out = append(out, t.text)
+		}
+	}
+	return out
+}
@@ -0,0 +1,265 @@
+// File: pkg/namedzone/zonefile/records.go
+package zonefile
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AddRecord adds r to the zone. r.Name and names in r.Data may be relative
+// to Origin; a nil TTL takes the $TTL in effect where the record is written.
+// The record goes after the last record with the same owner, or after the
+// last record of the file. Adding a record that exists (same name, type and
+// rdata) fails with ErrRecordExists. Unless r is the SOA, the SOA serial is
+// bumped.
+func (z *Zone) AddRecord(r Record) error {
+	rec, err := z.resolve(r)
+	if err != nil {
+		return err
+	}
+	if z.find(rec) >= 0 {
+		return fmt.Errorf("%w: %s", ErrRecordExists, rec)
+	}
+	at, last := len(z.entries), -1
+	for i, e := range z.entries {
+		if e.kind != entryRecord {
+			continue
+		}
+		last = i
+		if strings.EqualFold(e.rec.Name, rec.Name) {
+			at = i + 1
+		}
+	}
+	if at == len(z.entries) && last >= 0 {
+		at = last + 1
+	}
+	if err := z.defaultTTL(&rec, at); err != nil {
+		return err
+	}
+	z.entries = slices.Insert(z.entries, at, &entry{kind: entryRecord, rec: rec})
+	return z.changed(rec)
+}
+
+// UpdateRecord replaces the record old (matched by name, type and rdata)
+// with r in place. A nil r.TTL takes the $TTL in effect there. It fails with
+// ErrNoRecord when old is not in the zone and with ErrRecordExists when r
+// is another record of the zone. Unless the SOA is updated, its serial is
+// bumped.
+func (z *Zone) UpdateRecord(old, r Record) error {
+	from, err := z.resolve(old)
+	if err != nil {
+		return err
+	}
+	i := z.find(from)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNoRecord, from)
+	}
+	rec, err := z.resolve(r)
+	if err != nil {
+		return err
+	}
+	if j := z.find(rec); j >= 0 && j != i {
+		return fmt.Errorf("%w: %s", ErrRecordExists, rec)
+	}
+	if err := z.defaultTTL(&rec, i); err != nil {
+		return err
+	}
+	if z.entries[i].rec.Type == "SOA" {
+		from = z.entries[i].rec
+	}
+	z.entries[i] = &entry{kind: entryRecord, rec: rec}
+	if from.Type == "SOA" {
+		return z.changed(from)
+	}
+	return z.changed(rec)
+}
+
+// DeleteRecord removes the record r (matched by name, type and rdata). It
+// fails with ErrNoRecord when r is not in the zone. Unless r is the SOA, the
+// SOA serial is bumped.
+func (z *Zone) DeleteRecord(r Record) error {
+	rec, err := z.resolve(r)
+	if err != nil {
+		return err
+	}
+	i := z.find(rec)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNoRecord, rec)
+	}
+	z.entries = slices.Delete(z.entries, i, i+1)
+	return z.changed(rec)
+}
+
+// resolve normalizes r as given to the edit methods: absolute names,
+// upper-case type and class.
+func (z *Zone) resolve(r Record) (Record, error) {
+	if r.Name == "" || r.Type == "" {
+		return Record{}, fmt.Errorf("zonefile: record needs a name and a type")
+	}
+	r = r.clone()
+	r.Name = absName(r.Name, z.Origin)
+	r.Type = strings.ToUpper(r.Type)
+	r.Class = strings.ToUpper(cmp.Or(r.Class, "IN"))
+	r.Data = absData(r.Type, fields(r.Data), z.Origin)
+	if r.Type == "SOA" {
+		f := fields(r.Data)
+		if len(f) != 7 {
+			return Record{}, fmt.Errorf("zonefile: SOA needs 7 fields, has %d", len(f))
+		}
+		if _, err := strconv.ParseUint(f[2], 10, 32); err != nil {
+			return Record{}, fmt.Errorf("zonefile: bad SOA serial %q", f[2])
+		}
+	}
+	return r, nil
+}
+
+// find returns the index of the entry holding r, by name, type and rdata.
+func (z *Zone) find(r Record) int {
+	key := r.key()
+	return slices.IndexFunc(z.entries, func(e *entry) bool { return e.kind == entryRecord && e.rec.key() == key })
+}
+
+func (r Record) key() string {
+	return strings.ToLower(r.Name) + " " + r.Type + " " + canonData(r.Type, r.Data, ".")
+}
+
+// defaultTTL sets a missing TTL of r to the default at entry i: $TTL, or the
+// TTL of the record before.
+func (z *Zone) defaultTTL(r *Record, i int) error {
+	if r.TTL != nil {
+		return nil
+	}
+	st := z.stateAt(i)
+	ttl := cmp.Or(st.defTTL, st.lastTTL)
+	if ttl == nil {
+		return fmt.Errorf("zonefile: no TTL for %s and no $TTL", r.Name)
+	}
+	v := *ttl
+	r.TTL = &v
+	return nil
+}
+
+// stateAt returns the state in effect before entry i.
+func (z *Zone) stateAt(i int) *state {
+	st := &state{origin: z.Origin}
+	for _, e := range z.entries[:i] {
+		st.advance(e)
+	}
+	return st
+}
+
+// advance moves st past e, which is known to be consistent with it.
+func (st *state) advance(e *entry) {
+	switch e.kind {
+	case entryDirective:
+		switch {
+		case len(e.args) == 0:
+		case e.directive == "$ORIGIN":
+			st.origin = absName(e.args[0], st.origin)
+		case e.directive == "$TTL":
+			if ttl, ok := ParseTTL(e.args[0]); ok {
+				st.defTTL = &ttl
+			}
+		}
+	case entryRecord:
+		st.owner, st.class, st.lastTTL = e.rec.Name, e.rec.Class, e.rec.TTL
+	}
+}
+
+// changed rewrites the lines an edit invalidated and bumps the serial
+// unless the edit was to the SOA itself.
+func (z *Zone) changed(r Record) error {
+	if err := z.reflow(); err != nil {
+		return err
+	}
+	if r.Type != "SOA" {
+		z.bumpSerial()
+	}
+	return nil
+}
+
+// reflow writes the text of new records, and rewrites records whose line
+// would now read differently because what they inherit from the lines
+// before (owner, TTL, class, $ORIGIN) changed.
+func (z *Zone) reflow() error {
+	st := &state{origin: z.Origin}
+	for i, e := range z.entries {
+		if e.kind != entryRecord || !e.stale(st) {
+			st.advance(e)
+			continue
+		}
+		lines, err := lex("", e.render(st))
+		if err != nil {
+			return err
+		}
+		ne, err := st.entry(lines[0])
+		if err != nil {
+			return err
+		}
+		z.entries[i] = ne
+	}
+	return nil
+}
+
+func (e *entry) stale(st *state) bool {
+	if e.text == "" || e.origin != st.origin {
+		return true
+	}
+	if e.blank && !strings.EqualFold(e.rec.Name, st.owner) {
+		return true
+	}
+	if !e.classSet && cmp.Or(st.class, "IN") != e.rec.Class {
+		return true
+	}
+	if ttl := cmp.Or(st.defTTL, st.lastTTL); !e.ttlSet && ttl != nil && *ttl != *e.rec.TTL {
+		return true
+	}
+	return false
+}
+
+// render writes e as one line in st: owner relative to $ORIGIN, the TTL
+// unless $TTL provides it, class, type and rdata.
+func (e *entry) render(st *state) string {
+	f := []string{relName(e.rec.Name, st.origin)}
+	if st.defTTL == nil || *st.defTTL != *e.rec.TTL {
+		f = append(f, strconv.FormatUint(uint64(*e.rec.TTL), 10))
+	}
+	f = append(f, e.rec.Class, e.rec.Type)
+	if d := relData(e.rec.Type, e.rec.Data, st.origin); d != "" {
+		f = append(f, d)
+	}
+	return strings.Join(f, "\t") + "\n"
+}
+
+// bumpSerial advances the SOA serial.
+func (z *Zone) bumpSerial() {
+	old, ok := z.Serial()
+	if !ok {
+		return
+	}
+	now := time.Now
+	if z.now != nil {
+		now = z.now
+	}
+	z.SetSerial(BumpSerial(old, now()))
+}
+
+// SetSerial sets the serial of the SOA record, rewriting only the serial in
+// its text. It reports false when the file has no SOA.
+func (z *Zone) SetSerial(serial uint32) bool {
+	e := z.soa()
+	if e == nil {
+		return false
+	}
+	s := strconv.FormatUint(uint64(serial), 10)
+	e.text = e.text[:e.serial.off] + s + e.text[e.serial.off+len(e.serial.text):]
+	e.serial.text = s
+	f := fields(e.rec.Data)
+	f[2] = s
+	e.rec.Data = strings.Join(f, " ")
+	return true
+}
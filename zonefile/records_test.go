@@ -0,0 +1,246 @@
+// File: pkg/namedzone/zonefile/records_test.go
+package zonefile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const recordsSrc = `$TTL 300
+@	IN	SOA	ns1 hostmaster 2024010101 3600 900 604800 300 ; serial
+	IN	NS	ns1
+ns1	IN	A	192.0.2.1
+; web servers
+www	IN	A	192.0.2.10 ; primary
+mail	600	IN	A	192.0.2.20
+	IN	AAAA	2001:db8::20
+`
+
+func parseRecords(t *testing.T) *Zone {
+	t.Helper()
+	z, err := Parse(strings.NewReader(recordsSrc), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	z.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+	return z
+}
+
+func TestRecordEdits(t *testing.T) {
+	ttl := uint32(60)
+	for _, tc := range []struct {
+		name string
+		edit func(*Zone) error
+		want string
+	}{
+		{
+			name: "add after owner",
+			edit: func(z *Zone) error { return z.AddRecord(Record{Name: "www", Type: "a", Data: "192.0.2.11"}) },
+			want: `$TTL 300
+@	IN	SOA	ns1 hostmaster 2024010102 3600 900 604800 300 ; serial
+	IN	NS	ns1
+ns1	IN	A	192.0.2.1
+; web servers
+www	IN	A	192.0.2.10 ; primary
+www	IN	A	192.0.2.11
+mail	600	IN	A	192.0.2.20
+	IN	AAAA	2001:db8::20
+`,
+		},
+		{
+			name: "add new owner at end",
+			edit: func(z *Zone) error {
+				return z.AddRecord(Record{Name: "ftp.example.com.", TTL: &ttl, Type: "CNAME", Data: "www"})
+			},
+			want: `$TTL 300
+@	IN	SOA	ns1 hostmaster 2024010102 3600 900 604800 300 ; serial
+	IN	NS	ns1
+ns1	IN	A	192.0.2.1
+; web servers
+www	IN	A	192.0.2.10 ; primary
+mail	600	IN	A	192.0.2.20
+	IN	AAAA	2001:db8::20
+ftp	60	IN	CNAME	www
+`,
+		},
+		{
+			name: "update in place",
+			edit: func(z *Zone) error {
+				return z.UpdateRecord(Record{Name: "www", Type: "A", Data: "192.0.2.10"}, Record{Name: "www", Type: "A", Data: "192.0.2.12"})
+			},
+			want: `$TTL 300
+@	IN	SOA	ns1 hostmaster 2024010102 3600 900 604800 300 ; serial
+	IN	NS	ns1
+ns1	IN	A	192.0.2.1
+; web servers
+www	IN	A	192.0.2.12
+mail	600	IN	A	192.0.2.20
+	IN	AAAA	2001:db8::20
+`,
+		},
+		{
+			name: "delete",
+			edit: func(z *Zone) error { return z.DeleteRecord(Record{Name: "ns1", Type: "A", Data: "192.0.2.1"}) },
+			want: `$TTL 300
+@	IN	SOA	ns1 hostmaster 2024010102 3600 900 604800 300 ; serial
+	IN	NS	ns1
+; web servers
+www	IN	A	192.0.2.10 ; primary
+mail	600	IN	A	192.0.2.20
+	IN	AAAA	2001:db8::20
+`,
+		},
+		{
+			// the AAAA line inherits its owner from the deleted line; its TTL
+			// is the $TTL, not the 600 written there
+			name: "reflow inherited owner",
+			edit: func(z *Zone) error { return z.DeleteRecord(Record{Name: "mail", Type: "A", Data: "192.0.2.20"}) },
+			want: `$TTL 300
+@	IN	SOA	ns1 hostmaster 2024010102 3600 900 604800 300 ; serial
+	IN	NS	ns1
+ns1	IN	A	192.0.2.1
+; web servers
+www	IN	A	192.0.2.10 ; primary
+mail	IN	AAAA	2001:db8::20
+`,
+		},
+		{
+			// renaming the line the AAAA inherits its owner from
+			name: "reflow after owner change",
+			edit: func(z *Zone) error {
+				return z.UpdateRecord(Record{Name: "mail", Type: "A", Data: "192.0.2.20"}, Record{Name: "smtp", TTL: &ttl, Type: "A", Data: "192.0.2.20"})
+			},
+			want: `$TTL 300
+@	IN	SOA	ns1 hostmaster 2024010102 3600 900 604800 300 ; serial
+	IN	NS	ns1
+ns1	IN	A	192.0.2.1
+; web servers
+www	IN	A	192.0.2.10 ; primary
+smtp	60	IN	A	192.0.2.20
+mail	IN	AAAA	2001:db8::20
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			z := parseRecords(t)
+			if err := tc.edit(z); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(z.Bytes()); got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+			if _, err := Parse(strings.NewReader(string(z.Bytes())), "example.com."); err != nil {
+				t.Errorf("reparse: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordEditErrors(t *testing.T) {
+	z := parseRecords(t)
+	www := Record{Name: "www", Type: "A", Data: "192.0.2.10"}
+	if err := z.AddRecord(www); !errors.Is(err, ErrRecordExists) {
+		t.Errorf("add existing: %v", err)
+	}
+	if err := z.AddRecord(Record{Name: "WWW.example.com.", Type: "A", Data: "192.0.2.10"}); !errors.Is(err, ErrRecordExists) {
+		t.Errorf("add existing, other spelling: %v", err)
+	}
+	if err := z.UpdateRecord(Record{Name: "nope", Type: "A", Data: "192.0.2.9"}, www); !errors.Is(err, ErrNoRecord) {
+		t.Errorf("update missing: %v", err)
+	}
+	if err := z.UpdateRecord(www, Record{Name: "ns1", Type: "A", Data: "192.0.2.1"}); !errors.Is(err, ErrRecordExists) {
+		t.Errorf("update onto another record: %v", err)
+	}
+	if err := z.DeleteRecord(Record{Name: "nope", Type: "A", Data: "192.0.2.9"}); !errors.Is(err, ErrNoRecord) {
+		t.Errorf("delete missing: %v", err)
+	}
+	if err := z.AddRecord(Record{Name: "@", Type: "SOA", Data: "ns1 hostmaster x 3600 900 604800 300"}); err == nil {
+		t.Error("SOA with a bad serial accepted")
+	}
+	if got := string(z.Bytes()); got != recordsSrc {
+		t.Errorf("failed edits changed the file:\n%s", got)
+	}
+	if err := New("example.net", 300).AddRecord(Record{Name: "a", Type: "A", Data: "192.0.2.1"}); err != nil {
+		t.Errorf("add to new zone: %v", err)
+	}
+	if err := (&Zone{Origin: "example.net."}).AddRecord(Record{Name: "a", Type: "A", Data: "192.0.2.1"}); err == nil {
+		t.Error("add without a TTL or $TTL accepted")
+	}
+}
+
+func TestRecordEditSerial(t *testing.T) {
+	z := parseRecords(t)
+	z.now = func() time.Time { return time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC) }
+	if err := z.AddRecord(Record{Name: "a", Type: "A", Data: "192.0.2.30"}); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := z.Serial(); s != 2024030500 {
+		t.Errorf("serial after add = %d, want 2024030500", s)
+	}
+	if err := z.DeleteRecord(Record{Name: "a", Type: "A", Data: "192.0.2.30"}); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := z.Serial(); s != 2024030501 {
+		t.Errorf("serial after delete = %d, want 2024030501", s)
+	}
+	if !strings.Contains(string(z.Bytes()), "2024030501 3600 900 604800 300 ; serial\n") {
+		t.Errorf("serial not rewritten in place:\n%s", z.Bytes())
+	}
+	// editing the SOA itself leaves its serial alone
+	soa := z.Lookup("@", "SOA")[0]
+	next := soa
+	next.Data = strings.Replace(soa.Data, "3600", "7200", 1)
+	if err := z.UpdateRecord(soa, next); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := z.Serial(); s != 2024030501 {
+		t.Errorf("serial after SOA update = %d, want 2024030501", s)
+	}
+}
+
+func TestBumpSerial(t *testing.T) {
+	now := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct{ in, want uint32 }{
+		{2024010101, 2024030500},
+		{2024030500, 2024030501},
+		{2024030599, 2024030600},
+		{2025010100, 2025010101},
+		{42, 43},
+		{1<<32 - 1, 0},
+	} {
+		if got := BumpSerial(tc.in, now); got != tc.want {
+			t.Errorf("BumpSerial(%d) = %d, want %d", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want uint32
+		ok   bool
+	}{
+		{"300", 300, true},
+		{"1h30m", 5400, true},
+		{"2D", 172800, true},
+		{"1w1s", 604801, true},
+		{"4294967295", 1<<32 - 1, true},
+		{"4294967295s", 1<<32 - 1, true},
+		{"4294967296", 0, false},
+		{"4294967296s", 0, false},
+		{"18446744073709551617s", 0, false},
+		{"18446744073709551617", 0, false},
+		{"7102w", 0, false},
+		{"4294967295s1s", 0, false},
+		{"h", 0, false},
+		{"1x", 0, false},
+		{"", 0, false},
+	} {
+		got, ok := ParseTTL(tc.in)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ParseTTL(%q) = %d, %v; want %d, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// File: pkg/namedzone/zonefile/serial.go
+package zonefile
+
+import "time"
+
+// BumpSerial returns the serial to follow serial on a change at now. A
+// serial in the YYYYMMDDnn convention moves to today's date when that is
+// ahead of it; otherwise, and for other serials, it is incremented (in
+// RFC 1982 serial arithmetic, so it wraps).
+func BumpSerial(serial uint32, now time.Time) uint32 {
+	if serial >= 1970010100 && serial <= 2099123199 {
+		y, m, d := now.Date()
+		today := uint32(y*1000000 + int(m)*10000 + d*100)
+		if today > serial {
+			return today
+		}
+	}
+	return serial + 1
+}
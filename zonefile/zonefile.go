@@ -0,0 +1,330 @@
+// File: pkg/namedzone/zonefile/zonefile.go
+
+// Package zonefile reads and edits zone data in master file format (RFC
+// 1035 section 5), the text files named.conf zones load with "file". Like
+// the named.conf model it edits the parsed text in place: lines that are not
+// touched keep their layout and comments, and only added or changed records
+// are written anew.
+package zonefile
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Zone is a parsed master file.
+type Zone struct {
+	// Origin is the zone name, absolute with a trailing dot. Relative names
+	// in the file and in records passed to the edit methods are relative to
+	// it (or to the $ORIGIN in effect where they are written).
+	Origin string
+
+	path    string
+	entries []*entry
+	now     func() time.Time
+}
+
+// Record is a resource record. Records returned by a Zone have absolute
+// names, in the owner and in the rdata, and the TTL they are served with.
+type Record struct {
+	Name  string  `json:"name"`
+	TTL   *uint32 `json:"ttl,omitempty"`   // nil: the $TTL default
+	Class string  `json:"class,omitempty"` // "IN" when empty
+	Type  string  `json:"type"`
+	Data  string  `json:"data"` // rdata in presentation format, one line
+}
+
+// String formats r as a master file line with absolute names.
+func (r Record) String() string {
+	f := []string{r.Name}
+	if r.TTL != nil {
+		f = append(f, strconv.FormatUint(uint64(*r.TTL), 10))
+	}
+	f = append(f, cmp.Or(r.Class, "IN"), r.Type, r.Data)
+	return strings.Join(f, "\t")
+}
+
+type entryKind int
+
+const (
+	entryTrivia entryKind = iota // blank lines and comments
+	entryRecord
+	entryDirective
+)
+
+// entry is one logical line of the file.
+type entry struct {
+	kind entryKind
+	text string
+	num  int
+
+	// records
+	rec      Record // absolute names; TTL and Class always set
+	origin   string // $ORIGIN in effect at the line
+	blank    bool   // owner omitted, inherited from the previous record
+	ttlSet   bool   // TTL written on the line
+	classSet bool   // class written on the line
+	serial   token  // SOA serial, at its offset in text
+
+	// directives
+	directive string   // "$ORIGIN", "$TTL", ...
+	args      []string // its arguments
+	inc       *Include
+	gen       *Generate
+}
+
+// state is what a line inherits from the lines before it.
+type state struct {
+	origin  string
+	defTTL  *uint32 // $TTL
+	lastTTL *uint32 // TTL of the previous record
+	owner   string
+	class   string
+}
+
+// New returns an empty master file for the zone origin that starts with a
+// $TTL directive for ttl.
+func New(origin string, ttl uint32) *Zone {
+	z := &Zone{Origin: fqdn(origin)}
+	z.entries = append(z.entries, &entry{kind: entryDirective, text: fmt.Sprintf("$TTL %d\n", ttl), num: 1,
+		directive: "$TTL", args: []string{strconv.FormatUint(uint64(ttl), 10)}})
+	return z
+}
+
+// Parse reads a master file for the zone origin. $INCLUDE directives are
+// kept but not followed.
+func Parse(r io.Reader, origin string) (*Zone, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parse("", string(data), &state{origin: fqdn(origin)}, nil)
+}
+
+// Load reads the master file at path for the zone origin. $INCLUDE
+// directives are kept but not followed; see LoadTree.
+func Load(path, origin string) (*Zone, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parse(path, string(data), &state{origin: fqdn(origin)}, nil)
+}
+
+func parse(file, src string, st *state, ld *loader) (*Zone, error) {
+	z := &Zone{Origin: st.origin, path: file}
+	lines, err := lex(file, src)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lines {
+		e, err := st.entry(l)
+		if err != nil {
+			if pe, ok := err.(*ParseError); ok {
+				pe.File = file
+			}
+			return nil, err
+		}
+		if e.inc != nil && ld != nil {
+			if err := ld.load(e.inc, st); err != nil {
+				return nil, err
+			}
+		}
+		z.entries = append(z.entries, e)
+	}
+	return z, nil
+}
+
+// entry interprets l in st and advances st past it.
+func (st *state) entry(l line) (*entry, error) {
+	e := &entry{text: l.text, num: l.num}
+	if len(l.toks) == 0 {
+		return e, nil
+	}
+	fail := func(format string, args ...any) error {
+		return &ParseError{Line: l.num, Msg: fmt.Sprintf(format, args...)}
+	}
+	if first := l.toks[0].text; !l.blank && strings.HasPrefix(first, "$") {
+		e.kind, e.directive = entryDirective, strings.ToUpper(first)
+		for _, t := range l.toks[1:] {
+			e.args = append(e.args, t.text)
+		}
+		switch e.directive {
+		case "$INCLUDE":
+			if len(e.args) == 0 {
+				return nil, fail("$INCLUDE without a file name")
+			}
+			e.inc = &Include{Path: strings.Trim(e.args[0], `"`), Origin: st.origin, Line: l.num}
+			if len(e.args) > 1 {
+				e.inc.Origin = absName(e.args[1], st.origin)
+			}
+		case "$GENERATE":
+			g, err := parseGenerate(e.args, st)
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			g.Line = l.num
+			e.gen = g
+		case "$ORIGIN":
+			if len(e.args) == 0 {
+				return nil, fail("$ORIGIN without a name")
+			}
+			st.origin = absName(e.args[0], st.origin)
+		case "$TTL":
+			if len(e.args) == 0 {
+				return nil, fail("$TTL without a value")
+			}
+			ttl, ok := ParseTTL(e.args[0])
+			if !ok {
+				return nil, fail("bad $TTL %q", e.args[0])
+			}
+			st.defTTL = &ttl
+		}
+		return e, nil
+	}
+
+	e.kind, e.origin, e.blank = entryRecord, st.origin, l.blank
+	toks := l.toks
+	if l.blank {
+		if st.owner == "" {
+			return nil, fail("record without an owner name")
+		}
+		e.rec.Name = st.owner
+	} else {
+		e.rec.Name = absName(toks[0].text, st.origin)
+		toks = toks[1:]
+	}
+	for len(toks) > 0 {
+		if ttl, ok := ParseTTL(toks[0].text); ok && !e.ttlSet {
+			e.rec.TTL, e.ttlSet = &ttl, true
+		} else if isClass(toks[0].text) && !e.classSet {
+			e.rec.Class, e.classSet = strings.ToUpper(toks[0].text), true
+		} else {
+			break
+		}
+		toks = toks[1:]
+	}
+	if len(toks) == 0 {
+		return nil, fail("record for %s without a type", e.rec.Name)
+	}
+	e.rec.Type = strings.ToUpper(toks[0].text)
+	data := toks[1:]
+	words := make([]string, len(data))
+	for i, t := range data {
+		words[i] = t.text
+	}
+	e.rec.Data = absData(e.rec.Type, words, st.origin)
+
+	if !e.classSet {
+		e.rec.Class = cmp.Or(st.class, "IN")
+	}
+	if !e.ttlSet {
+		switch {
+		case st.defTTL != nil:
+			e.rec.TTL = st.defTTL
+		case st.lastTTL != nil:
+			e.rec.TTL = st.lastTTL
+		case e.rec.Type == "SOA" && len(words) == 7:
+			// no TTL yet: named falls back to the SOA minimum
+			ttl, ok := ParseTTL(words[6])
+			if !ok {
+				return nil, fail("bad SOA minimum %q", words[6])
+			}
+			e.rec.TTL = &ttl
+		default:
+			return nil, fail("no TTL for %s and no $TTL", e.rec.Name)
+		}
+	}
+	if e.rec.Type == "SOA" {
+		if len(data) != 7 {
+			return nil, fail("SOA for %s needs 7 fields, has %d", e.rec.Name, len(data))
+		}
+		if _, err := strconv.ParseUint(data[2].text, 10, 32); err != nil {
+			return nil, fail("bad SOA serial %q", data[2].text)
+		}
+		e.serial = data[2]
+	}
+	st.owner, st.class, st.lastTTL = e.rec.Name, e.rec.Class, e.rec.TTL
+	return e, nil
+}
+
+// Bytes returns the master file text.
+func (z *Zone) Bytes() []byte {
+	var sb strings.Builder
+	for _, e := range z.entries {
+		sb.WriteString(e.text)
+	}
+	return []byte(sb.String())
+}
+
+// WriteTo writes the master file text to w.
+func (z *Zone) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(z.Bytes())
+	return int64(n), err
+}
+
+// Save writes the master file to path, keeping the mode of an existing file.
+func (z *Zone) Save(path string) error {
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	return os.WriteFile(path, z.Bytes(), mode)
+}
+
+// Records returns the records written in the file, in order; AllRecords
+// adds generated and included ones.
+func (z *Zone) Records() []Record {
+	var out []Record
+	for _, e := range z.entries {
+		if e.kind == entryRecord {
+			out = append(out, e.rec.clone())
+		}
+	}
+	return out
+}
+
+// Lookup returns the records at name (relative to Origin or absolute) of
+// type typ, or of every type when typ is empty.
+func (z *Zone) Lookup(name, typ string) []Record {
+	name = absName(name, z.Origin)
+	var out []Record
+	for _, e := range z.entries {
+		if e.kind == entryRecord && strings.EqualFold(e.rec.Name, name) && (typ == "" || strings.EqualFold(e.rec.Type, typ)) {
+			out = append(out, e.rec.clone())
+		}
+	}
+	return out
+}
+
+// Serial returns the serial of the zone's SOA record; false when the file
+// has none.
+func (z *Zone) Serial() (uint32, bool) {
+	if e := z.soa(); e != nil {
+		n, _ := strconv.ParseUint(e.serial.text, 10, 32)
+		return uint32(n), true
+	}
+	return 0, false
+}
+
+func (z *Zone) soa() *entry {
+	for _, e := range z.entries {
+		if e.kind == entryRecord && e.rec.Type == "SOA" {
+			return e
+		}
+	}
+	return nil
+}
+
+func (r Record) clone() Record {
+	if r.TTL != nil {
+		ttl := *r.TTL
+		r.TTL = &ttl
+	}
+	return r
+}